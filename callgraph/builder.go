@@ -0,0 +1,385 @@
+package callgraph
+
+import (
+	"fmt"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// Option is an option to build the graph
+type Option func(*config)
+
+type config struct {
+	info *types.Info
+}
+
+// WithInfo uses the types already assigned to the project instead of
+// checking it again
+func WithInfo(info *types.Info) Option {
+	return func(c *config) {
+		c.info = info
+	}
+}
+
+// New builds the call graph of the project
+func New(p *solcparser.Project, opts ...Option) *Graph {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.info == nil {
+		c.info = types.Check(p)
+	}
+
+	b := &builder{
+		project: p,
+		info:    c.info,
+		g: &Graph{
+			nodes: map[interface{}]*Node{},
+			edges: map[edgeKey]*Edge{},
+		},
+		owner: map[interface{}]*solcparser.ContractDefinition{},
+		lin:   map[*solcparser.ContractDefinition][]*solcparser.ContractDefinition{},
+	}
+	b.declare()
+
+	// the nodes of the Yul functions and the call sites are added while
+	// visiting the bodies
+	decls := append([]*Node{}, b.g.Nodes...)
+	for _, n := range decls {
+		b.visit(n)
+	}
+	return b.g
+}
+
+type builder struct {
+	project *solcparser.Project
+	info    *types.Info
+	g       *Graph
+
+	// owner is the contract that declares each member
+	owner map[interface{}]*solcparser.ContractDefinition
+
+	lin map[*solcparser.ContractDefinition][]*solcparser.ContractDefinition
+
+	// state of the current walk
+	caller *Node
+}
+
+func (b *builder) addNode(kind NodeKind, name string, decl interface{}, contract *solcparser.ContractDefinition, file string) *Node {
+	n := &Node{
+		ID:       len(b.g.Nodes),
+		Kind:     kind,
+		Name:     name,
+		Decl:     decl,
+		Contract: contract,
+		File:     file,
+	}
+	b.g.Nodes = append(b.g.Nodes, n)
+	b.g.nodes[decl] = n
+	return n
+}
+
+func (b *builder) addEdge(caller, callee *Node, kind EdgeKind, site interface{}) {
+	if caller == nil || callee == nil {
+		return
+	}
+	key := edgeKey{caller: caller, callee: callee, kind: kind}
+	if _, ok := b.g.edges[key]; ok {
+		return
+	}
+	e := &Edge{Caller: caller, Callee: callee, Kind: kind, Site: site}
+	b.g.edges[key] = e
+	b.g.Edges = append(b.g.Edges, e)
+	caller.Out = append(caller.Out, e)
+	callee.In = append(callee.In, e)
+}
+
+// declare adds the nodes of the functions and the modifiers
+func (b *builder) declare() {
+	for _, file := range b.project.Files() {
+		for _, child := range b.project.Units[file].Children {
+			switch obj := child.(type) {
+			case *solcparser.FunctionDefinition:
+				b.addNode(KindFunction, functionName(obj), obj, nil, file)
+
+			case *solcparser.ContractDefinition:
+				for _, node := range obj.SubNodes {
+					switch decl := node.(type) {
+					case *solcparser.FunctionDefinition:
+						b.owner[decl] = obj
+						b.addNode(KindFunction, obj.Name+"."+functionName(decl), decl, obj, file)
+					case *solcparser.ModifierDefinition:
+						b.owner[decl] = obj
+						b.addNode(KindModifier, obj.Name+"."+decl.Name, decl, obj, file)
+					}
+				}
+			}
+		}
+	}
+}
+
+// functionName returns the name of a function or the kind of the special functions
+func functionName(fn *solcparser.FunctionDefinition) string {
+	switch {
+	case fn.IsConstructor:
+		return "constructor"
+	case fn.IsReceiveEther:
+		return "receive"
+	case fn.IsFallback, fn.Name == "":
+		return "fallback"
+	}
+	return fn.Name
+}
+
+// linearize returns the cached linearization of a contract
+func (b *builder) linearize(contract *solcparser.ContractDefinition) []*solcparser.ContractDefinition {
+	if lin, ok := b.lin[contract]; ok {
+		return lin
+	}
+	lin, err := b.project.Linearize(contract)
+	if err != nil {
+		lin = []*solcparser.ContractDefinition{contract}
+	}
+	b.lin[contract] = lin
+	return lin
+}
+
+// derived calls fn with the linearization of every contract that inherits from
+// the contract (including itself) and the position of the contract in it
+func (b *builder) derived(contract *solcparser.ContractDefinition, fn func(lin []*solcparser.ContractDefinition, indx int)) {
+	for _, c := range b.project.Contracts() {
+		lin := b.linearize(c)
+		for i, base := range lin {
+			if base == contract {
+				fn(lin, i)
+				break
+			}
+		}
+	}
+}
+
+// visit adds the edges of the calls made by a function or a modifier
+func (b *builder) visit(n *Node) {
+	b.caller = n
+
+	var body interface{}
+	switch obj := n.Decl.(type) {
+	case *solcparser.FunctionDefinition:
+		for _, m := range obj.Modifiers {
+			inv, ok := m.(*solcparser.ModifierInvocation)
+			if !ok {
+				continue
+			}
+			b.modifier(inv)
+			for _, arg := range inv.Arguments {
+				b.inspect(arg)
+			}
+		}
+		body = obj.Body
+	case *solcparser.ModifierDefinition:
+		body = obj.Body
+	}
+	if body != nil {
+		b.inspect(body)
+	}
+}
+
+func (b *builder) inspect(node interface{}) {
+	solcparser.Inspect(node, func(n interface{}) bool {
+		switch obj := n.(type) {
+		case *solcparser.FunctionCall:
+			b.call(obj)
+		case *solcparser.InlineAssemblyStatement:
+			b.assembly(obj)
+			return false
+		}
+		return true
+	})
+}
+
+func (b *builder) call(obj *solcparser.FunctionCall) {
+	callee := obj.Expression
+	if nv, ok := callee.(*solcparser.NameValueExpression); ok {
+		// f{value: x}(...)
+		callee = nv.Expression
+	}
+
+	if ma, ok := callee.(*solcparser.MemberAccess); ok {
+		switch ma.MemberName {
+		case "call", "delegatecall", "staticcall":
+			if _, ok := b.info.TypeOf(ma.Expression).(*types.AddressType); ok {
+				b.callSite(obj, ma.MemberName)
+				return
+			}
+		}
+	}
+
+	// the overloads are resolved on the expression of the call
+	fn, ok := b.info.RefOf(obj.Expression).(*solcparser.FunctionDefinition)
+	if !ok {
+		if fn, ok = b.info.RefOf(callee).(*solcparser.FunctionDefinition); !ok {
+			return
+		}
+	}
+	owner := b.owner[fn]
+	isLibrary := owner != nil && owner.Kind == "library"
+
+	switch c := callee.(type) {
+	case *solcparser.Identifier:
+		if isLibrary && owner != b.caller.Contract {
+			b.edgeTo(fn, EdgeLibrary, obj)
+		} else {
+			b.dispatch(b.caller.Contract, fn, EdgeInternal, obj)
+		}
+
+	case *solcparser.MemberAccess:
+		switch typ := b.info.TypeOf(c.Expression).(type) {
+		case *types.ContractType:
+			if typ.Super {
+				b.super(fn, obj)
+			} else {
+				b.dispatch(typ.Def, fn, EdgeExternal, obj)
+			}
+		case *types.TypeType:
+			// Lib.f() or Base.f() are not virtual
+			if isLibrary {
+				b.edgeTo(fn, EdgeLibrary, obj)
+			} else {
+				b.edgeTo(fn, EdgeInternal, obj)
+			}
+		default:
+			if isLibrary {
+				// functions attached with 'using for'
+				b.edgeTo(fn, EdgeLibrary, obj)
+			}
+		}
+	}
+}
+
+func (b *builder) edgeTo(decl interface{}, kind EdgeKind, site interface{}) {
+	b.addEdge(b.caller, b.g.nodes[decl], kind, site)
+}
+
+// dispatch adds an edge to every implementation of the function that may be
+// called from the contracts that inherit from the base contract
+func (b *builder) dispatch(base *solcparser.ContractDefinition, fn *solcparser.FunctionDefinition, kind EdgeKind, site interface{}) {
+	if base == nil || b.owner[fn] == nil || fn.Visibility == "private" {
+		b.edgeTo(fn, kind, site)
+		return
+	}
+	found := false
+	b.derived(base, func(lin []*solcparser.ContractDefinition, indx int) {
+		if impl := b.lookup(lin, fn); impl != nil {
+			found = true
+			b.edgeTo(impl, kind, site)
+		}
+	})
+	if !found {
+		b.edgeTo(fn, kind, site)
+	}
+}
+
+// super adds an edge to the next implementation of the function after the
+// caller contract in the linearization of the contracts that inherit from it
+func (b *builder) super(fn *solcparser.FunctionDefinition, site interface{}) {
+	found := false
+	if contract := b.caller.Contract; contract != nil {
+		b.derived(contract, func(lin []*solcparser.ContractDefinition, indx int) {
+			if impl := b.lookup(lin[indx+1:], fn); impl != nil {
+				found = true
+				b.edgeTo(impl, EdgeSuper, site)
+			}
+		})
+	}
+	if !found {
+		b.edgeTo(fn, EdgeSuper, site)
+	}
+}
+
+// lookup returns the first function of the contracts with the same signature
+func (b *builder) lookup(lin []*solcparser.ContractDefinition, fn *solcparser.FunctionDefinition) *solcparser.FunctionDefinition {
+	for _, c := range lin {
+		for _, node := range c.SubNodes {
+			if other, ok := node.(*solcparser.FunctionDefinition); ok && b.sameSignature(fn, other) {
+				return other
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builder) sameSignature(x, y *solcparser.FunctionDefinition) bool {
+	if x.Name != y.Name || x.IsConstructor != y.IsConstructor || len(x.Parameters) != len(y.Parameters) {
+		return false
+	}
+	for i := range x.Parameters {
+		tx, ty := b.info.TypeOf(x.Parameters[i]), b.info.TypeOf(y.Parameters[i])
+		if tx != nil && ty != nil && !types.Identical(tx, ty) {
+			return false
+		}
+	}
+	return true
+}
+
+// modifier adds an edge to the implementations of the modifier, which are
+// virtual like the functions
+func (b *builder) modifier(inv *solcparser.ModifierInvocation) {
+	if b.caller.Contract == nil {
+		return
+	}
+	b.derived(b.caller.Contract, func(lin []*solcparser.ContractDefinition, indx int) {
+		for _, c := range lin {
+			for _, node := range c.SubNodes {
+				if m, ok := node.(*solcparser.ModifierDefinition); ok && m.Name == inv.Name {
+					b.edgeTo(m, EdgeModifier, inv)
+					return
+				}
+			}
+		}
+	})
+}
+
+// callSite adds the node of a low-level call
+func (b *builder) callSite(obj *solcparser.FunctionCall, member string) {
+	name := member + "@" + b.caller.File
+	if loc := obj.GetLoc(); loc != nil {
+		name += fmt.Sprintf(":%d:%d", loc.Start.Line, loc.Start.Column+1)
+	}
+	n := b.addNode(KindCallSite, name, obj, b.caller.Contract, b.caller.File)
+	b.addEdge(b.caller, n, EdgeLowLevel, obj)
+}
+
+// assembly adds the Yul functions of an inline assembly block and their calls
+func (b *builder) assembly(obj *solcparser.InlineAssemblyStatement) {
+	parent := b.caller
+	defs := map[string]*Node{}
+	solcparser.Inspect(obj.Body, func(n interface{}) bool {
+		if def, ok := n.(*solcparser.AssemblyFunctionDefinition); ok {
+			node := b.addNode(KindYulFunction, parent.Name+"."+def.Name, def, parent.Contract, parent.File)
+			if _, ok := defs[def.Name]; !ok {
+				defs[def.Name] = node
+			}
+		}
+		return true
+	})
+	if len(defs) != 0 {
+		b.yul(obj.Body, parent, defs)
+	}
+}
+
+func (b *builder) yul(body interface{}, caller *Node, defs map[string]*Node) {
+	solcparser.Inspect(body, func(n interface{}) bool {
+		switch obj := n.(type) {
+		case *solcparser.AssemblyFunctionDefinition:
+			b.yul(obj.Body, b.g.nodes[obj], defs)
+			return false
+		case *solcparser.AssemblyCall:
+			if target, ok := defs[obj.FunctionName]; ok {
+				b.addEdge(caller, target, EdgeYul, obj)
+			}
+		}
+		return true
+	})
+}
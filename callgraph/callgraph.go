@@ -0,0 +1,328 @@
+// Package callgraph builds the call graph of a project.
+//
+// The nodes are the functions, the modifiers, the Yul functions of the inline
+// assembly blocks and the low-level call sites. The calls to virtual functions
+// have an edge to every implementation that may be dispatched from a contract
+// deriving from the caller, as resolved with the linearization of the contract.
+package callgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// NodeKind is the kind of a node of the call graph
+type NodeKind int
+
+const (
+	KindFunction NodeKind = iota
+	KindModifier
+	KindYulFunction
+
+	// KindCallSite is a low-level call (call, delegatecall or staticcall)
+	KindCallSite
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case KindFunction:
+		return "function"
+	case KindModifier:
+		return "modifier"
+	case KindYulFunction:
+		return "yul-function"
+	case KindCallSite:
+		return "call-site"
+	}
+	return fmt.Sprintf("NodeKind(%d)", int(k))
+}
+
+// EdgeKind is the kind of call of an edge
+type EdgeKind int
+
+const (
+	// EdgeInternal is an internal call (i.e. 'f()' or 'Base.f()')
+	EdgeInternal EdgeKind = iota
+
+	// EdgeSuper is a call through 'super'
+	EdgeSuper
+
+	// EdgeLibrary is a call to a library function, including the functions
+	// attached with 'using for'
+	EdgeLibrary
+
+	// EdgeExternal is a call through a contract or interface type (i.e. 'token.transfer()')
+	EdgeExternal
+
+	// EdgeLowLevel is a low-level call to an address
+	EdgeLowLevel
+
+	// EdgeModifier is the invocation of a modifier
+	EdgeModifier
+
+	// EdgeYul is a call to a Yul function
+	EdgeYul
+)
+
+func (k EdgeKind) String() string {
+	switch k {
+	case EdgeInternal:
+		return "internal"
+	case EdgeSuper:
+		return "super"
+	case EdgeLibrary:
+		return "library"
+	case EdgeExternal:
+		return "external"
+	case EdgeLowLevel:
+		return "low-level"
+	case EdgeModifier:
+		return "modifier"
+	case EdgeYul:
+		return "yul"
+	}
+	return fmt.Sprintf("EdgeKind(%d)", int(k))
+}
+
+// Node is a node of the call graph
+type Node struct {
+	ID   int
+	Kind NodeKind
+
+	// Name is 'Contract.function' for the members of a contract, 'function' for
+	// the free functions, 'Contract.function.yulFunction' for the Yul functions
+	// and 'member@file:line:col' for the low-level call sites
+	Name string
+
+	// Decl is the *FunctionDefinition, *ModifierDefinition,
+	// *AssemblyFunctionDefinition or the *FunctionCall of the call site
+	Decl interface{}
+
+	// Contract is the contract that declares the node, it is nil for the free functions
+	Contract *solcparser.ContractDefinition
+
+	File string
+
+	// In and Out are the incoming and outgoing edges
+	In  []*Edge
+	Out []*Edge
+}
+
+func (n *Node) String() string {
+	return n.Name
+}
+
+// Loc returns the position of the declaration if it is known
+func (n *Node) Loc() *solcparser.Location {
+	if node, ok := n.Decl.(solcparser.INode); ok {
+		return node.GetLoc()
+	}
+	return nil
+}
+
+// IsEntryPoint returns true if the node is a function that can be called from
+// outside the contract (the public and external functions, the fallback and
+// receive functions) of a contract that is not an interface
+func (n *Node) IsEntryPoint() bool {
+	fn, ok := n.Decl.(*solcparser.FunctionDefinition)
+	if !ok || n.Kind != KindFunction || n.Contract == nil || fn.IsConstructor {
+		return false
+	}
+	if n.Contract.Kind == "interface" || fn.Body == nil {
+		return false
+	}
+	switch fn.Visibility {
+	case "public", "external", "default":
+		return true
+	}
+	return fn.IsFallback || fn.IsReceiveEther
+}
+
+// Edge is a call from the caller to the callee
+type Edge struct {
+	Caller *Node
+	Callee *Node
+	Kind   EdgeKind
+
+	// Site is the first expression of the caller that makes the call
+	// (*FunctionCall, *ModifierInvocation or *AssemblyCall)
+	Site interface{}
+}
+
+// Graph is the call graph of a project
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+
+	nodes map[interface{}]*Node
+	edges map[edgeKey]*Edge
+}
+
+type edgeKey struct {
+	caller, callee *Node
+	kind           EdgeKind
+}
+
+// Node returns the node of a declaration or a call site
+func (g *Graph) Node(decl interface{}) *Node {
+	return g.nodes[decl]
+}
+
+// Lookup returns the nodes with the given name. The overloaded functions
+// share the same name.
+func (g *Graph) Lookup(name string) []*Node {
+	res := []*Node{}
+	for _, n := range g.Nodes {
+		if n.Name == name {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// Callers returns the nodes that call the node directly
+func (g *Graph) Callers(n *Node) []*Node {
+	res := []*Node{}
+	seen := map[*Node]bool{}
+	for _, e := range n.In {
+		if !seen[e.Caller] {
+			seen[e.Caller] = true
+			res = append(res, e.Caller)
+		}
+	}
+	return res
+}
+
+// Callees returns the nodes called directly by the node
+func (g *Graph) Callees(n *Node) []*Node {
+	res := []*Node{}
+	seen := map[*Node]bool{}
+	for _, e := range n.Out {
+		if !seen[e.Callee] {
+			seen[e.Callee] = true
+			res = append(res, e.Callee)
+		}
+	}
+	return res
+}
+
+// WhoCanReach returns the nodes that can reach the node through one or more
+// calls, in the order of the graph. The node is included only if it is recursive.
+func (g *Graph) WhoCanReach(n *Node) []*Node {
+	return g.closure(n, func(e *Edge) *Node { return e.Caller }, func(n *Node) []*Edge { return n.In })
+}
+
+// Reachable returns the nodes reachable from the node through one or more
+// calls, in the order of the graph. The node is included only if it is recursive.
+func (g *Graph) Reachable(n *Node) []*Node {
+	return g.closure(n, func(e *Edge) *Node { return e.Callee }, func(n *Node) []*Edge { return n.Out })
+}
+
+// EntryPoints returns the entry points of the contracts that can reach the node
+func (g *Graph) EntryPoints(n *Node) []*Node {
+	res := []*Node{}
+	if n.IsEntryPoint() {
+		res = append(res, n)
+	}
+	for _, m := range g.WhoCanReach(n) {
+		if m != n && m.IsEntryPoint() {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+func (g *Graph) closure(n *Node, next func(e *Edge) *Node, edges func(n *Node) []*Edge) []*Node {
+	visited := map[*Node]bool{}
+	queue := []*Node{n}
+	for len(queue) != 0 {
+		m := queue[0]
+		queue = queue[1:]
+		for _, e := range edges(m) {
+			if other := next(e); !visited[other] {
+				visited[other] = true
+				queue = append(queue, other)
+			}
+		}
+	}
+	res := []*Node{}
+	for _, m := range g.Nodes {
+		if visited[m] {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// Dot returns the graph in the Graphviz dot format
+func (g *Graph) Dot() string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph callgraph {\n")
+	for _, n := range g.Nodes {
+		shape := "box"
+		switch n.Kind {
+		case KindModifier:
+			shape = "hexagon"
+		case KindYulFunction:
+			shape = "ellipse"
+		case KindCallSite:
+			shape = "diamond"
+		}
+		fmt.Fprintf(&buf, "  n%d [label=\"%s\", shape=%s];\n", n.ID, dotEscape(n.Name), shape)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&buf, "  n%d -> n%d [label=%q];\n", e.Caller.ID, e.Callee.ID, e.Kind.String())
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func dotEscape(str string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(str)
+}
+
+type jsonNode struct {
+	ID         int                  `json:"id"`
+	Name       string               `json:"name"`
+	Kind       string               `json:"kind"`
+	File       string               `json:"file,omitempty"`
+	Loc        *solcparser.Location `json:"loc,omitempty"`
+	EntryPoint bool                 `json:"entryPoint,omitempty"`
+}
+
+type jsonEdge struct {
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Kind string `json:"kind"`
+}
+
+type jsonGraph struct {
+	Nodes []*jsonNode `json:"nodes"`
+	Edges []*jsonEdge `json:"edges"`
+}
+
+// MarshalJSON encodes the nodes and the edges of the graph
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	res := &jsonGraph{
+		Nodes: []*jsonNode{},
+		Edges: []*jsonEdge{},
+	}
+	for _, n := range g.Nodes {
+		res.Nodes = append(res.Nodes, &jsonNode{
+			ID:         n.ID,
+			Name:       n.Name,
+			Kind:       n.Kind.String(),
+			File:       n.File,
+			Loc:        n.Loc(),
+			EntryPoint: n.IsEntryPoint(),
+		})
+	}
+	for _, e := range g.Edges {
+		res.Edges = append(res.Edges, &jsonEdge{From: e.Caller.ID, To: e.Callee.ID, Kind: e.Kind.String()})
+	}
+	return json.Marshal(res)
+}
@@ -0,0 +1,236 @@
+package callgraph
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+const testSource = `
+interface IToken {
+    function transfer(address to, uint amount) external returns (bool);
+}
+
+library SafeMath {
+    function add(uint a, uint b) internal pure returns (uint) {
+        return a + b;
+    }
+}
+
+contract Token is IToken {
+    function transfer(address to, uint amount) external override returns (bool) {
+        return true;
+    }
+}
+
+contract Base {
+    using SafeMath for uint;
+
+    address owner;
+    uint total;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner);
+        _;
+    }
+
+    function init() public {
+        _mint(1);
+    }
+
+    function _mint(uint amount) internal virtual {
+        total = total.add(amount);
+    }
+
+    function setOwner(address o) internal {
+        owner = o;
+    }
+}
+
+contract Child is Base {
+    function _mint(uint amount) internal override {
+        super._mint(amount);
+    }
+
+    function mint(uint amount) external onlyOwner {
+        _mint(amount);
+    }
+
+    function pay(IToken token, address to) public {
+        token.transfer(to, 1);
+    }
+
+    function forward(address target, bytes memory data) public {
+        (bool ok, ) = target.delegatecall(data);
+        require(ok);
+    }
+
+    function rescue() external {
+        setOwner(msg.sender);
+    }
+
+    function yul() public {
+        assembly {
+            function double(x) -> y { y := inc(inc(x)) }
+            function inc(x) -> y { y := add(x, 1) }
+            let z := double(1)
+        }
+    }
+}
+`
+
+func buildGraph(t *testing.T) *Graph {
+	t.Helper()
+
+	p := solcparser.NewProject()
+	if err := p.AddSource("a.sol", testSource); err != nil {
+		t.Fatal(err)
+	}
+	return New(p)
+}
+
+func lookup(t *testing.T, g *Graph, name string) *Node {
+	t.Helper()
+
+	nodes := g.Lookup(name)
+	if len(nodes) != 1 {
+		t.Fatalf("expected one node for %s but found %d", name, len(nodes))
+	}
+	return nodes[0]
+}
+
+func names(nodes []*Node) []string {
+	res := []string{}
+	for _, n := range nodes {
+		res = append(res, n.Name)
+	}
+	return res
+}
+
+func TestCallGraphEdges(t *testing.T) {
+	g := buildGraph(t)
+
+	edges := []string{}
+	for _, e := range g.Edges {
+		edges = append(edges, e.Caller.Name+" -"+e.Kind.String()+"-> "+e.Callee.Name)
+	}
+	sort.Strings(edges)
+
+	expected := []string{
+		"Base._mint -library-> SafeMath.add",
+		"Base.init -internal-> Base._mint",
+		"Base.init -internal-> Child._mint",
+		"Child._mint -super-> Base._mint",
+		"Child.forward -low-level-> delegatecall@a.sol:56:23",
+		"Child.mint -internal-> Child._mint",
+		"Child.mint -modifier-> Base.onlyOwner",
+		"Child.pay -external-> IToken.transfer",
+		"Child.pay -external-> Token.transfer",
+		"Child.rescue -internal-> Base.setOwner",
+		"Child.yul -yul-> Child.yul.double",
+		"Child.yul.double -yul-> Child.yul.inc",
+	}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Fatalf("bad edges:\n%s", strings.Join(edges, "\n"))
+	}
+}
+
+func TestCallGraphQueries(t *testing.T) {
+	g := buildGraph(t)
+
+	cases := []struct {
+		name        string
+		reach       []string
+		entryPoints []string
+	}{
+		{
+			"Base.setOwner",
+			[]string{"Child.rescue"},
+			[]string{"Child.rescue"},
+		},
+		{
+			"SafeMath.add",
+			[]string{"Base.init", "Base._mint", "Child._mint", "Child.mint"},
+			[]string{"Base.init", "Child.mint"},
+		},
+		{
+			"Child.yul.inc",
+			[]string{"Child.yul", "Child.yul.double"},
+			[]string{"Child.yul"},
+		},
+		{
+			"Child.pay",
+			[]string{},
+			[]string{"Child.pay"},
+		},
+	}
+
+	for _, c := range cases {
+		n := lookup(t, g, c.name)
+		if reach := names(g.WhoCanReach(n)); !reflect.DeepEqual(reach, c.reach) {
+			t.Fatalf("bad reach for %s: %v", c.name, reach)
+		}
+		if entry := names(g.EntryPoints(n)); !reflect.DeepEqual(entry, c.entryPoints) {
+			t.Fatalf("bad entry points for %s: %v", c.name, entry)
+		}
+	}
+
+	mint := lookup(t, g, "Child.mint")
+	if callees := names(g.Callees(mint)); !reflect.DeepEqual(callees, []string{"Base.onlyOwner", "Child._mint"}) {
+		t.Fatalf("bad callees %v", callees)
+	}
+	if reach := names(g.Reachable(mint)); !reflect.DeepEqual(reach, []string{"SafeMath.add", "Base.onlyOwner", "Base._mint", "Child._mint"}) {
+		t.Fatalf("bad reachable %v", reach)
+	}
+}
+
+func TestCallGraphExport(t *testing.T) {
+	g := buildGraph(t)
+
+	dot := g.Dot()
+	rescue, setOwner := lookup(t, g, "Child.rescue"), lookup(t, g, "Base.setOwner")
+	for _, str := range []string{
+		"digraph callgraph {",
+		"label=\"Base.onlyOwner\", shape=hexagon",
+		"label=\"Child.yul.double\", shape=ellipse",
+		"n" + strconv.Itoa(rescue.ID) + " -> n" + strconv.Itoa(setOwner.ID) + " [label=\"internal\"];",
+	} {
+		if !strings.Contains(dot, str) {
+			t.Fatalf("%q not found in\n%s", str, dot)
+		}
+	}
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		Nodes []struct {
+			ID         int    `json:"id"`
+			Name       string `json:"name"`
+			Kind       string `json:"kind"`
+			EntryPoint bool   `json:"entryPoint"`
+		} `json:"nodes"`
+		Edges []struct {
+			From int    `json:"from"`
+			To   int    `json:"to"`
+			Kind string `json:"kind"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Nodes) != len(g.Nodes) || len(res.Edges) != len(g.Edges) {
+		t.Fatal("bad json size")
+	}
+	for _, n := range res.Nodes {
+		if n.Name == "Child.rescue" && (!n.EntryPoint || n.Kind != "function") {
+			t.Fatal("bad json node")
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package cfg
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+type loop struct {
+	breakTarget    *Block
+	continueTarget *Block
+}
+
+type builder struct {
+	*config
+	g *CFG
+
+	// current is the block being built
+	current *Block
+
+	// ret is the target of the return statements. It is the exit block
+	// or the code after the placeholder of the enclosing modifier.
+	ret *Block
+
+	loops []*loop
+
+	// placeholder builds the code that replaces '_' in a modifier
+	placeholder func()
+}
+
+func (b *builder) newBlock(comment string) *Block {
+	block := &Block{
+		Index:   len(b.g.Blocks),
+		Comment: comment,
+	}
+	b.g.Blocks = append(b.g.Blocks, block)
+	return block
+}
+
+func addEdge(from, to *Block) {
+	from.Succs = append(from.Succs, to)
+	to.Preds = append(to.Preds, from)
+}
+
+func (b *builder) add(node interface{}) {
+	b.current.Nodes = append(b.current.Nodes, node)
+}
+
+// jump ends the current block with an edge to the target
+func (b *builder) jump(to *Block) {
+	addEdge(b.current, to)
+}
+
+// terminate ends the current block with a jump. The code that follows is unreachable.
+func (b *builder) terminate(to *Block) {
+	b.jump(to)
+	b.current = b.newBlock("unreachable")
+}
+
+// branch ends the current block with a condition
+func (b *builder) branch(cond interface{}, ifTrue, ifFalse *Block) {
+	b.current.Cond = cond
+	addEdge(b.current, ifTrue)
+	addEdge(b.current, ifFalse)
+}
+
+// function builds the function body with the modifiers inlined
+func (b *builder) function(modifiers []interface{}, body interface{}) {
+	if len(modifiers) == 0 {
+		b.stmt(body)
+		return
+	}
+
+	inv, ok := modifiers[0].(*solcparser.ModifierInvocation)
+	if !ok {
+		b.function(modifiers[1:], body)
+		return
+	}
+	// evaluate the arguments
+	b.add(inv)
+
+	def := b.lookupModifier(inv.Name)
+	if def == nil || def.Body == nil {
+		// base constructor call or unknown modifier
+		b.function(modifiers[1:], body)
+		return
+	}
+
+	outer := b.placeholder
+	b.placeholder = func() {
+		after := b.newBlock("modifier.after." + inv.Name)
+
+		ret, loops := b.ret, b.loops
+		b.ret, b.loops = after, nil
+		b.function(modifiers[1:], body)
+		b.jump(after)
+		b.ret, b.loops = ret, loops
+
+		b.current = after
+	}
+	b.stmt(def.Body)
+	b.placeholder = outer
+}
+
+// lookupModifier finds the definition of a modifier in the linearization of the contract
+func (b *builder) lookupModifier(name string) *solcparser.ModifierDefinition {
+	if b.contract == nil {
+		return nil
+	}
+	bases := []*solcparser.ContractDefinition{b.contract}
+	if b.project != nil {
+		if lin, err := b.project.Linearize(b.contract); err == nil {
+			bases = lin
+		}
+	}
+	for _, c := range bases {
+		for _, node := range c.SubNodes {
+			if m, ok := node.(*solcparser.ModifierDefinition); ok && m.Name == name {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builder) pushLoop(breakTarget, continueTarget *Block) {
+	b.loops = append(b.loops, &loop{breakTarget: breakTarget, continueTarget: continueTarget})
+}
+
+func (b *builder) popLoop() {
+	b.loops = b.loops[:len(b.loops)-1]
+}
+
+func (b *builder) stmt(s interface{}) {
+	switch obj := s.(type) {
+	case nil:
+		return
+
+	case *solcparser.Block:
+		for _, st := range obj.Statements {
+			b.stmt(st)
+		}
+
+	case *solcparser.UncheckedStatement:
+		b.stmt(obj.Block)
+
+	case *solcparser.IfStatement:
+		b.add(obj.Condition)
+		then := b.newBlock("if.then")
+		done := b.newBlock("if.done")
+		els := done
+		if obj.FalseBody != nil {
+			els = b.newBlock("if.else")
+		}
+		b.branch(obj.Condition, then, els)
+
+		b.current = then
+		b.stmt(obj.TrueBody)
+		b.jump(done)
+
+		if obj.FalseBody != nil {
+			b.current = els
+			b.stmt(obj.FalseBody)
+			b.jump(done)
+		}
+		b.current = done
+
+	case *solcparser.WhileStatement:
+		cond := b.newBlock("while.cond")
+		body := b.newBlock("while.body")
+		done := b.newBlock("while.done")
+		b.jump(cond)
+
+		b.current = cond
+		b.add(obj.Condition)
+		b.branch(obj.Condition, body, done)
+
+		b.current = body
+		b.pushLoop(done, cond)
+		b.stmt(obj.Body)
+		b.popLoop()
+		b.jump(cond)
+
+		b.current = done
+
+	case *solcparser.DoWhileStatement:
+		body := b.newBlock("do.body")
+		cond := b.newBlock("do.cond")
+		done := b.newBlock("do.done")
+		b.jump(body)
+
+		b.current = body
+		b.pushLoop(done, cond)
+		b.stmt(obj.Body)
+		b.popLoop()
+		b.jump(cond)
+
+		b.current = cond
+		b.add(obj.Condition)
+		b.branch(obj.Condition, body, done)
+
+		b.current = done
+
+	case *solcparser.ForStatement:
+		if obj.InitExpression != nil {
+			b.stmt(obj.InitExpression)
+		}
+		cond := b.newBlock("for.cond")
+		body := b.newBlock("for.body")
+		post := b.newBlock("for.post")
+		done := b.newBlock("for.done")
+		b.jump(cond)
+
+		b.current = cond
+		if obj.ConditionExpression != nil {
+			b.add(obj.ConditionExpression)
+			b.branch(obj.ConditionExpression, body, done)
+		} else {
+			b.jump(body)
+		}
+
+		b.current = body
+		b.pushLoop(done, post)
+		b.stmt(obj.Body)
+		b.popLoop()
+		b.jump(post)
+
+		b.current = post
+		if loop, ok := obj.LoopExpression.(*solcparser.ExpressionStatement); ok && loop.Expression != nil {
+			b.add(loop)
+		}
+		b.jump(cond)
+
+		b.current = done
+
+	case *solcparser.BreakStatement:
+		b.add(obj)
+		if len(b.loops) != 0 {
+			b.terminate(b.loops[len(b.loops)-1].breakTarget)
+		}
+
+	case *solcparser.ContinueStatement:
+		b.add(obj)
+		if len(b.loops) != 0 {
+			b.terminate(b.loops[len(b.loops)-1].continueTarget)
+		}
+
+	case *solcparser.ReturnStatement:
+		b.add(obj)
+		b.terminate(b.ret)
+
+	case *solcparser.RevertStatement, *solcparser.ThrowStatement:
+		b.add(obj)
+		b.terminate(b.g.Revert)
+
+	case *solcparser.TryStatement:
+		b.tryStmt(obj)
+
+	case *solcparser.ExpressionStatement:
+		b.exprStmt(obj)
+
+	default:
+		b.add(s)
+	}
+}
+
+func (b *builder) exprStmt(obj *solcparser.ExpressionStatement) {
+	if ident, ok := obj.Expression.(*solcparser.Identifier); ok && ident.Name == "_" && b.placeholder != nil {
+		// the placeholder of a modifier is replaced with the function body
+		placeholder := b.placeholder
+		b.placeholder = nil
+		placeholder()
+		b.placeholder = placeholder
+		return
+	}
+
+	b.add(obj)
+
+	call, ok := obj.Expression.(*solcparser.FunctionCall)
+	if !ok {
+		return
+	}
+	switch calleeName(call) {
+	case "require", "assert":
+		if len(call.Arguments) == 0 {
+			return
+		}
+		ok := b.newBlock(calleeName(call) + ".ok")
+		b.branch(call.Arguments[0], ok, b.g.Revert)
+		b.current = ok
+
+	case "revert":
+		b.terminate(b.g.Revert)
+	}
+}
+
+func (b *builder) tryStmt(obj *solcparser.TryStatement) {
+	b.add(obj.Expression)
+	from := b.current
+	done := b.newBlock("try.done")
+
+	body := b.newBlock("try.body")
+	addEdge(from, body)
+	b.current = body
+	b.addParams(obj.ReturnParameters)
+	b.stmt(obj.Body)
+	b.jump(done)
+
+	for _, c := range obj.CatchClause {
+		clause, ok := c.(*solcparser.CatchClause)
+		if !ok {
+			continue
+		}
+		catch := b.newBlock("try.catch")
+		addEdge(from, catch)
+		b.current = catch
+		b.addParams(clause.Parameters)
+		b.stmt(clause.Body)
+		b.jump(done)
+	}
+	b.current = done
+}
+
+// addParams adds the variables declared by the try and catch clauses
+func (b *builder) addParams(params interface{}) {
+	list, ok := params.([]interface{})
+	if !ok {
+		return
+	}
+	for _, p := range list {
+		if p != nil {
+			b.add(p)
+		}
+	}
+}
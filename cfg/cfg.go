@@ -0,0 +1,274 @@
+// Package cfg builds the control flow graph of the function and modifier bodies.
+//
+// The nodes of the basic blocks are the simple statements of the body and the
+// conditions of the branches. The modifiers of a function are inlined in its
+// graph with the function body in place of the '_' placeholder.
+package cfg
+
+import (
+	"fmt"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// Block is a basic block of the graph
+type Block struct {
+	Index int
+
+	// Comment describes the block (i.e. if.then or for.body)
+	Comment string
+
+	// Nodes are the statements and the conditions executed in the block
+	Nodes []interface{}
+
+	// Cond is the condition evaluated at the end of the block. The first successor
+	// is taken when it is true and the second one when it is false.
+	Cond interface{}
+
+	Succs []*Block
+	Preds []*Block
+}
+
+func (b *Block) String() string {
+	return fmt.Sprintf("b%d", b.Index)
+}
+
+// CFG is the control flow graph of a function or modifier
+type CFG struct {
+	// Decl is the function or modifier definition
+	Decl interface{}
+
+	Blocks []*Block
+	Entry  *Block
+
+	// Exit is reached when the execution finishes without reverting
+	Exit *Block
+
+	// Revert is reached when the execution reverts
+	Revert *Block
+}
+
+// Option is an option to build the graph
+type Option func(*config)
+
+type config struct {
+	project  *solcparser.Project
+	contract *solcparser.ContractDefinition
+}
+
+// WithProject resolves the modifiers declared in the project
+func WithProject(p *solcparser.Project) Option {
+	return func(c *config) {
+		c.project = p
+	}
+}
+
+// WithContract resolves the modifiers from the contract that declares the function
+func WithContract(contract *solcparser.ContractDefinition) Option {
+	return func(c *config) {
+		c.contract = contract
+	}
+}
+
+// New builds the graph of a function or modifier definition
+func New(decl interface{}, opts ...Option) (*CFG, error) {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	g := &CFG{Decl: decl}
+	b := &builder{config: cfg, g: g}
+	g.Entry = b.newBlock("entry")
+	g.Exit = b.newBlock("exit")
+	g.Revert = b.newBlock("revert")
+	b.current = g.Entry
+	b.ret = g.Exit
+
+	switch obj := decl.(type) {
+	case *solcparser.FunctionDefinition:
+		if obj.Body == nil {
+			return nil, fmt.Errorf("function %s does not have a body", obj.Name)
+		}
+		b.function(obj.Modifiers, obj.Body)
+
+	case *solcparser.ModifierDefinition:
+		if obj.Body == nil {
+			return nil, fmt.Errorf("modifier %s does not have a body", obj.Name)
+		}
+		b.stmt(obj.Body)
+
+	default:
+		return nil, fmt.Errorf("unexpected declaration %T", decl)
+	}
+	b.jump(g.Exit)
+
+	return g, nil
+}
+
+// ForProject builds the graphs of all the functions and modifiers with a body
+func ForProject(p *solcparser.Project) []*CFG {
+	res := []*CFG{}
+	add := func(decl interface{}, contract *solcparser.ContractDefinition) {
+		if g, err := New(decl, WithProject(p), WithContract(contract)); err == nil {
+			res = append(res, g)
+		}
+	}
+	for _, file := range p.Files() {
+		for _, child := range p.Units[file].Children {
+			switch obj := child.(type) {
+			case *solcparser.FunctionDefinition:
+				add(obj, nil)
+			case *solcparser.ContractDefinition:
+				for _, node := range obj.SubNodes {
+					switch node.(type) {
+					case *solcparser.FunctionDefinition, *solcparser.ModifierDefinition:
+						add(node, obj)
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// Reachable returns the blocks reachable from the entry block
+func (g *CFG) Reachable() map[*Block]bool {
+	visited := map[*Block]bool{}
+	queue := []*Block{g.Entry}
+	for len(queue) != 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if visited[b] {
+			continue
+		}
+		visited[b] = true
+		queue = append(queue, b.Succs...)
+	}
+	return visited
+}
+
+// Unreachable returns the first statement of every region of code that can
+// never be executed. A region starts at an unreachable block whose predecessors
+// are all reachable and spans the unreachable blocks that follow it.
+func (g *CFG) Unreachable() []interface{} {
+	reachable := g.Reachable()
+
+	isEntry := func(b *Block) bool {
+		for _, pred := range b.Preds {
+			if !reachable[pred] {
+				return false
+			}
+		}
+		return true
+	}
+
+	res := []interface{}{}
+	visited := map[*Block]bool{}
+	region := func(entry *Block) {
+		// the first statement of the region in the order of execution
+		var first interface{}
+		queue := []*Block{entry}
+		for len(queue) != 0 {
+			b := queue[0]
+			queue = queue[1:]
+			if reachable[b] || visited[b] {
+				continue
+			}
+			visited[b] = true
+			if first == nil && len(b.Nodes) != 0 {
+				first = b.Nodes[0]
+			}
+			queue = append(queue, b.Succs...)
+		}
+		if first != nil {
+			res = append(res, first)
+		}
+	}
+
+	for _, b := range g.Blocks {
+		if !reachable[b] && !visited[b] && isEntry(b) {
+			region(b)
+		}
+	}
+	// the regions that are a cycle of unreachable blocks have no entry block
+	for _, b := range g.Blocks {
+		if !reachable[b] && !visited[b] {
+			region(b)
+		}
+	}
+	return res
+}
+
+// Dot returns the graph in the graphviz dot format
+func (g *CFG) Dot() string {
+	var buf strings.Builder
+	buf.WriteString("digraph cfg {\n")
+	buf.WriteString("  node [shape=box];\n")
+	for _, b := range g.Blocks {
+		lines := []string{fmt.Sprintf("%s: %s", b, b.Comment)}
+		for _, n := range b.Nodes {
+			lines = append(lines, dotEscape(describe(n)))
+		}
+		fmt.Fprintf(&buf, "  %s [label=\"%s\\l\"];\n", b, strings.Join(lines, "\\l"))
+	}
+	for _, b := range g.Blocks {
+		for i, succ := range b.Succs {
+			if b.Cond != nil && len(b.Succs) == 2 {
+				branch := "true"
+				if i == 1 {
+					branch = "false"
+				}
+				fmt.Fprintf(&buf, "  %s -> %s [label=%q];\n", b, succ, branch)
+			} else {
+				fmt.Fprintf(&buf, "  %s -> %s;\n", b, succ)
+			}
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func dotEscape(str string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(str)
+}
+
+// describe returns a short description of a node with its position
+func describe(n interface{}) string {
+	node, ok := n.(solcparser.INode)
+	if !ok {
+		return fmt.Sprintf("%T", n)
+	}
+	str := node.GetType()
+	switch obj := n.(type) {
+	case *solcparser.ExpressionStatement:
+		if call, ok := obj.Expression.(*solcparser.FunctionCall); ok {
+			if name := calleeName(call); name != "" {
+				str += " " + name + "(...)"
+			}
+		}
+	case *solcparser.ModifierInvocation:
+		str += " " + obj.Name
+	case *solcparser.VariableDeclaration:
+		str += " " + obj.Name
+	}
+	if loc := node.GetLoc(); loc != nil {
+		str += fmt.Sprintf(" @%d:%d", loc.Start.Line, loc.Start.Column+1)
+	}
+	return str
+}
+
+// calleeName returns the name of the function called (i.e. require or token.transfer)
+func calleeName(call *solcparser.FunctionCall) string {
+	switch obj := call.Expression.(type) {
+	case *solcparser.Identifier:
+		return obj.Name
+	case *solcparser.MemberAccess:
+		if ident, ok := obj.Expression.(*solcparser.Identifier); ok {
+			return ident.Name + "." + obj.MemberName
+		}
+		return obj.MemberName
+	}
+	return ""
+}
@@ -0,0 +1,219 @@
+package cfg
+
+import (
+	"strings"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+func buildFunction(t *testing.T, src string, name string) *CFG {
+	t.Helper()
+
+	p := solcparser.NewProject()
+	if err := p.AddSource("a.sol", src); err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range ForProject(p) {
+		if fn, ok := g.Decl.(*solcparser.FunctionDefinition); ok && fn.Name == name {
+			return g
+		}
+	}
+	t.Fatalf("function %s not found", name)
+	return nil
+}
+
+// edges returns the edges of the reachable blocks as 'comment -> comment'
+func edges(g *CFG) []string {
+	reachable := g.Reachable()
+	res := []string{}
+	for _, b := range g.Blocks {
+		if !reachable[b] {
+			continue
+		}
+		for _, succ := range b.Succs {
+			res = append(res, b.Comment+" -> "+succ.Comment)
+		}
+	}
+	return res
+}
+
+func TestCFG(t *testing.T) {
+	cases := []struct {
+		body  string
+		edges []string
+	}{
+		{
+			"uint a = 1;",
+			[]string{"entry -> exit"},
+		},
+		{
+			"if (x > 0) { x = 1; } else { x = 2; }",
+			[]string{"entry -> if.then", "entry -> if.else", "if.then -> if.done", "if.done -> exit", "if.else -> if.done"},
+		},
+		{
+			"while (x > 0) { x--; }",
+			[]string{"entry -> while.cond", "while.cond -> while.body", "while.cond -> while.done", "while.body -> while.cond", "while.done -> exit"},
+		},
+		{
+			"do { x--; } while (x > 0);",
+			[]string{"entry -> do.body", "do.body -> do.cond", "do.cond -> do.body", "do.cond -> do.done", "do.done -> exit"},
+		},
+		{
+			"for (uint i = 0; i < 10; i++) { if (i == x) { break; } continue; }",
+			[]string{
+				"entry -> for.cond",
+				"for.cond -> for.body", "for.cond -> for.done",
+				"for.body -> if.then", "for.body -> if.done",
+				"for.post -> for.cond",
+				"for.done -> exit",
+				"if.then -> for.done",
+				"if.done -> for.post",
+			},
+		},
+		{
+			"require(x > 0, \"zero\"); return;",
+			[]string{"entry -> require.ok", "entry -> revert", "require.ok -> exit"},
+		},
+		{
+			"if (x == 0) { revert(\"zero\"); } unchecked { x++; }",
+			[]string{"entry -> if.then", "entry -> if.done", "if.then -> revert", "if.done -> exit"},
+		},
+		{
+			"try this.g() returns (uint v) { x = v; } catch Error(string memory) { x = 1; } catch { x = 2; }",
+			[]string{"entry -> try.body", "entry -> try.catch", "entry -> try.catch", "try.done -> exit", "try.body -> try.done", "try.catch -> try.done", "try.catch -> try.done"},
+		},
+	}
+
+	for _, c := range cases {
+		src := "contract A { function g() external returns (uint) {} function f(uint x) public { " + c.body + " } }"
+		g := buildFunction(t, src, "f")
+		found := edges(g)
+		if strings.Join(found, ", ") != strings.Join(c.edges, ", ") {
+			t.Fatalf("bad edges for '%s':\n%v\n%v", c.body, found, c.edges)
+		}
+	}
+}
+
+func TestCFGModifiers(t *testing.T) {
+	src := `
+contract Base {
+	address owner;
+
+	modifier onlyOwner() {
+		require(msg.sender == owner);
+		_;
+	}
+}
+
+contract A is Base {
+	bool locked;
+
+	modifier nonReentrant() {
+		locked = true;
+		_;
+		locked = false;
+	}
+
+	function f(uint x) public onlyOwner nonReentrant returns (uint) {
+		if (x == 0) {
+			return 1;
+		}
+		return x;
+	}
+}
+`
+	g := buildFunction(t, src, "f")
+	expected := []string{
+		"entry -> require.ok",
+		"entry -> revert",
+		"require.ok -> if.then",
+		"require.ok -> if.done",
+		"modifier.after.onlyOwner -> exit",
+		"modifier.after.nonReentrant -> modifier.after.onlyOwner",
+		"if.then -> modifier.after.nonReentrant",
+		"if.done -> modifier.after.nonReentrant",
+	}
+	if found := edges(g); strings.Join(found, ", ") != strings.Join(expected, ", ") {
+		t.Fatalf("bad edges:\n%v\n%v", found, expected)
+	}
+
+	// the code after the placeholder is executed after the returns
+	var after *Block
+	for _, b := range g.Blocks {
+		if b.Comment == "modifier.after.nonReentrant" {
+			after = b
+		}
+	}
+	if len(after.Nodes) != 1 {
+		t.Fatal("the code after the placeholder expected")
+	}
+}
+
+func TestCFGUnreachable(t *testing.T) {
+	src := `
+contract A {
+	function f(uint x) public returns (uint) {
+		if (x > 0) {
+			return 1;
+		} else {
+			revert();
+		}
+		x = 2;
+		return x;
+	}
+}
+`
+	g := buildFunction(t, src, "f")
+	unreachable := g.Unreachable()
+	if len(unreachable) != 1 {
+		t.Fatalf("one unreachable statement expected but found %d", len(unreachable))
+	}
+	loc := unreachable[0].(solcparser.INode).GetLoc()
+	if loc.Start.Line != 9 {
+		t.Fatalf("bad unreachable statement at line %d", loc.Start.Line)
+	}
+}
+
+func TestCFGUnreachableRegion(t *testing.T) {
+	src := `
+contract A {
+	function f(uint x) public returns (uint) {
+		return 1;
+		if (x > 1) {
+			x = 3;
+		}
+		while (x > 0) {
+			x--;
+		}
+		return x;
+	}
+}
+`
+	g := buildFunction(t, src, "f")
+	unreachable := g.Unreachable()
+	if len(unreachable) != 1 {
+		t.Fatalf("one unreachable region expected but found %d", len(unreachable))
+	}
+	loc := unreachable[0].(solcparser.INode).GetLoc()
+	if loc.Start.Line != 5 {
+		t.Fatalf("bad unreachable statement at line %d", loc.Start.Line)
+	}
+}
+
+func TestCFGDot(t *testing.T) {
+	g := buildFunction(t, "contract A { function f(uint x) public { require(x > 0); } }", "f")
+	dot := g.Dot()
+
+	expected := []string{
+		"digraph cfg {",
+		`b0 [label="b0: entry\lExpressionStatement require(...) @1:42\l"];`,
+		`b0 -> b3 [label="true"];`,
+		`b0 -> b2 [label="false"];`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(dot, e) {
+			t.Fatalf("'%s' not found in:\n%s", e, dot)
+		}
+	}
+}
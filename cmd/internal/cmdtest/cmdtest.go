@@ -0,0 +1,62 @@
+// Package cmdtest runs the commands in the tests with their standard streams
+// redirected to files.
+package cmdtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Result is the exit code and the output of a command
+type Result struct {
+	Code   int
+	Stdout string
+	Stderr string
+}
+
+// Run runs the entry point of a command with the arguments, stdin is the
+// input of the command
+func Run(t *testing.T, run func(args []string) int, stdin string, args ...string) *Result {
+	t.Helper()
+
+	dir := t.TempDir()
+	open := func(name string, data string) *os.File {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	inFile, outFile, errFile := open("stdin", stdin), open("stdout", ""), open("stderr", "")
+	defer inFile.Close()
+	defer outFile.Close()
+	defer errFile.Close()
+
+	oldIn, oldOut, oldErr := os.Stdin, os.Stdout, os.Stderr
+	os.Stdin, os.Stdout, os.Stderr = inFile, outFile, errFile
+	code := func() int {
+		defer func() {
+			os.Stdin, os.Stdout, os.Stderr = oldIn, oldOut, oldErr
+		}()
+		return run(args)
+	}()
+
+	read := func(name string) string {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	return &Result{
+		Code:   code,
+		Stdout: read("stdout"),
+		Stderr: read("stderr"),
+	}
+}
@@ -0,0 +1,87 @@
+// Package source loads the Solidity files given to the commands.
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// StdinName is the name of the source read from stdin
+const StdinName = "<stdin>"
+
+// File is a file given to a command
+type File struct {
+	Name string
+	Text string
+}
+
+// Load reads the files, the Solidity files of the directories (in lexical
+// order) and stdin if the path is '-'
+func Load(paths []string) ([]*File, error) {
+	res := []*File{}
+	add := func(file string) error {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		res = append(res, &File{Name: filepath.ToSlash(file), Text: string(data)})
+		return nil
+	}
+
+	for _, path := range paths {
+		if path == "-" {
+			data, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, &File{Name: StdinName, Text: string(data)})
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if err := add(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		files := []string{}
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasSuffix(file, ".sol") {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			if err := add(file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// LoadMap is like Load but returns the text of the files by name
+func LoadMap(paths []string) (map[string]string, error) {
+	files, err := Load(paths)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]string{}
+	for _, file := range files {
+		res[file.Name] = file.Text
+	}
+	return res, nil
+}
@@ -0,0 +1,129 @@
+// Command sollint runs the lint rules over Solidity files.
+//
+//	sollint [-config .sollint.yaml] [-format text|json|sarif] [-fix] <file or directory>...
+//
+// With -fix the suggested fixes are written to the files and only the
+// diagnostics that could not be fixed are reported.
+// Without -config it uses the .sollint.yaml, .sollint.yml or .sollint.json file
+// of the current directory if there is one, or enables all the rules otherwise.
+// The path '-' reads the source from stdin, its fixes are not written.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/umbracle/solidity-parser-go/cmd/internal/source"
+	"github.com/umbracle/solidity-parser-go/lint"
+
+	// the built-in rules
+	_ "github.com/umbracle/solidity-parser-go/lint/legacy"
+	_ "github.com/umbracle/solidity-parser-go/lint/security"
+	_ "github.com/umbracle/solidity-parser-go/lint/style"
+)
+
+var configFiles = []string{".sollint.yaml", ".sollint.yml", ".sollint.json"}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("sollint", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: sollint [flags] <file or directory>...\n\n")
+		flags.PrintDefaults()
+	}
+
+	var configPath, format string
+	var listRules, fix bool
+	flags.StringVar(&configPath, "config", "", "path of the config file")
+	flags.StringVar(&format, "format", "text", "output format: text, json or sarif")
+	flags.BoolVar(&listRules, "rules", false, "list the available rules")
+	flags.BoolVar(&fix, "fix", false, "apply the suggested fixes to the files")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if listRules {
+		for _, rule := range lint.Rules() {
+			fmt.Printf("%-32s %-8s %s\n", rule.ID(), rule.Severity(), rule.Doc())
+		}
+		return 0
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	reporter, err := lint.NewReporter(format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	sources, err := source.LoadMap(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	var diags []*lint.Diagnostic
+	if fix {
+		diags, err = fixSources(lint.New(config), sources)
+	} else {
+		diags, err = lint.New(config).Lint(sources)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if err := reporter.Report(os.Stdout, diags); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	for _, d := range diags {
+		if d.Severity == lint.SeverityError {
+			return 1
+		}
+	}
+	return 0
+}
+
+// fixSources applies the fixes to the files and returns the remaining diagnostics
+func fixSources(linter *lint.Linter, sources map[string]string) ([]*lint.Diagnostic, error) {
+	fixed, diags, err := linter.Fix(sources)
+	if err != nil {
+		return nil, err
+	}
+	for file, src := range fixed {
+		// the source read from stdin has no file to write
+		if src == sources[file] || file == source.StdinName {
+			continue
+		}
+		if err := ioutil.WriteFile(filepath.FromSlash(file), []byte(src), 0644); err != nil {
+			return nil, err
+		}
+	}
+	return diags, nil
+}
+
+func loadConfig(path string) (*lint.Config, error) {
+	if path != "" {
+		return lint.LoadConfig(path)
+	}
+	for _, name := range configFiles {
+		if _, err := os.Stat(name); err == nil {
+			return lint.LoadConfig(name)
+		}
+	}
+	return lint.DefaultConfig(), nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/cmd/internal/cmdtest"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+	}{
+		{
+			[]string{"-config", "testdata/config.yaml", "testdata/wallet.sol"},
+			"",
+			1,
+			"testdata/wallet.sol:7:17: error: tx.origin used for authorization [tx-origin]\n",
+		},
+		{
+			// the warnings do not fail
+			[]string{"-config", "testdata/config.yaml", "testdata/legacy.sol"},
+			"",
+			0,
+			"testdata/legacy.sol:4:5: warning: function f has no explicit visibility [function-visibility]\n",
+		},
+		{
+			[]string{"-config", "testdata/config.yaml", "-"},
+			"contract A { function f() {} }",
+			0,
+			"<stdin>:1:14: warning: function f has no explicit visibility [function-visibility]\n",
+		},
+		{
+			// the usage errors
+			[]string{"-config", "testdata/missing.yaml", "testdata/legacy.sol"},
+			"",
+			2,
+			"",
+		},
+		{
+			[]string{"-format", "xml", "testdata/legacy.sol"},
+			"",
+			2,
+			"",
+		},
+		{
+			[]string{"testdata/missing.sol"},
+			"",
+			2,
+			"",
+		},
+		{
+			[]string{"-unknown", "testdata/legacy.sol"},
+			"",
+			2,
+			"",
+		},
+		{
+			[]string{},
+			"",
+			2,
+			"",
+		},
+	}
+
+	for _, c := range cases {
+		res := cmdtest.Run(t, run, c.stdin, c.args...)
+		if res.Code != c.code {
+			t.Fatalf("bad exit code for %v: expected %d but found %d (%s)", c.args, c.code, res.Code, res.Stderr)
+		}
+		if res.Stdout != c.stdout {
+			t.Fatalf("bad output for %v:\n%s", c.args, res.Stdout)
+		}
+	}
+}
+
+func TestRunRules(t *testing.T) {
+	res := cmdtest.Run(t, run, "", "-rules")
+	if res.Code != 0 {
+		t.Fatalf("bad exit code %d", res.Code)
+	}
+	if lines := strings.Split(strings.TrimSpace(res.Stdout), "\n"); len(lines) != len(lint.Rules()) {
+		t.Fatalf("one line per rule expected but found %d", len(lines))
+	}
+	if !strings.Contains(res.Stdout, "tx-origin ") {
+		t.Fatalf("rule tx-origin not found in:\n%s", res.Stdout)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	res := cmdtest.Run(t, run, "", "-config", "testdata/config.yaml", "-format", "json", "testdata/legacy.sol")
+	if res.Code != 0 {
+		t.Fatalf("bad exit code %d (%s)", res.Code, res.Stderr)
+	}
+	var diags []*lint.Diagnostic
+	if err := json.Unmarshal([]byte(res.Stdout), &diags); err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 1 || diags[0].Rule != "function-visibility" || len(diags[0].Fixes) != 1 {
+		t.Fatalf("bad diagnostics:\n%s", res.Stdout)
+	}
+}
+
+func TestRunFix(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/legacy.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "legacy.sol")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res := cmdtest.Run(t, run, "", "-config", "testdata/config.yaml", "-fix", path)
+	if res.Code != 0 || res.Stdout != "" {
+		t.Fatalf("bad result %d:\n%s%s", res.Code, res.Stdout, res.Stderr)
+	}
+	fixed, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(fixed), "function f() public {") {
+		t.Fatalf("the fix was not written:\n%s", fixed)
+	}
+
+	// the source of stdin is fixed but not written
+	res = cmdtest.Run(t, run, "contract A { function f() {} }", "-config", "testdata/config.yaml", "-fix", "-")
+	if res.Code != 0 || res.Stdout != "" {
+		t.Fatalf("bad result %d:\n%s%s", res.Code, res.Stdout, res.Stderr)
+	}
+}
@@ -0,0 +1,10 @@
+pragma solidity ^0.8.0;
+
+contract Wallet {
+    address owner;
+
+    function withdraw() public {
+        require(tx.origin == owner);
+        payable(msg.sender).transfer(address(this).balance);
+    }
+}
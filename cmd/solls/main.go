@@ -0,0 +1,45 @@
+// Command solls is a language server for Solidity. It speaks the Language
+// Server Protocol over stdin and stdout.
+//
+//	solls [-stdio]
+//
+// The lint diagnostics use the .sollint.yaml, .sollint.yml or .sollint.json
+// file of the root of the workspace if there is one, or enable all the rules
+// otherwise.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/umbracle/solidity-parser-go/lsp"
+
+	// the built-in rules
+	_ "github.com/umbracle/solidity-parser-go/lint/legacy"
+	_ "github.com/umbracle/solidity-parser-go/lint/security"
+	_ "github.com/umbracle/solidity-parser-go/lint/style"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("solls", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: solls [flags]\n\n")
+		flags.PrintDefaults()
+	}
+	// the editors pass -stdio to the servers, it is the only transport
+	flags.Bool("stdio", true, "communicate over stdin and stdout")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := lsp.NewServer(os.Stdin, os.Stdout).Run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/cmd/internal/cmdtest"
+)
+
+// frame returns the messages with their Content-Length headers
+func frame(msgs ...string) string {
+	var buf strings.Builder
+	for _, msg := range msgs {
+		fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+	return buf.String()
+}
+
+const (
+	initialize = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{}}}`
+	shutdown   = `{"jsonrpc":"2.0","id":2,"method":"shutdown"}`
+	exit       = `{"jsonrpc":"2.0","method":"exit"}`
+)
+
+func TestRun(t *testing.T) {
+	didOpen := `{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///a.sol","languageId":"solidity","version":1,"text":"contract A {"}}}`
+
+	res := cmdtest.Run(t, run, frame(initialize, `{"jsonrpc":"2.0","method":"initialized","params":{}}`, didOpen, shutdown, exit), "-stdio")
+	if res.Code != 0 {
+		t.Fatalf("bad exit code %d (%s)", res.Code, res.Stderr)
+	}
+	for _, str := range []string{
+		`{"id":1,"jsonrpc":"2.0","result":{"capabilities":`,
+		`"method":"textDocument/publishDiagnostics","params":{"uri":"file:///a.sol"`,
+		`{"id":2,"jsonrpc":"2.0","result":null}`,
+	} {
+		if !strings.Contains(res.Stdout, str) {
+			t.Fatalf("'%s' not found in:\n%s", str, res.Stdout)
+		}
+	}
+
+	// the end of the input stops the server
+	if res := cmdtest.Run(t, run, frame(initialize)); res.Code != 0 {
+		t.Fatalf("bad exit code %d (%s)", res.Code, res.Stderr)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	// exit before shutdown
+	res := cmdtest.Run(t, run, frame(initialize, exit))
+	if res.Code != 1 || res.Stderr != "exit before shutdown\n" {
+		t.Fatalf("bad result %d: %s", res.Code, res.Stderr)
+	}
+
+	res = cmdtest.Run(t, run, "", "-unknown")
+	if res.Code != 2 || !strings.Contains(res.Stderr, "Usage: solls") {
+		t.Fatalf("bad result %d: %s", res.Code, res.Stderr)
+	}
+}
@@ -0,0 +1,98 @@
+// Command solmigrate rewrites the Solidity files to the syntax of a newer
+// compiler version.
+//
+//	solmigrate [-target 0.8.0] [-n] [-format text|json|sarif] <file or directory>...
+//
+// It applies the fixes of the legacy lint rules and reports the legacy
+// constructs that have to be migrated by hand. With -n the files are not
+// written, it only prints the files that would change. The path '-' reads the
+// source from stdin, it is only checked.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/umbracle/solidity-parser-go/cmd/internal/source"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/lint/legacy"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("solmigrate", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: solmigrate [flags] <file or directory>...\n\n")
+		flags.PrintDefaults()
+	}
+
+	var target, format string
+	var dryRun bool
+	flags.StringVar(&target, "target", "0.8.0", "compiler version to migrate to")
+	flags.StringVar(&format, "format", "text", "output format of the remaining diagnostics: text, json or sarif")
+	flags.BoolVar(&dryRun, "n", false, "print the files that would change without writing them")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	config, err := legacy.Config(target)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	reporter, err := lint.NewReporter(format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	sources, err := source.LoadMap(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	fixed, diags, err := lint.New(config).Fix(sources)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	files := []string{}
+	for file, src := range fixed {
+		// the source read from stdin has no file to write
+		if src != sources[file] && file != source.StdinName {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if !dryRun {
+			if err := ioutil.WriteFile(filepath.FromSlash(file), []byte(fixed[file]), 0644); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+		}
+		fmt.Fprintf(os.Stderr, "migrated %s\n", file)
+	}
+
+	if err := reporter.Report(os.Stdout, diags); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if len(diags) != 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/cmd/internal/cmdtest"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			[]string{"-n", "testdata/fixable.sol"},
+			"",
+			0,
+			"",
+			"migrated testdata/fixable.sol\n",
+		},
+		{
+			// the constructs that have to be migrated by hand
+			[]string{"-n", "testdata"},
+			"",
+			1,
+			"testdata/manual.sol:5:17: warning: the low-level calls take a single bytes argument, encode the arguments with abi.encodeWithSignature [low-level-call]\n",
+			"migrated testdata/fixable.sol\nmigrated testdata/manual.sol\n",
+		},
+		{
+			// the source of stdin is only checked
+			[]string{"-"},
+			"pragma solidity ^0.4.24;\ncontract A { function f() { throw; } }",
+			0,
+			"",
+			"",
+		},
+		{
+			// the usage errors
+			[]string{"-target", "x", "testdata/fixable.sol"},
+			"",
+			2,
+			"",
+			"invalid compiler version 'x'\n",
+		},
+		{
+			[]string{"-format", "xml", "testdata/fixable.sol"},
+			"",
+			2,
+			"",
+			"unknown format 'xml'\n",
+		},
+		{
+			[]string{"testdata/missing.sol"},
+			"",
+			2,
+			"",
+			"stat testdata/missing.sol: no such file or directory\n",
+		},
+	}
+
+	for _, c := range cases {
+		res := cmdtest.Run(t, run, c.stdin, c.args...)
+		if res.Code != c.code {
+			t.Fatalf("bad exit code for %v: expected %d but found %d (%s)", c.args, c.code, res.Code, res.Stderr)
+		}
+		if res.Stdout != c.stdout {
+			t.Fatalf("bad output for %v:\n%s", c.args, res.Stdout)
+		}
+		if res.Stderr != c.stderr {
+			t.Fatalf("bad errors for %v:\n%s", c.args, res.Stderr)
+		}
+	}
+
+	// the usage is printed without paths
+	if res := cmdtest.Run(t, run, ""); res.Code != 2 || !strings.HasPrefix(res.Stderr, "Usage: solmigrate") {
+		t.Fatalf("bad result %d:\n%s", res.Code, res.Stderr)
+	}
+}
+
+func TestRunWrite(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/fixable.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "fixable.sol")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res := cmdtest.Run(t, run, "", path)
+	if res.Code != 0 || res.Stdout != "" {
+		t.Fatalf("bad result %d:\n%s%s", res.Code, res.Stdout, res.Stderr)
+	}
+	migrated, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "pragma solidity ^0.8.0;\n\ncontract Fixable {\n    function f(uint x) public {\n        if (x == 0) revert();\n    }\n}\n"
+	if string(migrated) != expected {
+		t.Fatalf("bad migrated file:\n%s", migrated)
+	}
+
+	// the migrated file has nothing left to migrate
+	if res := cmdtest.Run(t, run, "", path); res.Code != 0 || res.Stderr != "" {
+		t.Fatalf("bad result %d:\n%s%s", res.Code, res.Stdout, res.Stderr)
+	}
+}
@@ -0,0 +1,7 @@
+pragma solidity ^0.4.24;
+
+contract Fixable {
+    function f(uint x) {
+        if (x == 0) throw;
+    }
+}
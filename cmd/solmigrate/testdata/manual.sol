@@ -0,0 +1,7 @@
+pragma solidity ^0.4.24;
+
+contract Manual {
+    function f(address to) public {
+        require(to.call(bytes4(keccak256("f(uint256)")), 1));
+    }
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/cmd/internal/source"
+	"github.com/umbracle/solidity-parser-go/constant"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// parse parses a source and prints its syntax errors
+func parse(src *source.File, locations bool) (*solcparser.SourceUnit, bool) {
+	var opts []solcparser.Option
+	if locations {
+		opts = append(opts, solcparser.WithLocations())
+	}
+	res := solcparser.Parse(src.Text, opts...)
+	printErrors(src, res.Errors)
+	unit, ok := res.Result.(*solcparser.SourceUnit)
+	return unit, ok && len(res.Errors) == 0
+}
+
+func printErrors(src *source.File, errs []*solcparser.SyntaxError) {
+	for _, err := range errs {
+		if loc := err.Loc(); loc != nil {
+			fmt.Fprintf(os.Stderr, "%s:%d:%d: %s\n", src.Name, loc.Start.Line, loc.Start.Column+1, err)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", src.Name, err)
+		}
+	}
+}
+
+// parseAll parses the sources with locations, it fails if any of them has
+// syntax errors
+func parseAll(sources []*source.File) ([]*solcparser.SourceUnit, bool) {
+	units := []*solcparser.SourceUnit{}
+	valid := true
+	for _, src := range sources {
+		unit, ok := parse(src, true)
+		units = append(units, unit)
+		valid = valid && ok
+	}
+	return units, valid
+}
+
+func runParse(flags *flag.FlagSet, args []string) int {
+	locations := flags.Bool("loc", false, "include the locations of the nodes")
+	indent := flags.Bool("indent", false, "indent the JSON output")
+	sources, code := parseArgs(flags, args)
+	if code != 0 {
+		return code
+	}
+
+	asts := map[string]*solcparser.SourceUnit{}
+	for _, src := range sources {
+		unit, ok := parse(src, *locations)
+		if !ok {
+			return 1
+		}
+		asts[src.Name] = unit
+	}
+
+	var res interface{} = asts
+	if len(sources) == 1 {
+		res = asts[sources[0].Name]
+	}
+	var data []byte
+	var err error
+	if *indent {
+		data, err = json.MarshalIndent(res, "", "  ")
+	} else {
+		data, err = json.Marshal(res)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	fmt.Println(string(data))
+	return 0
+}
+
+func runTokens(flags *flag.FlagSet, args []string) int {
+	whitespace := flags.Bool("ws", false, "include the whitespace")
+	sources, code := parseArgs(flags, args)
+	if code != 0 {
+		return code
+	}
+
+	for _, src := range sources {
+		it := solcparser.NewIterator(src.Text)
+		for {
+			t, ok := it.Next()
+			if !ok {
+				break
+			}
+			if t.Kind == solcparser.TokenWhitespace && !*whitespace {
+				continue
+			}
+			fmt.Printf("%s:%d:%d\t%s\t%q\n", src.Name, t.Loc.Start.Line, t.Loc.Start.Column+1, t.Kind, t.Text)
+		}
+	}
+	return 0
+}
+
+func runCheck(flags *flag.FlagSet, args []string) int {
+	treeSitter := flags.Bool("tree-sitter", false, "check the syntax with tree-sitter, it is faster")
+	sources, code := parseArgs(flags, args)
+	if code != 0 {
+		return code
+	}
+	if *treeSitter {
+		valid := true
+		for _, src := range sources {
+			root, err := solcparser.NewTreeSitterCtx(context.Background(), src.Text)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", src.Name, err)
+				return 1
+			}
+			errs := solcparser.TreeSitterErrors(root, src.Text)
+			printErrors(src, errs)
+			valid = valid && len(errs) == 0
+		}
+		if !valid {
+			return 1
+		}
+		return 0
+	}
+	if _, ok := parseAll(sources); !ok {
+		return 1
+	}
+	return 0
+}
+
+func runOutline(flags *flag.FlagSet, args []string) int {
+	sources, code := parseArgs(flags, args)
+	if code != 0 {
+		return code
+	}
+	units, ok := parseAll(sources)
+	if !ok {
+		return 1
+	}
+
+	for i, src := range sources {
+		var outline func(node interface{}, depth int)
+		outline = func(node interface{}, depth int) {
+			if obj, ok := node.(*solcparser.StateVariableDeclaration); ok {
+				for _, v := range obj.Variables {
+					outline(v, depth)
+				}
+				return
+			}
+			n, ok := node.(solcparser.INode)
+			if !ok || n.GetLoc() == nil {
+				return
+			}
+			switch node.(type) {
+			case *solcparser.PragmaDirective, *solcparser.ImportDirective, *solcparser.UsingForDeclaration:
+				return
+			}
+			fmt.Printf("%s:%d: %s%s\n", src.Name, n.GetLoc().Start.Line, strings.Repeat("  ", depth), signature(src.Text, node))
+			if obj, ok := node.(*solcparser.ContractDefinition); ok {
+				for _, sub := range obj.SubNodes {
+					outline(sub, depth+1)
+				}
+			}
+		}
+		for _, child := range units[i].Children {
+			outline(child, 0)
+		}
+	}
+	return 0
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// signature returns the text of a declaration up to its body with the
+// whitespace collapsed (i.e. "function transfer(address to) public")
+func signature(text string, node interface{}) string {
+	loc := node.(solcparser.INode).GetLoc()
+	start, end := loc.Start.Offset, loc.End.Offset
+
+	// the body of the functions and the initial value of the variables
+	var rest interface{}
+	switch obj := node.(type) {
+	case *solcparser.FunctionDefinition:
+		rest = obj.Body
+	case *solcparser.ModifierDefinition:
+		rest = obj.Body
+	case *solcparser.StateVariableDeclarationVariable:
+		rest = obj.Expression
+	case *solcparser.FileLevelConstant:
+		rest = obj.InitialValue
+	case *solcparser.ContractDefinition, *solcparser.StructDefinition, *solcparser.EnumDefinition:
+		if i := strings.Index(text[start:end], "{"); i != -1 {
+			end = start + i
+		}
+	}
+	if r, ok := rest.(solcparser.INode); ok && r.GetLoc() != nil && r.GetLoc().Start.Offset > start {
+		end = r.GetLoc().Start.Offset
+	}
+
+	str := strings.TrimSpace(spaces.ReplaceAllString(text[start:end], " "))
+	str = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(str, ";"), "="))
+	str = strings.ReplaceAll(str, "( ", "(")
+	return strings.TrimSpace(strings.ReplaceAll(str, " )", ")"))
+}
+
+func runSelectors(flags *flag.FlagSet, args []string) int {
+	sources, code := parseArgs(flags, args)
+	if code != 0 {
+		return code
+	}
+	units, ok := parseAll(sources)
+	if !ok {
+		return 1
+	}
+
+	p := solcparser.NewProject()
+	for i, src := range sources {
+		p.Add(src.Name, units[i])
+	}
+	info := types.Check(p)
+
+	for _, contract := range p.Contracts() {
+		// the members of the bases that are not overridden
+		bases, err := p.Linearize(contract)
+		if err != nil {
+			bases = []*solcparser.ContractDefinition{contract}
+		}
+		seen := map[string]bool{}
+		for _, base := range bases {
+			for _, node := range base.SubNodes {
+				members := []interface{}{node}
+				if obj, ok := node.(*solcparser.StateVariableDeclaration); ok {
+					members = obj.Variables
+				}
+				for _, member := range members {
+					kind, ok := selectorKind(member)
+					if !ok {
+						continue
+					}
+					sig, ok := info.Signature(member)
+					if !ok || seen[kind+sig] {
+						continue
+					}
+					seen[kind+sig] = true
+
+					selector := types.Selector(sig)
+					if kind == "event" {
+						selector = topic(sig)
+					}
+					fmt.Printf("%s\t%s\t0x%s\t%s\n", contract.Name, kind, hex.EncodeToString(selector), sig)
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// selectorKind returns the kind of the members of a contract with a selector:
+// the public functions, the getters, the events and the errors
+func selectorKind(node interface{}) (string, bool) {
+	switch obj := node.(type) {
+	case *solcparser.FunctionDefinition:
+		switch obj.Visibility {
+		case "public", "external", "default":
+			return "function", true
+		}
+	case *solcparser.StateVariableDeclarationVariable:
+		if obj.Visibility == "public" {
+			return "function", true
+		}
+	case *solcparser.EventDefinition:
+		return "event", !obj.IsAnonymous
+	case *solcparser.CustomErrorDefinition:
+		return "error", true
+	}
+	return "", false
+}
+
+// topic returns the topic of an event, the hash of its signature
+func topic(signature string) []byte {
+	return constant.Keccak256([]byte(signature))
+}
+
+func runImports(flags *flag.FlagSet, args []string) int {
+	sources, code := parseArgs(flags, args)
+	if code != 0 {
+		return code
+	}
+	units, ok := parseAll(sources)
+	if !ok {
+		return 1
+	}
+	for i, src := range sources {
+		for _, child := range units[i].Children {
+			if imp, ok := child.(*solcparser.ImportDirective); ok {
+				fmt.Printf("%s\t%s\n", src.Name, imp.Path)
+			}
+		}
+	}
+	return 0
+}
+
+func runTreeSitter(flags *flag.FlagSet, args []string) int {
+	sources, code := parseArgs(flags, args)
+	if code != 0 {
+		return code
+	}
+	for _, src := range sources {
+		if len(sources) > 1 {
+			fmt.Printf("; %s\n", src.Name)
+		}
+		root, err := solcparser.NewTreeSitterCtx(context.Background(), src.Text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", src.Name, err)
+			return 1
+		}
+		fmt.Println(root.String())
+	}
+	return 0
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/cmd/internal/cmdtest"
+)
+
+func TestCommands(t *testing.T) {
+	cases := []struct {
+		args   []string
+		code   int
+		stdout string
+	}{
+		{
+			[]string{"tokens", "testdata/ownable.sol"},
+			0,
+			"testdata/ownable.sol:1:1\tkeyword\t\"contract\"\n" +
+				"testdata/ownable.sol:1:10\tidentifier\t\"Ownable\"\n" +
+				"testdata/ownable.sol:1:18\tpunctuation\t\"{\"\n" +
+				"testdata/ownable.sol:2:5\telementary-type\t\"address\"\n" +
+				"testdata/ownable.sol:2:13\tkeyword\t\"public\"\n" +
+				"testdata/ownable.sol:2:20\tidentifier\t\"owner\"\n" +
+				"testdata/ownable.sol:2:25\tpunctuation\t\";\"\n" +
+				"testdata/ownable.sol:3:1\tpunctuation\t\"}\"\n",
+		},
+		{
+			[]string{"check", "testdata/token.sol", "testdata/ownable.sol"},
+			0,
+			"",
+		},
+		{
+			[]string{"check", "testdata/invalid.sol"},
+			1,
+			"",
+		},
+		{
+			[]string{"check", "-tree-sitter", "testdata/invalid.sol"},
+			1,
+			"",
+		},
+		{
+			[]string{"outline", "testdata/token.sol"},
+			0,
+			"testdata/token.sol:5: contract Token is Ownable\n" +
+				"testdata/token.sol:6:   uint256 public totalSupply\n" +
+				"testdata/token.sol:8:   event Transfer(address indexed from, address indexed to, uint256 value)\n" +
+				"testdata/token.sol:10:   error Unauthorized(address caller)\n" +
+				"testdata/token.sol:12:   function transfer(address to, uint256 value) external returns (bool)\n",
+		},
+		{
+			[]string{"outline", "testdata/invalid.sol"},
+			1,
+			"",
+		},
+		{
+			[]string{"selectors", "testdata/token.sol", "testdata/ownable.sol"},
+			0,
+			"Token\tfunction\t0x18160ddd\ttotalSupply()\n" +
+				"Token\tevent\t0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef\tTransfer(address,address,uint256)\n" +
+				"Token\terror\t0x8e4a23d6\tUnauthorized(address)\n" +
+				"Token\tfunction\t0xa9059cbb\ttransfer(address,uint256)\n" +
+				"Token\tfunction\t0x8da5cb5b\towner()\n" +
+				"Ownable\tfunction\t0x8da5cb5b\towner()\n",
+		},
+		{
+			[]string{"selectors", "testdata/invalid.sol"},
+			1,
+			"",
+		},
+		{
+			[]string{"imports", "testdata/token.sol", "testdata/ownable.sol"},
+			0,
+			"testdata/token.sol\t./ownable.sol\n",
+		},
+		{
+			[]string{"imports", "testdata/invalid.sol"},
+			1,
+			"",
+		},
+		{
+			[]string{"parse", "testdata/invalid.sol"},
+			1,
+			"",
+		},
+		{
+			// the usage errors
+			[]string{"check", "testdata/missing.sol"},
+			2,
+			"",
+		},
+		{
+			[]string{"tokens", "-unknown", "testdata/ownable.sol"},
+			2,
+			"",
+		},
+		{
+			[]string{"unknown"},
+			2,
+			"",
+		},
+		{
+			[]string{},
+			2,
+			"",
+		},
+	}
+
+	for _, c := range cases {
+		res := cmdtest.Run(t, run, "", c.args...)
+		if res.Code != c.code {
+			t.Fatalf("bad exit code for %v: expected %d but found %d (%s)", c.args, c.code, res.Code, res.Stderr)
+		}
+		if res.Stdout != c.stdout {
+			t.Fatalf("bad output for %v:\n%s", c.args, res.Stdout)
+		}
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	res := cmdtest.Run(t, run, "", "check", "testdata/invalid.sol")
+	if !strings.HasPrefix(res.Stderr, "testdata/invalid.sol:2:14: mismatched input ';'") {
+		t.Fatalf("bad errors '%s'", res.Stderr)
+	}
+	res = cmdtest.Run(t, run, "", "check", "-tree-sitter", "testdata/invalid.sol")
+	if res.Stderr != "testdata/invalid.sol:2:12: expected an expression after '='\n" {
+		t.Fatalf("bad errors '%s'", res.Stderr)
+	}
+}
+
+func TestCommandParse(t *testing.T) {
+	cases := []struct {
+		args  []string
+		stdin string
+		files []string
+	}{
+		{[]string{"parse", "testdata/ownable.sol"}, "", nil},
+		{[]string{"parse"}, "contract Ownable { address public owner; }", nil},
+		{[]string{"parse", "testdata/ownable.sol", "testdata/token.sol"}, "", []string{"testdata/ownable.sol", "testdata/token.sol"}},
+	}
+
+	for _, c := range cases {
+		res := cmdtest.Run(t, run, c.stdin, c.args...)
+		if res.Code != 0 {
+			t.Fatalf("bad exit code for %v: %d (%s)", c.args, res.Code, res.Stderr)
+		}
+
+		// a single file prints its AST, the files an object with each AST
+		asts := map[string]map[string]interface{}{}
+		if c.files == nil {
+			var ast map[string]interface{}
+			if err := json.Unmarshal([]byte(res.Stdout), &ast); err != nil {
+				t.Fatal(err)
+			}
+			asts["ownable"] = ast
+		} else if err := json.Unmarshal([]byte(res.Stdout), &asts); err != nil {
+			t.Fatal(err)
+		}
+		for name, ast := range asts {
+			if ast["type"] != "SourceUnit" {
+				t.Fatalf("bad AST of %s for %v: %s", name, c.args, res.Stdout)
+			}
+		}
+		for _, file := range c.files {
+			if _, ok := asts[file]; !ok {
+				t.Fatalf("AST of %s not found", file)
+			}
+		}
+		if !strings.Contains(res.Stdout, `"name":"Ownable"`) {
+			t.Fatalf("contract Ownable not found in %s", res.Stdout)
+		}
+	}
+
+	// the locations of the nodes
+	res := cmdtest.Run(t, run, "", "parse", "-loc", "testdata/ownable.sol")
+	if res.Code != 0 || !strings.Contains(res.Stdout, `"loc":`) {
+		t.Fatalf("locations not found in %s", res.Stdout)
+	}
+}
@@ -0,0 +1,96 @@
+// Command solparse exposes the parser to the shell.
+//
+//	solparse <command> [flags] [file or directory]...
+//
+// The commands are:
+//
+//	parse        print the AST as JSON (-loc adds the locations)
+//	tokens       print the kind and the text of the tokens, comments included (-ws
+//	             adds the whitespace)
+//	check        print the syntax errors, it fails if there is any (-tree-sitter
+//	             checks with tree-sitter)
+//	outline      print the contracts and their members with their signatures
+//	selectors    print the function and error selectors and the event topics
+//	imports      print the imports of the files
+//	tree-sitter  print the tree-sitter syntax tree as an S-expression
+//
+// The directories are walked for .sol files and the source is read from stdin
+// if there are no paths or the path is '-'. The parse command prints the AST of
+// a single file or an object with the AST of each file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/umbracle/solidity-parser-go/cmd/internal/source"
+)
+
+// command is a subcommand of solparse
+type command struct {
+	name  string
+	usage string
+	run   func(flags *flag.FlagSet, args []string) int
+}
+
+var commands = []*command{
+	{"parse", "print the AST as JSON", runParse},
+	{"tokens", "print the tokens of the lexer", runTokens},
+	{"check", "print the syntax errors", runCheck},
+	{"outline", "print the contracts and their members", runOutline},
+	{"selectors", "print the selectors and the event topics", runSelectors},
+	{"imports", "print the imports", runImports},
+	{"tree-sitter", "print the tree-sitter syntax tree", runTreeSitter},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: solparse <command> [flags] [file or directory]...\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		flags := flag.NewFlagSet("solparse "+cmd.name, flag.ContinueOnError)
+		flags.Usage = func() {
+			fmt.Fprintf(os.Stderr, "Usage: solparse %s [flags] [file or directory]...\n\n", cmd.name)
+			flags.PrintDefaults()
+		}
+		return cmd.run(flags, args[1:])
+	}
+	if args[0] != "help" && args[0] != "-h" && args[0] != "-help" {
+		fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", args[0])
+	}
+	usage()
+	return 2
+}
+
+// parseArgs parses the flags of a command and loads its sources
+func parseArgs(flags *flag.FlagSet, args []string) ([]*source.File, int) {
+	if err := flags.Parse(args); err != nil {
+		return nil, 2
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	sources, err := source.Load(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, 2
+	}
+	return sources, 0
+}
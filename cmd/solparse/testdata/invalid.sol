@@ -0,0 +1,3 @@
+contract A {
+    uint a = ;
+}
@@ -0,0 +1,3 @@
+contract Ownable {
+    address public owner;
+}
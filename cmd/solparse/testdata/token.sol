@@ -0,0 +1,16 @@
+pragma solidity ^0.8.0;
+
+import "./ownable.sol";
+
+contract Token is Ownable {
+    uint256 public totalSupply;
+
+    event Transfer(address indexed from, address indexed to, uint256 value);
+
+    error Unauthorized(address caller);
+
+    function transfer(address to, uint256 value) external returns (bool) {
+        emit Transfer(msg.sender, to, value);
+        return true;
+    }
+}
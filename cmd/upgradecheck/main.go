@@ -0,0 +1,120 @@
+// Command upgradecheck compares two versions of an upgradeable contract and
+// reports the changes that are not safe to deploy behind a proxy.
+//
+//	upgradecheck -contract Token ./v1 ./v2
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/upgradecheck"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("upgradecheck", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: upgradecheck -contract <name> [-json] <old> <new>\n\n")
+		flags.PrintDefaults()
+	}
+
+	var contract string
+	var jsonOutput bool
+	flags.StringVar(&contract, "contract", "", "name of the upgradeable contract")
+	flags.BoolVar(&jsonOutput, "json", false, "print the report as json")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if contract == "" || flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	oldProject, err := loadProject(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	newProject, err := loadProject(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	report, err := upgradecheck.Check(oldProject, newProject, contract)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, p := range report.Problems {
+			fmt.Printf("%s [%s]\n", p, p.Kind)
+		}
+	}
+
+	if !report.Ok() {
+		return 1
+	}
+	return 0
+}
+
+// loadProject parses a Solidity file or all the Solidity files in a directory
+func loadProject(path string) (*solcparser.Project, error) {
+	project := solcparser.NewProject()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := project.AddSource(path, string(data)); err != nil {
+			return nil, err
+		}
+		return project, nil
+	}
+
+	err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(file, ".sol") {
+			return nil
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		return project.AddSource(name, string(data))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/cmd/internal/cmdtest"
+	"github.com/umbracle/solidity-parser-go/upgradecheck"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		args   []string
+		code   int
+		stdout string
+	}{
+		{
+			// a new variable at the end and a constructor allowed by its annotation
+			[]string{"-contract", "Token", "testdata/v1", "testdata/v2"},
+			0,
+			"",
+		},
+		{
+			[]string{"-contract", "Token", "testdata/v1", "testdata/v3"},
+			1,
+			"token.sol:5:5: variable Token.totalSupply moved from slot 50 (offset 0) to slot 51 (offset 0) [moved]\n" +
+				"token.sol:4:5: variable Token.paused was inserted at slot 50 in the middle of the existing layout [inserted]\n",
+		},
+		{
+			// the usage errors
+			[]string{"-contract", "Missing", "testdata/v1", "testdata/v3"},
+			2,
+			"",
+		},
+		{
+			// the base contract is in another file
+			[]string{"-contract", "Token", "testdata/v1/token.sol", "testdata/v3/token.sol"},
+			2,
+			"",
+		},
+		{
+			[]string{"-contract", "Token", "testdata/v1", "testdata/missing"},
+			2,
+			"",
+		},
+		{
+			[]string{"testdata/v1", "testdata/v3"},
+			2,
+			"",
+		},
+		{
+			[]string{"-contract", "Token", "testdata/v1"},
+			2,
+			"",
+		},
+	}
+
+	for _, c := range cases {
+		res := cmdtest.Run(t, run, "", c.args...)
+		if res.Code != c.code {
+			t.Fatalf("bad exit code for %v: expected %d but found %d (%s)", c.args, c.code, res.Code, res.Stderr)
+		}
+		if res.Stdout != c.stdout {
+			t.Fatalf("bad output for %v:\n%s", c.args, res.Stdout)
+		}
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	res := cmdtest.Run(t, run, "", "-contract", "Token", "-json", "testdata/v1", "testdata/v3")
+	if res.Code != 1 {
+		t.Fatalf("bad exit code %d (%s)", res.Code, res.Stderr)
+	}
+
+	var report upgradecheck.Report
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Problems) != 2 {
+		t.Fatalf("two problems expected but found %d", len(report.Problems))
+	}
+	p := report.Problems[1]
+	if p.Kind != upgradecheck.KindInserted || p.File != "token.sol" || p.Loc.Start.Line != 4 {
+		t.Fatalf("bad problem %v", p)
+	}
+}
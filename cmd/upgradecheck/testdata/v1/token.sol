@@ -0,0 +1,5 @@
+import "./base.sol";
+
+contract Token is Base {
+    uint256 totalSupply;
+}
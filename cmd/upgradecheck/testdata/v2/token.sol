@@ -0,0 +1,11 @@
+import "./base.sol";
+
+contract Token is Base {
+    uint256 totalSupply;
+    mapping(address => uint256) balances;
+
+    /// @custom:oz-upgrades-unsafe-allow constructor
+    constructor() {
+        _disableInitializers();
+    }
+}
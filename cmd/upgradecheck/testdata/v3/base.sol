@@ -0,0 +1,4 @@
+contract Base {
+    address owner;
+    uint256[49] __gap;
+}
@@ -0,0 +1,6 @@
+import "./base.sol";
+
+contract Token is Base {
+    bool paused;
+    uint256 totalSupply;
+}
@@ -0,0 +1,387 @@
+// Package constant evaluates compile time constant expressions.
+//
+// Number literals are folded into exact rationals, like the Solidity compiler
+// does, and only truncated when they are converted to a sized type. String,
+// hex and hash values are folded into bytes.
+package constant
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// Kinds of constant values
+const (
+	KindRational = "rational"
+	KindBool     = "bool"
+	KindBytes    = "bytes"
+)
+
+// maxBits is the largest number of bits of a rational constant
+const maxBits = 4096
+
+// Value is the value of a constant expression
+type Value struct {
+	Kind string
+
+	// Rat is the value of the numbers
+	Rat *big.Rat
+
+	// Bytes is the value of the string, hex and fixed bytes values
+	Bytes []byte
+
+	Bool bool
+
+	// Type is the elementary type of the value (i.e. uint8 or bytes32).
+	// It is empty for the literals and the expressions built only from them.
+	Type string
+}
+
+func (v *Value) String() string {
+	switch v.Kind {
+	case KindRational:
+		if v.Rat.IsInt() {
+			return v.Rat.Num().String()
+		}
+		return v.Rat.String()
+	case KindBool:
+		if v.Bool {
+			return "true"
+		}
+		return "false"
+	}
+	if _, ok := bytesSize(v.Type); ok {
+		return "0x" + hex.EncodeToString(v.Bytes)
+	}
+	return fmt.Sprintf("%q", string(v.Bytes))
+}
+
+// Int returns the value as an integer if it is one
+func (v *Value) Int() (*big.Int, bool) {
+	if v.Kind != KindRational || !v.Rat.IsInt() {
+		return nil, false
+	}
+	return new(big.Int).Set(v.Rat.Num()), true
+}
+
+// Option is an option for the evaluation
+type Option func(*config)
+
+type config struct {
+	project  *solcparser.Project
+	contract *solcparser.ContractDefinition
+}
+
+// WithProject resolves the constant variables declared in the project
+func WithProject(p *solcparser.Project) Option {
+	return func(c *config) {
+		c.project = p
+	}
+}
+
+// WithContract resolves the constant variables visible from the contract
+func WithContract(contract *solcparser.ContractDefinition) Option {
+	return func(c *config) {
+		c.contract = contract
+	}
+}
+
+// Eval evaluates a constant expression
+func Eval(expr interface{}, opts ...Option) (*Value, error) {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	e := &evaluator{
+		config:   cfg,
+		visiting: map[interface{}]bool{},
+	}
+	return e.eval(expr, cfg.contract)
+}
+
+type evaluator struct {
+	*config
+
+	// visiting are the constants being evaluated to detect cycles
+	visiting map[interface{}]bool
+}
+
+func rational(x *big.Rat, typ string) *Value {
+	return &Value{Kind: KindRational, Rat: x, Type: typ}
+}
+
+func integer(x *big.Int, typ string) *Value {
+	return rational(new(big.Rat).SetInt(x), typ)
+}
+
+func (e *evaluator) eval(expr interface{}, scope *solcparser.ContractDefinition) (*Value, error) {
+	switch obj := expr.(type) {
+	case *solcparser.NumberLiteral:
+		if obj.Value != nil {
+			return rational(new(big.Rat).Set(obj.Value), ""), nil
+		}
+		// the literals without a value are invalid or not built by the parser
+		x, err := solcparser.ParseNumber(obj.Number, obj.SubDenomination)
+		if err != nil {
+			return nil, err
+		}
+		return rational(x, ""), nil
+
+	case *solcparser.BooleanLiteral:
+		return &Value{Kind: KindBool, Bool: obj.Value}, nil
+
+	case *solcparser.StringLiteral:
+		return &Value{Kind: KindBytes, Bytes: []byte(obj.Value)}, nil
+
+	case *solcparser.HexLiteral:
+		if obj.Data == nil {
+			return nil, fmt.Errorf("invalid hex literal")
+		}
+		return &Value{Kind: KindBytes, Bytes: obj.Data}, nil
+
+	case *solcparser.TupleExpression:
+		if obj.IsArray || len(obj.Components) != 1 {
+			return nil, fmt.Errorf("tuples are not constant")
+		}
+		return e.eval(obj.Components[0], scope)
+
+	case *solcparser.Identifier:
+		return e.constant(obj.Name, scope)
+
+	case *solcparser.MemberAccess:
+		return e.member(obj, scope)
+
+	case *solcparser.UnaryOperation:
+		x, err := e.eval(obj.SubExpression, scope)
+		if err != nil {
+			return nil, err
+		}
+		return unaryOp(obj.Operator, x)
+
+	case *solcparser.BinaryOperation:
+		x, err := e.eval(obj.Left, scope)
+		if err != nil {
+			return nil, err
+		}
+		y, err := e.eval(obj.Right, scope)
+		if err != nil {
+			return nil, err
+		}
+		return binaryOp(obj.Operator, x, y)
+
+	case *solcparser.Conditional:
+		cond, err := e.eval(obj.Condition, scope)
+		if err != nil {
+			return nil, err
+		}
+		if cond.Kind != KindBool {
+			return nil, fmt.Errorf("condition is not a bool")
+		}
+		if cond.Bool {
+			return e.eval(obj.TrueExpression, scope)
+		}
+		return e.eval(obj.FalseExpression, scope)
+
+	case *solcparser.FunctionCall:
+		return e.call(obj, scope)
+	}
+	return nil, fmt.Errorf("expression %T is not constant", expr)
+}
+
+// constant evaluates the constant variable with the given name
+func (e *evaluator) constant(name string, scope *solcparser.ContractDefinition) (*Value, error) {
+	if e.project == nil {
+		return nil, fmt.Errorf("constant %s not found", name)
+	}
+
+	var decl interface{}
+	var typeName, value interface{}
+	var declScope *solcparser.ContractDefinition
+
+	if scope != nil {
+		lin, err := e.project.Linearize(scope)
+		if err != nil {
+			lin = []*solcparser.ContractDefinition{scope}
+		}
+		for _, c := range lin {
+			if vv, ok := findConstant(c, name); ok {
+				decl, typeName, value, declScope = vv, vv.TypeName, vv.Expression, c
+				break
+			}
+		}
+	}
+	if decl == nil {
+		for _, file := range e.project.Files() {
+			for _, child := range e.project.Units[file].Children {
+				if c, ok := child.(*solcparser.FileLevelConstant); ok && c.Name == name {
+					decl, typeName, value = c, c.TypeName, c.InitialValue
+				}
+			}
+		}
+	}
+	if decl == nil {
+		return nil, fmt.Errorf("constant %s not found", name)
+	}
+	return e.constantValue(name, decl, typeName, value, declScope)
+}
+
+func (e *evaluator) constantValue(name string, decl, typeName, value interface{}, scope *solcparser.ContractDefinition) (*Value, error) {
+	if e.visiting[decl] {
+		return nil, fmt.Errorf("constant %s is defined recursively", name)
+	}
+	e.visiting[decl] = true
+	defer delete(e.visiting, decl)
+
+	v, err := e.eval(value, scope)
+	if err != nil {
+		return nil, err
+	}
+	elem, ok := typeName.(*solcparser.ElementaryTypeName)
+	if !ok {
+		return v, nil
+	}
+	res, err := implicitConvert(v, canonical(elem.Name))
+	if err != nil {
+		return nil, fmt.Errorf("constant %s: %v", name, err)
+	}
+	return res, nil
+}
+
+func findConstant(c *solcparser.ContractDefinition, name string) (*solcparser.StateVariableDeclarationVariable, bool) {
+	for _, node := range c.SubNodes {
+		decl, ok := node.(*solcparser.StateVariableDeclaration)
+		if !ok {
+			continue
+		}
+		for _, v := range decl.Variables {
+			vv, ok := v.(*solcparser.StateVariableDeclarationVariable)
+			if ok && vv.IsDeclaredConst && vv.Name == name {
+				return vv, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (e *evaluator) member(obj *solcparser.MemberAccess, scope *solcparser.ContractDefinition) (*Value, error) {
+	// type(T).min and type(T).max
+	if call, ok := obj.Expression.(*solcparser.FunctionCall); ok {
+		if ident, ok := call.Expression.(*solcparser.Identifier); ok && ident.Name == "type" && len(call.Arguments) == 1 {
+			typ, ok := typeName(call.Arguments[0])
+			if !ok {
+				return nil, fmt.Errorf("type(...) argument is not an elementary type")
+			}
+			signed, bits, ok := intType(typ)
+			if !ok {
+				return nil, fmt.Errorf("type(%s).%s is not constant", typ, obj.MemberName)
+			}
+			switch obj.MemberName {
+			case "min":
+				return integer(minInt(signed, bits), typ), nil
+			case "max":
+				return integer(maxInt(signed, bits), typ), nil
+			}
+			return nil, fmt.Errorf("type(%s).%s is not constant", typ, obj.MemberName)
+		}
+	}
+
+	// Contract.CONSTANT
+	if ident, ok := obj.Expression.(*solcparser.Identifier); ok && e.project != nil {
+		if c, ok := e.project.Contract(ident.Name); ok {
+			if vv, ok := findConstant(c, obj.MemberName); ok {
+				return e.constantValue(obj.MemberName, vv, vv.TypeName, vv.Expression, c)
+			}
+		}
+	}
+	return nil, fmt.Errorf("member %s is not constant", obj.MemberName)
+}
+
+// typeName returns the elementary type referenced by an expression
+func typeName(expr interface{}) (string, bool) {
+	switch obj := expr.(type) {
+	case *solcparser.ElementaryTypeName:
+		return canonical(obj.Name), true
+	case *solcparser.TypeNameExpression:
+		return typeName(obj.TypeName)
+	}
+	return "", false
+}
+
+func (e *evaluator) call(obj *solcparser.FunctionCall, scope *solcparser.ContractDefinition) (*Value, error) {
+	args := []*Value{}
+	for _, arg := range obj.Arguments {
+		v, err := e.eval(arg, scope)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+
+	// explicit conversion
+	if typ, ok := typeName(obj.Expression); ok {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("conversion to %s expects one argument", typ)
+		}
+		return explicitConvert(args[0], typ)
+	}
+
+	var name string
+	switch callee := obj.Expression.(type) {
+	case *solcparser.Identifier:
+		name = callee.Name
+	case *solcparser.MemberAccess:
+		if ident, ok := callee.Expression.(*solcparser.Identifier); ok {
+			name = ident.Name + "." + callee.MemberName
+		}
+	}
+
+	switch name {
+	case "keccak256", "sha256":
+		if len(args) != 1 || args[0].Kind != KindBytes {
+			return nil, fmt.Errorf("%s expects a bytes argument", name)
+		}
+		return &Value{Kind: KindBytes, Bytes: hash(name, args[0].Bytes), Type: "bytes32"}, nil
+
+	case "abi.encodePacked", "bytes.concat", "string.concat":
+		data := []byte{}
+		for _, arg := range args {
+			packed, err := encodePacked(arg)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, packed...)
+		}
+		typ := "bytes"
+		if name == "string.concat" {
+			typ = "string"
+		}
+		return &Value{Kind: KindBytes, Bytes: data, Type: typ}, nil
+	}
+	return nil, fmt.Errorf("function call is not constant")
+}
+
+// encodePacked returns the non standard packed encoding of a value
+func encodePacked(v *Value) ([]byte, error) {
+	switch v.Kind {
+	case KindBytes:
+		return v.Bytes, nil
+	case KindBool:
+		if v.Bool {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	}
+	signed, bits, ok := intType(v.Type)
+	if !ok {
+		if v.Type == "address" {
+			bits = 160
+		} else {
+			return nil, fmt.Errorf("literal %s cannot be packed without a type", v)
+		}
+	}
+	x, _ := v.Int()
+	return toBytes(wrap(x, signed, bits), bits/8), nil
+}
@@ -0,0 +1,150 @@
+package constant
+
+import (
+	"strings"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// lookupConstant returns the constant X declared in the contract A
+func lookupConstant(t *testing.T, src string) (*solcparser.Project, *solcparser.ContractDefinition, *solcparser.StateVariableDeclarationVariable) {
+	t.Helper()
+
+	p := solcparser.NewProject()
+	if err := p.AddSource("a.sol", src); err != nil {
+		t.Fatal(err)
+	}
+	c, ok := p.Contract("A")
+	if !ok {
+		t.Fatal("contract A not found")
+	}
+	vv, ok := findConstant(c, "X")
+	if !ok {
+		t.Fatal("constant X not found")
+	}
+	return p, c, vv
+}
+
+// evalConstant evaluates the constant X declared in the contract A, the value
+// is converted to the type of the declaration
+func evalConstant(t *testing.T, src string) (*Value, error) {
+	t.Helper()
+
+	p, c, _ := lookupConstant(t, src)
+	return Eval(&solcparser.Identifier{Name: "X"}, WithProject(p), WithContract(c))
+}
+
+// evalExpression evaluates the initial value of the constant X declared in the
+// contract A without the type of the declaration
+func evalExpression(t *testing.T, src string) (*Value, error) {
+	t.Helper()
+
+	p, c, vv := lookupConstant(t, src)
+	return Eval(vv.Expression, WithProject(p), WithContract(c))
+}
+
+func TestEval(t *testing.T) {
+	cases := []struct {
+		src   string
+		value string
+	}{
+		{"uint constant X = 1_000;", "1000"},
+		{"uint constant X = 1e18;", "1000000000000000000"},
+		{"uint constant X = 2.5e3;", "2500"},
+		{"uint constant X = 0x1F;", "31"},
+		{"uint constant X = 1 ether;", "1000000000000000000"},
+		{"uint constant X = 5 gwei;", "5000000000"},
+		{"uint constant X = 2 days;", "172800"},
+		{"uint constant X = 1.5 ether;", "1500000000000000000"},
+		{"uint constant X = (1 / 3) * 3;", "1"},
+		{"uint constant X = 2 ** 10 - 1;", "1023"},
+		{"uint constant X = 1 << 255;", "57896044618658097711785492504343953926634992332820282019728792003956564819968"},
+		{"uint constant X = type(uint256).max;", "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{"int constant X = type(int8).min;", "-128"},
+		{"uint constant FEE = 30; uint constant DENOM = 10_000; uint constant X = 1 ether * FEE / DENOM;", "3000000000000000"},
+		{"uint8 constant X = uint8(300 - 45);", "255"},
+		{"uint8 constant X = uint8(uint256(300));", "44"},
+		{"bool constant X = 1 < 2 && true;", "true"},
+		{"bytes32 constant X = keccak256(\"MINTER_ROLE\");", "0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6"},
+		{"bytes32 constant X = keccak256(\"\");", "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"bytes32 constant X = keccak256(abi.encodePacked(\"MINTER\", \"_ROLE\"));", "0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6"},
+		{"bytes4 constant X = bytes4(0x12345678);", "0x12345678"},
+		{"bytes4 constant B = \"ab\"; bytes4 constant X = B;", "0x61620000"},
+		{"string constant X = \"abc\";", "\"abc\""},
+		{"string constant X = \"a\\x62\\u0063\";", "\"abc\""},
+		{"bytes32 constant X = keccak256(\"MINTER\\x5fROLE\");", "0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6"},
+		{"bytes constant X = hex\"00ff\";", "\"\\x00\\xff\""},
+		{"uint constant X = B.Y + 1; } contract B { uint constant Y = 41;", "42"},
+	}
+
+	for _, c := range cases {
+		v, err := evalConstant(t, "contract A { "+c.src+" }")
+		if err != nil {
+			t.Fatalf("failed to evaluate '%s': %v", c.src, err)
+		}
+		if v.String() != c.value {
+			t.Fatalf("bad value for '%s': expected %s but found %s", c.src, c.value, v.String())
+		}
+	}
+}
+
+func TestEvalFileLevelConstant(t *testing.T) {
+	v, err := evalConstant(t, "uint constant BASE = 10; contract A { uint constant X = BASE ** 2; }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.String() != "100" {
+		t.Fatalf("bad value %s", v)
+	}
+}
+
+func TestEvalRational(t *testing.T) {
+	// the literals fold as rationals until the value is converted to a type
+	cases := []struct {
+		src   string
+		value string
+	}{
+		{"uint constant X = 30 * 100 / 10000;", "3/10"},
+		{"uint constant X = 1 / 3 + 1 / 6;", "1/2"},
+		{"uint constant X = 0.25 * 4;", "1"},
+	}
+
+	for _, c := range cases {
+		v, err := evalExpression(t, "contract A { "+c.src+" }")
+		if err != nil {
+			t.Fatalf("failed to evaluate '%s': %v", c.src, err)
+		}
+		if v.String() != c.value {
+			t.Fatalf("bad value for '%s': expected %s but found %s", c.src, c.value, v.String())
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	cases := []struct {
+		src string
+		err string
+	}{
+		{"uint8 constant A = 256; uint constant X = A;", "value 256 does not fit in uint8"},
+		{"uint8 constant A = 200; uint8 constant X = A + 100;", "arithmetic overflow: 300 does not fit in uint8"},
+		{"uint8 constant A = 2; uint8 constant X = A ** 8;", "arithmetic overflow"},
+		{"uint constant X = 1 << 5000;", "constant overflow"},
+		{"uint constant X = 2 ** 10000;", "constant overflow"},
+		{"uint constant X = 1 / 0;", "division by zero"},
+		{"uint constant X = 30 * 100 / 10000;", "cannot implicitly convert 3/10 to uint256"},
+		{"uint constant X = Y; uint constant Y = X;", "defined recursively"},
+		{"uint constant X = Z;", "constant Z not found"},
+		{"uint constant X = block.number;", "is not constant"},
+	}
+
+	for _, c := range cases {
+		_, err := evalConstant(t, "contract A { "+c.src+" }")
+		if err == nil {
+			t.Fatalf("expected an error for '%s'", c.src)
+		}
+		if !strings.Contains(err.Error(), c.err) {
+			t.Fatalf("bad error for '%s': %v", c.src, err)
+		}
+	}
+}
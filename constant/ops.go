@@ -0,0 +1,549 @@
+package constant
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Fold evaluates a binary operation over two rational literals
+func Fold(op string, a, b *big.Rat) (*big.Rat, error) {
+	res, err := fold(op, a, b)
+	if err != nil {
+		return nil, err
+	}
+	if res.Num().BitLen() > maxBits || res.Denom().BitLen() > maxBits {
+		return nil, fmt.Errorf("constant overflow: the result of %s has more than %d bits", op, maxBits)
+	}
+	return res, nil
+}
+
+func fold(op string, a, b *big.Rat) (*big.Rat, error) {
+	switch op {
+	case "+":
+		return new(big.Rat).Add(a, b), nil
+	case "-":
+		return new(big.Rat).Sub(a, b), nil
+	case "*":
+		return new(big.Rat).Mul(a, b), nil
+	case "/":
+		if b.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return new(big.Rat).Quo(a, b), nil
+	}
+
+	// the rest of the operators are only defined for integers
+	if !a.IsInt() || !b.IsInt() {
+		return nil, fmt.Errorf("operator %s not compatible with fractional constants", op)
+	}
+	x, y := a.Num(), b.Num()
+	res := new(big.Int)
+
+	switch op {
+	case "%":
+		if y.Sign() == 0 {
+			return nil, fmt.Errorf("modulo zero")
+		}
+		res.Rem(x, y)
+	case "**":
+		if y.Sign() < 0 {
+			return nil, fmt.Errorf("negative exponent")
+		}
+		if x.CmpAbs(big.NewInt(1)) > 0 && (!y.IsInt64() || int64(x.BitLen()-1)*y.Int64() > maxBits) {
+			return nil, fmt.Errorf("constant overflow: the result of ** has more than %d bits", maxBits)
+		}
+		res.Exp(x, exponent(x, y), nil)
+	case "<<":
+		if y.Sign() < 0 {
+			return nil, fmt.Errorf("negative shift")
+		}
+		if x.Sign() != 0 && (!y.IsInt64() || int64(x.BitLen())+y.Int64() > maxBits) {
+			return nil, fmt.Errorf("constant overflow: the result of << has more than %d bits", maxBits)
+		}
+		if x.Sign() != 0 {
+			res.Lsh(x, uint(y.Int64()))
+		}
+	case ">>":
+		if y.Sign() < 0 {
+			return nil, fmt.Errorf("negative shift")
+		}
+		if !y.IsInt64() || y.Int64() > maxBits {
+			if x.Sign() < 0 {
+				res.SetInt64(-1)
+			}
+		} else {
+			res.Rsh(x, uint(y.Int64()))
+		}
+	case "&":
+		res.And(x, y)
+	case "|":
+		res.Or(x, y)
+	case "^":
+		res.Xor(x, y)
+	default:
+		return nil, fmt.Errorf("operator %s not supported for constants", op)
+	}
+	return new(big.Rat).SetInt(res), nil
+}
+
+// exponent reduces large exponents of 0, 1 and -1 since the result
+// only depends on their parity
+func exponent(x, y *big.Int) *big.Int {
+	if x.CmpAbs(big.NewInt(1)) > 0 || y.BitLen() <= 2 {
+		return y
+	}
+	if y.Bit(0) == 1 {
+		return big.NewInt(1)
+	}
+	return big.NewInt(2)
+}
+
+func unaryOp(op string, x *Value) (*Value, error) {
+	switch op {
+	case "!":
+		if x.Kind != KindBool {
+			return nil, fmt.Errorf("operator ! expects a bool")
+		}
+		return &Value{Kind: KindBool, Bool: !x.Bool}, nil
+
+	case "-":
+		if x.Kind != KindRational {
+			return nil, fmt.Errorf("operator - expects a number")
+		}
+		res := new(big.Rat).Neg(x.Rat)
+		if x.Type != "" {
+			signed, bits, _ := intType(x.Type)
+			if !signed {
+				return nil, fmt.Errorf("operator - not compatible with %s", x.Type)
+			}
+			if !fits(res.Num(), signed, bits) {
+				return nil, fmt.Errorf("arithmetic overflow: %s does not fit in %s", res.Num(), x.Type)
+			}
+		}
+		return rational(res, x.Type), nil
+
+	case "~":
+		if x.Kind == KindBytes {
+			if _, ok := bytesSize(x.Type); !ok {
+				return nil, fmt.Errorf("operator ~ not compatible with %s", x)
+			}
+			res := make([]byte, len(x.Bytes))
+			for i, b := range x.Bytes {
+				res[i] = ^b
+			}
+			return &Value{Kind: KindBytes, Bytes: res, Type: x.Type}, nil
+		}
+		n, ok := x.Int()
+		if !ok {
+			return nil, fmt.Errorf("operator ~ expects an integer")
+		}
+		res := new(big.Int).Not(n)
+		if signed, bits, ok := intType(x.Type); ok {
+			res = wrap(res, signed, bits)
+		}
+		return integer(res, x.Type), nil
+	}
+	return nil, fmt.Errorf("operator %s not supported for constants", op)
+}
+
+func binaryOp(op string, x, y *Value) (*Value, error) {
+	switch op {
+	case "&&", "||":
+		if x.Kind != KindBool || y.Kind != KindBool {
+			return nil, fmt.Errorf("operator %s expects bool operands", op)
+		}
+		if op == "&&" {
+			return &Value{Kind: KindBool, Bool: x.Bool && y.Bool}, nil
+		}
+		return &Value{Kind: KindBool, Bool: x.Bool || y.Bool}, nil
+
+	case "==", "!=", "<", ">", "<=", ">=":
+		cmp, err := compare(op, x, y)
+		if err != nil {
+			return nil, err
+		}
+		var res bool
+		switch op {
+		case "==":
+			res = cmp == 0
+		case "!=":
+			res = cmp != 0
+		case "<":
+			res = cmp < 0
+		case ">":
+			res = cmp > 0
+		case "<=":
+			res = cmp <= 0
+		case ">=":
+			res = cmp >= 0
+		}
+		return &Value{Kind: KindBool, Bool: res}, nil
+	}
+
+	if x.Kind == KindBytes && y.Kind == KindBytes {
+		return bytesOp(op, x, y)
+	}
+	if x.Kind != KindRational || y.Kind != KindRational {
+		return nil, fmt.Errorf("operator %s not compatible with %s and %s", op, x, y)
+	}
+
+	if x.Type == "" && (y.Type == "" || op == "**" || op == "<<" || op == ">>") {
+		res, err := Fold(op, x.Rat, y.Rat)
+		if err != nil {
+			return nil, err
+		}
+		return rational(res, ""), nil
+	}
+	return typedOp(op, x, y)
+}
+
+// typedOp evaluates an operation with the checked arithmetic of the sized integers
+func typedOp(op string, x, y *Value) (*Value, error) {
+	// the result of the shifts and exponents has the type of the left operand
+	typ := x.Type
+	switch op {
+	case "**", "<<", ">>":
+	default:
+		var err error
+		if typ, err = commonType(x.Type, y.Type); err != nil {
+			return nil, fmt.Errorf("operator %s not compatible with %s and %s", op, x.Type, y.Type)
+		}
+	}
+	signed, bits, ok := intType(typ)
+	if !ok {
+		return nil, fmt.Errorf("operator %s not compatible with %s", op, typ)
+	}
+
+	a, aok := x.Int()
+	b, bok := y.Int()
+	if !aok || !bok {
+		return nil, fmt.Errorf("operator %s expects integer operands", op)
+	}
+	if x.Type == "" && !fits(a, signed, bits) {
+		return nil, fmt.Errorf("cannot implicitly convert %s to %s", a, typ)
+	}
+	if y.Type == "" && op != "**" && op != "<<" && op != ">>" && !fits(b, signed, bits) {
+		return nil, fmt.Errorf("cannot implicitly convert %s to %s", b, typ)
+	}
+
+	res := new(big.Int)
+	switch op {
+	case "+":
+		res.Add(a, b)
+	case "-":
+		res.Sub(a, b)
+	case "*":
+		res.Mul(a, b)
+	case "/":
+		if b.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		res.Quo(a, b)
+	case "%":
+		if b.Sign() == 0 {
+			return nil, fmt.Errorf("modulo zero")
+		}
+		res.Rem(a, b)
+	case "**":
+		if b.Sign() < 0 {
+			return nil, fmt.Errorf("negative exponent")
+		}
+		if a.CmpAbs(big.NewInt(1)) > 0 && (!b.IsInt64() || int64(a.BitLen()-1)*b.Int64() > int64(bits)) {
+			return nil, fmt.Errorf("arithmetic overflow: %s ** %s does not fit in %s", a, b, typ)
+		}
+		res.Exp(a, exponent(a, b), nil)
+	case "<<", ">>":
+		// shifts are not checked, the result is truncated
+		if b.Sign() < 0 {
+			return nil, fmt.Errorf("negative shift")
+		}
+		if !b.IsInt64() || b.Int64() > int64(bits) {
+			if op == ">>" && a.Sign() < 0 {
+				res.SetInt64(-1)
+			}
+			return integer(res, typ), nil
+		}
+		if op == "<<" {
+			res = wrap(res.Lsh(a, uint(b.Int64())), signed, bits)
+		} else {
+			res.Rsh(a, uint(b.Int64()))
+		}
+		return integer(res, typ), nil
+	case "&":
+		res.And(a, b)
+	case "|":
+		res.Or(a, b)
+	case "^":
+		res.Xor(a, b)
+	default:
+		return nil, fmt.Errorf("operator %s not supported for constants", op)
+	}
+
+	if !fits(res, signed, bits) {
+		return nil, fmt.Errorf("arithmetic overflow: %s does not fit in %s", res, typ)
+	}
+	return integer(res, typ), nil
+}
+
+func bytesOp(op string, x, y *Value) (*Value, error) {
+	size, ok := bytesSize(x.Type)
+	if !ok || x.Type != y.Type {
+		return nil, fmt.Errorf("operator %s not compatible with %s and %s", op, x, y)
+	}
+	res := make([]byte, size)
+	for i := range res {
+		switch op {
+		case "&":
+			res[i] = x.Bytes[i] & y.Bytes[i]
+		case "|":
+			res[i] = x.Bytes[i] | y.Bytes[i]
+		case "^":
+			res[i] = x.Bytes[i] ^ y.Bytes[i]
+		default:
+			return nil, fmt.Errorf("operator %s not supported for %s", op, x.Type)
+		}
+	}
+	return &Value{Kind: KindBytes, Bytes: res, Type: x.Type}, nil
+}
+
+func compare(op string, x, y *Value) (int, error) {
+	if x.Kind != y.Kind {
+		return 0, fmt.Errorf("operator %s not compatible with %s and %s", op, x, y)
+	}
+	switch x.Kind {
+	case KindRational:
+		return x.Rat.Cmp(y.Rat), nil
+	case KindBytes:
+		return bytes.Compare(x.Bytes, y.Bytes), nil
+	}
+	if op != "==" && op != "!=" {
+		return 0, fmt.Errorf("operator %s not compatible with bool", op)
+	}
+	if x.Bool == y.Bool {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// commonType returns the integer type both operands can be converted to
+func commonType(a, b string) (string, error) {
+	if a == "" || a == b {
+		return b, nil
+	}
+	if b == "" {
+		return a, nil
+	}
+	as, abits, aok := intType(a)
+	bs, bbits, bok := intType(b)
+	if !aok || !bok || as != bs {
+		return "", fmt.Errorf("no common type")
+	}
+	if abits > bbits {
+		return a, nil
+	}
+	return b, nil
+}
+
+// implicitConvert converts the value to the declared type of a constant
+func implicitConvert(v *Value, typ string) (*Value, error) {
+	if signed, bits, ok := intType(typ); ok {
+		n, ok := v.Int()
+		if !ok {
+			return nil, fmt.Errorf("cannot implicitly convert %s to %s", v, typ)
+		}
+		if v.Type != "" {
+			vs, vbits, ok := intType(v.Type)
+			if !ok || (vs == signed && vbits > bits) || (vs != signed && (vs || vbits >= bits)) {
+				return nil, fmt.Errorf("cannot implicitly convert %s to %s", v.Type, typ)
+			}
+		} else if !fits(n, signed, bits) {
+			return nil, fmt.Errorf("value %s does not fit in %s", n, typ)
+		}
+		return integer(n, typ), nil
+	}
+
+	if size, ok := bytesSize(typ); ok {
+		switch v.Kind {
+		case KindBytes:
+			if vsize, ok := bytesSize(v.Type); (ok && vsize > size) || (!ok && (v.Type != "" || len(v.Bytes) > size)) {
+				return nil, fmt.Errorf("cannot implicitly convert %s to %s", v, typ)
+			}
+			return &Value{Kind: KindBytes, Bytes: padRight(v.Bytes, size), Type: typ}, nil
+		case KindRational:
+			if v.Type == "" && v.Rat.Sign() == 0 {
+				return &Value{Kind: KindBytes, Bytes: make([]byte, size), Type: typ}, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot implicitly convert %s to %s", v, typ)
+	}
+
+	switch typ {
+	case "string", "bytes":
+		if v.Kind != KindBytes {
+			return nil, fmt.Errorf("cannot implicitly convert %s to %s", v, typ)
+		}
+		return &Value{Kind: KindBytes, Bytes: v.Bytes, Type: typ}, nil
+	case "bool":
+		if v.Kind != KindBool {
+			return nil, fmt.Errorf("cannot implicitly convert %s to %s", v, typ)
+		}
+		return v, nil
+	case "address":
+		n, ok := v.Int()
+		if !ok || !fits(n, false, 160) {
+			return nil, fmt.Errorf("cannot implicitly convert %s to %s", v, typ)
+		}
+		return integer(n, typ), nil
+	}
+	return v, nil
+}
+
+// explicitConvert evaluates a type conversion (i.e. uint8(x))
+func explicitConvert(v *Value, typ string) (*Value, error) {
+	if signed, bits, ok := intType(typ); ok {
+		switch v.Kind {
+		case KindRational:
+			n, ok := v.Int()
+			if !ok {
+				return nil, fmt.Errorf("cannot convert %s to %s", v, typ)
+			}
+			if v.Type == "" && !fits(n, signed, bits) {
+				return nil, fmt.Errorf("value %s does not fit in %s", n, typ)
+			}
+			return integer(wrap(n, signed, bits), typ), nil
+		case KindBytes:
+			if size, ok := bytesSize(v.Type); ok && size*8 == bits {
+				return integer(wrap(new(big.Int).SetBytes(v.Bytes), signed, bits), typ), nil
+			}
+		}
+		return nil, fmt.Errorf("cannot convert %s to %s", v, typ)
+	}
+
+	if size, ok := bytesSize(typ); ok {
+		switch v.Kind {
+		case KindBytes:
+			if _, ok := bytesSize(v.Type); ok {
+				if len(v.Bytes) > size {
+					return &Value{Kind: KindBytes, Bytes: v.Bytes[:size], Type: typ}, nil
+				}
+				return &Value{Kind: KindBytes, Bytes: padRight(v.Bytes, size), Type: typ}, nil
+			}
+			if v.Type == "" && len(v.Bytes) <= size {
+				return &Value{Kind: KindBytes, Bytes: padRight(v.Bytes, size), Type: typ}, nil
+			}
+		case KindRational:
+			n, ok := v.Int()
+			if ok && n.Sign() >= 0 && n.BitLen() <= size*8 {
+				return &Value{Kind: KindBytes, Bytes: toBytes(n, size), Type: typ}, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot convert %s to %s", v, typ)
+	}
+
+	switch typ {
+	case "address":
+		if v.Kind == KindBytes && v.Type == "bytes20" {
+			return integer(new(big.Int).SetBytes(v.Bytes), typ), nil
+		}
+		n, ok := v.Int()
+		if !ok || n.Sign() < 0 || n.BitLen() > 160 {
+			return nil, fmt.Errorf("cannot convert %s to %s", v, typ)
+		}
+		return integer(n, typ), nil
+	}
+	return implicitConvert(v, typ)
+}
+
+func canonical(name string) string {
+	switch name {
+	case "uint":
+		return "uint256"
+	case "int":
+		return "int256"
+	case "byte":
+		return "bytes1"
+	}
+	return name
+}
+
+// intType returns the signedness and size of an integer type name
+func intType(typ string) (bool, int, bool) {
+	var bits int
+	signed := strings.HasPrefix(typ, "int")
+	if !signed && !strings.HasPrefix(typ, "uint") {
+		return false, 0, false
+	}
+	if _, err := fmt.Sscanf(strings.TrimPrefix(typ, "u"), "int%d", &bits); err != nil {
+		return false, 0, false
+	}
+	if bits < 8 || bits > 256 || bits%8 != 0 {
+		return false, 0, false
+	}
+	return signed, bits, true
+}
+
+// bytesSize returns the size of a fixed bytes type name
+func bytesSize(typ string) (int, bool) {
+	var size int
+	if !strings.HasPrefix(typ, "bytes") || typ == "bytes" {
+		return 0, false
+	}
+	if _, err := fmt.Sscanf(typ, "bytes%d", &size); err != nil || size < 1 || size > 32 {
+		return 0, false
+	}
+	return size, true
+}
+
+func minInt(signed bool, bits int) *big.Int {
+	if !signed {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(bits-1)))
+}
+
+func maxInt(signed bool, bits int) *big.Int {
+	if signed {
+		bits--
+	}
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits)), big.NewInt(1))
+}
+
+func fits(x *big.Int, signed bool, bits int) bool {
+	return x.Cmp(minInt(signed, bits)) >= 0 && x.Cmp(maxInt(signed, bits)) <= 0
+}
+
+// wrap truncates the integer to the two's complement representation of the type
+func wrap(x *big.Int, signed bool, bits int) *big.Int {
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	res := new(big.Int).Mod(x, mod)
+	if signed && res.Cmp(maxInt(true, bits)) > 0 {
+		res.Sub(res, mod)
+	}
+	return res
+}
+
+// toBytes returns the big endian two's complement representation of the integer
+func toBytes(x *big.Int, size int) []byte {
+	if x.Sign() < 0 {
+		x = new(big.Int).Add(x, new(big.Int).Lsh(big.NewInt(1), uint(size*8)))
+	}
+	buf := make([]byte, size)
+	return x.FillBytes(buf)
+}
+
+func padRight(b []byte, size int) []byte {
+	res := make([]byte, size)
+	copy(res, b)
+	return res
+}
+
+func hash(name string, data []byte) []byte {
+	if name == "sha256" {
+		h := sha256.Sum256(data)
+		return h[:]
+	}
+	return Keccak256(data)
+}
@@ -0,0 +1,211 @@
+package solcparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// SyntaxNode is a node of the concrete syntax tree of a source, either a rule
+// of the grammar with its children or a token. The tree is lossless, the text
+// between the tokens (whitespace, comments and the tokens skipped by the
+// error recovery) is kept so the exact text of any node can be reproduced.
+type SyntaxNode struct {
+	// Rule is the rule of the grammar of the node (i.e. "functionDefinition"),
+	// it is empty for the tokens
+	Rule     string
+	Children []*SyntaxNode
+
+	// Token is the token of a leaf
+	Token *SyntaxToken
+
+	// Trailing is the text after the last token of the source, only the root
+	// has it
+	Trailing []*Token
+
+	// Loc is the range from the first to the last token of the node, it is
+	// nil for the rules without tokens
+	Loc *Location
+}
+
+// SyntaxToken is a token of the concrete syntax tree
+type SyntaxToken struct {
+	Token
+
+	// Leading is the text between the token and the token before it
+	Leading []*Token
+}
+
+// WithCST builds the concrete syntax tree of the source (i.e. Parser.CST),
+// it is only available with the antlr backend
+func WithCST() Option {
+	return func(c *config) {
+		c.cst = true
+	}
+}
+
+// cstBuilder converts the antlr parse tree into the concrete syntax tree
+type cstBuilder struct {
+	tokens    *antlr.CommonTokenStream
+	ruleNames []string
+	srcMap    *sourceMap
+
+	// next is the index of the first token of the stream that is not in the tree
+	next int
+}
+
+func (b *cstBuilder) build(tree antlr.Tree) *SyntaxNode {
+	root := b.node(tree)
+	if root == nil {
+		root = &SyntaxNode{}
+	}
+	root.Trailing = b.skipped(b.tokens.Size() - 1)
+	return root
+}
+
+func (b *cstBuilder) node(tree antlr.Tree) *SyntaxNode {
+	switch obj := tree.(type) {
+	case antlr.TerminalNode:
+		t := obj.GetSymbol()
+		// the tokens of the error recovery (i.e. a missing ';') are not in
+		// the source and the EOF is not a token of the tree
+		if t.GetTokenIndex() < b.next || t.GetTokenType() == antlr.TokenEOF {
+			return nil
+		}
+		leading := b.skipped(t.GetTokenIndex())
+		b.next = t.GetTokenIndex() + 1
+
+		token := &SyntaxToken{
+			Token:   *b.token(t),
+			Leading: leading,
+		}
+		return &SyntaxNode{
+			Token: token,
+			Loc:   token.Loc,
+		}
+
+	case antlr.ParserRuleContext:
+		n := &SyntaxNode{
+			Rule:     b.ruleNames[obj.GetRuleIndex()],
+			Children: []*SyntaxNode{},
+		}
+		for _, child := range obj.GetChildren() {
+			c := b.node(child)
+			if c == nil {
+				continue
+			}
+			n.Children = append(n.Children, c)
+			if c.Loc != nil {
+				if n.Loc == nil {
+					n.Loc = &Location{Start: c.Loc.Start}
+				}
+				n.Loc.End = c.Loc.End
+			}
+		}
+		return n
+	}
+	return nil
+}
+
+// skipped returns the tokens from the last token of the tree to an index
+func (b *cstBuilder) skipped(index int) []*Token {
+	res := []*Token{}
+	for ; b.next < index; b.next++ {
+		res = append(res, b.token(b.tokens.Get(b.next)))
+	}
+	return res
+}
+
+func (b *cstBuilder) token(t antlr.Token) *Token {
+	start, end := b.srcMap.runePosition(t.GetStart()), b.srcMap.runePosition(t.GetStop()+1)
+	channel := DefaultChannel
+	if t.GetChannel() != antlr.TokenDefaultChannel {
+		channel = HiddenChannel
+	}
+	text := t.GetText()
+	return &Token{
+		Kind:    tokenKind(t.GetTokenType(), text),
+		Text:    text,
+		Loc:     &Location{Start: start, End: end},
+		Channel: channel,
+	}
+}
+
+// Tokens returns the tokens of the node
+func (n *SyntaxNode) Tokens() []*SyntaxToken {
+	res := []*SyntaxToken{}
+	var walk func(n *SyntaxNode)
+	walk = func(n *SyntaxNode) {
+		if n.Token != nil {
+			res = append(res, n.Token)
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(n)
+	return res
+}
+
+// Text returns the text of the node as it is in the source, from its first to
+// its last token
+func (n *SyntaxNode) Text() string {
+	var b strings.Builder
+	for i, t := range n.Tokens() {
+		if i != 0 {
+			for _, l := range t.Leading {
+				b.WriteString(l.Text)
+			}
+		}
+		b.WriteString(t.Text)
+	}
+	return b.String()
+}
+
+// FullText returns the text of the node with the text before its first token,
+// the full text of the root is the source
+func (n *SyntaxNode) FullText() string {
+	var b strings.Builder
+	if tokens := n.Tokens(); len(tokens) != 0 {
+		for _, l := range tokens[0].Leading {
+			b.WriteString(l.Text)
+		}
+	}
+	b.WriteString(n.Text())
+	for _, t := range n.Trailing {
+		b.WriteString(t.Text)
+	}
+	return b.String()
+}
+
+// Find returns the innermost rule node with a location (i.e. the location of
+// a node of the AST), it returns nil if there is none
+func (n *SyntaxNode) Find(loc *Location) *SyntaxNode {
+	if n.Loc == nil || loc == nil || loc.Start.Offset < n.Loc.Start.Offset || loc.End.Offset > n.Loc.End.Offset {
+		return nil
+	}
+	for _, child := range n.Children {
+		if found := child.Find(loc); found != nil {
+			return found
+		}
+	}
+	if n.Rule != "" && n.Loc.Start.Offset == loc.Start.Offset && n.Loc.End.Offset == loc.End.Offset {
+		return n
+	}
+	return nil
+}
+
+// StringFragment returns the quote, the raw text and the decoded value of a
+// string or a hex string token. It fails if the token is not a string or the
+// string has an invalid escape sequence.
+func (t *SyntaxToken) StringFragment() (*StringFragment, error) {
+	if t.Kind != TokenString && t.Kind != TokenHexString {
+		return nil, fmt.Errorf("token '%s' is not a string", t.Text)
+	}
+	f, err := parseStringFragment(t.Text)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,103 @@
+package solcparser
+
+import (
+	"testing"
+)
+
+func TestCSTLossless(t *testing.T) {
+	cases := []string{
+		"",
+		"  // only a comment\n",
+		"pragma solidity ^0.8.0;\n\n/* ü */ contract A {\n\tuint a; // the value\n}\n\n",
+		"contract A { function f() public { a = 'x' ; } } # illegal",
+		"contract A { function f() public { a = ; } }",
+		"contract A {\r\n  string s = unicode\"é\";\r\n}",
+	}
+
+	for _, src := range cases {
+		p := Parse(src, WithCST())
+		if p.CST == nil {
+			t.Fatalf("CST not found for '%s'", src)
+		}
+		if text := p.CST.FullText(); text != src {
+			t.Fatalf("bad text for '%s': '%s'", src, text)
+		}
+	}
+
+	// the CST is optional
+	if Parse("contract A {}").CST != nil {
+		t.Fatal("CST not expected")
+	}
+}
+
+func TestCSTFind(t *testing.T) {
+	src := "contract A {\n  function f(uint x) public {\n    a = x /* the x */ ;\n  }\n}\n"
+
+	p := Parse(src, WithCST(), WithLocations())
+	fn := p.Result.(*SourceUnit).Children[0].(*ContractDefinition).SubNodes[0].(*FunctionDefinition)
+
+	n := p.CST.Find(fn.GetLoc())
+	if n == nil || n.Rule != "functionDefinition" {
+		t.Fatalf("bad node %v", n)
+	}
+	if n.Text() != "function f(uint x) public {\n    a = x /* the x */ ;\n  }" {
+		t.Fatalf("bad text '%s'", n.Text())
+	}
+	if n.FullText() != "\n  "+n.Text() {
+		t.Fatalf("bad full text '%s'", n.FullText())
+	}
+
+	// the keywords and the punctuation are in the tree
+	tokens := n.Tokens()
+	if tokens[0].Text != "function" || tokens[0].Kind != TokenKeyword || tokens[len(tokens)-1].Text != "}" {
+		t.Fatalf("bad tokens %v", tokens)
+	}
+
+	// the comment is before the ';'
+	stmt := fn.Body.(*Block).Statements[0].(*ExpressionStatement)
+	tokens = p.CST.Find(stmt.GetLoc()).Tokens()
+	last := tokens[len(tokens)-1]
+	if last.Text != ";" || len(last.Leading) != 3 || last.Leading[1].Kind != TokenComment {
+		t.Fatalf("bad leading text of ';' %v", last.Leading)
+	}
+
+	if p.CST.Find(&Location{Start: Position{Offset: 1}, End: Position{Offset: 2}}) != nil {
+		t.Fatal("node not expected")
+	}
+}
+
+func TestCSTStringFragment(t *testing.T) {
+	src := `contract A { function f() public { a = 'it\'s\n' "b" unicode"é" hex"00_ff"; } }`
+
+	p := Parse(src, WithCST())
+	fragments := []*StringFragment{}
+	for _, tok := range p.CST.Tokens() {
+		if tok.Kind != TokenString && tok.Kind != TokenHexString {
+			continue
+		}
+		f, err := tok.StringFragment()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fragments = append(fragments, f)
+	}
+
+	expected := []StringFragment{
+		{Prefix: "", Quote: '\'', Raw: `it\'s\n`, Value: "it's\n"},
+		{Prefix: "", Quote: '"', Raw: "b", Value: "b"},
+		{Prefix: "unicode", Quote: '"', Raw: `é`, Value: "é"},
+		{Prefix: "hex", Quote: '"', Raw: "00_ff", Value: "\x00\xff"},
+	}
+	if len(fragments) != len(expected) {
+		t.Fatalf("bad fragments %v", fragments)
+	}
+	for i, f := range fragments {
+		if *f != expected[i] {
+			t.Fatalf("bad fragment %d: %v", i, f)
+		}
+	}
+
+	if _, err := p.CST.Tokens()[0].StringFragment(); err == nil {
+		t.Fatal("it should fail")
+	}
+}
@@ -0,0 +1,219 @@
+// Package dataflow implements a generic intra-procedural dataflow engine over
+// the control flow graphs of the cfg package, together with the reaching
+// definitions, live variables, def-use chains and taint analyses.
+//
+// The variables are identified by their declarations (*VariableDeclaration for
+// the parameters and the locals and *StateVariableDeclarationVariable for the
+// state variables), as resolved by the types package.
+package dataflow
+
+import (
+	"github.com/umbracle/solidity-parser-go/cfg"
+)
+
+// Direction is the direction in which the facts are propagated
+type Direction int
+
+const (
+	Forward Direction = iota
+	Backward
+)
+
+// Fact is an element of the lattice of an analysis
+type Fact interface{}
+
+// Lattice is the domain of the facts of an analysis
+type Lattice interface {
+	// Bottom is the initial fact of every block
+	Bottom() Fact
+
+	// Join merges the facts of two incoming paths
+	Join(a, b Fact) Fact
+
+	// Equal returns true if both facts are the same
+	Equal(a, b Fact) bool
+}
+
+// Analysis is a dataflow problem
+type Analysis interface {
+	Lattice
+
+	Direction() Direction
+
+	// Boundary is the fact at the entry block for the forward analyses and
+	// at the exit block for the backward analyses. Nothing flows out of the
+	// revert block.
+	Boundary() Fact
+
+	// Transfer returns the fact after the node is executed given the fact
+	// before it (in the direction of the analysis)
+	Transfer(node interface{}, fact Fact) Fact
+}
+
+// Result are the facts computed by the analysis. The facts are in program
+// order (In is the fact before the block is executed) for both directions.
+type Result struct {
+	In  map[*cfg.Block]Fact
+	Out map[*cfg.Block]Fact
+
+	// Before and After are the facts around each node of the graph
+	Before map[interface{}]Fact
+	After  map[interface{}]Fact
+}
+
+// Solve computes the fixed point of the analysis over the graph
+func Solve(g *cfg.CFG, a Analysis) *Result {
+	forward := a.Direction() == Forward
+
+	// the facts at the start (head) and at the end (tail) of the blocks in
+	// the direction of the analysis
+	head := map[*cfg.Block]Fact{}
+	tail := map[*cfg.Block]Fact{}
+	for _, b := range g.Blocks {
+		head[b] = a.Bottom()
+		tail[b] = a.Bottom()
+	}
+
+	boundary := g.Entry
+	if !forward {
+		boundary = g.Exit
+	}
+
+	inputs := func(b *cfg.Block) []*cfg.Block {
+		if forward {
+			return b.Preds
+		}
+		return b.Succs
+	}
+	outputs := func(b *cfg.Block) []*cfg.Block {
+		if forward {
+			return b.Succs
+		}
+		return b.Preds
+	}
+
+	queued := map[*cfg.Block]bool{}
+	worklist := []*cfg.Block{}
+	push := func(b *cfg.Block) {
+		if !queued[b] {
+			queued[b] = true
+			worklist = append(worklist, b)
+		}
+	}
+	if forward {
+		for _, b := range g.Blocks {
+			push(b)
+		}
+	} else {
+		for i := len(g.Blocks) - 1; i >= 0; i-- {
+			push(g.Blocks[i])
+		}
+	}
+
+	for len(worklist) != 0 {
+		b := worklist[0]
+		worklist = worklist[1:]
+		queued[b] = false
+
+		fact := a.Bottom()
+		if b == boundary {
+			fact = a.Boundary()
+		}
+		for _, in := range inputs(b) {
+			fact = a.Join(fact, tail[in])
+		}
+		head[b] = fact
+
+		out := transferBlock(a, b, fact, nil)
+		if !a.Equal(out, tail[b]) {
+			tail[b] = out
+			for _, next := range outputs(b) {
+				push(next)
+			}
+		}
+	}
+
+	res := &Result{
+		In:     map[*cfg.Block]Fact{},
+		Out:    map[*cfg.Block]Fact{},
+		Before: map[interface{}]Fact{},
+		After:  map[interface{}]Fact{},
+	}
+	for _, b := range g.Blocks {
+		if forward {
+			res.In[b], res.Out[b] = head[b], tail[b]
+		} else {
+			res.In[b], res.Out[b] = tail[b], head[b]
+		}
+		transferBlock(a, b, head[b], res)
+	}
+	return res
+}
+
+// transferBlock applies the transfer function to the nodes of the block
+// in the direction of the analysis and records the facts of the nodes
+func transferBlock(a Analysis, b *cfg.Block, fact Fact, res *Result) Fact {
+	n := len(b.Nodes)
+	for i := 0; i < n; i++ {
+		node := b.Nodes[i]
+		if a.Direction() == Backward {
+			node = b.Nodes[n-1-i]
+		}
+		next := a.Transfer(node, fact)
+		if res != nil {
+			if a.Direction() == Forward {
+				res.Before[node], res.After[node] = fact, next
+			} else {
+				res.Before[node], res.After[node] = next, fact
+			}
+		}
+		fact = next
+	}
+	return fact
+}
+
+// Set is a set of values. It is the fact of the analyses over the powerset lattice.
+type Set map[interface{}]struct{}
+
+// Has returns true if the value is in the set
+func (s Set) Has(v interface{}) bool {
+	_, ok := s[v]
+	return ok
+}
+
+// Copy returns a copy of the set
+func (s Set) Copy() Set {
+	res := Set{}
+	for v := range s {
+		res[v] = struct{}{}
+	}
+	return res
+}
+
+// SetLattice is the powerset lattice ordered by inclusion
+type SetLattice struct{}
+
+func (SetLattice) Bottom() Fact {
+	return Set{}
+}
+
+func (SetLattice) Join(a, b Fact) Fact {
+	res := a.(Set).Copy()
+	for v := range b.(Set) {
+		res[v] = struct{}{}
+	}
+	return res
+}
+
+func (SetLattice) Equal(a, b Fact) bool {
+	x, y := a.(Set), b.(Set)
+	if len(x) != len(y) {
+		return false
+	}
+	for v := range x {
+		if !y.Has(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,262 @@
+package dataflow
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/cfg"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// the body of the function starts at line 5
+const testContract = `contract C {
+    address owner;
+    mapping(address => uint) balances;
+    function f(uint x, bool c, address payable to) public returns (uint r) {
+%s
+    }
+    function g(address a) internal {
+        owner = a;
+    }
+}`
+
+func buildGraph(t *testing.T, body string, name string) (*cfg.CFG, *types.Info) {
+	t.Helper()
+
+	p := solcparser.NewProject()
+	if err := p.AddSource("a.sol", fmt.Sprintf(testContract, body)); err != nil {
+		t.Fatal(err)
+	}
+	info := types.Check(p)
+	for _, g := range cfg.ForProject(p) {
+		if fn, ok := g.Decl.(*solcparser.FunctionDefinition); ok && fn.Name == name {
+			return g, info
+		}
+	}
+	t.Fatalf("function %s not found", name)
+	return nil, nil
+}
+
+func varName(v interface{}) string {
+	switch obj := v.(type) {
+	case *solcparser.VariableDeclaration:
+		return obj.Name
+	case *solcparser.StateVariableDeclarationVariable:
+		return obj.Name
+	}
+	return "?"
+}
+
+// describe returns 'name:line' or 'name:entry' for the definitions without a node
+func describe(d *Definition) string {
+	if d.Node == nil {
+		return varName(d.Var) + ":entry"
+	}
+	return fmt.Sprintf("%s:%d", varName(d.Var), d.Loc().Start.Line)
+}
+
+func TestDefUseChains(t *testing.T) {
+	cases := []struct {
+		body string
+		// defs are the definitions that reach the uses of 'r'
+		defs []string
+	}{
+		{
+			"x = 1;\nif (c) { x = 2; }\nr = x;",
+			[]string{"x:5", "x:6"},
+		},
+		{
+			"x = 1;\nx = 2;\nr = x;",
+			[]string{"x:6"},
+		},
+		{
+			"r = x;",
+			[]string{"x:entry"},
+		},
+		{
+			"uint y = x;\nwhile (c) { y = y + 1; }\nr = y;",
+			[]string{"y:5", "y:6"},
+		},
+		{
+			"uint[] memory a = new uint[](1);\na[0] = x;\nr = a[0];",
+			[]string{"a:5", "a:6"},
+		},
+		{
+			"r = balances[owner];",
+			[]string{"balances:entry", "owner:entry"},
+		},
+	}
+
+	for _, c := range cases {
+		g, info := buildGraph(t, c.body, "f")
+		chains := DefUseChains(g, info)
+
+		found := []string{}
+		seen := map[string]bool{}
+		for use, defs := range chains.Defs {
+			ident, ok := use.Node.(*solcparser.ExpressionStatement)
+			if !ok || !strings.HasPrefix(exprString(ident), "r =") {
+				continue
+			}
+			for _, d := range defs {
+				if name := describe(d); !seen[name] {
+					seen[name] = true
+					found = append(found, name)
+				}
+			}
+		}
+		sort.Strings(found)
+		if !reflect.DeepEqual(found, c.defs) {
+			t.Fatalf("bad defs for %q: %v", c.body, found)
+		}
+	}
+}
+
+// exprString returns a short description of the assignment of a statement
+func exprString(stmt *solcparser.ExpressionStatement) string {
+	if op, ok := stmt.Expression.(*solcparser.BinaryOperation); ok {
+		if ident, ok := op.Left.(*solcparser.Identifier); ok {
+			return ident.Name + " " + op.Operator
+		}
+	}
+	return ""
+}
+
+func TestDeadStores(t *testing.T) {
+	cases := []struct {
+		body string
+		dead []string
+	}{
+		{
+			"uint y = x;\nr = y;",
+			[]string{},
+		},
+		{
+			"uint y = x;\ny = 2;\nr = y;",
+			[]string{"y:5"},
+		},
+		{
+			"x = 1;\nreturn 2;",
+			[]string{"x:5"},
+		},
+		{
+			"uint y;\nif (c) { y = 1; } else { y = 2; }\nr = y;",
+			[]string{},
+		},
+		{
+			"uint y = 1;\nwhile (c) { r = y; y = 2; }",
+			[]string{},
+		},
+		{
+			"owner = to;\nr = 1;\nr = 2;",
+			[]string{"r:6"},
+		},
+	}
+
+	for _, c := range cases {
+		g, info := buildGraph(t, c.body, "f")
+		found := []string{}
+		for _, d := range DeadStores(g, info) {
+			found = append(found, describe(d))
+		}
+		if !reflect.DeepEqual(found, c.dead) {
+			t.Fatalf("bad dead stores for %q: %v", c.body, found)
+		}
+	}
+}
+
+func TestLiveVariables(t *testing.T) {
+	g, info := buildGraph(t, "uint y = x;\nif (c) { r = y; }", "f")
+	res := Solve(g, NewLiveVariables(g, info))
+
+	live := []string{}
+	for v := range res.In[g.Entry].(Set) {
+		live = append(live, varName(v))
+	}
+	sort.Strings(live)
+
+	// 'r' is live since the named return keeps its value when c is false
+	if !reflect.DeepEqual(live, []string{"c", "r", "x"}) {
+		t.Fatalf("bad live variables %v", live)
+	}
+}
+
+func TestTaint(t *testing.T) {
+	cases := []struct {
+		body     string
+		name     string
+		findings []string
+	}{
+		{
+			"owner = msg.sender;",
+			"f",
+			[]string{"storage-write:5 msg.sender"},
+		},
+		{
+			"balances[msg.sender] = 1;",
+			"f",
+			[]string{"storage-write:5 msg.sender"},
+		},
+		{
+			"uint y = 1;\nbalances[owner] = y;",
+			"f",
+			[]string{},
+		},
+		{
+			"to.call{value: x}(\"\");",
+			"f",
+			[]string{"call:5 params"},
+		},
+		{
+			"address payable y = to;\ny = payable(owner);\ny.call(\"\");",
+			"f",
+			[]string{},
+		},
+		{
+			"address payable y = payable(owner);\nif (c) { y = payable(tx.origin); }\ny.delegatecall(\"\");",
+			"f",
+			[]string{"delegatecall:7 tx.origin"},
+		},
+		{
+			"selfdestruct(to);",
+			"f",
+			[]string{"selfdestruct:5 params"},
+		},
+		{
+			"",
+			"g",
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		g, info := buildGraph(t, c.body, c.name)
+		found := []string{}
+		for _, f := range Taint(g, info, nil) {
+			found = append(found, fmt.Sprintf("%s:%d %s", f.Sink, f.Loc().Start.Line, strings.Join(f.Sources, ", ")))
+		}
+		if !reflect.DeepEqual(found, c.findings) {
+			t.Fatalf("bad findings for %q: %v", c.body, found)
+		}
+	}
+}
+
+func TestTaintConfig(t *testing.T) {
+	g, info := buildGraph(t, "owner = msg.sender;\nselfdestruct(to);", "f")
+
+	config := &TaintConfig{
+		Sources: []string{SourceParams},
+		Sinks:   []string{SinkSelfdestruct, SinkStorageWrite},
+	}
+	findings := Taint(g, info, config)
+	if len(findings) != 1 || findings[0].Sink != SinkSelfdestruct {
+		t.Fatalf("bad findings %v", findings)
+	}
+	if findings[0].String() != "tainted data from params reaches selfdestruct" {
+		t.Fatal(findings[0].String())
+	}
+}
@@ -0,0 +1,110 @@
+package dataflow
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/cfg"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// LiveVariables is the backward analysis of the variables whose current value
+// may be read later. The facts are a Set of variable declarations.
+type LiveVariables struct {
+	SetLattice
+
+	// Exit are the variables live when the function returns: the state
+	// variables and the named returns
+	Exit []interface{}
+
+	acc map[interface{}]*accesses
+}
+
+// NewLiveVariables creates the live variables analysis of the graph
+func NewLiveVariables(g *cfg.CFG, info *types.Info) *LiveVariables {
+	l := &LiveVariables{
+		acc: graphAccesses(g, info),
+	}
+
+	exit := map[interface{}]bool{}
+	addExit := func(v interface{}) {
+		if !exit[v] {
+			exit[v] = true
+			l.Exit = append(l.Exit, v)
+		}
+	}
+	for _, acc := range l.acc {
+		for _, d := range acc.defs {
+			if IsStateVariable(d.Var) {
+				addExit(d.Var)
+			}
+		}
+	}
+	for _, p := range returnParams(g) {
+		addExit(p)
+	}
+	return l
+}
+
+func (l *LiveVariables) Direction() Direction {
+	return Backward
+}
+
+func (l *LiveVariables) Boundary() Fact {
+	res := Set{}
+	for _, v := range l.Exit {
+		res[v] = struct{}{}
+	}
+	return res
+}
+
+func (l *LiveVariables) Transfer(node interface{}, fact Fact) Fact {
+	acc := l.acc[node]
+	if acc == nil || (len(acc.defs) == 0 && len(acc.uses) == 0) {
+		return fact
+	}
+	res := fact.(Set).Copy()
+	for _, d := range acc.defs {
+		if !d.Weak {
+			delete(res, d.Var)
+		}
+	}
+	for _, u := range acc.uses {
+		res[u.Var] = struct{}{}
+	}
+	return res
+}
+
+// DeadStores returns the writes of local variables that are never read. The
+// declarations without a value, the try and catch parameters and the writes
+// through storage pointers are not reported.
+func DeadStores(g *cfg.CFG, info *types.Info) []*Definition {
+	l := NewLiveVariables(g, info)
+	res := Solve(g, l)
+
+	dead := []*Definition{}
+	reachable := g.Reachable()
+	for _, b := range g.Blocks {
+		if !reachable[b] {
+			continue
+		}
+		for _, node := range b.Nodes {
+			after, ok := res.After[node].(Set)
+			if !ok {
+				continue
+			}
+			if _, ok := node.(*solcparser.VariableDeclaration); ok {
+				// parameters of the try and catch clauses
+				continue
+			}
+			for _, d := range l.acc[node].defs {
+				if IsStateVariable(d.Var) || d.Weak || after.Has(d.Var) {
+					continue
+				}
+				if decl, ok := node.(*solcparser.VariableDeclarationStatement); ok && decl.InitialValue == nil {
+					continue
+				}
+				dead = append(dead, &Definition{Var: d.Var, Node: node, Expr: d.Expr, Weak: d.Weak})
+			}
+		}
+	}
+	return dead
+}
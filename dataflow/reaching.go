@@ -0,0 +1,187 @@
+package dataflow
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/cfg"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// Definition is a write of a variable
+type Definition struct {
+	Var interface{}
+
+	// Node is the node of the graph that writes the variable. It is nil for the
+	// initial values of the parameters and the state variables.
+	Node interface{}
+
+	// Expr is the identifier or the declaration written
+	Expr interface{}
+
+	// Weak definitions (i.e. 'a[i] = x') do not kill the previous ones
+	Weak bool
+}
+
+// Loc returns the position of the definition
+func (d *Definition) Loc() *solcparser.Location {
+	return locOf(d.Expr)
+}
+
+// Use is a read of a variable
+type Use struct {
+	Var  interface{}
+	Node interface{}
+
+	// Expr is the identifier read
+	Expr interface{}
+}
+
+// Loc returns the position of the use
+func (u *Use) Loc() *solcparser.Location {
+	return locOf(u.Expr)
+}
+
+func locOf(expr interface{}) *solcparser.Location {
+	if node, ok := expr.(solcparser.INode); ok {
+		return node.GetLoc()
+	}
+	return nil
+}
+
+// ReachingDefinitions is the forward analysis of the definitions that may
+// reach each point of the graph. The facts are a Set of *Definition.
+type ReachingDefinitions struct {
+	SetLattice
+
+	// Entry are the definitions of the parameters and state variables
+	Entry []*Definition
+
+	// Defs are the definitions of each node
+	Defs map[interface{}][]*Definition
+
+	// Uses are the uses of each node
+	Uses map[interface{}][]*Use
+}
+
+// NewReachingDefinitions creates the reaching definitions analysis of the graph
+func NewReachingDefinitions(g *cfg.CFG, info *types.Info) *ReachingDefinitions {
+	r := &ReachingDefinitions{
+		Defs: map[interface{}][]*Definition{},
+		Uses: map[interface{}][]*Use{},
+	}
+
+	entry := map[interface{}]bool{}
+	addEntry := func(v, expr interface{}) {
+		if !entry[v] {
+			entry[v] = true
+			r.Entry = append(r.Entry, &Definition{Var: v, Expr: expr})
+		}
+	}
+	for _, p := range params(g) {
+		addEntry(p, p)
+	}
+
+	for _, b := range g.Blocks {
+		for _, node := range b.Nodes {
+			if _, ok := r.Defs[node]; ok {
+				continue
+			}
+			acc := collectAccesses(info, node)
+			defs := []*Definition{}
+			for _, d := range acc.defs {
+				defs = append(defs, &Definition{Var: d.Var, Node: node, Expr: d.Expr, Weak: d.Weak})
+			}
+			uses := []*Use{}
+			for _, u := range acc.uses {
+				uses = append(uses, &Use{Var: u.Var, Node: node, Expr: u.Expr})
+				if IsStateVariable(u.Var) {
+					addEntry(u.Var, u.Var)
+				}
+			}
+			r.Defs[node] = defs
+			r.Uses[node] = uses
+		}
+	}
+	return r
+}
+
+func (r *ReachingDefinitions) Direction() Direction {
+	return Forward
+}
+
+func (r *ReachingDefinitions) Boundary() Fact {
+	res := Set{}
+	for _, d := range r.Entry {
+		res[d] = struct{}{}
+	}
+	return res
+}
+
+func (r *ReachingDefinitions) Transfer(node interface{}, fact Fact) Fact {
+	defs := r.Defs[node]
+	if len(defs) == 0 {
+		return fact
+	}
+	res := fact.(Set).Copy()
+	for _, d := range defs {
+		if !d.Weak {
+			for v := range res {
+				if v.(*Definition).Var == d.Var {
+					delete(res, v)
+				}
+			}
+		}
+		res[d] = struct{}{}
+	}
+	return res
+}
+
+// DefUse are the def-use chains of a graph
+type DefUse struct {
+	// Uses are the uses reached by each definition
+	Uses map[*Definition][]*Use
+
+	// Defs are the definitions that may reach each use
+	Defs map[*Use][]*Definition
+}
+
+// DefUseChains computes the def-use chains of the locals, parameters and state variables
+func DefUseChains(g *cfg.CFG, info *types.Info) *DefUse {
+	r := NewReachingDefinitions(g, info)
+	res := Solve(g, r)
+
+	chains := &DefUse{
+		Uses: map[*Definition][]*Use{},
+		Defs: map[*Use][]*Definition{},
+	}
+	for _, b := range g.Blocks {
+		for _, node := range b.Nodes {
+			before, ok := res.Before[node].(Set)
+			if !ok {
+				continue
+			}
+			for _, u := range r.Uses[node] {
+				if _, ok := chains.Defs[u]; ok {
+					continue
+				}
+				chains.Defs[u] = []*Definition{}
+				for _, d := range sortedDefs(before) {
+					if d.Var == u.Var {
+						chains.Defs[u] = append(chains.Defs[u], d)
+						chains.Uses[d] = append(chains.Uses[d], u)
+					}
+				}
+			}
+		}
+	}
+	return chains
+}
+
+// sortedDefs returns the definitions of the set in source order
+func sortedDefs(s Set) []*Definition {
+	res := []*Definition{}
+	for v := range s {
+		res = append(res, v.(*Definition))
+	}
+	sortByLoc(res, func(i int) *solcparser.Location { return res[i].Loc() })
+	return res
+}
@@ -0,0 +1,381 @@
+package dataflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/cfg"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// Sources of tainted data
+const (
+	SourceMsgSender = "msg.sender"
+	SourceMsgValue  = "msg.value"
+	SourceMsgData   = "msg.data"
+	SourceTxOrigin  = "tx.origin"
+
+	// SourceParams are the parameters of the public and external functions
+	SourceParams = "params"
+)
+
+// Sinks of tainted data
+const (
+	SinkCall         = "call"
+	SinkDelegatecall = "delegatecall"
+	SinkStaticcall   = "staticcall"
+	SinkSelfdestruct = "selfdestruct"
+	SinkStorageWrite = "storage-write"
+)
+
+// TaintConfig are the sources and the sinks of the taint analysis
+type TaintConfig struct {
+	Sources []string
+	Sinks   []string
+}
+
+// DefaultTaintConfig returns a config with all the sources and sinks
+func DefaultTaintConfig() *TaintConfig {
+	return &TaintConfig{
+		Sources: []string{SourceMsgSender, SourceMsgValue, SourceMsgData, SourceTxOrigin, SourceParams},
+		Sinks:   []string{SinkCall, SinkDelegatecall, SinkStaticcall, SinkSelfdestruct, SinkStorageWrite},
+	}
+}
+
+func (c *TaintConfig) hasSource(source string) bool {
+	return contains(c.Sources, source)
+}
+
+func (c *TaintConfig) hasSink(sink string) bool {
+	return contains(c.Sinks, sink)
+}
+
+func contains(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// TaintFinding is tainted data that reaches a sink
+type TaintFinding struct {
+	Sink string
+
+	// Sources are the sources of the tainted data
+	Sources []string
+
+	// Node is the expression of the sink
+	Node interface{}
+}
+
+// Loc returns the position of the sink
+func (f *TaintFinding) Loc() *solcparser.Location {
+	return locOf(f.Node)
+}
+
+func (f *TaintFinding) String() string {
+	return fmt.Sprintf("tainted data from %s reaches %s", strings.Join(f.Sources, ", "), f.Sink)
+}
+
+// taintFact are the sources that may taint each variable
+type taintFact map[interface{}]Set
+
+// TaintAnalysis is the forward analysis of the sources that may taint each
+// variable. The facts are a map from the variable to the Set of sources.
+type TaintAnalysis struct {
+	g      *cfg.CFG
+	info   *types.Info
+	config *TaintConfig
+}
+
+// NewTaintAnalysis creates the taint analysis of the graph
+func NewTaintAnalysis(g *cfg.CFG, info *types.Info, config *TaintConfig) *TaintAnalysis {
+	if config == nil {
+		config = DefaultTaintConfig()
+	}
+	return &TaintAnalysis{g: g, info: info, config: config}
+}
+
+func (t *TaintAnalysis) Direction() Direction {
+	return Forward
+}
+
+func (t *TaintAnalysis) Bottom() Fact {
+	return taintFact{}
+}
+
+func (t *TaintAnalysis) Join(a, b Fact) Fact {
+	res := a.(taintFact).copy()
+	for v, sources := range b.(taintFact) {
+		res.add(v, sources)
+	}
+	return res
+}
+
+func (t *TaintAnalysis) Equal(a, b Fact) bool {
+	x, y := a.(taintFact), b.(taintFact)
+	if len(x) != len(y) {
+		return false
+	}
+	for v, sources := range x {
+		other, ok := y[v]
+		if !ok || !(SetLattice{}).Equal(sources, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *TaintAnalysis) Boundary() Fact {
+	res := taintFact{}
+	fn, ok := t.g.Decl.(*solcparser.FunctionDefinition)
+	if !ok || !t.config.hasSource(SourceParams) {
+		return res
+	}
+	switch fn.Visibility {
+	case "public", "external", "default", "":
+		for _, p := range fn.Parameters {
+			if decl, ok := p.(*solcparser.VariableDeclaration); ok {
+				res[decl] = Set{SourceParams: struct{}{}}
+			}
+		}
+	}
+	return res
+}
+
+func (f taintFact) copy() taintFact {
+	res := taintFact{}
+	for v, sources := range f {
+		res[v] = sources
+	}
+	return res
+}
+
+// add merges the sources of a variable without modifying the shared sets
+func (f taintFact) add(v interface{}, sources Set) {
+	if len(sources) == 0 {
+		return
+	}
+	if prev, ok := f[v]; ok {
+		f[v] = (SetLattice{}).Join(prev, sources).(Set)
+	} else {
+		f[v] = sources
+	}
+}
+
+func (f taintFact) set(v interface{}, sources Set) {
+	if len(sources) == 0 {
+		delete(f, v)
+	} else {
+		f[v] = sources
+	}
+}
+
+func (t *TaintAnalysis) variable(expr interface{}) interface{} {
+	decl := t.info.RefOf(expr)
+	if !IsVariable(decl) {
+		return nil
+	}
+	return decl
+}
+
+// TaintOf returns the sources that may taint the value of an expression given
+// the fact before the node that contains it
+func (t *TaintAnalysis) TaintOf(expr interface{}, fact Fact) Set {
+	return t.taintOf(expr, fact.(taintFact))
+}
+
+// taintOf returns the sources that may taint the value of an expression
+func (t *TaintAnalysis) taintOf(expr interface{}, fact taintFact) Set {
+	res := Set{}
+	solcparser.Inspect(expr, func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *solcparser.MemberAccess:
+			if ident, ok := obj.Expression.(*solcparser.Identifier); ok {
+				source := ident.Name + "." + obj.MemberName
+				switch source {
+				case SourceMsgSender, SourceMsgValue, SourceMsgData, SourceTxOrigin:
+					if t.config.hasSource(source) && t.info.RefOf(ident) == nil {
+						res[source] = struct{}{}
+					}
+					return false
+				}
+			}
+
+		case *solcparser.Identifier:
+			if v := t.variable(obj); v != nil {
+				for source := range fact[v] {
+					res[source] = struct{}{}
+				}
+			}
+		}
+		return true
+	})
+	return res
+}
+
+func (t *TaintAnalysis) Transfer(node interface{}, fact Fact) Fact {
+	in := fact.(taintFact)
+	res := in.copy()
+
+	switch obj := node.(type) {
+	case *solcparser.VariableDeclarationStatement:
+		sources := Set{}
+		if obj.InitialValue != nil {
+			sources = t.taintOf(obj.InitialValue, in)
+		}
+		for _, v := range obj.Variables {
+			if decl, ok := v.(*solcparser.VariableDeclaration); ok {
+				res.set(decl, sources)
+			}
+		}
+		return res
+
+	case *solcparser.VariableDeclaration:
+		res.set(obj, nil)
+		return res
+	}
+
+	solcparser.Inspect(node, func(n interface{}) bool {
+		switch obj := n.(type) {
+		case *solcparser.BinaryOperation:
+			if !isAssignment(obj.Operator) {
+				return true
+			}
+			sources := t.taintOf(obj.Right, in)
+			if obj.Operator != "=" {
+				sources = (SetLattice{}).Join(sources, t.taintOf(obj.Left, in)).(Set)
+			}
+			t.assign(res, obj.Left, obj.Right, sources, in)
+
+		case *solcparser.UnaryOperation:
+			if obj.Operator == "delete" {
+				if v := t.variable(obj.SubExpression); v != nil {
+					res.set(v, nil)
+				}
+			}
+		}
+		return true
+	})
+	return res
+}
+
+func (t *TaintAnalysis) assign(res taintFact, lhs, rhs interface{}, sources Set, in taintFact) {
+	switch obj := lhs.(type) {
+	case *solcparser.Identifier:
+		if v := t.variable(obj); v != nil {
+			res.set(v, sources)
+		}
+
+	case *solcparser.TupleExpression:
+		values, ok := rhs.(*solcparser.TupleExpression)
+		for i, comp := range obj.Components {
+			if comp == nil {
+				continue
+			}
+			if ok && len(values.Components) == len(obj.Components) {
+				t.assign(res, comp, values.Components[i], t.taintOf(values.Components[i], in), in)
+			} else {
+				t.assign(res, comp, nil, sources, in)
+			}
+		}
+
+	default:
+		// partial writes do not remove the previous sources
+		if ident := baseIdentifier(lhs); ident != nil {
+			if v := t.variable(ident); v != nil {
+				res.add(v, sources)
+			}
+		}
+	}
+}
+
+// sinks returns the tainted sinks of a node given the fact before it
+func (t *TaintAnalysis) sinks(node interface{}, fact taintFact) []*TaintFinding {
+	res := []*TaintFinding{}
+	report := func(sink string, expr interface{}, sources Set) {
+		if len(sources) == 0 || !t.config.hasSink(sink) {
+			return
+		}
+		labels := []string{}
+		for s := range sources {
+			labels = append(labels, s.(string))
+		}
+		sort.Strings(labels)
+		res = append(res, &TaintFinding{Sink: sink, Sources: labels, Node: expr})
+	}
+
+	if _, ok := node.(*solcparser.VariableDeclaration); ok {
+		return res
+	}
+
+	solcparser.Inspect(node, func(n interface{}) bool {
+		switch obj := n.(type) {
+		case *solcparser.FunctionCall:
+			callee := obj.Expression
+			if nv, ok := callee.(*solcparser.NameValueExpression); ok {
+				// call{value: x}(...)
+				callee = nv.Expression
+			}
+			switch c := callee.(type) {
+			case *solcparser.MemberAccess:
+				switch c.MemberName {
+				case "call", "delegatecall", "staticcall":
+					sources := t.taintOf(c.Expression, fact)
+					for _, arg := range obj.Arguments {
+						sources = (SetLattice{}).Join(sources, t.taintOf(arg, fact)).(Set)
+					}
+					report(c.MemberName, obj, sources)
+				}
+			case *solcparser.Identifier:
+				if (c.Name == "selfdestruct" || c.Name == "suicide") && len(obj.Arguments) == 1 {
+					report(SinkSelfdestruct, obj, t.taintOf(obj.Arguments[0], fact))
+				}
+			}
+
+		case *solcparser.BinaryOperation:
+			if !isAssignment(obj.Operator) {
+				return true
+			}
+			if ident := baseIdentifier(obj.Left); ident != nil && IsStateVariable(t.info.RefOf(ident)) {
+				// tainted values or keys written to storage
+				sources := t.taintOf(obj.Right, fact)
+				if _, ok := obj.Left.(*solcparser.Identifier); !ok {
+					sources = (SetLattice{}).Join(sources, t.taintOf(obj.Left, fact)).(Set)
+				}
+				report(SinkStorageWrite, obj, sources)
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// Taint returns the sinks reached by tainted data in the graph
+func Taint(g *cfg.CFG, info *types.Info, config *TaintConfig) []*TaintFinding {
+	t := NewTaintAnalysis(g, info, config)
+	res := Solve(g, t)
+
+	findings := []*TaintFinding{}
+	seen := map[interface{}]bool{}
+	reachable := g.Reachable()
+	for _, b := range g.Blocks {
+		if !reachable[b] {
+			continue
+		}
+		for _, node := range b.Nodes {
+			before, ok := res.Before[node].(taintFact)
+			if !ok || seen[node] {
+				continue
+			}
+			seen[node] = true
+			findings = append(findings, t.sinks(node, before)...)
+		}
+	}
+	sortByLoc(findings, func(i int) *solcparser.Location { return findings[i].Loc() })
+	return findings
+}
@@ -0,0 +1,246 @@
+package dataflow
+
+import (
+	"sort"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/cfg"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// access is a read or a write of a variable in a node of the graph
+type access struct {
+	Var interface{}
+
+	// Expr is the identifier or the declaration of the variable
+	Expr interface{}
+
+	// Weak writes (i.e. 'a[i] = x') only update part of the variable
+	Weak bool
+}
+
+// accesses are the variables read and written by a node
+type accesses struct {
+	defs []*access
+	uses []*access
+}
+
+// IsVariable returns true if the declaration is a local, parameter or state variable
+func IsVariable(decl interface{}) bool {
+	switch decl.(type) {
+	case *solcparser.VariableDeclaration, *solcparser.StateVariableDeclarationVariable:
+		return true
+	}
+	return false
+}
+
+// IsStateVariable returns true if the declaration is a state variable
+func IsStateVariable(decl interface{}) bool {
+	_, ok := decl.(*solcparser.StateVariableDeclarationVariable)
+	return ok
+}
+
+type varCollector struct {
+	info *types.Info
+	acc  *accesses
+}
+
+// collectAccesses returns the variables read and written by a node of the graph
+func collectAccesses(info *types.Info, node interface{}) *accesses {
+	c := &varCollector{info: info, acc: &accesses{}}
+
+	switch obj := node.(type) {
+	case *solcparser.VariableDeclarationStatement:
+		if obj.InitialValue != nil {
+			c.expr(obj.InitialValue)
+		}
+		for _, v := range obj.Variables {
+			if decl, ok := v.(*solcparser.VariableDeclaration); ok {
+				c.def(decl, decl, false)
+			}
+		}
+
+	case *solcparser.VariableDeclaration:
+		c.def(obj, obj, false)
+
+	case *solcparser.InlineAssemblyStatement:
+		// the assembly blocks are not analyzed
+
+	default:
+		c.expr(node)
+	}
+	return c.acc
+}
+
+func (c *varCollector) def(decl, expr interface{}, weak bool) {
+	c.acc.defs = append(c.acc.defs, &access{Var: decl, Expr: expr, Weak: weak})
+}
+
+func (c *varCollector) use(decl, expr interface{}) {
+	c.acc.uses = append(c.acc.uses, &access{Var: decl, Expr: expr})
+}
+
+// variable returns the variable referenced by the identifier if any
+func (c *varCollector) variable(expr interface{}) interface{} {
+	ident, ok := expr.(*solcparser.Identifier)
+	if !ok {
+		return nil
+	}
+	decl := c.info.RefOf(ident)
+	if !IsVariable(decl) {
+		return nil
+	}
+	return decl
+}
+
+func (c *varCollector) expr(e interface{}) {
+	solcparser.Inspect(e, func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *solcparser.Identifier:
+			if decl := c.variable(obj); decl != nil {
+				c.use(decl, obj)
+			}
+
+		case *solcparser.BinaryOperation:
+			if isAssignment(obj.Operator) {
+				c.expr(obj.Right)
+				c.assign(obj.Left, obj.Operator != "=")
+				return false
+			}
+
+		case *solcparser.UnaryOperation:
+			switch obj.Operator {
+			case "++", "--":
+				c.assign(obj.SubExpression, true)
+				return false
+			case "delete":
+				c.assign(obj.SubExpression, false)
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// assign records the write of the left hand side of an assignment
+func (c *varCollector) assign(lhs interface{}, compound bool) {
+	switch obj := lhs.(type) {
+	case *solcparser.Identifier:
+		if decl := c.variable(obj); decl != nil {
+			if compound {
+				c.use(decl, obj)
+			}
+			c.def(decl, obj, false)
+		}
+
+	case *solcparser.TupleExpression:
+		for _, comp := range obj.Components {
+			if comp != nil {
+				c.assign(comp, compound)
+			}
+		}
+
+	case *solcparser.IndexAccess, *solcparser.MemberAccess, *solcparser.IndexRangeAccess:
+		// the indexes and the base are read and the base is partially written
+		c.expr(obj)
+		if ident := baseIdentifier(obj); ident != nil {
+			if decl := c.variable(ident); decl != nil {
+				c.def(decl, ident, true)
+			}
+		}
+
+	default:
+		c.expr(lhs)
+	}
+}
+
+// baseIdentifier returns the variable at the root of an lvalue (i.e. 'a' in 'a[i].b')
+func baseIdentifier(expr interface{}) *solcparser.Identifier {
+	for {
+		switch obj := expr.(type) {
+		case *solcparser.Identifier:
+			return obj
+		case *solcparser.IndexAccess:
+			expr = obj.Base
+		case *solcparser.IndexRangeAccess:
+			expr = obj.Base
+		case *solcparser.MemberAccess:
+			expr = obj.Expression
+		case *solcparser.TupleExpression:
+			if len(obj.Components) != 1 {
+				return nil
+			}
+			expr = obj.Components[0]
+		default:
+			return nil
+		}
+	}
+}
+
+func isAssignment(op string) bool {
+	switch op {
+	case "=", "+=", "-=", "*=", "/=", "%=", "|=", "&=", "^=", "<<=", ">>=":
+		return true
+	}
+	return false
+}
+
+// params returns the parameters and the named returns of the function of the graph
+func params(g *cfg.CFG) []*solcparser.VariableDeclaration {
+	var lists []interface{}
+	switch obj := g.Decl.(type) {
+	case *solcparser.FunctionDefinition:
+		lists = []interface{}{obj.Parameters, obj.ReturnParameters}
+	case *solcparser.ModifierDefinition:
+		lists = []interface{}{obj.Parameters}
+	}
+
+	res := []*solcparser.VariableDeclaration{}
+	for _, list := range lists {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			if decl, ok := item.(*solcparser.VariableDeclaration); ok {
+				res = append(res, decl)
+			}
+		}
+	}
+	return res
+}
+
+// returnParams returns the named returns of the function of the graph
+func returnParams(g *cfg.CFG) []*solcparser.VariableDeclaration {
+	res := []*solcparser.VariableDeclaration{}
+	if fn, ok := g.Decl.(*solcparser.FunctionDefinition); ok {
+		items, _ := fn.ReturnParameters.([]interface{})
+		for _, item := range items {
+			if decl, ok := item.(*solcparser.VariableDeclaration); ok && decl.Name != "" {
+				res = append(res, decl)
+			}
+		}
+	}
+	return res
+}
+
+// graphAccesses computes the accesses of all the nodes of the graph
+func graphAccesses(g *cfg.CFG, info *types.Info) map[interface{}]*accesses {
+	res := map[interface{}]*accesses{}
+	for _, b := range g.Blocks {
+		for _, node := range b.Nodes {
+			if _, ok := res[node]; !ok {
+				res[node] = collectAccesses(info, node)
+			}
+		}
+	}
+	return res
+}
+
+// sortByLoc sorts the slice in source order, the values without position go first
+func sortByLoc(slice interface{}, loc func(i int) *solcparser.Location) {
+	sort.SliceStable(slice, func(i, j int) bool {
+		a, b := loc(i), loc(j)
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.Start.Offset < b.Start.Offset
+	})
+}
@@ -0,0 +1,318 @@
+package solcparser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	treesitter "github.com/umbracle/solidity-parser-go/tree-sitter"
+)
+
+// Document is a source and its tree-sitter syntax tree. The tree is reparsed
+// incrementally after each edit, the parts of the old tree that the edits do
+// not touch are reused.
+//
+// The points of the nodes of a reparsed tree are not reliable since the
+// bindings do not pass the new end point of the edits to tree-sitter, the
+// points of the document are computed from the byte offsets instead.
+type Document struct {
+	src    []byte
+	parser *sitter.Parser
+	tree   *sitter.Tree
+
+	// lineStarts is the offset at which each line of the source starts
+	lineStarts []int
+}
+
+// DocumentChange is the result of the edits of a document
+type DocumentChange struct {
+	// Ranges are the ranges of the new tree whose syntax changed or whose
+	// text was edited, they do not overlap and are sorted
+	Ranges []sitter.Range
+
+	// Declarations are the top level nodes of the new tree (contracts,
+	// functions, pragmas...) that intersect the ranges
+	Declarations []*sitter.Node
+}
+
+// NewDocument parses a source with tree-sitter
+func NewDocument(src string) (*Document, error) {
+	d := &Document{
+		src:        []byte(src),
+		parser:     sitter.NewParser(),
+		lineStarts: append([]int{0}, lineStarts(src, 0)...),
+	}
+	d.parser.SetLanguage(treesitter.GetLanguage())
+
+	tree, err := d.parser.ParseCtx(context.Background(), nil, d.src)
+	if err != nil {
+		return nil, err
+	}
+	d.tree = tree
+	return d, nil
+}
+
+// Source returns the current source of the document
+func (d *Document) Source() string {
+	return string(d.src)
+}
+
+// Tree returns the current syntax tree of the document
+func (d *Document) Tree() *sitter.Tree {
+	return d.tree
+}
+
+// RootNode returns the root of the current syntax tree
+func (d *Document) RootNode() *sitter.Node {
+	return d.tree.RootNode()
+}
+
+// AST converts the current syntax tree into the AST, it is the result of
+// Parse(src, WithBackend(TreeSitter)) without parsing the source again
+func (d *Document) AST(opts ...Option) *Parser {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return treeSitterResult(d.src, d.tree.RootNode(), cfg)
+}
+
+// Errors returns the syntax errors of the current syntax tree
+func (d *Document) Errors() []*SyntaxError {
+	return treeSitterErrors(d.src, newSourceMap(string(d.src)), d.tree.RootNode())
+}
+
+// Declaration converts a top level node of the syntax tree into the AST
+// (i.e. one of the declarations of a change)
+func (d *Document) Declaration(n *sitter.Node, opts ...Option) interface{} {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	b := &treeSitterBuilder{src: d.src}
+	if cfg.locations {
+		b.srcMap = newSourceMap(string(d.src))
+	}
+	return b.declaration(n)
+}
+
+// Point returns the point (zero based row and byte column) of an offset
+func (d *Document) Point(offset int) sitter.Point {
+	row := sort.Search(len(d.lineStarts), func(i int) bool {
+		return d.lineStarts[i] > offset
+	}) - 1
+	return sitter.Point{
+		Row:    uint32(row),
+		Column: uint32(offset - d.lineStarts[row]),
+	}
+}
+
+// Offset returns the offset of a point, it fails if the point is out of the source
+func (d *Document) Offset(p sitter.Point) (int, error) {
+	if int(p.Row) >= len(d.lineStarts) {
+		return 0, fmt.Errorf("point %d:%d is out of the source", p.Row, p.Column)
+	}
+	start, end := d.lineStarts[p.Row], len(d.src)
+	if int(p.Row)+1 < len(d.lineStarts) {
+		// the end of the line before the newline
+		end = d.lineStarts[p.Row+1] - 1
+	}
+	if start+int(p.Column) > end {
+		return 0, fmt.Errorf("point %d:%d is out of the source", p.Row, p.Column)
+	}
+	return start + int(p.Column), nil
+}
+
+// replace replaces the text of an edit in the source and updates the line starts
+func (d *Document) replace(edit TextEdit) {
+	src := make([]byte, 0, len(d.src)+len(edit.NewText)-(edit.End-edit.Start))
+	src = append(src, d.src[:edit.Start]...)
+	src = append(src, edit.NewText...)
+	d.src = append(src, d.src[edit.End:]...)
+
+	// the lines that start before the edit are kept, the ones that start in
+	// the replaced text are replaced by the lines of the new text and the
+	// ones after it are moved
+	first := sort.Search(len(d.lineStarts), func(i int) bool {
+		return d.lineStarts[i] > edit.Start
+	})
+	last := sort.Search(len(d.lineStarts), func(i int) bool {
+		return d.lineStarts[i] > edit.End
+	})
+	delta := len(edit.NewText) - (edit.End - edit.Start)
+
+	starts := append([]int{}, d.lineStarts[:first]...)
+	starts = append(starts, lineStarts(edit.NewText, edit.Start)...)
+	for _, start := range d.lineStarts[last:] {
+		starts = append(starts, start+delta)
+	}
+	d.lineStarts = starts
+}
+
+// lineStarts returns the offsets of the lines that start after the newlines
+// of a text placed at the given offset
+func lineStarts(text string, offset int) []int {
+	res := []int{}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			res = append(res, offset+i+1)
+		}
+	}
+	return res
+}
+
+// EditPoints replaces the text between two points and reparses the document
+func (d *Document) EditPoints(start, end sitter.Point, newText string) (*DocumentChange, error) {
+	startOffset, err := d.Offset(start)
+	if err != nil {
+		return nil, err
+	}
+	endOffset, err := d.Offset(end)
+	if err != nil {
+		return nil, err
+	}
+	return d.Edit(TextEdit{Start: startOffset, End: endOffset, NewText: newText})
+}
+
+// Edit applies the edits in order and reparses the document once. Unlike the
+// edits of a fix, each edit refers to the source after the edits before it.
+func (d *Document) Edit(edits ...TextEdit) (*DocumentChange, error) {
+	size := len(d.src)
+	for _, edit := range edits {
+		if edit.Start < 0 || edit.Start > edit.End || edit.End > size {
+			return nil, fmt.Errorf("edit [%d, %d) is out of the source", edit.Start, edit.End)
+		}
+		size += len(edit.NewText) - (edit.End - edit.Start)
+	}
+
+	// the old tree is kept to compare it with the new one
+	oldTree := d.tree.Copy()
+
+	// the edited ranges in the offsets of the new source
+	edited := []byteRange{}
+	for _, edit := range edits {
+		newEnd := edit.Start + len(edit.NewText)
+		delta := newEnd - edit.End
+		for i, r := range edited {
+			if r.start >= edit.End {
+				edited[i] = byteRange{r.start + delta, r.end + delta}
+			} else if r.end >= edit.Start {
+				edited[i] = byteRange{minInt(r.start, edit.Start), maxInt(r.end+delta, newEnd)}
+			}
+		}
+		edited = append(edited, byteRange{edit.Start, newEnd})
+
+		input := sitter.EditInput{
+			StartIndex:  uint32(edit.Start),
+			OldEndIndex: uint32(edit.End),
+			NewEndIndex: uint32(newEnd),
+			StartPoint:  d.Point(edit.Start),
+			OldEndPoint: d.Point(edit.End),
+		}
+		d.replace(edit)
+
+		input.NewEndPoint = d.Point(newEnd)
+		oldTree.Edit(input)
+		d.tree.Edit(input)
+	}
+
+	tree, err := d.parser.ParseCtx(context.Background(), d.tree, d.src)
+	if err != nil {
+		return nil, err
+	}
+	d.tree = tree
+
+	ranges := edited
+	changedRanges(oldTree.RootNode(), tree.RootNode(), &ranges)
+	oldTree.Close()
+	ranges = mergeRanges(ranges)
+
+	change := &DocumentChange{
+		Ranges:       []sitter.Range{},
+		Declarations: []*sitter.Node{},
+	}
+	for _, r := range ranges {
+		change.Ranges = append(change.Ranges, sitter.Range{
+			StartByte:  uint32(r.start),
+			EndByte:    uint32(r.end),
+			StartPoint: d.Point(r.start),
+			EndPoint:   d.Point(r.end),
+		})
+	}
+	for _, decl := range namedChildren(tree.RootNode()) {
+		start, end := int(decl.StartByte()), int(decl.EndByte())
+		for _, r := range ranges {
+			// an empty range is the deletion of the text at its offset
+			if r.start < end && start < maxInt(r.end, r.start+1) {
+				change.Declarations = append(change.Declarations, decl)
+				break
+			}
+		}
+	}
+	return change, nil
+}
+
+// Close frees the syntax tree of the document
+func (d *Document) Close() {
+	d.tree.Close()
+}
+
+// byteRange is a range of offsets of the source, end is exclusive
+type byteRange struct {
+	start, end int
+}
+
+// changedRanges adds the ranges of the new tree whose syntax is different in
+// the old tree. The old tree has the same edits as the source so the offsets
+// of the nodes of both trees are comparable.
+func changedRanges(old, new *sitter.Node, ranges *[]byteRange) {
+	// the changes of the text of a leaf are in the edited ranges and the
+	// leaves after an edit of the whitespace are marked as changed too
+	sameRange := old.StartByte() == new.StartByte() && old.EndByte() == new.EndByte()
+	if old.Type() == new.Type() && sameRange && (!old.HasChanges() || new.ChildCount() == 0) {
+		return
+	}
+
+	oldChildren, newChildren := allChildren(old), allChildren(new)
+	if old.Type() != new.Type() || len(oldChildren) != len(newChildren) || len(newChildren) == 0 {
+		*ranges = append(*ranges, byteRange{
+			start: minInt(int(old.StartByte()), int(new.StartByte())),
+			end:   maxInt(int(old.EndByte()), int(new.EndByte())),
+		})
+		return
+	}
+	for i := range newChildren {
+		changedRanges(oldChildren[i], newChildren[i], ranges)
+	}
+}
+
+// mergeRanges sorts the ranges and merges the ones that overlap or touch
+func mergeRanges(ranges []byteRange) []byteRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+	res := []byteRange{}
+	for _, r := range ranges {
+		if last := len(res) - 1; last >= 0 && r.start <= res[last].end {
+			res[last].end = maxInt(res[last].end, r.end)
+			continue
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,184 @@
+package solcparser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+func TestDocumentIncremental(t *testing.T) {
+	d, err := NewDocument("contract A {\n  uint a;\n}\n\ncontract B {\n  function f() public { a = 1; }\n}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	edits := [][]TextEdit{
+		// rename a variable
+		{{Start: 20, End: 21, NewText: "bb"}},
+		// insert a statement and a function
+		{{Start: 70, End: 70, NewText: " b = 2;"}, {Start: 80, End: 80, NewText: "  function g() {}\n"}},
+		// delete the first contract
+		{{Start: 0, End: 27, NewText: ""}},
+		// an invalid statement
+		{{Start: 36, End: 36, NewText: "if ("}},
+	}
+
+	for _, edit := range edits {
+		if _, err := d.Edit(edit...); err != nil {
+			t.Fatal(err)
+		}
+
+		// the reparsed tree is the same as a new one
+		root, err := NewTreeSitterCtx(context.Background(), d.Source())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found, expected := d.RootNode().String(), root.String(); found != expected {
+			t.Fatalf("bad tree for '%s'\n%s\n%s", d.Source(), found, expected)
+		}
+		found := d.AST(WithLocations())
+		expected := Parse(d.Source(), WithLocations(), WithBackend(TreeSitter))
+		if !reflect.DeepEqual(found.Result, expected.Result) || len(found.Errors) != len(expected.Errors) {
+			t.Fatalf("bad AST for '%s'", d.Source())
+		}
+		if len(d.Errors()) != len(expected.Errors) {
+			t.Fatalf("bad errors for '%s'", d.Source())
+		}
+	}
+}
+
+func TestDocumentChange(t *testing.T) {
+	src := "pragma solidity ^0.8.0;\n\ncontract A {\n  uint a;\n}\n\ncontract B {\n  function f() public { a = 1; }\n}\n"
+
+	cases := []struct {
+		edit         TextEdit
+		declarations []string
+	}{
+		{
+			// the value of the assignment
+			TextEdit{Start: 92, End: 93, NewText: "22"},
+			[]string{"contract_declaration"},
+		},
+		{
+			// the version of the pragma and the name of the first contract
+			TextEdit{Start: 17, End: 35, NewText: "0.8.1;\n\ncontract C"},
+			[]string{"pragma_directive", "contract_declaration"},
+		},
+		{
+			// the whitespace between the contracts
+			TextEdit{Start: 50, End: 50, NewText: "\n\n"},
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		d, err := NewDocument(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		change, err := d.Edit(c.edit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		types := []string{}
+		for _, decl := range change.Declarations {
+			types = append(types, decl.Type())
+		}
+		if !reflect.DeepEqual(types, c.declarations) {
+			t.Fatalf("bad declarations for %v: %v", c.edit, types)
+		}
+
+		// the ranges cover the new text
+		covered := false
+		for _, r := range change.Ranges {
+			if int(r.StartByte) <= c.edit.Start && c.edit.Start+len(c.edit.NewText) <= int(r.EndByte) {
+				covered = true
+			}
+		}
+		if !covered {
+			t.Fatalf("edit %v not covered by %v", c.edit, change.Ranges)
+		}
+		d.Close()
+	}
+}
+
+func TestDocumentEditPoints(t *testing.T) {
+	d, err := NewDocument("contract A {\n  uint a;\n}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	change, err := d.EditPoints(sitter.Point{Row: 1, Column: 2}, sitter.Point{Row: 1, Column: 6}, "bool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Source() != "contract A {\n  bool a;\n}\n" {
+		t.Fatalf("bad source '%s'", d.Source())
+	}
+	if len(change.Ranges) == 0 || change.Ranges[0].StartPoint != (sitter.Point{Row: 1, Column: 2}) {
+		t.Fatalf("bad ranges %v", change.Ranges)
+	}
+
+	vv := d.Declaration(change.Declarations[0]).(*ContractDefinition).SubNodes[0].(*StateVariableDeclaration)
+	if name := vv.Variables[0].(*StateVariableDeclarationVariable).TypeName.(*ElementaryTypeName).Name; name != "bool" {
+		t.Fatalf("bad type %s", name)
+	}
+
+	// the points and the edits out of the source
+	if _, err := d.EditPoints(sitter.Point{Row: 1, Column: 20}, sitter.Point{Row: 1, Column: 20}, ""); err == nil {
+		t.Fatal("it should fail")
+	}
+	if _, err := d.EditPoints(sitter.Point{Row: 5}, sitter.Point{Row: 5}, ""); err == nil {
+		t.Fatal("it should fail")
+	}
+	if _, err := d.Edit(TextEdit{Start: 10, End: 100}); err == nil {
+		t.Fatal("it should fail")
+	}
+}
+
+func TestDocumentPoints(t *testing.T) {
+	d, err := NewDocument("contract A {\n  uint a;\n}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	edits := []TextEdit{
+		{Start: 12, End: 12, NewText: "\n  uint b;\n  uint c;"},
+		{Start: 0, End: 0, NewText: "// a\n\n"},
+		{Start: 5, End: 19, NewText: ""},
+		{Start: 3, End: 20, NewText: "x\ny"},
+		{Start: 0, End: 4, NewText: "contract B {}\n"},
+	}
+	for _, edit := range edits {
+		if _, err := d.Edit(edit); err != nil {
+			t.Fatal(err)
+		}
+
+		// the points of the source computed from the start
+		src := d.Source()
+		p := sitter.Point{}
+		for offset := 0; offset <= len(src); offset++ {
+			if found := d.Point(offset); found != p {
+				t.Fatalf("bad point of %d in %q: expected %v but found %v", offset, src, p, found)
+			}
+			if found, err := d.Offset(p); err != nil || found != offset {
+				t.Fatalf("bad offset of %v in %q: expected %d but found %d (%v)", p, src, offset, found, err)
+			}
+			if offset < len(src) && src[offset] == '\n' {
+				p.Row++
+				p.Column = 0
+			} else {
+				p.Column++
+			}
+		}
+		if _, err := d.Offset(sitter.Point{Row: p.Row + 1}); err == nil {
+			t.Fatal("it should fail")
+		}
+	}
+}
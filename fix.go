@@ -0,0 +1,134 @@
+package solcparser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// TextEdit replaces the bytes of the source from Start to End (exclusive) with NewText
+type TextEdit struct {
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	NewText string `json:"newText"`
+}
+
+// SuggestedFix is a change of the source that fixes a problem. The edits refer
+// to the original source and do not overlap.
+type SuggestedFix struct {
+	Message string     `json:"message"`
+	Edits   []TextEdit `json:"edits"`
+}
+
+// overlaps returns true if the edits change the same part of the source. Two
+// insertions at the same offset overlap since their order is ambiguous.
+func (e TextEdit) overlaps(o TextEdit) bool {
+	if e.Start == e.End && o.Start == o.End {
+		return e.Start == o.Start
+	}
+	return e.Start < o.End && o.Start < e.End
+}
+
+// ApplyFixes applies the fixes in order to the source. A fix with an edit that
+// overlaps an edit of a fix applied before is skipped and returned as a
+// conflict, it can be applied to the result in another round. The edits that
+// are identical to an edit already applied are ignored. It fails if an edit
+// is out of the source or overlaps another edit of the same fix.
+func ApplyFixes(src string, fixes []*SuggestedFix) (string, []*SuggestedFix, error) {
+	applied := []TextEdit{}
+	conflicts := []*SuggestedFix{}
+
+	for _, fix := range fixes {
+		for i, edit := range fix.Edits {
+			if edit.Start < 0 || edit.Start > edit.End || edit.End > len(src) {
+				return "", nil, fmt.Errorf("fix '%s': edit [%d, %d) is out of the source", fix.Message, edit.Start, edit.End)
+			}
+			for _, other := range fix.Edits[:i] {
+				if edit.overlaps(other) {
+					return "", nil, fmt.Errorf("fix '%s': edits [%d, %d) and [%d, %d) overlap", fix.Message, other.Start, other.End, edit.Start, edit.End)
+				}
+			}
+		}
+
+		edits := []TextEdit{}
+		conflict := false
+	EDITS:
+		for _, edit := range fix.Edits {
+			for _, other := range applied {
+				if edit == other {
+					continue EDITS
+				}
+				if edit.overlaps(other) {
+					conflict = true
+					break EDITS
+				}
+			}
+			edits = append(edits, edit)
+		}
+		if conflict {
+			conflicts = append(conflicts, fix)
+			continue
+		}
+		applied = append(applied, edits...)
+	}
+
+	// the insertions go before the replacements that start at the same offset
+	sort.SliceStable(applied, func(i, j int) bool {
+		if applied[i].Start != applied[j].Start {
+			return applied[i].Start < applied[j].Start
+		}
+		return applied[i].End < applied[j].End
+	})
+
+	var res strings.Builder
+	offset := 0
+	for _, edit := range applied {
+		res.WriteString(src[offset:edit.Start])
+		res.WriteString(edit.NewText)
+		offset = edit.End
+	}
+	res.WriteString(src[offset:])
+	return res.String(), conflicts, nil
+}
+
+var (
+	missingTokenRe = regexp.MustCompile(`^missing '(.+)' at `)
+	expectingRe    = regexp.MustCompile(`^mismatched input .* expecting (.*)$`)
+)
+
+// syntaxFix returns the fix of a syntax error that misses a token: the token
+// is inserted after the previous token
+func syntaxFix(src *sourceMap, recognizer antlr.Recognizer, msg string) *SuggestedFix {
+	parser, ok := recognizer.(antlr.Parser)
+	if !ok || src == nil {
+		return nil
+	}
+
+	var token string
+	if match := missingTokenRe.FindStringSubmatch(msg); match != nil {
+		token = match[1]
+	} else if match := expectingRe.FindStringSubmatch(msg); match != nil {
+		expected := strings.Split(strings.Trim(match[1], "{}"), ", ")
+		for _, e := range expected {
+			if e == "';'" {
+				token = ";"
+			}
+		}
+	}
+	if token == "" {
+		return nil
+	}
+
+	prev := parser.GetTokenStream().LT(-1)
+	if prev == nil {
+		return nil
+	}
+	offset := src.runePosition(prev.GetStop() + 1).Offset
+	return &SuggestedFix{
+		Message: fmt.Sprintf("insert '%s'", token),
+		Edits:   []TextEdit{{Start: offset, End: offset, NewText: token}},
+	}
+}
@@ -0,0 +1,91 @@
+package solcparser
+
+import (
+	"testing"
+)
+
+func TestApplyFixes(t *testing.T) {
+	src := "contract A { function f() constant {} }"
+
+	view := &SuggestedFix{Message: "view", Edits: []TextEdit{{Start: 26, End: 34, NewText: "view"}}}
+	public := &SuggestedFix{Message: "public", Edits: []TextEdit{{Start: 25, End: 25, NewText: " public"}}}
+	pure := &SuggestedFix{Message: "pure", Edits: []TextEdit{{Start: 26, End: 34, NewText: "pure"}}}
+	rename := &SuggestedFix{Message: "rename", Edits: []TextEdit{
+		{Start: 9, End: 10, NewText: "B"},
+		{Start: 22, End: 23, NewText: "g"},
+	}}
+
+	res, conflicts, err := ApplyFixes(src, []*SuggestedFix{view, public, pure, rename, view})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "contract B { function g() public view {} }" {
+		t.Fatal(res)
+	}
+	if len(conflicts) != 1 || conflicts[0] != pure {
+		t.Fatal("pure should conflict with view")
+	}
+
+	// two insertions at the same offset conflict
+	other := &SuggestedFix{Message: "external", Edits: []TextEdit{{Start: 25, End: 25, NewText: " external"}}}
+	if _, conflicts, _ := ApplyFixes(src, []*SuggestedFix{public, other}); len(conflicts) != 1 {
+		t.Fatal("insertions at the same offset should conflict")
+	}
+
+	// an insertion at the start of a replacement goes before it
+	res, _, err = ApplyFixes(src, []*SuggestedFix{view, {Edits: []TextEdit{{Start: 26, End: 26, NewText: "external "}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "contract A { function f() external view {} }" {
+		t.Fatal(res)
+	}
+
+	bad := []*SuggestedFix{
+		{Edits: []TextEdit{{Start: 10, End: 100}}},
+		{Edits: []TextEdit{{Start: 10, End: 5}}},
+		{Edits: []TextEdit{{Start: 1, End: 5}, {Start: 4, End: 6}}},
+	}
+	for _, fix := range bad {
+		if _, _, err := ApplyFixes(src, []*SuggestedFix{fix}); err == nil {
+			t.Fatalf("fix %v should fail", fix.Edits)
+		}
+	}
+}
+
+func TestSyntaxErrorFixes(t *testing.T) {
+	cases := []struct {
+		src   string
+		fixed string
+	}{
+		{
+			"contract A { function f() public { uint x = 1\n uint y = 2; } }",
+			"contract A { function f() public { uint x = 1;\n uint y = 2; } }",
+		},
+		{
+			"contract A { uint x }",
+			"contract A { uint x; }",
+		},
+		{
+			"contract A { function f() public returns (uint) { return 1 } }",
+			"contract A { function f() public returns (uint) { return 1; } }",
+		},
+	}
+
+	for _, c := range cases {
+		p := Parse(c.src)
+		if len(p.Errors) == 0 {
+			t.Fatalf("%q should fail", c.src)
+		}
+		res, _, err := ApplyFixes(c.src, p.Errors[0].Fixes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != c.fixed {
+			t.Fatalf("bad fix for %q: %q", c.src, res)
+		}
+		if errs := Parse(res).Errors; len(errs) != 0 {
+			t.Fatalf("fixed source fails: %v", errs[0])
+		}
+	}
+}
@@ -6,10 +6,10 @@ require (
 	github.com/antlr/antlr4/runtime/Go/antlr v0.0.0-20211204205839-489de649cafb
 	github.com/smacker/go-tree-sitter v0.0.0-20211116060328-db7fde9b5e82
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
@@ -0,0 +1,674 @@
+// Package layout computes the storage layout of a contract without compiling
+// it. The output follows the shape of the 'storageLayout' output of solc.
+//
+// The parser does not assign AST ids to the nodes, so 'astId' is always zero
+// and the type identifiers use the qualified name of the user defined types
+// (i.e. t_struct(A.S)_storage) instead of the name followed by the AST id.
+package layout
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// StorageLayout is the storage layout of a contract
+type StorageLayout struct {
+	Storage []*StorageItem       `json:"storage"`
+	Types   map[string]*TypeInfo `json:"types"`
+}
+
+// StorageItem is a state variable (or struct member) placed in storage
+type StorageItem struct {
+	AstID    int    `json:"astId"`
+	Contract string `json:"contract"`
+	Label    string `json:"label"`
+	Offset   uint64 `json:"offset"`
+	Slot     string `json:"slot"`
+	Type     string `json:"type"`
+
+	// Variable is the declaration of the item
+	Variable *solcparser.VariableDeclaration `json:"-"`
+
+	// Scope is the contract that declares the item
+	Scope *solcparser.ContractDefinition `json:"-"`
+}
+
+// TypeInfo describes a type referenced in the storage layout
+type TypeInfo struct {
+	Encoding      string         `json:"encoding"`
+	Label         string         `json:"label"`
+	NumberOfBytes string         `json:"numberOfBytes"`
+	Base          string         `json:"base,omitempty"`
+	Key           string         `json:"key,omitempty"`
+	Value         string         `json:"value,omitempty"`
+	Members       []*StorageItem `json:"members,omitempty"`
+}
+
+const (
+	EncodingInplace      = "inplace"
+	EncodingMapping      = "mapping"
+	EncodingDynamicArray = "dynamic_array"
+	EncodingBytes        = "bytes"
+)
+
+// Compute returns the storage layout of the contract with the given name
+func Compute(p *solcparser.Project, name string) (*StorageLayout, error) {
+	c, ok := p.Contract(name)
+	if !ok {
+		return nil, fmt.Errorf("contract %s not found", name)
+	}
+	return ComputeContract(p, c)
+}
+
+// ComputeContract returns the storage layout of the contract. The state variables
+// of the base contracts are placed first, following the linearization order.
+func ComputeContract(p *solcparser.Project, c *solcparser.ContractDefinition) (*StorageLayout, error) {
+	lin, err := p.Linearize(c)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{
+		project: p,
+		lin:     lin,
+		types:   map[string]*storageType{},
+		pending: map[string][]func(){},
+		layout: &StorageLayout{
+			Storage: []*StorageItem{},
+			Types:   map[string]*TypeInfo{},
+		},
+	}
+
+	members := []*member{}
+	for i := len(lin) - 1; i >= 0; i-- {
+		scope := lin[i]
+		for _, node := range scope.SubNodes {
+			decl, ok := node.(*solcparser.StateVariableDeclaration)
+			if !ok {
+				continue
+			}
+			for _, v := range decl.Variables {
+				vv, ok := v.(*solcparser.StateVariableDeclarationVariable)
+				if !ok {
+					continue
+				}
+				if vv.IsDeclaredConst || vv.IsInmutable {
+					// constants and immutables are not stored in storage
+					continue
+				}
+				typ, err := b.resolve(vv.TypeName, scope)
+				if err != nil {
+					return nil, fmt.Errorf("variable %s.%s: %v", scope.Name, vv.Name, err)
+				}
+				members = append(members, &member{
+					variable: &vv.VariableDeclaration,
+					scope:    scope,
+					typ:      typ,
+				})
+			}
+		}
+	}
+
+	// the types of the mapping values and of the structs being defined are
+	// not sized when the variables are resolved, they are checked at the end
+	ids := make([]string, 0, len(b.types))
+	for id := range b.types {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if typ := b.types[id]; typ.size != nil && typ.slots().Cmp(maxSlots) > 0 {
+			return nil, fmt.Errorf("type %s is too large for storage", typ.label)
+		}
+	}
+
+	var slots *big.Int
+	b.layout.Storage, slots = b.place(members)
+	if slots.Cmp(maxSlots) > 0 {
+		return nil, fmt.Errorf("contract %s requires too much storage", c.Name)
+	}
+	return b.layout, nil
+}
+
+// maxSlots is the number of slots of the storage (2^256)
+var maxSlots = new(big.Int).Lsh(big.NewInt(1), 256)
+
+type member struct {
+	variable *solcparser.VariableDeclaration
+	scope    *solcparser.ContractDefinition
+	typ      *storageType
+}
+
+// storageType is the resolved type of a variable in storage
+type storageType struct {
+	id    string
+	label string
+
+	// size is the number of bytes used by the type
+	size *big.Int
+
+	// packed is true if the type can share a slot with other types
+	packed bool
+}
+
+// slots returns the number of slots used by the type
+func (s *storageType) slots() *big.Int {
+	return ceilDiv(s.size, big.NewInt(32))
+}
+
+type builder struct {
+	project *solcparser.Project
+	lin     []*solcparser.ContractDefinition
+	types   map[string]*storageType
+	layout  *StorageLayout
+
+	// pending are the functions that set the size of the types that depend
+	// on a struct that is being defined, by the id of the struct
+	pending map[string][]func()
+}
+
+// place assigns slots and offsets to the members following the packing rules
+// of solc and returns the number of slots used
+func (b *builder) place(members []*member) ([]*StorageItem, *big.Int) {
+	items := []*StorageItem{}
+
+	slot := big.NewInt(0)
+	offset := uint64(0)
+
+	for _, m := range members {
+		size := m.typ.size.Uint64()
+		if !m.typ.packed || offset+size > 32 {
+			// start a new slot
+			if offset != 0 {
+				slot = new(big.Int).Add(slot, big.NewInt(1))
+				offset = 0
+			}
+		}
+
+		contract := ""
+		if m.scope != nil {
+			contract = b.project.SourceOf(m.scope) + ":" + m.scope.Name
+		}
+		items = append(items, &StorageItem{
+			Contract: contract,
+			Label:    m.variable.Name,
+			Offset:   offset,
+			Slot:     slot.String(),
+			Type:     m.typ.id,
+			Variable: m.variable,
+			Scope:    m.scope,
+		})
+
+		if m.typ.packed {
+			offset += size
+		} else {
+			slot = new(big.Int).Add(slot, m.typ.slots())
+		}
+	}
+
+	if offset != 0 {
+		slot = new(big.Int).Add(slot, big.NewInt(1))
+	}
+	return items, slot
+}
+
+func (b *builder) register(typ *storageType, info *TypeInfo) *storageType {
+	info.Label = typ.label
+	info.NumberOfBytes = typ.size.String()
+	b.types[typ.id] = typ
+	b.layout.Types[typ.id] = info
+	return typ
+}
+
+// complete sets the size of a type and of the types that were waiting for it
+func (b *builder) complete(typ *storageType, size *big.Int) {
+	typ.size = size
+	pending := b.pending[typ.id]
+	delete(b.pending, typ.id)
+	for _, f := range pending {
+		f()
+	}
+}
+
+// resolve returns the storage type of a type name declared in the given scope.
+// The size of the type is nil if it contains by value a struct that is being
+// defined (i.e. the S[2] of a mapping(uint => S[2]) member of S).
+func (b *builder) resolve(typeName interface{}, scope *solcparser.ContractDefinition) (*storageType, error) {
+	switch obj := typeName.(type) {
+	case *solcparser.ElementaryTypeName:
+		return b.resolveElementary(obj)
+
+	case *solcparser.UserDefinedTypeName:
+		return b.resolveUserDefined(obj.NamePath, scope)
+
+	case *solcparser.Mapping:
+		key, err := b.resolveMappingKey(obj.KeyType, scope)
+		if err != nil {
+			return nil, err
+		}
+		value, err := b.resolve(obj.ValueType, scope)
+		if err != nil {
+			return nil, err
+		}
+		id := "t_mapping(" + key.id + "," + value.id + ")"
+		if typ, ok := b.types[id]; ok {
+			return typ, nil
+		}
+		typ := &storageType{
+			id:    id,
+			label: "mapping(" + key.label + " => " + value.label + ")",
+			size:  big.NewInt(32),
+		}
+		return b.register(typ, &TypeInfo{
+			Encoding: EncodingMapping,
+			Key:      key.id,
+			Value:    value.id,
+		}), nil
+
+	case *solcparser.ArrayTypeName:
+		base, err := b.resolve(obj.BaseTypeName, scope)
+		if err != nil {
+			return nil, err
+		}
+		if obj.Length == nil {
+			id := "t_array(" + base.id + ")dyn_storage"
+			if typ, ok := b.types[id]; ok {
+				return typ, nil
+			}
+			typ := &storageType{
+				id:    id,
+				label: base.label + "[]",
+				size:  big.NewInt(32),
+			}
+			return b.register(typ, &TypeInfo{
+				Encoding: EncodingDynamicArray,
+				Base:     base.id,
+			}), nil
+		}
+
+		length, err := b.arrayLength(obj.Length, scope)
+		if err != nil {
+			return nil, err
+		}
+		id := "t_array(" + base.id + ")" + length.String() + "_storage"
+		if typ, ok := b.types[id]; ok {
+			return typ, nil
+		}
+
+		typ := &storageType{
+			id:    id,
+			label: base.label + "[" + length.String() + "]",
+		}
+		info := &TypeInfo{
+			Encoding: EncodingInplace,
+			Base:     base.id,
+		}
+		if base.size == nil {
+			// the base is a struct that is being defined
+			b.types[id] = typ
+			b.layout.Types[id] = info
+			info.Label = typ.label
+			b.pending[base.id] = append(b.pending[base.id], func() {
+				size := arraySize(base, length)
+				info.NumberOfBytes = size.String()
+				b.complete(typ, size)
+			})
+			return typ, nil
+		}
+		typ.size = arraySize(base, length)
+		return b.register(typ, info), nil
+
+	case *solcparser.FunctionTypeName:
+		return b.resolveFunctionType(obj, scope)
+
+	default:
+		return nil, fmt.Errorf("type %T not expected", typeName)
+	}
+}
+
+// arraySize returns the number of bytes of a static array
+func arraySize(base *storageType, length *big.Int) *big.Int {
+	var slots *big.Int
+	if base.packed {
+		perSlot := big.NewInt(32 / base.size.Int64())
+		slots = ceilDiv(length, perSlot)
+	} else {
+		slots = new(big.Int).Mul(length, base.slots())
+	}
+	return new(big.Int).Mul(slots, big.NewInt(32))
+}
+
+func (b *builder) resolveElementary(obj *solcparser.ElementaryTypeName) (*storageType, error) {
+	name := canonicalElementary(obj.Name)
+
+	var typ *storageType
+	switch {
+	case name == "address":
+		if obj.StateMutability == "payable" {
+			typ = &storageType{id: "t_address_payable", label: "address payable"}
+		} else {
+			typ = &storageType{id: "t_address", label: "address"}
+		}
+		typ.size = big.NewInt(20)
+		typ.packed = true
+
+	case name == "bool":
+		typ = &storageType{id: "t_bool", label: "bool", size: big.NewInt(1), packed: true}
+
+	case name == "string" || name == "bytes":
+		typ = &storageType{id: "t_" + name + "_storage", label: name, size: big.NewInt(32)}
+		if _, ok := b.types[typ.id]; !ok {
+			b.register(typ, &TypeInfo{Encoding: EncodingBytes})
+		}
+		return typ, nil
+
+	default:
+		size, ok := elementarySize(name)
+		if !ok {
+			return nil, fmt.Errorf("type %s cannot be stored", obj.Name)
+		}
+		typ = &storageType{id: "t_" + name, label: name, size: big.NewInt(int64(size)), packed: true}
+	}
+
+	if _, ok := b.types[typ.id]; !ok {
+		b.register(typ, &TypeInfo{Encoding: EncodingInplace})
+	}
+	return b.types[typ.id], nil
+}
+
+func (b *builder) resolveMappingKey(key interface{}, scope *solcparser.ContractDefinition) (*storageType, error) {
+	if elem, ok := key.(*solcparser.ElementaryTypeName); ok {
+		name := canonicalElementary(elem.Name)
+		if name == "string" || name == "bytes" {
+			// dynamic keys are hashed from memory
+			typ := &storageType{id: "t_" + name + "_memory_ptr", label: name, size: big.NewInt(32)}
+			if _, ok := b.types[typ.id]; !ok {
+				b.register(typ, &TypeInfo{Encoding: EncodingBytes})
+			}
+			return typ, nil
+		}
+	}
+	return b.resolve(key, scope)
+}
+
+func (b *builder) resolveFunctionType(obj *solcparser.FunctionTypeName, scope *solcparser.ContractDefinition) (*storageType, error) {
+	params := func(list []interface{}) ([]string, []string, error) {
+		ids, labels := []string{}, []string{}
+		for _, p := range list {
+			decl, ok := p.(*solcparser.VariableDeclaration)
+			if !ok {
+				continue
+			}
+			typ, err := b.resolve(decl.TypeName, scope)
+			if err != nil {
+				return nil, nil, err
+			}
+			ids = append(ids, typ.id)
+			labels = append(labels, typ.label)
+		}
+		return ids, labels, nil
+	}
+	inIDs, inLabels, err := params(obj.ParameterTypes)
+	if err != nil {
+		return nil, err
+	}
+	outIDs, outLabels, err := params(obj.ReturnTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	visibility := "internal"
+	size := int64(8)
+	if obj.Visibility == "external" {
+		// address and selector
+		visibility = "external"
+		size = 24
+	}
+	mutability := obj.StateMutability
+	if mutability == "" {
+		mutability = "nonpayable"
+	}
+
+	label := "function (" + strings.Join(inLabels, ",") + ")"
+	if visibility == "external" {
+		label += " external"
+	}
+	if obj.StateMutability != "" {
+		label += " " + obj.StateMutability
+	}
+	if len(outLabels) != 0 {
+		label += " returns (" + strings.Join(outLabels, ",") + ")"
+	}
+
+	typ := &storageType{
+		id:     "t_function_" + visibility + "_" + mutability + "(" + strings.Join(inIDs, ",") + ")returns(" + strings.Join(outIDs, ",") + ")",
+		label:  label,
+		size:   big.NewInt(size),
+		packed: true,
+	}
+	if _, ok := b.types[typ.id]; !ok {
+		b.register(typ, &TypeInfo{Encoding: EncodingInplace})
+	}
+	return b.types[typ.id], nil
+}
+
+func (b *builder) resolveUserDefined(path string, scope *solcparser.ContractDefinition) (*storageType, error) {
+	def, defScope, ok := b.lookup(path, scope)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", path)
+	}
+
+	qualified := func(name string) string {
+		if defScope == nil {
+			return name
+		}
+		return defScope.Name + "." + name
+	}
+
+	switch obj := def.(type) {
+	case *solcparser.ContractDefinition:
+		id := "t_contract(" + obj.Name + ")"
+		if typ, ok := b.types[id]; ok {
+			return typ, nil
+		}
+		typ := &storageType{id: id, label: "contract " + obj.Name, size: big.NewInt(20), packed: true}
+		return b.register(typ, &TypeInfo{Encoding: EncodingInplace}), nil
+
+	case *solcparser.EnumDefinition:
+		name := qualified(obj.Name)
+		id := "t_enum(" + name + ")"
+		if typ, ok := b.types[id]; ok {
+			return typ, nil
+		}
+		typ := &storageType{id: id, label: "enum " + name, size: big.NewInt(1), packed: true}
+		return b.register(typ, &TypeInfo{Encoding: EncodingInplace}), nil
+
+	case *solcparser.TypeDefinition:
+		name := qualified(obj.Name)
+		id := "t_userDefinedValueType(" + name + ")"
+		if typ, ok := b.types[id]; ok {
+			return typ, nil
+		}
+		underlying, err := b.resolve(obj.Definition, defScope)
+		if err != nil {
+			return nil, err
+		}
+		typ := &storageType{id: id, label: name, size: underlying.size, packed: true}
+		return b.register(typ, &TypeInfo{Encoding: EncodingInplace}), nil
+
+	case *solcparser.StructDefinition:
+		name := qualified(obj.Name)
+		id := "t_struct(" + name + ")_storage"
+		if typ, ok := b.types[id]; ok {
+			// the size is nil while the struct is being defined, the
+			// mappings and the dynamic arrays of the struct do not need it
+			return typ, nil
+		}
+
+		// register the type before resolving the members, the size of the
+		// members that contain the struct by value is unknown
+		typ := &storageType{id: id, label: "struct " + name}
+		b.types[id] = typ
+
+		members := []*member{}
+		for _, m := range obj.Members {
+			decl, ok := m.(*solcparser.VariableDeclaration)
+			if !ok {
+				continue
+			}
+			mTyp, err := b.resolve(decl.TypeName, defScope)
+			if err != nil {
+				return nil, err
+			}
+			if mTyp.size == nil {
+				return nil, fmt.Errorf("recursive struct %s", name)
+			}
+			members = append(members, &member{variable: decl, typ: mTyp})
+		}
+		items, slots := b.place(members)
+		if slots.Sign() == 0 {
+			slots = big.NewInt(1)
+		}
+		b.complete(typ, new(big.Int).Mul(slots, big.NewInt(32)))
+
+		return b.register(typ, &TypeInfo{
+			Encoding: EncodingInplace,
+			Members:  items,
+		}), nil
+
+	default:
+		return nil, fmt.Errorf("%s is not a type", path)
+	}
+}
+
+// lookup finds the definition of a user defined name. It returns the definition
+// and the contract where it is defined (nil if it is defined at file level).
+func (b *builder) lookup(path string, scope *solcparser.ContractDefinition) (interface{}, *solcparser.ContractDefinition, bool) {
+	parts := strings.Split(path, ".")
+	if len(parts) > 1 {
+		// Contract.Name (imported unit aliases are ignored)
+		c, ok := b.project.Contract(parts[len(parts)-2])
+		if !ok {
+			return nil, nil, false
+		}
+		if def, ok := findInContract(c, parts[len(parts)-1]); ok {
+			return def, c, true
+		}
+		return nil, nil, false
+	}
+
+	name := parts[0]
+	if scope != nil {
+		lin, err := b.project.Linearize(scope)
+		if err != nil {
+			lin = []*solcparser.ContractDefinition{scope}
+		}
+		for _, c := range lin {
+			if def, ok := findInContract(c, name); ok {
+				return def, c, true
+			}
+		}
+	}
+	for _, file := range b.project.Files() {
+		for _, child := range b.project.Units[file].Children {
+			if declName(child) == name {
+				return child, nil, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+func findInContract(c *solcparser.ContractDefinition, name string) (interface{}, bool) {
+	for _, node := range c.SubNodes {
+		switch node.(type) {
+		case *solcparser.StructDefinition, *solcparser.EnumDefinition, *solcparser.TypeDefinition:
+			if declName(node) == name {
+				return node, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func declName(node interface{}) string {
+	switch obj := node.(type) {
+	case *solcparser.ContractDefinition:
+		return obj.Name
+	case *solcparser.StructDefinition:
+		return obj.Name
+	case *solcparser.EnumDefinition:
+		return obj.Name
+	case *solcparser.TypeDefinition:
+		return obj.Name
+	case *solcparser.FileLevelConstant:
+		return obj.Name
+	}
+	return ""
+}
+
+func canonicalElementary(name string) string {
+	switch name {
+	case "uint":
+		return "uint256"
+	case "int":
+		return "int256"
+	case "byte":
+		return "bytes1"
+	case "fixed":
+		return "fixed128x18"
+	case "ufixed":
+		return "ufixed128x18"
+	}
+	return name
+}
+
+// elementarySize returns the size in bytes of the value types
+func elementarySize(name string) (int, bool) {
+	var bits int
+	switch {
+	case strings.HasPrefix(name, "uint"):
+		if _, err := fmt.Sscanf(name, "uint%d", &bits); err != nil {
+			return 0, false
+		}
+		return bits / 8, true
+
+	case strings.HasPrefix(name, "int"):
+		if _, err := fmt.Sscanf(name, "int%d", &bits); err != nil {
+			return 0, false
+		}
+		return bits / 8, true
+
+	case strings.HasPrefix(name, "bytes"):
+		var size int
+		if _, err := fmt.Sscanf(name, "bytes%d", &size); err != nil {
+			return 0, false
+		}
+		return size, true
+
+	case strings.HasPrefix(name, "ufixed"):
+		if _, err := fmt.Sscanf(name, "ufixed%dx", &bits); err != nil {
+			return 0, false
+		}
+		return bits / 8, true
+
+	case strings.HasPrefix(name, "fixed"):
+		if _, err := fmt.Sscanf(name, "fixed%dx", &bits); err != nil {
+			return 0, false
+		}
+		return bits / 8, true
+	}
+	return 0, false
+}
+
+func ceilDiv(a, b *big.Int) *big.Int {
+	res, mod := new(big.Int).DivMod(a, b, new(big.Int))
+	if mod.Sign() != 0 {
+		res.Add(res, big.NewInt(1))
+	}
+	return res
+}
@@ -0,0 +1,221 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+type item struct {
+	label  string
+	slot   string
+	offset uint64
+	typ    string
+}
+
+func TestLayout(t *testing.T) {
+	cases := []struct {
+		code     string
+		contract string
+		items    []item
+	}{
+		{
+			`contract A {
+				uint8 a;
+				uint16 b;
+				uint256 c;
+				address d;
+				bool e;
+				mapping(address => uint) m;
+				uint[] arr;
+				uint8[3] sa;
+				string str;
+				bytes32 h;
+				uint constant C = 1;
+				uint immutable I;
+			}`,
+			"A",
+			[]item{
+				{"a", "0", 0, "t_uint8"},
+				{"b", "0", 1, "t_uint16"},
+				{"c", "1", 0, "t_uint256"},
+				{"d", "2", 0, "t_address"},
+				{"e", "2", 20, "t_bool"},
+				{"m", "3", 0, "t_mapping(t_address,t_uint256)"},
+				{"arr", "4", 0, "t_array(t_uint256)dyn_storage"},
+				{"sa", "5", 0, "t_array(t_uint8)3_storage"},
+				{"str", "6", 0, "t_string_storage"},
+				{"h", "7", 0, "t_bytes32"},
+			},
+		},
+		{
+			`contract A {
+				struct S { uint128 x; uint128 y; uint z; }
+				bool a;
+				S s;
+				bool b;
+				S[2] ss;
+				uint128[3] arr;
+			}`,
+			"A",
+			[]item{
+				{"a", "0", 0, "t_bool"},
+				{"s", "1", 0, "t_struct(A.S)_storage"},
+				{"b", "3", 0, "t_bool"},
+				{"ss", "4", 0, "t_array(t_struct(A.S)_storage)2_storage"},
+				{"arr", "8", 0, "t_array(t_uint128)3_storage"},
+			},
+		},
+		{
+			`uint constant N = 2;
+			enum E { A, B }
+			type Price is uint64;
+			interface I {}
+			contract A {
+				E e;
+				Price p;
+				I i;
+				address payable owner;
+				uint[N * 2] arr;
+				function (uint) external returns (bool) f;
+			}`,
+			"A",
+			[]item{
+				{"e", "0", 0, "t_enum(E)"},
+				{"p", "0", 1, "t_userDefinedValueType(Price)"},
+				{"i", "0", 9, "t_contract(I)"},
+				{"owner", "1", 0, "t_address_payable"},
+				{"arr", "2", 0, "t_array(t_uint256)4_storage"},
+				{"f", "6", 0, "t_function_external_nonpayable(t_uint256)returns(t_bool)"},
+			},
+		},
+		{
+			`contract A { uint a; }
+			contract B is A { uint128 b; }
+			contract C is A { uint128 c; }
+			contract D is B, C { uint128 d; }`,
+			"D",
+			[]item{
+				{"a", "0", 0, "t_uint256"},
+				{"b", "1", 0, "t_uint128"},
+				{"c", "1", 16, "t_uint128"},
+				{"d", "2", 0, "t_uint128"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		p := solcparser.NewProject()
+		if err := p.AddSource("a.sol", c.code); err != nil {
+			t.Fatal(err)
+		}
+		layout, err := Compute(p, c.contract)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		items := []item{}
+		for _, i := range layout.Storage {
+			items = append(items, item{i.Label, i.Slot, i.Offset, i.Type})
+		}
+		if !reflect.DeepEqual(items, c.items) {
+			t.Fatalf("bad layout for %s:\n%v\n%v", c.contract, items, c.items)
+		}
+	}
+}
+
+func TestLayoutTypes(t *testing.T) {
+	p := solcparser.NewProject()
+	code := `contract A {
+		struct S { uint128 x; address y; }
+		mapping(address => S) m;
+	}`
+	if err := p.AddSource("a.sol", code); err != nil {
+		t.Fatal(err)
+	}
+	layout, err := Compute(p, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item := layout.Storage[0]; item.Contract != "a.sol:A" {
+		t.Fatalf("bad contract %s", item.Contract)
+	}
+
+	mapping := layout.Types["t_mapping(t_address,t_struct(A.S)_storage)"]
+	if mapping == nil {
+		t.Fatal("mapping type not found")
+	}
+	if mapping.Encoding != EncodingMapping || mapping.Key != "t_address" || mapping.Value != "t_struct(A.S)_storage" {
+		t.Fatalf("bad mapping type %v", mapping)
+	}
+	if mapping.Label != "mapping(address => struct A.S)" {
+		t.Fatalf("bad label %s", mapping.Label)
+	}
+
+	s := layout.Types["t_struct(A.S)_storage"]
+	if s.NumberOfBytes != "64" {
+		t.Fatalf("bad struct size %s", s.NumberOfBytes)
+	}
+	if len(s.Members) != 2 || s.Members[1].Slot != "1" || s.Members[1].Offset != 0 {
+		t.Fatalf("bad struct members")
+	}
+}
+
+func TestLayoutRecursiveStructs(t *testing.T) {
+	cases := []struct {
+		member string
+		typ    string
+		bytes  string
+	}{
+		{"mapping(uint => S) kids;", "t_mapping(t_uint256,t_struct(A.S)_storage)", "32"},
+		{"S[] kids;", "t_array(t_struct(A.S)_storage)dyn_storage", "32"},
+		{"mapping(uint => S[2]) kids;", "t_array(t_struct(A.S)_storage)2_storage", "128"},
+	}
+	for _, c := range cases {
+		p := solcparser.NewProject()
+		code := "contract A { struct S { uint x; " + c.member + " } S s; uint y; }"
+		if err := p.AddSource("a.sol", code); err != nil {
+			t.Fatal(err)
+		}
+		layout, err := Compute(p, "A")
+		if err != nil {
+			t.Fatalf("unexpected error for '%s': %v", c.member, err)
+		}
+		if s := layout.Types["t_struct(A.S)_storage"]; s == nil || s.NumberOfBytes != "64" {
+			t.Fatalf("bad struct for '%s'", c.member)
+		}
+		if typ := layout.Types[c.typ]; typ == nil || typ.NumberOfBytes != c.bytes {
+			t.Fatalf("bad type %s for '%s'", c.typ, c.member)
+		}
+		if y := layout.Storage[1]; y.Label != "y" || y.Slot != "2" {
+			t.Fatalf("bad slot for '%s': %s", c.member, y.Slot)
+		}
+	}
+}
+
+func TestLayoutErrors(t *testing.T) {
+	cases := []string{
+		"contract A { Unknown a; }",
+		"contract A { struct S { S s; } S s; }",
+		"contract A { struct S { S[2] s; } S s; }",
+		"contract A { struct S { T t; } struct T { S s; } S s; }",
+		"contract A { uint[N] a; }",
+		"contract A is B { }",
+		"contract A { uint[2**300] a; }",
+		"contract A { uint[2**255][3] a; }",
+		"contract A { struct S { uint[2**256] a; uint b; } S s; }",
+		"contract A { mapping(uint => uint[2**300]) m; }",
+		"contract A { uint[2**256] a; uint b; }",
+	}
+	for _, c := range cases {
+		p := solcparser.NewProject()
+		if err := p.AddSource("a.sol", c); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Compute(p, "A"); err == nil {
+			t.Fatalf("expected an error for %s", c)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package layout
+
+import (
+	"fmt"
+	"math/big"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/constant"
+)
+
+// arrayLength evaluates the length of a static array. The length must
+// be a constant expression.
+func (b *builder) arrayLength(expr interface{}, scope *solcparser.ContractDefinition) (*big.Int, error) {
+	value, err := constant.Eval(expr, constant.WithProject(b.project), constant.WithContract(scope))
+	if err != nil {
+		return nil, err
+	}
+	res, ok := value.Int()
+	if !ok {
+		return nil, fmt.Errorf("array length must be an integer")
+	}
+	if res.Sign() <= 0 {
+		return nil, fmt.Errorf("array length must be positive")
+	}
+	return res, nil
+}
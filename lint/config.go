@@ -0,0 +1,127 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config enables and tunes the rules. In YAML:
+//
+//	recommended: true
+//	rules:
+//	  no-tx-origin: error
+//	  max-line-length:
+//	    severity: warning
+//	    options:
+//	      max: 120
+//	  no-inline-assembly: off
+type Config struct {
+	// Recommended enables all the registered rules with their default severity
+	Recommended bool `json:"recommended" yaml:"recommended"`
+
+	// Rules are the rules enabled or disabled explicitly
+	Rules map[string]*RuleConfig `json:"rules" yaml:"rules"`
+}
+
+// RuleConfig is the configuration of a rule. It can be written as the
+// severity alone (i.e. 'error' or 'off').
+type RuleConfig struct {
+	// Severity overrides the severity of the rule, 'off' disables it
+	Severity string                 `json:"severity" yaml:"severity"`
+	Options  map[string]interface{} `json:"options" yaml:"options"`
+}
+
+func (r *RuleConfig) UnmarshalJSON(data []byte) error {
+	var severity string
+	if err := json.Unmarshal(data, &severity); err == nil {
+		r.Severity = severity
+		return nil
+	}
+	type ruleConfig RuleConfig
+	return json.Unmarshal(data, (*ruleConfig)(r))
+}
+
+func (r *RuleConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		r.Severity = node.Value
+		return nil
+	}
+	type ruleConfig RuleConfig
+	return node.Decode((*ruleConfig)(r))
+}
+
+// DefaultConfig enables all the registered rules
+func DefaultConfig() *Config {
+	return &Config{Recommended: true}
+}
+
+// ParseConfig parses a config in YAML or JSON (which is also YAML)
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadConfig reads a config file. The files with the .json extension are
+// decoded as JSON and the rest as YAML.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(filepath.Ext(path)) != ".json" {
+		return ParseConfig(data)
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	for id, rule := range c.Rules {
+		if _, ok := Lookup(id); !ok {
+			return fmt.Errorf("unknown rule '%s'", id)
+		}
+		if rule == nil || rule.Severity == "" || rule.Severity == "off" {
+			continue
+		}
+		if _, err := ParseSeverity(rule.Severity); err != nil {
+			return fmt.Errorf("rule %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// enabled returns the severity and the options of a rule if it is enabled
+func (c *Config) enabled(rule Rule) (Severity, map[string]interface{}, bool) {
+	conf, ok := c.Rules[rule.ID()]
+	if !ok || conf == nil {
+		return rule.Severity(), nil, c.Recommended
+	}
+	switch conf.Severity {
+	case "off":
+		return 0, nil, false
+	case "":
+		return rule.Severity(), conf.Options, true
+	}
+	severity, err := ParseSeverity(conf.Severity)
+	if err != nil {
+		severity = rule.Severity()
+	}
+	return severity, conf.Options, true
+}
@@ -0,0 +1,192 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// Linter runs a set of rules over the source units of a project
+type Linter struct {
+	config *Config
+	rules  []Rule
+}
+
+// New creates a linter with the registered rules enabled by the config. A nil
+// config enables all of them.
+func New(config *Config) *Linter {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Linter{config: config, rules: Rules()}
+}
+
+// NewWithRules creates a linter that runs only the given rules
+func NewWithRules(config *Config, rules ...Rule) *Linter {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Linter{config: config, rules: rules}
+}
+
+// Enabled returns the rules that run with the config of the linter
+func (l *Linter) Enabled() []Rule {
+	res := []Rule{}
+	for _, rule := range l.rules {
+		if _, _, ok := l.config.enabled(rule); ok {
+			res = append(res, rule)
+		}
+	}
+	return res
+}
+
+// Lint parses the sources (by file name) and runs the rules over them
+func (l *Linter) Lint(sources map[string]string) ([]*Diagnostic, error) {
+	names := []string{}
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p := solcparser.NewProject()
+	for _, name := range names {
+		if err := p.AddSource(name, sources[name]); err != nil {
+			return nil, err
+		}
+	}
+	return l.Run(p, sources), nil
+}
+
+// Run runs the rules over the source units of an already parsed project. The
+// sources are used for the suppression comments and by the rules that check
+// the text of the file, the units without source are linted too.
+func (l *Linter) Run(p *solcparser.Project, sources map[string]string) []*Diagnostic {
+	info := types.Check(p)
+	rules := l.Enabled()
+	memo := map[interface{}]interface{}{}
+
+	res := []*Diagnostic{}
+	for _, file := range p.Files() {
+		src := sources[file]
+		supp := parseSuppressions(src)
+
+		for _, rule := range rules {
+			severity, options, _ := l.config.enabled(rule)
+			pass := &Pass{
+				Rule:     rule,
+				File:     file,
+				Source:   src,
+				Unit:     p.Units[file],
+				Project:  p,
+				Info:     info,
+				Severity: severity,
+				Options:  options,
+				memo:     memo,
+				report: func(d *Diagnostic) {
+					line := 1
+					if d.Loc != nil {
+						line = d.Loc.Start.Line
+					}
+					if !supp.suppressed(d.Rule, line) {
+						res = append(res, d)
+					}
+				},
+			}
+			l.check(rule, pass)
+		}
+	}
+	SortDiagnostics(res)
+	return res
+}
+
+// maxFixRounds is the limit of rounds of Fix, the fixes of a round can
+// conflict with others or enable new ones
+const maxFixRounds = 10
+
+// Fix lints the sources and applies the suggested fixes until there is nothing
+// left to fix. It returns the fixed sources and the diagnostics of the fixed
+// sources. It fails if the fixes break the syntax of a file.
+func (l *Linter) Fix(sources map[string]string) (map[string]string, []*Diagnostic, error) {
+	res := map[string]string{}
+	for file, src := range sources {
+		res[file] = src
+	}
+
+	for i := 0; i < maxFixRounds; i++ {
+		diags, err := l.Lint(res)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fixes := map[string][]*solcparser.SuggestedFix{}
+		for _, d := range diags {
+			fixes[d.File] = append(fixes[d.File], d.Fixes...)
+		}
+		changed := false
+		for file, list := range fixes {
+			src, ok := res[file]
+			if !ok || len(list) == 0 {
+				continue
+			}
+			fixed, _, err := solcparser.ApplyFixes(src, list)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to fix %s: %v", file, err)
+			}
+			if fixed == src {
+				continue
+			}
+			if errs := solcparser.Parse(fixed).Errors; len(errs) != 0 {
+				return nil, nil, fmt.Errorf("the fixes of %s break the source: %v", file, errs[0])
+			}
+			res[file] = fixed
+			changed = true
+		}
+		if !changed {
+			return res, diags, nil
+		}
+	}
+
+	diags, err := l.Lint(res)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, diags, nil
+}
+
+// check runs a rule and reports a panic of the rule as a diagnostic
+func (l *Linter) check(rule Rule, pass *Pass) {
+	defer func() {
+		if err := recover(); err != nil {
+			pass.report(&Diagnostic{
+				Rule:     rule.ID(),
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("rule %s failed: %v", rule.ID(), err),
+				File:     pass.File,
+			})
+		}
+	}()
+	rule.Check(pass)
+}
+
+// SortDiagnostics sorts the diagnostics by file, position and rule
+func SortDiagnostics(diags []*Diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i], diags[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if offset(a) != offset(b) {
+			return offset(a) < offset(b)
+		}
+		return a.Rule < b.Rule
+	})
+}
+
+func offset(d *Diagnostic) int {
+	if d.Loc == nil {
+		return -1
+	}
+	return d.Loc.Start.Offset
+}
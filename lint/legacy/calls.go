@@ -0,0 +1,269 @@
+package legacy
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// lowLevelCall reports the low-level calls (call, delegatecall and
+// staticcall) that are not valid since 0.5.0: they take a single bytes
+// argument and return (bool, bytes) instead of a bool
+type lowLevelCall struct{}
+
+func (lowLevelCall) ID() string              { return "low-level-call" }
+func (lowLevelCall) Severity() lint.Severity { return lint.SeverityWarning }
+func (lowLevelCall) Doc() string {
+	return "the low-level calls take a single bytes argument and return (bool, bytes) since 0.5.0"
+}
+
+func (lowLevelCall) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+
+	// the calls whose result is used as a bool, with the statement
+	// 'require(x.call(...));' of a block that can be rewritten
+	asBool := map[*solcparser.FunctionCall]*solcparser.ExpressionStatement{}
+	used := func(expr interface{}, stmt *solcparser.ExpressionStatement) {
+		if call, ok := expr.(*solcparser.FunctionCall); ok && isLowLevelCall(pass, call) {
+			if _, ok := asBool[call]; !ok {
+				asBool[call] = stmt
+			}
+		}
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *solcparser.Block:
+			for _, st := range obj.Statements {
+				stmt, ok := st.(*solcparser.ExpressionStatement)
+				if !ok {
+					continue
+				}
+				if call, ok := stmt.Expression.(*solcparser.FunctionCall); ok && isCheck(call) && len(call.Arguments) != 0 {
+					used(call.Arguments[0], stmt)
+				}
+			}
+		case *solcparser.FunctionCall:
+			if isCheck(obj) && len(obj.Arguments) != 0 {
+				used(obj.Arguments[0], nil)
+			}
+		case *solcparser.IfStatement:
+			used(obj.Condition, nil)
+		case *solcparser.WhileStatement:
+			used(obj.Condition, nil)
+		case *solcparser.DoWhileStatement:
+			used(obj.Condition, nil)
+		case *solcparser.ForStatement:
+			used(obj.ConditionExpression, nil)
+		case *solcparser.Conditional:
+			used(obj.Condition, nil)
+		case *solcparser.UnaryOperation:
+			if obj.Operator == "!" {
+				used(obj.SubExpression, nil)
+			}
+		case *solcparser.BinaryOperation:
+			if obj.Operator == "&&" || obj.Operator == "||" {
+				used(obj.Left, nil)
+				used(obj.Right, nil)
+			}
+		case *solcparser.ReturnStatement:
+			used(obj.Expression, nil)
+		case *solcparser.VariableDeclarationStatement:
+			if len(obj.Variables) == 1 {
+				used(obj.InitialValue, nil)
+			}
+		}
+		return true
+	})
+
+	names := map[string]bool{}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		call, ok := node.(*solcparser.FunctionCall)
+		if !ok || !isLowLevelCall(pass, call) {
+			return true
+		}
+		if len(call.Arguments) > 1 {
+			pass.Report(call, "the low-level calls take a single bytes argument, encode the arguments with abi.encodeWithSignature")
+			return true
+		}
+
+		stmt, isBool := asBool[call]
+		if !isBool {
+			if len(call.Arguments) == 0 {
+				var fix *solcparser.SuggestedFix
+				if offset, ok := argsEnd(pass.Source, call); ok {
+					fix = insert(offset, `""`, "call with empty data")
+				}
+				pass.ReportFix(call, fix, "the low-level calls take a single bytes argument")
+			}
+			return true
+		}
+		var fix *solcparser.SuggestedFix
+		if stmt != nil {
+			fix = checkResult(pass.Source, stmt, call, freshName(pass.Source, "success", names))
+		}
+		pass.ReportFix(call, fix, "the low-level calls return (bool, bytes), use the bool of the result")
+		return true
+	})
+}
+
+// isCheck returns true for the calls of require and assert
+func isCheck(call *solcparser.FunctionCall) bool {
+	id, ok := call.Expression.(*solcparser.Identifier)
+	return ok && (id.Name == "require" || id.Name == "assert")
+}
+
+// isLowLevelCall returns true for the calls of the call, delegatecall and
+// staticcall members of an address, with or without call options
+func isLowLevelCall(pass *lint.Pass, call *solcparser.FunctionCall) bool {
+	expr := call.Expression
+	for {
+		if options, ok := expr.(*solcparser.NameValueExpression); ok {
+			expr = options.Expression
+			continue
+		}
+		// x.call.value(1)
+		inner, ok := expr.(*solcparser.FunctionCall)
+		if !ok {
+			break
+		}
+		member, ok := inner.Expression.(*solcparser.MemberAccess)
+		if !ok || (member.MemberName != "value" && member.MemberName != "gas") {
+			return false
+		}
+		expr = member.Expression
+	}
+	member, ok := expr.(*solcparser.MemberAccess)
+	if !ok {
+		return false
+	}
+	switch member.MemberName {
+	case "call", "delegatecall", "staticcall":
+	default:
+		return false
+	}
+	_, ok = pass.Info.TypeOf(member.Expression).(*types.AddressType)
+	return ok
+}
+
+// argsEnd returns the offset of the closing parenthesis of the arguments of a call
+func argsEnd(src string, call *solcparser.FunctionCall) (int, bool) {
+	if call.Loc == nil || call.Loc.End.Offset > len(src) || !strings.HasSuffix(src[:call.Loc.End.Offset], ")") {
+		return 0, false
+	}
+	return call.Loc.End.Offset - 1, true
+}
+
+// freshName returns a name that is not in the source and was not returned
+// before (i.e. success, success2...)
+func freshName(src, name string, names map[string]bool) string {
+	for i := 1; ; i++ {
+		candidate := name
+		if i != 1 {
+			candidate += strconv.Itoa(i)
+		}
+		if !names[candidate] && !regexp.MustCompile(`\b`+candidate+`\b`).MatchString(src) {
+			names[candidate] = true
+			return candidate
+		}
+	}
+}
+
+// checkResult returns the fix that declares the result of the call and
+// checks its bool (i.e. 'require(x.call(""));' becomes
+// '(bool success, ) = x.call(""); require(success);')
+func checkResult(src string, stmt *solcparser.ExpressionStatement, call *solcparser.FunctionCall, success string) *solcparser.SuggestedFix {
+	check := stmt.Expression.(*solcparser.FunctionCall)
+	callText, ok := text(src, call)
+	if !ok || stmt.Loc == nil || check.Loc == nil || check.Loc.End.Offset > len(src) {
+		return nil
+	}
+	if len(call.Arguments) == 0 {
+		callText = strings.TrimSuffix(callText, ")") + `"")`
+	}
+
+	// the other arguments of the check (i.e. the message of require)
+	args := success
+	if len(check.Arguments) > 1 {
+		last, ok := check.Arguments[len(check.Arguments)-1].(solcparser.INode)
+		if !ok || last.GetLoc() == nil {
+			return nil
+		}
+		args += src[call.Loc.End.Offset:last.GetLoc().End.Offset]
+	}
+	name := check.Expression.(*solcparser.Identifier).Name
+
+	start := stmt.Loc.Start.Offset
+	lineStart := strings.LastIndex(src[:start], "\n") + 1
+	indent := src[lineStart:start]
+	if strings.TrimSpace(indent) != "" {
+		indent = ""
+	}
+	return replace(start, check.Loc.End.Offset, "(bool "+success+", ) = "+callText+";\n"+indent+name+"("+args+")", "check the bool of the result")
+}
+
+// payableAddress reports the ether sent to an address that is not payable
+// (selfdestruct, transfer and send), an error since 0.5.0. The msg.sender and
+// tx.origin addresses are payable until 0.8.0.
+type payableAddress struct{}
+
+func (payableAddress) ID() string              { return "payable-address" }
+func (payableAddress) Severity() lint.Severity { return lint.SeverityWarning }
+func (payableAddress) Doc() string {
+	return "the ether can only be sent to an address payable since 0.5.0"
+}
+
+func (payableAddress) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	t, _ := target(pass)
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		call, ok := node.(*solcparser.FunctionCall)
+		if !ok {
+			return true
+		}
+		var addr interface{}
+		switch obj := call.Expression.(type) {
+		case *solcparser.Identifier:
+			if (obj.Name == "selfdestruct" || obj.Name == "suicide") && len(call.Arguments) == 1 && pass.Info.RefOf(obj) == nil {
+				addr = call.Arguments[0]
+			}
+		case *solcparser.MemberAccess:
+			if obj.MemberName == "transfer" || obj.MemberName == "send" {
+				addr = obj.Expression
+			}
+		}
+		if addr == nil {
+			return true
+		}
+		if typ, ok := pass.Info.TypeOf(addr).(*types.AddressType); !ok || typ.Payable {
+			return true
+		}
+		if member, ok := addr.(*solcparser.MemberAccess); ok && t.less(version{0, 8, 0}) {
+			if id, ok := member.Expression.(*solcparser.Identifier); ok && (id.Name == "msg" && member.MemberName == "sender" || id.Name == "tx" && member.MemberName == "origin") {
+				return true
+			}
+		}
+
+		str, ok := text(pass.Source, addr)
+		if !ok {
+			pass.Report(addr, "the address is not payable")
+			return true
+		}
+		// payable(x) is valid since 0.6.0
+		payable := "payable(" + str + ")"
+		if t.less(version{0, 6, 0}) {
+			payable = "address(uint160(" + str + "))"
+		}
+		loc := addr.(solcparser.INode).GetLoc()
+		fix := replace(loc.Start.Offset, loc.End.Offset, payable, "convert to "+payable)
+		pass.ReportFix(addr, fix, "address %s is not payable", str)
+		return true
+	})
+}
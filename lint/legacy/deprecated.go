@@ -0,0 +1,389 @@
+package legacy
+
+import (
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// varDeclaration reports the variables declared with var, removed in 0.5.0
+type varDeclaration struct{}
+
+func (varDeclaration) ID() string              { return "var-declaration" }
+func (varDeclaration) Severity() lint.Severity { return lint.SeverityWarning }
+func (varDeclaration) Doc() string {
+	return "var was removed in 0.5.0, the type of the variables must be explicit"
+}
+
+func (varDeclaration) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		obj, ok := node.(*solcparser.VariableDeclarationStatement)
+		if !ok || len(obj.Variables) == 0 {
+			return true
+		}
+
+		if len(obj.Variables) == 1 {
+			// var x = ...
+			decl, ok := obj.Variables[0].(*solcparser.VariableDeclaration)
+			if !ok {
+				return true
+			}
+			typeName, ok := decl.TypeName.(*solcparser.ElementaryTypeName)
+			if !ok || typeName.Name != "var" {
+				return true
+			}
+			var fix *solcparser.SuggestedFix
+			if name, ok := explicitType(pass.Info.TypeOf(decl)); ok && typeName.Loc != nil {
+				fix = replace(typeName.Loc.Start.Offset, typeName.Loc.End.Offset, name, "declare "+decl.Name+" as "+name)
+			}
+			pass.ReportFix(obj, fix, "variable %s is declared with var", decl.Name)
+			return true
+		}
+
+		// var (x, y) = ...
+		names := []string{}
+		components := []string{}
+		complete := true
+		for _, v := range obj.Variables {
+			decl, ok := v.(*solcparser.VariableDeclaration)
+			if !ok {
+				components = append(components, "")
+				continue
+			}
+			if decl.TypeName != nil {
+				// (uint x, uint y) = ...
+				return true
+			}
+			names = append(names, decl.Name)
+			name, ok := explicitType(pass.Info.TypeOf(decl))
+			if !ok {
+				complete = false
+			}
+			components = append(components, name+" "+decl.Name)
+		}
+		var fix *solcparser.SuggestedFix
+		if obj.Loc != nil && complete {
+			start := obj.Loc.Start.Offset
+			if end, ok := paramsEnd(pass.Source, start, obj.Loc.End.Offset); ok {
+				fix = replace(start, end, "("+strings.Join(components, ", ")+")", "declare the types of the variables")
+			}
+		}
+		pass.ReportFix(obj, fix, "variables %s are declared with var", strings.Join(names, ", "))
+		return true
+	})
+}
+
+// explicitType returns the type name of the declaration of a local variable
+// of the type
+func explicitType(typ types.Type) (string, bool) {
+	switch obj := typ.(type) {
+	case *types.IntType, *types.FixedPointType, *types.AddressType, *types.BoolType,
+		*types.FixedBytesType, *types.BytesType, *types.StringType:
+		return obj.String(), true
+	case *types.StructType:
+		return withLocation(obj.Name, obj.Location), true
+	case *types.EnumType:
+		return obj.Name, true
+	case *types.ContractType:
+		if obj.Super {
+			return "", false
+		}
+		return obj.Def.Name, true
+	case *types.UserDefinedValueType:
+		return obj.Name, true
+	case *types.ArrayType:
+		base := obj.Base
+		if t, ok := base.(*types.StructType); ok {
+			// the location of the base is the location of the array
+			base = &types.StructType{Name: t.Name, Def: t.Def}
+		}
+		name, ok := explicitType(base)
+		if !ok {
+			return "", false
+		}
+		name = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(name, " storage"), " memory"), " calldata") + "["
+		if obj.Length != nil {
+			name += obj.Length.String()
+		}
+		return withLocation(name+"]", obj.Location), true
+	}
+	return "", false
+}
+
+func withLocation(name, location string) string {
+	if location == "" {
+		return name
+	}
+	return name + " " + location
+}
+
+// unnamedFallback reports the fallback functions declared with 'function ()',
+// replaced by fallback() and receive() in 0.6.0
+type unnamedFallback struct{}
+
+func (unnamedFallback) ID() string              { return "unnamed-fallback" }
+func (unnamedFallback) Severity() lint.Severity { return lint.SeverityWarning }
+func (unnamedFallback) Doc() string {
+	return "the unnamed fallback function was split in fallback() and receive() in 0.6.0"
+}
+
+func (unnamedFallback) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 6, 0}) {
+		return
+	}
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		if !fn.IsFallback {
+			return
+		}
+		start, end, ok := header(pass.Source, fn)
+		if !ok || !strings.HasPrefix(pass.Source[start:end], "function") {
+			// fallback()
+			return
+		}
+
+		// it is not replaced by receive() even if it only accepts ether, receive()
+		// would reject the calls with data
+		name := "fallback"
+		var fix *solcparser.SuggestedFix
+		if params, ok := paramsEnd(pass.Source, start, end); ok {
+			fix = &solcparser.SuggestedFix{
+				Message: "replace with " + name + "()",
+				Edits:   []solcparser.TextEdit{{Start: start, End: params, NewText: name + "()"}},
+			}
+			switch fn.Visibility {
+			case "default":
+				fix.Edits = append(fix.Edits, solcparser.TextEdit{Start: params, End: params, NewText: " external"})
+			case "public":
+				if offset, ok := findWord(pass.Source, params, end, "public"); ok {
+					fix.Edits = append(fix.Edits, solcparser.TextEdit{Start: offset, End: offset + len("public"), NewText: "external"})
+				}
+			}
+		}
+		pass.ReportFix(fn, fix, "unnamed fallback function, use %s()", name)
+	})
+}
+
+// constructorName reports the constructors declared as a function with the
+// name of the contract, removed in 0.5.0
+type constructorName struct{}
+
+func (constructorName) ID() string              { return "constructor-name" }
+func (constructorName) Severity() lint.Severity { return lint.SeverityWarning }
+func (constructorName) Doc() string {
+	return "the constructors must be declared with the constructor keyword since 0.5.0"
+}
+
+func (constructorName) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	t, _ := target(pass)
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		if c.Kind != "contract" || fn.Name != c.Name {
+			return
+		}
+		var fix *solcparser.SuggestedFix
+		if start, end, ok := header(pass.Source, fn); ok {
+			offset, ok := findWord(pass.Source, start+len("function"), end, fn.Name)
+			params, ok2 := paramsEnd(pass.Source, start, end)
+			if ok && ok2 {
+				fix = &solcparser.SuggestedFix{
+					Message: "replace with constructor",
+					Edits:   []solcparser.TextEdit{{Start: start, End: offset + len(fn.Name), NewText: "constructor"}},
+				}
+				// the visibility of the constructors is ignored since 0.7.0
+				if fn.Visibility == "default" && t.less(version{0, 7, 0}) {
+					fix.Edits = append(fix.Edits, solcparser.TextEdit{Start: params, End: params, NewText: " public"})
+				}
+			}
+		}
+		pass.ReportFix(fn, fix, "constructor declared as function %s, use constructor", fn.Name)
+	})
+}
+
+// yearsDenomination reports the 'years' sub-denomination, removed in 0.5.0
+type yearsDenomination struct{}
+
+func (yearsDenomination) ID() string              { return "years-denomination" }
+func (yearsDenomination) Severity() lint.Severity { return lint.SeverityWarning }
+func (yearsDenomination) Doc() string {
+	return "the years sub-denomination was removed in 0.5.0, use 365 days"
+}
+
+func (yearsDenomination) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		obj, ok := node.(*solcparser.NumberLiteral)
+		if !ok || obj.SubDenomination != solcparser.Years {
+			return true
+		}
+		var fix *solcparser.SuggestedFix
+		if obj.Loc != nil {
+			days := "(" + obj.Number + " * 365 days)"
+			if obj.Number == "1" {
+				days = "365 days"
+			}
+			fix = replace(obj.Loc.Start.Offset, obj.Loc.End.Offset, days, "replace with "+days)
+		}
+		pass.ReportFix(obj, fix, "years is deprecated, use 365 days")
+		return true
+	})
+}
+
+// afterOperator reports the 'after' unary operator, removed in 0.5.0
+type afterOperator struct{}
+
+func (afterOperator) ID() string              { return "after-operator" }
+func (afterOperator) Severity() lint.Severity { return lint.SeverityError }
+func (afterOperator) Doc() string {
+	return "the after operator was never implemented and is a reserved keyword since 0.5.0"
+}
+
+func (afterOperator) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		if obj, ok := node.(*solcparser.UnaryOperation); ok && obj.Operator == "after" {
+			pass.Report(obj, "the after operator is not supported")
+		}
+		return true
+	})
+}
+
+// byteType reports the 'byte' type, an alias of bytes1 removed in 0.8.0
+type byteType struct{}
+
+func (byteType) ID() string              { return "byte-type" }
+func (byteType) Severity() lint.Severity { return lint.SeverityWarning }
+func (byteType) Doc() string {
+	return "the byte type was removed in 0.8.0, use bytes1"
+}
+
+func (byteType) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 8, 0}) {
+		return
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		obj, ok := node.(*solcparser.ElementaryTypeName)
+		if !ok || obj.Name != "byte" {
+			return true
+		}
+		var fix *solcparser.SuggestedFix
+		if obj.Loc != nil {
+			fix = replace(obj.Loc.Start.Offset, obj.Loc.End.Offset, "bytes1", "replace with bytes1")
+		}
+		pass.ReportFix(obj, fix, "byte is deprecated, use bytes1")
+		return true
+	})
+}
+
+// deprecatedBuiltin reports the builtins sha3, suicide and callcode, removed in 0.5.0
+type deprecatedBuiltin struct{}
+
+func (deprecatedBuiltin) ID() string              { return "deprecated-builtin" }
+func (deprecatedBuiltin) Severity() lint.Severity { return lint.SeverityWarning }
+func (deprecatedBuiltin) Doc() string {
+	return "sha3, suicide and callcode were removed in 0.5.0, use keccak256, selfdestruct and delegatecall"
+}
+
+var builtinReplacements = map[string]string{
+	"sha3":    "keccak256",
+	"suicide": "selfdestruct",
+}
+
+func (deprecatedBuiltin) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *solcparser.Identifier:
+			name, ok := builtinReplacements[obj.Name]
+			if !ok || pass.Info.RefOf(obj) != nil {
+				return true
+			}
+			var fix *solcparser.SuggestedFix
+			if obj.Loc != nil {
+				fix = replace(obj.Loc.Start.Offset, obj.Loc.End.Offset, name, "replace with "+name)
+			}
+			pass.ReportFix(obj, fix, "%s is deprecated, use %s", obj.Name, name)
+
+		case *solcparser.MemberAccess:
+			if obj.MemberName != "callcode" {
+				return true
+			}
+			if _, ok := pass.Info.TypeOf(obj.Expression).(*types.AddressType); ok {
+				// delegatecall keeps msg.sender and msg.value, there is no fix
+				pass.Report(obj, "callcode is deprecated, use delegatecall")
+			}
+		}
+		return true
+	})
+}
+
+// callValue reports the options of external calls set with .value() and
+// .gas(), replaced by {value: ..., gas: ...} in 0.7.0
+type callValue struct{}
+
+func (callValue) ID() string              { return "call-value" }
+func (callValue) Severity() lint.Severity { return lint.SeverityWarning }
+func (callValue) Doc() string {
+	return ".value() and .gas() were removed in 0.7.0, use {value: ..., gas: ...}"
+}
+
+func (callValue) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 7, 0}) {
+		return
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		obj, ok := node.(*solcparser.FunctionCall)
+		if !ok {
+			return true
+		}
+
+		// x.call.value(1).gas(2)(data) is a call of x.call with the calls of the options in between
+		options := []string{}
+		complete := true
+		expr := obj.Expression
+		for {
+			call, ok := expr.(*solcparser.FunctionCall)
+			if !ok {
+				break
+			}
+			member, ok := call.Expression.(*solcparser.MemberAccess)
+			if !ok || (member.MemberName != "value" && member.MemberName != "gas") || len(call.Arguments) != 1 {
+				break
+			}
+			arg, ok := text(pass.Source, call.Arguments[0])
+			if !ok {
+				complete = false
+			}
+			options = append([]string{member.MemberName + ": " + arg}, options...)
+			expr = member.Expression
+		}
+		if len(options) == 0 {
+			return true
+		}
+		fn, ok := pass.Info.TypeOf(expr).(*types.FunctionType)
+		if !ok || (fn.Kind != types.FunctionKindExternal && fn.Kind != types.FunctionKindBuiltin && fn.Kind != types.FunctionKindCreation) {
+			return true
+		}
+
+		var fix *solcparser.SuggestedFix
+		base, ok := expr.(solcparser.INode)
+		callee := obj.Expression.(solcparser.INode)
+		if complete && ok && base.GetLoc() != nil && callee.GetLoc() != nil {
+			fix = replace(base.GetLoc().End.Offset, callee.GetLoc().End.Offset, "{"+strings.Join(options, ", ")+"}", "use the call options")
+		}
+		pass.ReportFix(obj, fix, "the call options are set with .value() or .gas(), use {value: ..., gas: ...}")
+		return true
+	})
+}
@@ -0,0 +1,336 @@
+package legacy
+
+import (
+	"sort"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// missingOverride reports the functions that override a function of a base
+// contract without the override keyword (required since 0.6.0)
+type missingOverride struct{}
+
+func (missingOverride) ID() string              { return "missing-override" }
+func (missingOverride) Severity() lint.Severity { return lint.SeverityWarning }
+func (missingOverride) Doc() string {
+	return "the functions that override a function of a base contract must be marked override since 0.6.0"
+}
+
+func (missingOverride) Check(pass *lint.Pass) {
+	v := compilerVersion(pass)
+	if v.less(version{0, 6, 0}) {
+		return
+	}
+	// the implementations of interface functions do not need override since 0.8.8
+	interfaces := v.less(version{0, 8, 8})
+
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		if fn.Name == "" || fn.IsOverride {
+			return
+		}
+		bases, err := pass.Project.Linearize(c)
+		if err != nil {
+			return
+		}
+
+		overridden := []*solcparser.ContractDefinition{}
+		for _, base := range bases[1:] {
+			if base.Kind == "interface" && !interfaces {
+				continue
+			}
+			for _, node := range base.SubNodes {
+				if other, ok := node.(*solcparser.FunctionDefinition); ok && other.Visibility != "private" && sameSignature(pass.Info, fn, other) {
+					overridden = append(overridden, base)
+					break
+				}
+			}
+		}
+		overridden = mostDerived(pass.Project, overridden)
+		if len(overridden) == 0 {
+			return
+		}
+
+		names := []string{}
+		for _, base := range overridden {
+			names = append(names, base.Name)
+		}
+		sort.Strings(names)
+		keyword := "override"
+		if len(names) > 1 {
+			keyword += "(" + strings.Join(names, ", ") + ")"
+		}
+
+		var fix *solcparser.SuggestedFix
+		if start, end, ok := header(pass.Source, fn); ok {
+			offset := end
+			if indx, ok := findWord(pass.Source, start, end, "returns"); ok {
+				offset = indx
+			}
+			text := keyword + " "
+			if offset > 0 && !strings.ContainsRune(" \t\r\n", rune(pass.Source[offset-1])) {
+				text = " " + text
+			}
+			fix = insert(offset, text, "add "+keyword)
+		}
+		pass.ReportFix(fn, fix, "function %s overrides the function of %s but is not marked override", fn.Name, strings.Join(names, ", "))
+	})
+}
+
+// mostDerived removes the contracts that are a base of another contract of the list
+func mostDerived(p *solcparser.Project, list []*solcparser.ContractDefinition) []*solcparser.ContractDefinition {
+	res := []*solcparser.ContractDefinition{}
+	for _, c := range list {
+		isBase := false
+		for _, other := range list {
+			if other == c {
+				continue
+			}
+			bases, err := p.Linearize(other)
+			if err != nil {
+				continue
+			}
+			for _, base := range bases[1:] {
+				if base == c {
+					isBase = true
+				}
+			}
+		}
+		if !isBase {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// sameSignature returns true if the functions have the same name and parameter types
+func sameSignature(info *types.Info, a, b *solcparser.FunctionDefinition) bool {
+	if a.Name != b.Name || len(a.Parameters) != len(b.Parameters) {
+		return false
+	}
+	for i := range a.Parameters {
+		ta, tb := info.TypeOf(a.Parameters[i]), info.TypeOf(b.Parameters[i])
+		if ta == nil || tb == nil || !types.Identical(ta, tb) {
+			return false
+		}
+	}
+	return true
+}
+
+// constantFunction reports the functions declared constant, replaced by view in 0.5.0
+type constantFunction struct{}
+
+func (constantFunction) ID() string              { return "constant-function" }
+func (constantFunction) Severity() lint.Severity { return lint.SeverityWarning }
+func (constantFunction) Doc() string {
+	return "the constant functions are view functions since 0.5.0"
+}
+
+func (constantFunction) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		if fn.StateMutability != "constant" {
+			return
+		}
+		mutability := "view"
+		if isPure(pass, fn) {
+			mutability = "pure"
+		}
+		var fix *solcparser.SuggestedFix
+		if start, end, ok := header(pass.Source, fn); ok {
+			if offset, ok := findWord(pass.Source, start, end, "constant"); ok {
+				fix = replace(offset, offset+len("constant"), mutability, "replace constant with "+mutability)
+			}
+		}
+		pass.ReportFix(fn, fix, "function %s is declared constant, use %s", fn.Name, mutability)
+	})
+}
+
+// isPure returns true if the function does not read the state
+func isPure(pass *lint.Pass, fn *solcparser.FunctionDefinition) bool {
+	if len(fn.Modifiers) != 0 || fn.Body == nil {
+		return false
+	}
+	pure := true
+	solcparser.Inspect(fn.Body, func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *solcparser.InlineAssemblyStatement:
+			pure = false
+		case *solcparser.Identifier:
+			switch ref := pass.Info.RefOf(obj).(type) {
+			case *solcparser.StateVariableDeclarationVariable:
+				pure = pure && ref.IsDeclaredConst
+			case *solcparser.FunctionDefinition:
+				pure = pure && ref.StateMutability == "pure"
+			case nil:
+				switch obj.Name {
+				case "msg", "block", "tx", "now", "this", "gasleft", "blockhash":
+					pure = false
+				}
+			}
+		case *solcparser.MemberAccess:
+			switch obj.MemberName {
+			case "balance", "code", "codehash":
+				pure = false
+			}
+			if fn, ok := pass.Info.TypeOf(obj).(*types.FunctionType); ok && fn.Kind != types.FunctionKindBuiltin && fn.Mutability != "pure" {
+				pure = false
+			}
+		}
+		return pure
+	})
+	return pure
+}
+
+// functionVisibility reports the functions without explicit visibility
+// (public by default before 0.5.0)
+type functionVisibility struct{}
+
+func (functionVisibility) ID() string              { return "function-visibility" }
+func (functionVisibility) Severity() lint.Severity { return lint.SeverityWarning }
+func (functionVisibility) Doc() string {
+	return "the visibility of the functions must be explicit since 0.5.0"
+}
+
+func (functionVisibility) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		// the constructors named after the contract are public by default
+		if fn.Visibility != "default" || fn.IsConstructor || fn.Name == c.Name || c.Kind == "interface" {
+			return
+		}
+		// the fallback function must be external
+		visibility, name := "public", "function "+fn.Name
+		if fn.IsFallback {
+			visibility, name = "external", "fallback function"
+		}
+		var fix *solcparser.SuggestedFix
+		if start, end, ok := header(pass.Source, fn); ok {
+			if offset, ok := paramsEnd(pass.Source, start, end); ok {
+				fix = insert(offset, " "+visibility, "make the function "+visibility)
+			}
+		}
+		pass.ReportFix(fn, fix, "%s has no explicit visibility", name)
+	})
+}
+
+// throwStatement reports the throw statements, replaced by revert() in 0.5.0
+type throwStatement struct{}
+
+func (throwStatement) ID() string              { return "throw-statement" }
+func (throwStatement) Severity() lint.Severity { return lint.SeverityWarning }
+func (throwStatement) Doc() string {
+	return "throw was removed in 0.5.0, use revert()"
+}
+
+func (throwStatement) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		obj, ok := node.(*solcparser.ThrowStatement)
+		if !ok {
+			return true
+		}
+		var fix *solcparser.SuggestedFix
+		if loc := obj.Loc; loc != nil && strings.HasPrefix(pass.Source[loc.Start.Offset:], "throw") {
+			fix = replace(loc.Start.Offset, loc.Start.Offset+len("throw"), "revert()", "replace throw with revert()")
+		}
+		pass.ReportFix(obj, fix, "throw is deprecated, use revert()")
+		return true
+	})
+}
+
+// dataLocation reports the parameters and the local variables of reference
+// types without a data location, required since 0.5.0. The parameters were
+// in memory (calldata for the external functions) and the local variables
+// were storage pointers.
+type dataLocation struct{}
+
+func (dataLocation) ID() string              { return "data-location" }
+func (dataLocation) Severity() lint.Severity { return lint.SeverityWarning }
+func (dataLocation) Doc() string {
+	return "the variables of reference types must have an explicit data location since 0.5.0"
+}
+
+func (dataLocation) Check(pass *lint.Pass) {
+	if !removedIn(pass, version{0, 5, 0}) {
+		return
+	}
+	check := func(node interface{}, location string) {
+		decl, ok := node.(*solcparser.VariableDeclaration)
+		if !ok || decl.StorageLocation != "" || !isReference(pass.Info.TypeOf(decl)) {
+			return
+		}
+		name := decl.Name
+		if name == "" {
+			name = "unnamed"
+		}
+		if location == "" {
+			// an uninitialized storage pointer is an error since 0.5.0
+			pass.Report(decl, "variable %s has no data location", name)
+			return
+		}
+		var fix *solcparser.SuggestedFix
+		if typeName, ok := decl.TypeName.(solcparser.INode); ok && typeName.GetLoc() != nil {
+			fix = insert(typeName.GetLoc().End.Offset, " "+location, "add the "+location+" data location")
+		}
+		pass.ReportFix(decl, fix, "variable %s has no data location, it is in %s", name, location)
+	}
+
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		location := "memory"
+		if fn.Visibility == "external" {
+			location = "calldata"
+		}
+		for _, p := range fn.Parameters {
+			check(p, location)
+		}
+		returns, _ := fn.ReturnParameters.([]interface{})
+		for _, p := range returns {
+			check(p, "memory")
+		}
+		if fn.Body == nil {
+			return
+		}
+		solcparser.Inspect(fn.Body, func(node interface{}) bool {
+			if stmt, ok := node.(*solcparser.VariableDeclarationStatement); ok {
+				location := ""
+				if stmt.InitialValue != nil {
+					location = "storage"
+				}
+				for _, v := range stmt.Variables {
+					check(v, location)
+				}
+			}
+			return true
+		})
+	})
+	for _, child := range pass.Unit.Children {
+		if c, ok := child.(*solcparser.ContractDefinition); ok {
+			for _, node := range c.SubNodes {
+				if m, ok := node.(*solcparser.ModifierDefinition); ok {
+					params, _ := m.Parameters.([]interface{})
+					for _, p := range params {
+						check(p, "memory")
+					}
+				}
+			}
+		}
+	}
+}
+
+// isReference returns true for the types that need a data location
+func isReference(typ types.Type) bool {
+	switch typ.(type) {
+	case *types.BytesType, *types.StringType, *types.ArrayType, *types.StructType:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+// Package legacy implements lint rules for the syntax of old compiler versions
+// that newer versions reject. The rules suggest the fix that rewrites the
+// code to the modern syntax when there is one. The rules are registered in the
+// lint registry when the package is imported.
+//
+// The 'target' option of the rules is the compiler version to migrate to
+// (i.e. '0.8.0'). The constructs still valid with the target version are not
+// reported. Without target all the legacy constructs are reported.
+package legacy
+
+import (
+	"regexp"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+var rules = []lint.Rule{
+	&missingOverride{},
+	&constantFunction{},
+	&functionVisibility{},
+	&throwStatement{},
+	&varDeclaration{},
+	&unnamedFallback{},
+	&constructorName{},
+	&yearsDenomination{},
+	&afterOperator{},
+	&byteType{},
+	&deprecatedBuiltin{},
+	&callValue{},
+	&dataLocation{},
+	&lowLevelCall{},
+	&payableAddress{},
+	&targetPragma{},
+}
+
+func init() {
+	for _, rule := range rules {
+		lint.Register(rule)
+	}
+}
+
+// Rules returns the legacy rules
+func Rules() []lint.Rule {
+	return append([]lint.Rule{}, rules...)
+}
+
+// functions calls fn for the functions of the contracts of the source unit
+func functions(unit *solcparser.SourceUnit, fn func(f *solcparser.FunctionDefinition, c *solcparser.ContractDefinition)) {
+	for _, child := range unit.Children {
+		c, ok := child.(*solcparser.ContractDefinition)
+		if !ok {
+			continue
+		}
+		for _, node := range c.SubNodes {
+			if f, ok := node.(*solcparser.FunctionDefinition); ok {
+				fn(f, c)
+			}
+		}
+	}
+}
+
+// header returns the range of the source of the function before its body
+// (i.e. 'function f() public returns (uint)')
+func header(src string, fn *solcparser.FunctionDefinition) (int, int, bool) {
+	if fn.Loc == nil || fn.Loc.End.Offset > len(src) {
+		return 0, 0, false
+	}
+	start, end := fn.Loc.Start.Offset, fn.Loc.End.Offset
+	if body, ok := fn.Body.(solcparser.INode); ok && body.GetLoc() != nil {
+		end = body.GetLoc().Start.Offset
+	} else if strings.HasSuffix(src[start:end], ";") {
+		end--
+	}
+	return start, end, true
+}
+
+// findWord returns the offset of the first keyword in the range of the source
+func findWord(src string, start, end int, word string) (int, bool) {
+	loc := regexp.MustCompile(`\b` + word + `\b`).FindStringIndex(src[start:end])
+	if loc == nil {
+		return 0, false
+	}
+	return start + loc[0], true
+}
+
+// paramsEnd returns the offset after the parameter list of the function header
+func paramsEnd(src string, start, end int) (int, bool) {
+	open := strings.Index(src[start:end], "(")
+	if open == -1 {
+		return 0, false
+	}
+	depth := 0
+	for i := start + open; i < end; i++ {
+		switch src[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// insert returns the fix that inserts the text at the offset
+func insert(offset int, text, message string) *solcparser.SuggestedFix {
+	return &solcparser.SuggestedFix{
+		Message: message,
+		Edits:   []solcparser.TextEdit{{Start: offset, End: offset, NewText: text}},
+	}
+}
+
+// replace returns the fix that replaces the range of the source with the text
+func replace(start, end int, text, message string) *solcparser.SuggestedFix {
+	return &solcparser.SuggestedFix{
+		Message: message,
+		Edits:   []solcparser.TextEdit{{Start: start, End: end, NewText: text}},
+	}
+}
+
+// text returns the source of a node
+func text(src string, node interface{}) (string, bool) {
+	n, ok := node.(solcparser.INode)
+	if !ok || n.GetLoc() == nil || n.GetLoc().End.Offset > len(src) {
+		return "", false
+	}
+	return src[n.GetLoc().Start.Offset:n.GetLoc().End.Offset], true
+}
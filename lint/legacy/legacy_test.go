@@ -0,0 +1,118 @@
+package legacy
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/lint/linttest"
+)
+
+func TestRules(t *testing.T) {
+	linttest.Run(t, "testdata", Rules())
+}
+
+func TestTarget(t *testing.T) {
+	src := `pragma solidity ^0.4.24;
+
+contract A {
+    byte flag;
+
+    function A() public {}
+
+    function f() public {
+        throw;
+        msg.sender.call.value(1)();
+        selfdestruct(msg.sender);
+    }
+}`
+	cases := []struct {
+		target string
+		rules  []string
+	}{
+		{"0.4.24", []string{}},
+		{"0.5.0", []string{"target-pragma", "constructor-name", "throw-statement", "low-level-call"}},
+		{"0.7.0", []string{"target-pragma", "constructor-name", "throw-statement", "call-value", "low-level-call"}},
+		{"0.8.0", []string{"target-pragma", "byte-type", "constructor-name", "throw-statement", "call-value", "low-level-call", "payable-address"}},
+	}
+	for _, c := range cases {
+		config, err := Config(c.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diags, err := lint.New(config).Lint(map[string]string{"a.sol": src})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules := []string{}
+		for _, d := range diags {
+			rules = append(rules, d.Rule)
+		}
+		if !reflect.DeepEqual(rules, c.rules) {
+			t.Fatalf("target %s: expected %v but found %v", c.target, c.rules, rules)
+		}
+	}
+
+	if _, err := Config("0.8.x"); err == nil {
+		t.Fatal("invalid target should fail")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join("testdata", "migrate", "token.sol")
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := ioutil.ReadFile(path + ".golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Config("0.8.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed, diags, err := lint.New(config).Fix(map[string]string{path: string(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics %v", diags)
+	}
+	if fixed[path] != string(golden) {
+		t.Fatalf("bad migration:\n%s", fixed[path])
+	}
+}
+
+func TestAllows(t *testing.T) {
+	cases := []struct {
+		pragma  string
+		version string
+		allowed bool
+	}{
+		{"^0.4.24", "0.4.26", true},
+		{"^0.4.24", "0.5.0", false},
+		{"^1.2.0", "1.9.0", true},
+		{"~0.8.1", "0.8.9", true},
+		{"~0.8.1", "0.9.0", false},
+		{">=0.4.22 <0.9.0", "0.8.0", true},
+		{">=0.4.22 <0.9.0", "0.9.0", false},
+		{"0.8.19", "0.8.19", true},
+		{"=0.8.19", "0.8.20", false},
+		{"^0.4.0 || ^0.8.0", "0.8.3", true},
+		{">0.8.0", "0.8.0", false},
+		{"<=0.8.0", "0.8.0", true},
+	}
+	for _, c := range cases {
+		v, err := parseVersion(c.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allows(c.pragma, v) != c.allowed {
+			t.Fatalf("%s allows %s should be %v", c.pragma, c.version, c.allowed)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package legacy
+
+import (
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// targetPragma reports the solidity pragma that does not accept the target
+// version. It is only checked if there is a target.
+type targetPragma struct{}
+
+func (targetPragma) ID() string              { return "target-pragma" }
+func (targetPragma) Severity() lint.Severity { return lint.SeverityWarning }
+func (targetPragma) Doc() string {
+	return "the solidity pragma must accept the target compiler version"
+}
+
+func (targetPragma) Check(pass *lint.Pass) {
+	t, ok := target(pass)
+	if !ok {
+		return
+	}
+	pragma := solidityPragma(pass.Unit)
+	if pragma == nil || allows(pragma.Value, t) {
+		return
+	}
+	var fix *solcparser.SuggestedFix
+	if loc := pragma.Loc; loc != nil && loc.End.Offset <= len(pass.Source) {
+		// the value goes from the end of 'solidity' to the semicolon
+		src := pass.Source[:loc.End.Offset]
+		if start, ok := findWord(src, loc.Start.Offset, len(src), "solidity"); ok {
+			start += len("solidity")
+			start += len(src[start:]) - len(strings.TrimLeft(src[start:], " \t\r\n"))
+			end := len(strings.TrimRight(strings.TrimSuffix(src, ";"), " \t\r\n"))
+			value := "^" + t.String()
+			fix = replace(start, end, value, "require "+value)
+		}
+	}
+	pass.ReportFix(pragma, fix, "pragma solidity %s does not accept the target version %s", pragma.Value, t)
+}
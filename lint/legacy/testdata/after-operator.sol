@@ -0,0 +1,10 @@
+pragma solidity ^0.4.24;
+
+contract A {
+    uint256 deadline;
+
+    function f() public {
+        after deadline; // want
+        delete deadline;
+    }
+}
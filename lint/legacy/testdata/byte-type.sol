@@ -0,0 +1,11 @@
+pragma solidity ^0.7.0;
+
+contract A {
+    byte public flag; // want
+    byte[] public flags; // want
+    bytes1 public other;
+
+    function first(bytes memory data) public pure returns (byte) { // want
+        return byte(data[0]); // want
+    }
+}
@@ -0,0 +1,20 @@
+pragma solidity ^0.6.0;
+
+interface Vault {
+    function deposit(uint256 id) external payable;
+}
+
+contract A {
+    struct Options {
+        uint256 value;
+    }
+
+    function pay(address payable to, Vault vault) public payable {
+        (bool ok, ) = to.call.value(msg.value)(""); // want
+        require(ok);
+        to.call.value(1 ether).gas(2300)(abi.encode(1)); // want
+        vault.deposit.value(msg.value)(1); // want
+        to.call{value: 1}("");
+        to.transfer(1);
+    }
+}
@@ -0,0 +1,18 @@
+pragma solidity ^0.4.24;
+
+contract A {
+    uint256 public total;
+    uint256 constant LIMIT = 10;
+
+    function get() public constant returns (uint256) { // want
+        return total;
+    }
+
+    function limit() constant public returns (uint256) { // want
+        return LIMIT;
+    }
+
+    function set(uint256 x) public {
+        total = x;
+    }
+}
@@ -0,0 +1,23 @@
+pragma solidity ^0.4.24;
+
+contract Token {
+    address owner;
+
+    function Token() { // want
+        owner = msg.sender;
+    }
+}
+
+contract Vault {
+    address owner;
+
+    function Vault(address _owner) public { // want
+        owner = _owner;
+    }
+
+    function Token() public {}
+}
+
+library Math {
+    function Math() internal {}
+}
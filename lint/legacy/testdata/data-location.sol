@@ -0,0 +1,28 @@
+pragma solidity ^0.4.24;
+
+contract A {
+    struct Point {
+        uint256 x;
+    }
+
+    Point[] points;
+
+    function hash(bytes data) public pure returns (bytes32) { // want
+        return keccak256(data);
+    }
+
+    function concat(bytes memory a) public pure returns (bytes b) { // want
+        b = a;
+    }
+
+    function store(uint256[] values) external { // want
+        Point p = points[0]; // want
+        Point q; // want
+        p.x = values[0];
+        q.x = 1;
+    }
+
+    function count(uint256 a, bytes calldata data) external pure returns (uint256) {
+        return a + data.length;
+    }
+}
@@ -0,0 +1,27 @@
+pragma solidity ^0.4.24;
+
+contract A {
+    address owner;
+
+    function hash(bytes data) public pure returns (bytes32) {
+        return sha3(data); // want
+    }
+
+    function kill() public {
+        suicide(owner); // want
+    }
+
+    function proxy(address target, bytes data) public {
+        require(target.callcode(data)); // want
+    }
+}
+
+contract B {
+    function sha3(uint256 x) internal pure returns (uint256) {
+        return x;
+    }
+
+    function f() public pure returns (uint256) {
+        return sha3(1);
+    }
+}
@@ -0,0 +1,29 @@
+pragma solidity ^0.4.24;
+
+interface I {
+    function f() external;
+}
+
+contract A {
+    uint256 total;
+
+    function A() {
+    }
+
+    function () payable { // want
+    }
+
+    function set(uint256 x) { // want
+        total = x;
+    }
+
+    function get() constant returns (uint256) { // want
+        return total;
+    }
+
+    function add(function (uint256) external f) internal {
+    }
+
+    function g(uint256[] memory xs) public {
+    }
+}
@@ -0,0 +1,16 @@
+pragma solidity ^0.4.24;
+
+contract A {
+    function pay(address to, bytes data) public {
+        require(to.call.value(1)()); // want
+        require(to.call(data), "call failed"); // want
+        to.call(); // want
+        if (!to.delegatecall(data)) { // want
+            revert();
+        }
+        if (msg.value > 0) assert(to.call(data)); // want
+        to.call(bytes4(keccak256("f(uint256)")), 1); // want
+        (bool ok, ) = to.call(data);
+        require(ok);
+    }
+}
@@ -0,0 +1,39 @@
+pragma solidity ^0.4.24;
+
+contract Token {
+    address owner;
+    mapping(address => uint256) balances;
+    uint256 constant LOCK = 1 years;
+    byte flag;
+
+    function Token() {
+        owner = msg.sender;
+    }
+
+    function () payable {
+    }
+
+    function balanceOf(address who) constant returns (uint256) {
+        return balances[who];
+    }
+
+    function lockTime() constant returns (uint256) {
+        return LOCK;
+    }
+
+    function withdraw(uint256 amount) {
+        if (balances[msg.sender] < amount) throw;
+        var remaining = balances[msg.sender] - amount;
+        balances[msg.sender] = remaining;
+        require(msg.sender.call.value(amount)());
+    }
+
+    function hash(bytes data) returns (bytes32) {
+        return sha3(data);
+    }
+
+    function kill() {
+        require(msg.sender == owner);
+        suicide(owner);
+    }
+}
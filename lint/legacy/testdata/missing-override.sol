@@ -0,0 +1,43 @@
+pragma solidity ^0.8.8;
+
+interface I {
+    function total() external view returns (uint256);
+}
+
+abstract contract A {
+    function f(uint256 x) public virtual returns (uint256);
+    function g() internal virtual {}
+    function h(uint256 x) public virtual {}
+    function p() private {}
+}
+
+contract B is A {
+    function f(uint256 x) public virtual returns (uint256) { // want
+        return x;
+    }
+    function g() internal virtual { // want
+    }
+    function h(address x) public {}
+    function p() private {}
+}
+
+contract C is B {
+    function f(uint256 x) public override returns (uint256) {
+        return x + 1;
+    }
+    function g() internal {} // want
+}
+
+abstract contract D {
+    function g() internal virtual {}
+}
+
+contract E is B, D {
+    function g() internal {} // want
+}
+
+contract F is I {
+    function total() external pure returns (uint256) {
+        return 1;
+    }
+}
@@ -0,0 +1,18 @@
+pragma solidity ^0.7.0;
+
+contract A {
+    address owner;
+    address payable wallet;
+
+    function withdraw() public {
+        msg.sender.transfer(1); // want
+        wallet.transfer(1);
+        owner.transfer(1); // want
+        require(owner.send(1)); // want
+        selfdestruct(owner); // want
+    }
+
+    function kill() public {
+        selfdestruct(msg.sender); // want
+    }
+}
@@ -0,0 +1,3 @@
+pragma solidity ^0.8.0;
+
+contract A {}
@@ -0,0 +1,13 @@
+pragma solidity ^0.4.24;
+
+contract A {
+    address owner;
+
+    function withdraw() public {
+        if (msg.sender != owner) throw; // want
+        if (this.balance == 0) {
+            throw; // want
+        }
+        msg.sender.transfer(this.balance);
+    }
+}
@@ -0,0 +1,29 @@
+pragma solidity ^0.5.0;
+
+contract A {
+    uint256 calls;
+
+    function () external payable { // want
+    }
+}
+
+contract B {
+    uint256 calls;
+
+    function () external { // want
+        calls++;
+    }
+}
+
+contract C {
+    event Received(uint256 value);
+
+    function() payable { // want
+        emit Received(msg.value);
+    }
+}
+
+contract D {
+    fallback() external {}
+    receive() external payable {}
+}
@@ -0,0 +1,27 @@
+pragma solidity ^0.4.24;
+
+contract A {
+    struct Point {
+        uint256 x;
+        uint256 y;
+    }
+
+    Point[] points;
+    uint256[] values;
+
+    function pair() internal pure returns (uint256, bool) {
+        return (1, true);
+    }
+
+    function f() public {
+        var count = 10; // want
+        var name = "token"; // want
+        var owner = msg.sender; // want
+        var point = points[0]; // want
+        var list = values; // want
+        var (a, b) = pair(); // want
+        var (, c) = pair(); // want
+        (uint256 d, bool e) = pair();
+        uint256 g = count;
+    }
+}
@@ -0,0 +1,11 @@
+pragma solidity ^0.4.24;
+
+contract A {
+    uint256 constant LOCK = 1 years; // want
+    uint256 constant VESTING = 4 years; // want
+    uint256 constant DELAY = 30 days;
+
+    function unlock(uint256 start) public view returns (uint256) {
+        return start + LOCK / 2 years; // want
+    }
+}
@@ -0,0 +1,151 @@
+package legacy
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// version is a compiler version (major, minor, patch)
+type version [3]int
+
+// latest is the compiler version assumed when there is no target
+var latest = version{0, 8, 30}
+
+func (v version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+func (v version) less(o version) bool {
+	for i := 0; i < 3; i++ {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+var versionRe = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?$`)
+
+// parseVersion parses a version like 0.8.0 or 0.8
+func parseVersion(str string) (version, error) {
+	var v version
+	match := versionRe.FindStringSubmatch(strings.TrimSpace(str))
+	if match == nil {
+		return v, fmt.Errorf("invalid compiler version '%s'", str)
+	}
+	for i := 0; i < 3; i++ {
+		v[i], _ = strconv.Atoi(match[i+1])
+	}
+	return v, nil
+}
+
+// target returns the compiler version of the 'target' option of the rule
+func target(pass *lint.Pass) (version, bool) {
+	v, err := parseVersion(pass.StringOption("target", ""))
+	if err != nil {
+		return latest, false
+	}
+	return v, true
+}
+
+// removedIn returns true if the construct removed in the version is not
+// valid with the target version
+func removedIn(pass *lint.Pass, v version) bool {
+	t, _ := target(pass)
+	return !t.less(v)
+}
+
+// compilerVersion returns the target version or the minimum version of the
+// solidity pragma of the unit if there is no target
+func compilerVersion(pass *lint.Pass) version {
+	if t, ok := target(pass); ok {
+		return t
+	}
+	if pragma := solidityPragma(pass.Unit); pragma != nil {
+		if min, ok := minVersion(pragma.Value); ok {
+			return min
+		}
+	}
+	return latest
+}
+
+// solidityPragma returns the solidity version pragma of the unit
+func solidityPragma(unit *solcparser.SourceUnit) *solcparser.PragmaDirective {
+	for _, child := range unit.Children {
+		if pragma, ok := child.(*solcparser.PragmaDirective); ok && pragma.Name == "solidity" {
+			return pragma
+		}
+	}
+	return nil
+}
+
+var constraintRe = regexp.MustCompile(`(\^|~|>=|<=|>|<|=)?\s*v?(\d+(?:\.\d+){0,2})`)
+
+// minVersion returns the first version in the value of a solidity pragma
+func minVersion(value string) (version, bool) {
+	match := constraintRe.FindStringSubmatch(value)
+	if match == nil {
+		return version{}, false
+	}
+	v, err := parseVersion(match[2])
+	return v, err == nil
+}
+
+// allows returns true if the value of a solidity pragma (i.e. '>=0.4.22 <0.9.0'
+// or '^0.8.0') accepts the version
+func allows(value string, v version) bool {
+	for _, set := range strings.Split(value, "||") {
+		ok := true
+		for _, match := range constraintRe.FindAllStringSubmatch(set, -1) {
+			c, err := parseVersion(match[2])
+			if err != nil {
+				return false
+			}
+			switch match[1] {
+			case "^":
+				// the versions with the same first non-zero component
+				upper := version{c[0] + 1}
+				if c[0] == 0 {
+					upper = version{0, c[1] + 1}
+				}
+				ok = ok && !v.less(c) && v.less(upper)
+			case "~":
+				ok = ok && !v.less(c) && v.less(version{c[0], c[1] + 1})
+			case ">=":
+				ok = ok && !v.less(c)
+			case ">":
+				ok = ok && c.less(v)
+			case "<=":
+				ok = ok && !c.less(v)
+			case "<":
+				ok = ok && v.less(c)
+			default:
+				ok = ok && v == c
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Config returns the config that enables the legacy rules for the target
+// compiler version
+func Config(target string) (*lint.Config, error) {
+	if _, err := parseVersion(target); err != nil {
+		return nil, err
+	}
+	config := &lint.Config{Rules: map[string]*lint.RuleConfig{}}
+	for _, rule := range rules {
+		config.Rules[rule.ID()] = &lint.RuleConfig{
+			Options: map[string]interface{}{"target": target},
+		}
+	}
+	return config, nil
+}
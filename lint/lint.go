@@ -0,0 +1,259 @@
+// Package lint is a framework to write and run checks over the AST.
+//
+// A Rule inspects one source unit at a time through a Pass and reports
+// diagnostics. The rules register themselves in a global registry and the
+// Linter runs the ones enabled by the Config. The diagnostics can be
+// suppressed with comments in the source:
+//
+//	// sollint-disable-next-line rule-a, rule-b
+//	// sollint-disable-line rule-a
+//	// sollint-disable rule-a
+//	// sollint-enable rule-a
+//
+// Without rule ids the comments apply to all the rules.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// Severity is the importance of a diagnostic
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity parses the name of a severity
+func ParseSeverity(str string) (Severity, error) {
+	switch str {
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return 0, fmt.Errorf("unknown severity '%s'", str)
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(data []byte) error {
+	res, err := ParseSeverity(string(data))
+	if err != nil {
+		return err
+	}
+	*s = res
+	return nil
+}
+
+// Rule is a check over a source unit
+type Rule interface {
+	// ID is the unique name of the rule (i.e. no-tx-origin)
+	ID() string
+
+	// Severity is the default severity of the diagnostics of the rule
+	Severity() Severity
+
+	// Doc is a short description of what the rule checks
+	Doc() string
+
+	// Check reports the problems found in the source unit of the pass
+	Check(pass *Pass)
+}
+
+// Diagnostic is a problem reported by a rule
+type Diagnostic struct {
+	Rule     string               `json:"rule"`
+	Severity Severity             `json:"severity"`
+	Message  string               `json:"message"`
+	File     string               `json:"file"`
+	Loc      *solcparser.Location `json:"loc,omitempty"`
+
+	// Fixes are the suggested changes of the source that fix the problem
+	Fixes []*solcparser.SuggestedFix `json:"fixes,omitempty"`
+}
+
+func (d *Diagnostic) String() string {
+	if d.Loc == nil {
+		return fmt.Sprintf("%s: %s: %s", d.File, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Loc.Start.Line, d.Loc.Start.Column+1, d.Severity, d.Message)
+}
+
+// Pass is the input of a rule for one source unit
+type Pass struct {
+	Rule Rule
+
+	// File is the name of the source unit in the project
+	File   string
+	Source string
+	Unit   *solcparser.SourceUnit
+
+	// Project are all the source units being linted
+	Project *solcparser.Project
+
+	// Info are the types of the project
+	Info *types.Info
+
+	// Severity is the configured severity of the rule
+	Severity Severity
+
+	// Options are the configured options of the rule
+	Options map[string]interface{}
+
+	report func(d *Diagnostic)
+	memo   map[interface{}]interface{}
+}
+
+// Memo returns the value computed by fn for the key. The values are shared by
+// all the passes of a run (i.e. the call graph of the project).
+func (p *Pass) Memo(key interface{}, fn func() interface{}) interface{} {
+	if val, ok := p.memo[key]; ok {
+		return val
+	}
+	val := fn()
+	p.memo[key] = val
+	return val
+}
+
+// Report reports a problem at the position of the node
+func (p *Pass) Report(node interface{}, format string, args ...interface{}) {
+	p.ReportFix(node, nil, format, args...)
+}
+
+// ReportFix reports a problem at the position of the node with a suggested fix.
+// The fix can be nil.
+func (p *Pass) ReportFix(node interface{}, fix *solcparser.SuggestedFix, format string, args ...interface{}) {
+	var loc *solcparser.Location
+	if n, ok := node.(solcparser.INode); ok {
+		loc = n.GetLoc()
+	}
+	p.ReportAtFix(loc, fix, format, args...)
+}
+
+// ReportAt reports a problem at a position of the source
+func (p *Pass) ReportAt(loc *solcparser.Location, format string, args ...interface{}) {
+	p.ReportAtFix(loc, nil, format, args...)
+}
+
+// ReportAtFix reports a problem at a position of the source with a suggested fix
+func (p *Pass) ReportAtFix(loc *solcparser.Location, fix *solcparser.SuggestedFix, format string, args ...interface{}) {
+	d := &Diagnostic{
+		Rule:     p.Rule.ID(),
+		Severity: p.Severity,
+		Message:  fmt.Sprintf(format, args...),
+		File:     p.File,
+		Loc:      loc,
+	}
+	if fix != nil {
+		d.Fixes = []*solcparser.SuggestedFix{fix}
+	}
+	p.report(d)
+}
+
+// IntOption returns an integer option of the rule or the default value
+func (p *Pass) IntOption(name string, def int) int {
+	switch obj := p.Options[name].(type) {
+	case int:
+		return obj
+	case float64:
+		return int(obj)
+	case string:
+		if num, err := strconv.Atoi(obj); err == nil {
+			return num
+		}
+	}
+	return def
+}
+
+// BoolOption returns a boolean option of the rule or the default value
+func (p *Pass) BoolOption(name string, def bool) bool {
+	if obj, ok := p.Options[name].(bool); ok {
+		return obj
+	}
+	return def
+}
+
+// StringOption returns a string option of the rule or the default value
+func (p *Pass) StringOption(name string, def string) string {
+	if obj, ok := p.Options[name].(string); ok {
+		return obj
+	}
+	return def
+}
+
+// StringsOption returns a list of strings option of the rule or the default value
+func (p *Pass) StringsOption(name string, def []string) []string {
+	list, ok := p.Options[name].([]interface{})
+	if !ok {
+		return def
+	}
+	res := []string{}
+	for _, item := range list {
+		if str, ok := item.(string); ok {
+			res = append(res, str)
+		}
+	}
+	return res
+}
+
+// LineOf returns the text of a line (starting at 1) of the source
+func (p *Pass) LineOf(line int) string {
+	lines := strings.Split(p.Source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSuffix(lines[line-1], "\r")
+}
+
+var registry = map[string]Rule{}
+
+// Register adds a rule to the registry. It panics if the id is already registered.
+func Register(rule Rule) {
+	if _, ok := registry[rule.ID()]; ok {
+		panic(fmt.Sprintf("lint: rule %s registered twice", rule.ID()))
+	}
+	registry[rule.ID()] = rule
+}
+
+// Lookup returns the registered rule with the id
+func Lookup(id string) (Rule, bool) {
+	rule, ok := registry[id]
+	return rule, ok
+}
+
+// Rules returns the registered rules sorted by id
+func Rules() []Rule {
+	res := []Rule{}
+	for _, rule := range registry {
+		res = append(res, rule)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID() < res[j].ID()
+	})
+	return res
+}
@@ -0,0 +1,321 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// funcRule reports every function
+type funcRule struct{}
+
+func (funcRule) ID() string         { return "test-func" }
+func (funcRule) Severity() Severity { return SeverityWarning }
+func (funcRule) Doc() string        { return "reports the functions" }
+
+func (funcRule) Check(pass *Pass) {
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		if fn, ok := node.(*solcparser.FunctionDefinition); ok {
+			pass.Report(fn, "function %s", fn.Name)
+		}
+		return true
+	})
+}
+
+// lineRule reports the lines longer than the 'max' option
+type lineRule struct{}
+
+func (lineRule) ID() string         { return "test-line" }
+func (lineRule) Severity() Severity { return SeverityInfo }
+func (lineRule) Doc() string        { return "reports the long lines" }
+
+func (lineRule) Check(pass *Pass) {
+	max := pass.IntOption("max", 80)
+	offset := 0
+	for i, line := range strings.Split(pass.Source, "\n") {
+		if len(line) > max {
+			pos := solcparser.Position{Offset: offset, Line: i + 1}
+			pass.ReportAt(&solcparser.Location{Start: pos, End: pos}, "line longer than %d", max)
+		}
+		offset += len(line) + 1
+	}
+}
+
+// panicRule fails on every file
+type panicRule struct{}
+
+func (panicRule) ID() string         { return "test-panic" }
+func (panicRule) Severity() Severity { return SeverityInfo }
+func (panicRule) Doc() string        { return "panics" }
+func (panicRule) Check(pass *Pass)   { panic("boom") }
+
+// growRule reports the function names shorter than 3 characters and
+// suggests to repeat their first character
+type growRule struct{}
+
+func (growRule) ID() string         { return "test-grow" }
+func (growRule) Severity() Severity { return SeverityWarning }
+func (growRule) Doc() string        { return "reports the short function names" }
+
+func (growRule) Check(pass *Pass) {
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		if fn, ok := node.(*solcparser.FunctionDefinition); ok && len(fn.Name) < 3 {
+			offset := fn.Loc.Start.Offset + strings.Index(pass.Source[fn.Loc.Start.Offset:], " "+fn.Name) + 1
+			fix := &solcparser.SuggestedFix{
+				Message: "grow",
+				Edits:   []solcparser.TextEdit{{Start: offset, End: offset, NewText: fn.Name[:1]}},
+			}
+			pass.ReportFix(fn, fix, "function %s is short", fn.Name)
+		}
+		return true
+	})
+}
+
+func init() {
+	Register(funcRule{})
+	Register(lineRule{})
+}
+
+func lintSource(t *testing.T, config *Config, src string, rules ...Rule) []string {
+	t.Helper()
+
+	diags, err := NewWithRules(config, rules...).Lint(map[string]string{"a.sol": src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := []string{}
+	for _, d := range diags {
+		res = append(res, d.String()+" ["+d.Rule+"]")
+	}
+	return res
+}
+
+func TestSuppressions(t *testing.T) {
+	cases := []struct {
+		src   string
+		diags []string
+	}{
+		{
+			"contract A {\n  function a() public {}\n  function b() public {}\n}",
+			[]string{
+				"a.sol:2:3: warning: function a [test-func]",
+				"a.sol:3:3: warning: function b [test-func]",
+			},
+		},
+		{
+			"contract A {\n  // sollint-disable-next-line test-func\n  function a() public {}\n  function b() public {} // sollint-disable-line\n}",
+			[]string{},
+		},
+		{
+			"contract A {\n  // sollint-disable-next-line other-rule, another-rule\n  function a() public {}\n}",
+			[]string{"a.sol:3:3: warning: function a [test-func]"},
+		},
+		{
+			"contract A {\n  /* sollint-disable test-func */\n  function a() public {}\n  // sollint-enable test-func\n  function b() public {}\n}",
+			[]string{"a.sol:5:3: warning: function b [test-func]"},
+		},
+		{
+			"// sollint-disable -- legacy code\ncontract A {\n  function a() public {}\n}",
+			[]string{},
+		},
+		{
+			"contract A {\n  string s = \"// sollint-disable-next-line\";\n  function a() public {}\n}",
+			[]string{"a.sol:3:3: warning: function a [test-func]"},
+		},
+		{
+			"contract A {\n  // sollint-disable other-rule, test-func\n  function a() public {}\n  // sollint-enable other-rule\n  function b() public {}\n  // sollint-enable test-func\n  function c() public {}\n}",
+			[]string{"a.sol:7:3: warning: function c [test-func]"},
+		},
+		{
+			"contract A {\n  // sollint-disable\n  function a() public {}\n  // sollint-enable test-func\n  function b() public {}\n}",
+			[]string{"a.sol:5:3: warning: function b [test-func]"},
+		},
+	}
+
+	for _, c := range cases {
+		diags := lintSource(t, nil, c.src, funcRule{})
+		if !reflect.DeepEqual(diags, c.diags) {
+			t.Fatalf("bad diagnostics for %q: %v", c.src, diags)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	src := "contract A {\n  function a() public {}\n}"
+
+	cases := []struct {
+		config string
+		diags  []string
+	}{
+		{
+			"recommended: true\nrules:\n  test-line:\n    options:\n      max: 20",
+			[]string{"a.sol:2:1: info: line longer than 20 [test-line]", "a.sol:2:3: warning: function a [test-func]"},
+		},
+		{
+			"rules:\n  test-func: error",
+			[]string{"a.sol:2:3: error: function a [test-func]"},
+		},
+		{
+			"recommended: true\nrules:\n  test-func: off\n  test-line:\n    options:\n      max: 40",
+			[]string{},
+		},
+		{
+			`{"rules": {"test-line": {"severity": "warning", "options": {"max": 10}}}}`,
+			[]string{"a.sol:1:1: warning: line longer than 10 [test-line]", "a.sol:2:1: warning: line longer than 10 [test-line]"},
+		},
+	}
+
+	for _, c := range cases {
+		config, err := ParseConfig([]byte(c.config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		diags := lintSource(t, config, src, funcRule{}, lineRule{})
+		if !reflect.DeepEqual(diags, c.diags) {
+			t.Fatalf("bad diagnostics for %q: %v", c.config, diags)
+		}
+	}
+
+	for _, str := range []string{"rules:\n  unknown-rule: error", "rules:\n  test-func: fatal"} {
+		if _, err := ParseConfig([]byte(str)); err == nil {
+			t.Fatalf("config %q should fail", str)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sollint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(`{"rules": {"test-func": "info"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Recommended || config.Rules["test-func"].Severity != "info" {
+		t.Fatal("bad config")
+	}
+}
+
+func TestRulePanic(t *testing.T) {
+	diags := lintSource(t, nil, "contract A {}", panicRule{})
+	if !reflect.DeepEqual(diags, []string{"a.sol: error: rule test-panic failed: boom [test-panic]"}) {
+		t.Fatal(diags)
+	}
+}
+
+func TestFix(t *testing.T) {
+	sources := map[string]string{
+		"a.sol": "contract A { function f() public {} function ggg() public {} }",
+		"b.sol": "contract B { function gg() public {} }",
+		"c.sol": "contract C {}",
+	}
+	fixed, diags, err := NewWithRules(nil, growRule{}).Fix(sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"a.sol": "contract A { function fff() public {} function ggg() public {} }",
+		"b.sol": "contract B { function ggg() public {} }",
+		"c.sol": "contract C {}",
+	}
+	if !reflect.DeepEqual(fixed, expected) {
+		t.Fatal(fixed)
+	}
+	if len(diags) != 0 {
+		t.Fatal(diags)
+	}
+	if sources["a.sol"] != "contract A { function f() public {} function ggg() public {} }" {
+		t.Fatal("the sources should not change")
+	}
+}
+
+func TestReporters(t *testing.T) {
+	diags, err := NewWithRules(nil, funcRule{}).Lint(map[string]string{"a.sol": "contract A {\n  function a() public {}\n}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// text
+	var buf bytes.Buffer
+	if err := (&TextReporter{}).Report(&buf, diags); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a.sol:2:3: warning: function a [test-func]\n" {
+		t.Fatal(buf.String())
+	}
+
+	// json
+	buf.Reset()
+	if err := (&JSONReporter{}).Report(&buf, diags); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*Diagnostic
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, diags) {
+		t.Fatal("bad json roundtrip")
+	}
+
+	// sarif
+	buf.Reset()
+	if err := (&SARIFReporter{}).Report(&buf, diags); err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatal("bad sarif log")
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Rules[0].ID != "test-func" || run.Results[0].Level != "warning" {
+		t.Fatal("bad sarif rule")
+	}
+	loc := run.Results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "a.sol" || loc.Region.StartLine != 2 || loc.Region.StartColumn != 3 {
+		t.Fatal("bad sarif location")
+	}
+
+	if _, err := NewReporter("xml"); err == nil {
+		t.Fatal("xml is not a format")
+	}
+}
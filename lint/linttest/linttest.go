@@ -0,0 +1,115 @@
+// Package linttest has the helpers to test the rules of the linter
+package linttest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// Run runs each rule over <dir>/<rule id>.sol and checks that the diagnostics
+// are reported on the lines with a '// want' comment. If there is a
+// <dir>/<rule id>.sol.golden file it must be the result of the fixes. The
+// other files of the directory are part of the project too.
+func Run(t *testing.T, dir string, rules []lint.Rule) {
+	t.Helper()
+
+	ids := map[string]bool{}
+	for _, rule := range rules {
+		ids[rule.ID()] = true
+	}
+	others := map[string]string{}
+	files, err := filepath.Glob(filepath.Join(dir, "*.sol"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if !ids[strings.TrimSuffix(filepath.Base(file), ".sol")] {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			others[file] = string(data)
+		}
+	}
+
+	for _, rule := range rules {
+		path := filepath.Join(dir, rule.ID()+".sol")
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		src := string(data)
+
+		expected := []int{}
+		for i, line := range strings.Split(src, "\n") {
+			if strings.Contains(line, "// want") {
+				expected = append(expected, i+1)
+			}
+		}
+
+		sources := map[string]string{path: src}
+		for file, other := range others {
+			sources[file] = other
+		}
+		diags, err := lint.NewWithRules(nil, rule).Lint(sources)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := []int{}
+		fileDiags := []*lint.Diagnostic{}
+		for _, d := range diags {
+			if d.File != path {
+				continue
+			}
+			if d.Loc == nil || d.Severity != rule.Severity() {
+				t.Fatalf("%s: bad diagnostic %s", rule.ID(), d)
+			}
+			found = append(found, d.Loc.Start.Line)
+			fileDiags = append(fileDiags, d)
+		}
+		if !reflect.DeepEqual(found, expected) {
+			t.Fatalf("%s: expected diagnostics at %v but found %v", rule.ID(), expected, fileDiags)
+		}
+
+		golden, err := ioutil.ReadFile(path + ".golden")
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		fixes := []*solcparser.SuggestedFix{}
+		for _, d := range fileDiags {
+			fixes = append(fixes, d.Fixes...)
+		}
+		fixed, conflicts, err := solcparser.ApplyFixes(src, fixes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conflicts) != 0 || fixed != string(golden) {
+			t.Fatalf("%s: bad fixes:\n%s", rule.ID(), fixed)
+		}
+	}
+}
+
+// FuncRule is a rule that reports every function
+type FuncRule struct{}
+
+func (FuncRule) ID() string              { return "test-func" }
+func (FuncRule) Severity() lint.Severity { return lint.SeverityWarning }
+func (FuncRule) Doc() string             { return "reports the functions" }
+
+func (FuncRule) Check(pass *lint.Pass) {
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		if fn, ok := node.(*solcparser.FunctionDefinition); ok {
+			pass.Report(fn, "function %s", fn.Name)
+		}
+		return true
+	})
+}
@@ -0,0 +1,198 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Reporter writes the diagnostics in an output format
+type Reporter interface {
+	Report(w io.Writer, diags []*Diagnostic) error
+}
+
+// NewReporter returns the reporter for the format: text, json or sarif
+func NewReporter(format string) (Reporter, error) {
+	switch format {
+	case "text", "":
+		return &TextReporter{}, nil
+	case "json":
+		return &JSONReporter{}, nil
+	case "sarif":
+		return &SARIFReporter{}, nil
+	}
+	return nil, fmt.Errorf("unknown format '%s'", format)
+}
+
+// TextReporter writes one line per diagnostic
+type TextReporter struct{}
+
+func (t *TextReporter) Report(w io.Writer, diags []*Diagnostic) error {
+	for _, d := range diags {
+		if _, err := fmt.Fprintf(w, "%s [%s]\n", d, d.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSONReporter writes the diagnostics as a JSON array
+type JSONReporter struct{}
+
+func (j *JSONReporter) Report(w io.Writer, diags []*Diagnostic) error {
+	if diags == nil {
+		diags = []*Diagnostic{}
+	}
+	data, err := json.MarshalIndent(diags, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// SARIFReporter writes a SARIF 2.1.0 log with one run
+type SARIFReporter struct {
+	// Rules are described in the log, it defaults to the rules of the diagnostics
+	Rules []Rule
+}
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    *sarifTool     `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver *sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string       `json:"name"`
+	Rules []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription"`
+	DefaultConfig    *sarifConfig  `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	RuleIndex int              `json:"ruleIndex"`
+	Level     string           `json:"level"`
+	Message   *sarifMessage    `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion   `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+func (s *SARIFReporter) Report(w io.Writer, diags []*Diagnostic) error {
+	rules := s.Rules
+	if rules == nil {
+		seen := map[string]bool{}
+		for _, d := range diags {
+			if seen[d.Rule] {
+				continue
+			}
+			seen[d.Rule] = true
+			if rule, ok := Lookup(d.Rule); ok {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	driver := &sarifDriver{Name: "sollint", Rules: []*sarifRule{}}
+	index := map[string]int{}
+	for _, rule := range rules {
+		index[rule.ID()] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, &sarifRule{
+			ID:               rule.ID(),
+			ShortDescription: &sarifMessage{Text: rule.Doc()},
+			DefaultConfig:    &sarifConfig{Level: sarifLevel(rule.Severity())},
+		})
+	}
+
+	run := &sarifRun{Tool: &sarifTool{Driver: driver}, Results: []*sarifResult{}}
+	for _, d := range diags {
+		indx, ok := index[d.Rule]
+		if !ok {
+			// rules that are not registered
+			indx = len(driver.Rules)
+			index[d.Rule] = indx
+			driver.Rules = append(driver.Rules, &sarifRule{
+				ID:               d.Rule,
+				ShortDescription: &sarifMessage{Text: d.Rule},
+				DefaultConfig:    &sarifConfig{Level: sarifLevel(d.Severity)},
+			})
+		}
+		loc := &sarifPhysicalLocation{ArtifactLocation: &sarifArtifact{URI: d.File}}
+		if d.Loc != nil {
+			loc.Region = &sarifRegion{
+				StartLine:   d.Loc.Start.Line,
+				StartColumn: d.Loc.Start.Column + 1,
+				EndLine:     d.Loc.End.Line,
+				EndColumn:   d.Loc.End.Column + 1,
+			}
+		}
+		run.Results = append(run.Results, &sarifResult{
+			RuleID:    d.Rule,
+			RuleIndex: indx,
+			Level:     sarifLevel(d.Severity),
+			Message:   &sarifMessage{Text: d.Message},
+			Locations: []*sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	log := &sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []*sarifRun{run}}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
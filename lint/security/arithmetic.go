@@ -0,0 +1,195 @@
+package security
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// weakPRNG reports the random numbers derived from the block attributes
+type weakPRNG struct{}
+
+func (weakPRNG) ID() string              { return "weak-prng" }
+func (weakPRNG) Severity() lint.Severity { return lint.SeverityError }
+func (weakPRNG) Doc() string {
+	return "the block attributes are known or set by the miners and cannot be used as a source of randomness"
+}
+
+func (weakPRNG) Check(pass *lint.Pass) {
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		isSource := func(n interface{}) bool {
+			switch obj := n.(type) {
+			case *solcparser.Identifier:
+				return obj.Name == "now" && pass.Info.RefOf(obj) == nil
+			case *solcparser.MemberAccess:
+				switch obj.MemberName {
+				case "timestamp", "number", "difficulty", "prevrandao":
+					return isGlobal(pass, obj, "block", obj.MemberName)
+				}
+			case *solcparser.FunctionCall:
+				return isBuiltin(pass, obj, "blockhash")
+			}
+			return false
+		}
+		tainted := localsTaintedBy(pass, fn, isSource)
+
+		solcparser.Inspect(fn.Body, func(node interface{}) bool {
+			obj, ok := node.(*solcparser.BinaryOperation)
+			if !ok || (obj.Operator != "%" && obj.Operator != "%=") {
+				return true
+			}
+			if contains(obj.Left, func(n interface{}) bool { return isSource(n) || tainted[pass.Info.RefOf(n)] }) {
+				pass.Report(obj, "weak source of randomness from the block attributes")
+			}
+			return true
+		})
+	})
+}
+
+// localsTaintedBy returns the local variables whose value may contain an
+// expression that matches the predicate. It is flow insensitive.
+func localsTaintedBy(pass *lint.Pass, fn *solcparser.FunctionDefinition, pred func(n interface{}) bool) map[interface{}]bool {
+	res := map[interface{}]bool{}
+	isTainted := func(expr interface{}) bool {
+		return contains(expr, func(n interface{}) bool {
+			if pred(n) {
+				return true
+			}
+			ref := pass.Info.RefOf(n)
+			return ref != nil && res[ref]
+		})
+	}
+
+	for changed := true; changed; {
+		changed = false
+		mark := func(decl interface{}) {
+			if _, ok := decl.(*solcparser.VariableDeclaration); ok && !res[decl] {
+				res[decl] = true
+				changed = true
+			}
+		}
+		solcparser.Inspect(fn.Body, func(node interface{}) bool {
+			switch obj := node.(type) {
+			case *solcparser.VariableDeclarationStatement:
+				if obj.InitialValue != nil && isTainted(obj.InitialValue) {
+					for _, v := range obj.Variables {
+						mark(v)
+					}
+				}
+			case *solcparser.BinaryOperation:
+				if isAssignment(obj.Operator) && isTainted(obj.Right) {
+					if ident, ok := obj.Left.(*solcparser.Identifier); ok {
+						mark(pass.Info.RefOf(ident))
+					}
+				}
+			}
+			return true
+		})
+	}
+	return res
+}
+
+func isAssignment(op string) bool {
+	switch op {
+	case "=", "+=", "-=", "*=", "/=", "%=", "|=", "&=", "^=", "<<=", ">>=":
+		return true
+	}
+	return false
+}
+
+// divideBeforeMultiply reports the multiplications of the result of a division
+type divideBeforeMultiply struct{}
+
+func (divideBeforeMultiply) ID() string              { return "divide-before-multiply" }
+func (divideBeforeMultiply) Severity() lint.Severity { return lint.SeverityWarning }
+func (divideBeforeMultiply) Doc() string {
+	return "the integer division truncates the result, the multiplication should be done first to keep the precision"
+}
+
+func (divideBeforeMultiply) Check(pass *lint.Pass) {
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		isDivision := func(n interface{}) bool {
+			obj, ok := unparen(n).(*solcparser.BinaryOperation)
+			return ok && (obj.Operator == "/" || obj.Operator == "/=")
+		}
+		divided := map[interface{}]bool{}
+		solcparser.Inspect(fn.Body, func(node interface{}) bool {
+			switch obj := node.(type) {
+			case *solcparser.VariableDeclarationStatement:
+				if len(obj.Variables) == 1 && obj.Variables[0] != nil && isDivision(obj.InitialValue) {
+					divided[obj.Variables[0]] = true
+				}
+			case *solcparser.BinaryOperation:
+				if ident, ok := obj.Left.(*solcparser.Identifier); ok {
+					if (obj.Operator == "=" && isDivision(obj.Right)) || obj.Operator == "/=" {
+						divided[pass.Info.RefOf(ident)] = true
+					}
+				}
+			}
+			return true
+		})
+		isDivided := func(n interface{}) bool {
+			n = unparen(n)
+			if isDivision(n) {
+				return true
+			}
+			ref := pass.Info.RefOf(n)
+			return ref != nil && divided[ref]
+		}
+
+		solcparser.Inspect(fn.Body, func(node interface{}) bool {
+			obj, ok := node.(*solcparser.BinaryOperation)
+			if !ok {
+				return true
+			}
+			switch obj.Operator {
+			case "*":
+				if isDivided(obj.Left) || isDivided(obj.Right) {
+					pass.Report(obj, "division before multiplication")
+				}
+			case "*=":
+				if isDivided(obj.Left) || isDivision(obj.Right) {
+					pass.Report(obj, "division before multiplication")
+				}
+			}
+			return true
+		})
+	})
+}
+
+// incorrectEquality reports the strict equalities with a balance
+type incorrectEquality struct{}
+
+func (incorrectEquality) ID() string              { return "incorrect-equality" }
+func (incorrectEquality) Severity() lint.Severity { return lint.SeverityWarning }
+func (incorrectEquality) Doc() string {
+	return "the balances can be changed by anyone (i.e. with selfdestruct or a transfer), compare them with >= or <="
+}
+
+func (incorrectEquality) Check(pass *lint.Pass) {
+	isBalance := func(n interface{}) bool {
+		switch obj := unparen(n).(type) {
+		case *solcparser.MemberAccess:
+			if obj.MemberName != "balance" {
+				return false
+			}
+			_, ok := pass.Info.TypeOf(obj.Expression).(*types.AddressType)
+			return ok
+		case *solcparser.FunctionCall:
+			ma, ok := callee(obj).(*solcparser.MemberAccess)
+			return ok && ma.MemberName == "balanceOf"
+		}
+		return false
+	}
+
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		obj, ok := node.(*solcparser.BinaryOperation)
+		if !ok || (obj.Operator != "==" && obj.Operator != "!=") {
+			return true
+		}
+		if isBalance(obj.Left) || isBalance(obj.Right) {
+			pass.Report(obj, "strict equality with a balance")
+		}
+		return true
+	})
+}
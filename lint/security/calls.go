@@ -0,0 +1,177 @@
+package security
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/cfg"
+	"github.com/umbracle/solidity-parser-go/dataflow"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// uncheckedCall reports the low-level calls whose success value is ignored
+type uncheckedCall struct{}
+
+func (uncheckedCall) ID() string              { return "unchecked-low-level-call" }
+func (uncheckedCall) Severity() lint.Severity { return lint.SeverityWarning }
+func (uncheckedCall) Doc() string {
+	return "the return value of a low-level call or send must be checked, the call does not revert on failure"
+}
+
+func (uncheckedCall) Check(pass *lint.Pass) {
+	report := func(expr interface{}) {
+		if ma, ok := lowLevelCall(pass, expr); ok {
+			pass.Report(expr, "return value of low-level %s is not checked", ma.MemberName)
+		}
+	}
+
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		// the variables that are read in the function
+		used := map[interface{}]bool{}
+		solcparser.Inspect(fn.Body, func(node interface{}) bool {
+			if ident, ok := node.(*solcparser.Identifier); ok {
+				if ref := pass.Info.RefOf(ident); ref != nil {
+					used[ref] = true
+				}
+			}
+			return true
+		})
+
+		solcparser.Inspect(fn.Body, func(node interface{}) bool {
+			switch obj := node.(type) {
+			case *solcparser.ExpressionStatement:
+				report(obj.Expression)
+
+			case *solcparser.VariableDeclarationStatement:
+				if len(obj.Variables) == 0 || obj.InitialValue == nil {
+					return true
+				}
+				if first := obj.Variables[0]; first == nil || !used[first] {
+					report(obj.InitialValue)
+				}
+
+			case *solcparser.BinaryOperation:
+				if obj.Operator != "=" {
+					return true
+				}
+				tuple, ok := obj.Left.(*solcparser.TupleExpression)
+				if ok && len(tuple.Components) > 1 && tuple.Components[0] == nil {
+					report(obj.Right)
+				}
+			}
+			return true
+		})
+	})
+}
+
+// controlledDelegatecall reports the delegatecalls to an address controlled by the caller
+type controlledDelegatecall struct{}
+
+func (controlledDelegatecall) ID() string              { return "controlled-delegatecall" }
+func (controlledDelegatecall) Severity() lint.Severity { return lint.SeverityError }
+func (controlledDelegatecall) Doc() string {
+	return "a delegatecall to an address set by the caller executes arbitrary code with the storage of the contract"
+}
+
+func (controlledDelegatecall) Check(pass *lint.Pass) {
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		if !isPublic(fn, c) {
+			return
+		}
+		g := graph(pass, fn, c)
+		if g == nil {
+			return
+		}
+		t, res := taint(pass, g, dataflow.SourceParams, dataflow.SourceMsgSender)
+
+		reachableNodes(g, func(b *cfg.Block, indx int, node interface{}) {
+			before, ok := res.Before[node]
+			if !ok {
+				return
+			}
+			solcparser.Inspect(node, func(n interface{}) bool {
+				call, ok := n.(*solcparser.FunctionCall)
+				if !ok {
+					return true
+				}
+				ma, ok := lowLevelCall(pass, call)
+				if !ok || (ma.MemberName != "delegatecall" && ma.MemberName != "callcode") {
+					return true
+				}
+				if len(t.TaintOf(ma.Expression, before)) != 0 {
+					pass.Report(call, "%s to an address controlled by the caller", ma.MemberName)
+				}
+				return true
+			})
+		})
+	})
+}
+
+// arbitrarySendERC20 reports the transferFrom calls with a 'from' address
+// set by the caller
+type arbitrarySendERC20 struct{}
+
+func (arbitrarySendERC20) ID() string              { return "arbitrary-send-erc20" }
+func (arbitrarySendERC20) Severity() lint.Severity { return lint.SeverityError }
+func (arbitrarySendERC20) Doc() string {
+	return "transferFrom with an arbitrary 'from' address moves the tokens of any account that approved the contract"
+}
+
+func (arbitrarySendERC20) Check(pass *lint.Pass) {
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		if !isPublic(fn, c) {
+			return
+		}
+		g := graph(pass, fn, c)
+		if g == nil {
+			return
+		}
+		t, res := taint(pass, g, dataflow.SourceParams)
+
+		reachableNodes(g, func(b *cfg.Block, indx int, node interface{}) {
+			before, ok := res.Before[node]
+			if !ok {
+				return
+			}
+			solcparser.Inspect(node, func(n interface{}) bool {
+				call, ok := n.(*solcparser.FunctionCall)
+				if !ok {
+					return true
+				}
+				from := transferFrom(pass, call)
+				if from == nil || len(t.TaintOf(from, before)) == 0 {
+					return true
+				}
+				safe := contains(from, func(n interface{}) bool {
+					if isGlobal(pass, n, "msg", "sender") {
+						return true
+					}
+					ident, ok := n.(*solcparser.Identifier)
+					return ok && ident.Name == "this"
+				})
+				if !safe {
+					pass.Report(call, "transferFrom with an arbitrary 'from' address")
+				}
+				return true
+			})
+		})
+	})
+}
+
+// transferFrom returns the 'from' argument of a transferFrom or safeTransferFrom call
+func transferFrom(pass *lint.Pass, call *solcparser.FunctionCall) interface{} {
+	ma, ok := callee(call).(*solcparser.MemberAccess)
+	if !ok || (ma.MemberName != "transferFrom" && ma.MemberName != "safeTransferFrom") {
+		return nil
+	}
+	switch len(call.Arguments) {
+	case 3:
+		// token.transferFrom(from, to, amount)
+		return call.Arguments[0]
+	case 4:
+		// SafeERC20.safeTransferFrom(token, from, to, amount)
+		if _, ok := pass.Info.TypeOf(ma.Expression).(*types.TypeType); ok {
+			return call.Arguments[1]
+		}
+	}
+	return nil
+}
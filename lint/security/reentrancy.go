@@ -0,0 +1,108 @@
+package security
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/cfg"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// reentrancy reports the state variables written after an external call
+type reentrancy struct{}
+
+func (reentrancy) ID() string              { return "reentrancy" }
+func (reentrancy) Severity() lint.Severity { return lint.SeverityError }
+func (reentrancy) Doc() string {
+	return "the state must be updated before the external calls (checks-effects-interactions), the callee can reenter the function"
+}
+
+// reentrancyGuards are the modifiers that prevent the reentrancy
+var reentrancyGuards = map[string]bool{
+	"nonReentrant": true,
+	"noReentrancy": true,
+	"lock":         true,
+}
+
+func (reentrancy) Check(pass *lint.Pass) {
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		if c == nil || c.Kind == "library" || fn.StateMutability == "view" || fn.StateMutability == "pure" {
+			return
+		}
+		for _, m := range fn.Modifiers {
+			if mod, ok := m.(*solcparser.ModifierInvocation); ok && reentrancyGuards[mod.Name] {
+				return
+			}
+		}
+		g := graph(pass, fn, c)
+		if g == nil {
+			return
+		}
+
+		reported := map[interface{}]bool{}
+		reachableNodes(g, func(b *cfg.Block, indx int, node interface{}) {
+			if !contains(node, func(n interface{}) bool { return isExternalCall(pass, n) }) {
+				return
+			}
+			after(g, b, indx, func(next interface{}) {
+				stateWrites(pass, next, func(v *solcparser.StateVariableDeclarationVariable, expr interface{}) {
+					if !reported[expr] {
+						reported[expr] = true
+						pass.Report(expr, "state variable %s written after an external call", v.Name)
+					}
+				})
+			})
+		})
+	})
+}
+
+// after calls fn for the nodes that can be executed after the node at the
+// index of the block
+func after(g *cfg.CFG, b *cfg.Block, indx int, fn func(node interface{})) {
+	for _, node := range b.Nodes[indx+1:] {
+		fn(node)
+	}
+	visited := map[*cfg.Block]bool{}
+	queue := append([]*cfg.Block{}, b.Succs...)
+	for len(queue) != 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if visited[next] {
+			continue
+		}
+		visited[next] = true
+		for _, node := range next.Nodes {
+			fn(node)
+		}
+		queue = append(queue, next.Succs...)
+	}
+}
+
+// isExternalCall returns true if the node is a call that transfers the
+// execution to another contract
+func isExternalCall(pass *lint.Pass, node interface{}) bool {
+	call, ok := node.(*solcparser.FunctionCall)
+	if !ok {
+		return false
+	}
+	ma, ok := callee(call).(*solcparser.MemberAccess)
+	if !ok {
+		return false
+	}
+	if _, ok := lowLevelCall(pass, call); ok {
+		switch ma.MemberName {
+		case "call", "delegatecall", "callcode":
+			return true
+		}
+		return false
+	}
+	typ, ok := pass.Info.TypeOf(ma.Expression).(*types.ContractType)
+	if !ok || typ.Super || typ.Def.Kind == "library" {
+		return false
+	}
+	if fn, ok := pass.Info.TypeOf(callee(call)).(*types.FunctionType); ok {
+		if fn.Mutability == "view" || fn.Mutability == "pure" {
+			return false
+		}
+	}
+	return true
+}
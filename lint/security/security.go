@@ -0,0 +1,252 @@
+// Package security implements lint rules that detect vulnerable patterns in
+// the contracts. The rules are registered in the lint registry when the package
+// is imported.
+package security
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/callgraph"
+	"github.com/umbracle/solidity-parser-go/cfg"
+	"github.com/umbracle/solidity-parser-go/dataflow"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+var rules = []lint.Rule{
+	&txOrigin{},
+	&uncheckedCall{},
+	&controlledDelegatecall{},
+	&unprotectedSelfdestruct{},
+	&reentrancy{},
+	&arbitrarySendERC20{},
+	&uninitializedStorage{},
+	&shadowingState{},
+	&weakPRNG{},
+	&divideBeforeMultiply{},
+	&incorrectEquality{},
+}
+
+func init() {
+	for _, rule := range rules {
+		lint.Register(rule)
+	}
+}
+
+// Rules returns the security rules
+func Rules() []lint.Rule {
+	return append([]lint.Rule{}, rules...)
+}
+
+// functions calls fn for the functions with a body of the source unit and
+// the contract that declares them (nil for the free functions)
+func functions(unit *solcparser.SourceUnit, fn func(f *solcparser.FunctionDefinition, c *solcparser.ContractDefinition)) {
+	for _, child := range unit.Children {
+		switch obj := child.(type) {
+		case *solcparser.FunctionDefinition:
+			if obj.Body != nil {
+				fn(obj, nil)
+			}
+		case *solcparser.ContractDefinition:
+			for _, node := range obj.SubNodes {
+				if f, ok := node.(*solcparser.FunctionDefinition); ok && f.Body != nil {
+					fn(f, obj)
+				}
+			}
+		}
+	}
+}
+
+// isPublic returns true if the function can be called from outside the contract
+func isPublic(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) bool {
+	if c == nil || c.Kind == "library" || fn.IsConstructor {
+		return false
+	}
+	switch fn.Visibility {
+	case "public", "external", "default":
+		return true
+	}
+	return false
+}
+
+// isGlobal returns true if the expression is the global 'name.member' (i.e. msg.sender)
+func isGlobal(pass *lint.Pass, expr interface{}, name, member string) bool {
+	obj, ok := unparen(expr).(*solcparser.MemberAccess)
+	if !ok || obj.MemberName != member {
+		return false
+	}
+	ident, ok := obj.Expression.(*solcparser.Identifier)
+	return ok && ident.Name == name && pass.Info.RefOf(ident) == nil
+}
+
+// unparen removes the parenthesis around an expression
+func unparen(expr interface{}) interface{} {
+	for {
+		tuple, ok := expr.(*solcparser.TupleExpression)
+		if !ok || tuple.IsArray || len(tuple.Components) != 1 {
+			return expr
+		}
+		expr = tuple.Components[0]
+	}
+}
+
+// contains returns true if any node of the tree matches the predicate
+func contains(node interface{}, pred func(n interface{}) bool) bool {
+	found := false
+	solcparser.Inspect(node, func(n interface{}) bool {
+		if found {
+			return false
+		}
+		if pred(n) {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// callee returns the function called without the call options (i.e. f in f{value: 1}())
+func callee(call *solcparser.FunctionCall) interface{} {
+	if nv, ok := call.Expression.(*solcparser.NameValueExpression); ok {
+		return nv.Expression
+	}
+	return call.Expression
+}
+
+// isBuiltin returns true if the call is to the builtin function with the name
+func isBuiltin(pass *lint.Pass, call *solcparser.FunctionCall, names ...string) bool {
+	ident, ok := callee(call).(*solcparser.Identifier)
+	if !ok || pass.Info.RefOf(ident) != nil {
+		return false
+	}
+	for _, name := range names {
+		if ident.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lowLevelCall returns the member access of a call, delegatecall, staticcall,
+// callcode or send to an address
+func lowLevelCall(pass *lint.Pass, expr interface{}) (*solcparser.MemberAccess, bool) {
+	call, ok := unparen(expr).(*solcparser.FunctionCall)
+	if !ok {
+		return nil, false
+	}
+	ma, ok := callee(call).(*solcparser.MemberAccess)
+	if !ok {
+		return nil, false
+	}
+	switch ma.MemberName {
+	case "call", "delegatecall", "staticcall", "callcode", "send":
+	default:
+		return nil, false
+	}
+	switch pass.Info.TypeOf(ma.Expression).(type) {
+	case *types.AddressType, nil:
+		return ma, true
+	}
+	return nil, false
+}
+
+// stateVariable returns the state variable at the root of an lvalue
+func stateVariable(pass *lint.Pass, expr interface{}) *solcparser.StateVariableDeclarationVariable {
+	for {
+		switch obj := expr.(type) {
+		case *solcparser.Identifier:
+			v, _ := pass.Info.RefOf(obj).(*solcparser.StateVariableDeclarationVariable)
+			return v
+		case *solcparser.IndexAccess:
+			expr = obj.Base
+		case *solcparser.MemberAccess:
+			expr = obj.Expression
+		case *solcparser.TupleExpression:
+			if len(obj.Components) != 1 {
+				return nil
+			}
+			expr = obj.Components[0]
+		default:
+			return nil
+		}
+	}
+}
+
+// stateWrites calls fn for the state variables written by a node
+func stateWrites(pass *lint.Pass, node interface{}, fn func(v *solcparser.StateVariableDeclarationVariable, expr interface{})) {
+	var lvalue func(lhs, expr interface{})
+	lvalue = func(lhs, expr interface{}) {
+		if tuple, ok := lhs.(*solcparser.TupleExpression); ok && len(tuple.Components) != 1 {
+			for _, comp := range tuple.Components {
+				if comp != nil {
+					lvalue(comp, expr)
+				}
+			}
+			return
+		}
+		if v := stateVariable(pass, lhs); v != nil {
+			fn(v, expr)
+		}
+	}
+
+	solcparser.Inspect(node, func(n interface{}) bool {
+		switch obj := n.(type) {
+		case *solcparser.BinaryOperation:
+			if isAssignment(obj.Operator) {
+				lvalue(obj.Left, obj)
+			}
+		case *solcparser.UnaryOperation:
+			switch obj.Operator {
+			case "++", "--", "delete":
+				lvalue(obj.SubExpression, obj)
+			}
+		case *solcparser.FunctionCall:
+			// push and pop of storage arrays
+			if ma, ok := callee(obj).(*solcparser.MemberAccess); ok && (ma.MemberName == "push" || ma.MemberName == "pop") {
+				if v := stateVariable(pass, ma.Expression); v != nil {
+					fn(v, obj)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// graph builds the control flow graph of a function
+func graph(pass *lint.Pass, fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) *cfg.CFG {
+	g, err := cfg.New(fn, cfg.WithProject(pass.Project), cfg.WithContract(c))
+	if err != nil {
+		return nil
+	}
+	return g
+}
+
+// reachableNodes calls fn for every node of the reachable blocks of the graph once
+func reachableNodes(g *cfg.CFG, fn func(b *cfg.Block, indx int, node interface{})) {
+	seen := map[interface{}]bool{}
+	reachable := g.Reachable()
+	for _, b := range g.Blocks {
+		if !reachable[b] {
+			continue
+		}
+		for i, node := range b.Nodes {
+			if !seen[node] {
+				seen[node] = true
+				fn(b, i, node)
+			}
+		}
+	}
+}
+
+// taint solves the taint analysis of the function with the given sources
+func taint(pass *lint.Pass, g *cfg.CFG, sources ...string) (*dataflow.TaintAnalysis, *dataflow.Result) {
+	t := dataflow.NewTaintAnalysis(g, pass.Info, &dataflow.TaintConfig{Sources: sources})
+	return t, dataflow.Solve(g, t)
+}
+
+// callGraph returns the call graph of the project shared by all the passes
+func callGraph(pass *lint.Pass) *callgraph.Graph {
+	return pass.Memo("security.callgraph", func() interface{} {
+		return callgraph.New(pass.Project, callgraph.WithInfo(pass.Info))
+	}).(*callgraph.Graph)
+}
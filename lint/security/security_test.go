@@ -0,0 +1,20 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/lint/linttest"
+)
+
+func TestRules(t *testing.T) {
+	linttest.Run(t, "testdata", Rules())
+}
+
+func TestRegistered(t *testing.T) {
+	for _, rule := range Rules() {
+		if _, ok := lint.Lookup(rule.ID()); !ok {
+			t.Fatalf("rule %s is not registered", rule.ID())
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package security
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/callgraph"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// unprotectedSelfdestruct reports the selfdestruct calls that any account can reach
+type unprotectedSelfdestruct struct{}
+
+func (unprotectedSelfdestruct) ID() string              { return "unprotected-selfdestruct" }
+func (unprotectedSelfdestruct) Severity() lint.Severity { return lint.SeverityError }
+func (unprotectedSelfdestruct) Doc() string {
+	return "selfdestruct must only be reachable by the functions that check the caller"
+}
+
+func (unprotectedSelfdestruct) Check(pass *lint.Pass) {
+	functions(pass.Unit, func(fn *solcparser.FunctionDefinition, c *solcparser.ContractDefinition) {
+		solcparser.Inspect(fn.Body, func(node interface{}) bool {
+			call, ok := node.(*solcparser.FunctionCall)
+			if !ok || !isBuiltin(pass, call, "selfdestruct", "suicide") {
+				return true
+			}
+			g := callGraph(pass)
+			n := g.Node(fn)
+			if n == nil {
+				return true
+			}
+			for _, entry := range g.EntryPoints(n) {
+				if !isProtected(pass, g, entry) {
+					pass.Report(call, "selfdestruct can be called by anyone through %s", entry.Name)
+					break
+				}
+			}
+			return true
+		})
+	})
+}
+
+// isProtected returns true if the function or one of the functions and
+// modifiers it calls checks msg.sender
+func isProtected(pass *lint.Pass, g *callgraph.Graph, n *callgraph.Node) bool {
+	if checksSender(pass, n.Decl) {
+		return true
+	}
+	for _, m := range g.Reachable(n) {
+		if checksSender(pass, m.Decl) {
+			return true
+		}
+	}
+	return false
+}
+
+// checksSender returns true if msg.sender is part of a require, assert or if condition
+func checksSender(pass *lint.Pass, decl interface{}) bool {
+	sender := func(n interface{}) bool {
+		return isGlobal(pass, n, "msg", "sender")
+	}
+	return contains(decl, func(n interface{}) bool {
+		switch obj := n.(type) {
+		case *solcparser.IfStatement:
+			return contains(obj.Condition, sender)
+		case *solcparser.FunctionCall:
+			if isBuiltin(pass, obj, "require", "assert") && len(obj.Arguments) != 0 {
+				return contains(obj.Arguments[0], sender)
+			}
+		}
+		return false
+	})
+}
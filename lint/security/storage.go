@@ -0,0 +1,106 @@
+package security
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// uninitializedStorage reports the local storage pointers without a value
+type uninitializedStorage struct{}
+
+func (uninitializedStorage) ID() string              { return "uninitialized-storage" }
+func (uninitializedStorage) Severity() lint.Severity { return lint.SeverityError }
+func (uninitializedStorage) Doc() string {
+	return "an uninitialized storage pointer points to the slot 0 and overwrites the first state variables"
+}
+
+func (uninitializedStorage) Check(pass *lint.Pass) {
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		obj, ok := node.(*solcparser.VariableDeclarationStatement)
+		if !ok || obj.InitialValue != nil {
+			return true
+		}
+		for _, v := range obj.Variables {
+			decl, ok := v.(*solcparser.VariableDeclaration)
+			if ok && isStoragePointer(pass, decl) {
+				pass.Report(decl, "storage variable %s is not initialized", decl.Name)
+			}
+		}
+		return true
+	})
+}
+
+// isStoragePointer returns true if the local variable is a reference to storage.
+// The structs and arrays without location are in storage in the old versions.
+func isStoragePointer(pass *lint.Pass, decl *solcparser.VariableDeclaration) bool {
+	if decl.StorageLocation == "storage" {
+		return true
+	}
+	if decl.StorageLocation != "" {
+		return false
+	}
+	switch obj := pass.Info.TypeOf(decl).(type) {
+	case *types.StructType:
+		return obj.Location == ""
+	case *types.ArrayType:
+		return obj.Location == ""
+	}
+	return false
+}
+
+// shadowingState reports the state variables that shadow a state variable of a base contract
+type shadowingState struct{}
+
+func (shadowingState) ID() string              { return "shadowing-state" }
+func (shadowingState) Severity() lint.Severity { return lint.SeverityError }
+func (shadowingState) Doc() string {
+	return "a state variable with the name of a state variable of a base contract creates a second variable"
+}
+
+func (shadowingState) Check(pass *lint.Pass) {
+	for _, child := range pass.Unit.Children {
+		c, ok := child.(*solcparser.ContractDefinition)
+		if !ok {
+			continue
+		}
+		bases, err := pass.Project.Linearize(c)
+		if err != nil || len(bases) < 2 {
+			continue
+		}
+		for _, v := range stateVariables(c) {
+			for _, base := range bases[1:] {
+				if shadowed := lookupStateVariable(base, v.Name); shadowed != nil {
+					pass.Report(v, "state variable %s shadows %s.%s", v.Name, base.Name, shadowed.Name)
+					break
+				}
+			}
+		}
+	}
+}
+
+// stateVariables returns the state variables declared in the contract
+func stateVariables(c *solcparser.ContractDefinition) []*solcparser.StateVariableDeclarationVariable {
+	res := []*solcparser.StateVariableDeclarationVariable{}
+	for _, node := range c.SubNodes {
+		decl, ok := node.(*solcparser.StateVariableDeclaration)
+		if !ok {
+			continue
+		}
+		for _, v := range decl.Variables {
+			if obj, ok := v.(*solcparser.StateVariableDeclarationVariable); ok {
+				res = append(res, obj)
+			}
+		}
+	}
+	return res
+}
+
+func lookupStateVariable(c *solcparser.ContractDefinition, name string) *solcparser.StateVariableDeclarationVariable {
+	for _, v := range stateVariables(c) {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+pragma solidity ^0.8.0;
+
+interface IERC20 {
+    function transferFrom(address from, address to, uint256 amount) external returns (bool);
+}
+
+library SafeERC20 {
+    function safeTransferFrom(IERC20 token, address from, address to, uint256 amount) internal {
+        token.transferFrom(from, to, amount);
+    }
+}
+
+contract Vault {
+    IERC20 token;
+
+    function deposit(uint256 amount) public {
+        token.transferFrom(msg.sender, address(this), amount);
+    }
+
+    function pull(address from, uint256 amount) public {
+        token.transferFrom(from, address(this), amount); // want
+    }
+
+    function pullSafe(address from, uint256 amount) public {
+        SafeERC20.safeTransferFrom(token, from, address(this), amount); // want
+    }
+
+    function depositSafe(uint256 amount) public {
+        SafeERC20.safeTransferFrom(token, msg.sender, address(this), amount);
+    }
+}
@@ -0,0 +1,22 @@
+pragma solidity ^0.8.0;
+
+contract Proxy {
+    address implementation;
+
+    function forward(address target, bytes memory data) public {
+        target.delegatecall(data); // want
+    }
+
+    function forwardLocal(address target, bytes memory data) public {
+        address t = target;
+        t.delegatecall(data); // want
+    }
+
+    function upgrade(bytes memory data) public {
+        implementation.delegatecall(data);
+    }
+
+    function internalForward(address target) internal {
+        target.delegatecall("");
+    }
+}
@@ -0,0 +1,22 @@
+pragma solidity ^0.8.0;
+
+contract Fees {
+    function fee(uint256 amount, uint256 rate) public pure returns (uint256) {
+        return amount / 100 * rate; // want
+    }
+
+    function feeLocal(uint256 amount, uint256 rate) public pure returns (uint256) {
+        uint256 base = amount / 100;
+        return base * rate; // want
+    }
+
+    function feeAssign(uint256 amount, uint256 rate) public pure returns (uint256) {
+        amount /= 100;
+        amount *= rate; // want
+        return amount;
+    }
+
+    function feeCorrect(uint256 amount, uint256 rate) public pure returns (uint256) {
+        return amount * rate / 100;
+    }
+}
@@ -0,0 +1,21 @@
+pragma solidity ^0.8.0;
+
+interface IERC20 {
+    function balanceOf(address who) external view returns (uint256);
+}
+
+contract Game {
+    IERC20 token;
+
+    function finished() public view returns (bool) {
+        return address(this).balance == 10 ether; // want
+    }
+
+    function empty(address who) public view returns (bool) {
+        return token.balanceOf(who) != 0; // want
+    }
+
+    function enough() public view returns (bool) {
+        return address(this).balance >= 10 ether;
+    }
+}
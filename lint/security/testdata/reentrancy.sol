@@ -0,0 +1,46 @@
+pragma solidity ^0.8.0;
+
+interface Token {
+    function transfer(address to, uint256 amount) external returns (bool);
+    function balanceOf(address who) external view returns (uint256);
+}
+
+contract Bank {
+    mapping(address => uint256) balances;
+    uint256 total;
+    Token token;
+
+    modifier nonReentrant() {
+        _;
+    }
+
+    function withdraw() public {
+        uint256 amount = balances[msg.sender];
+        (bool ok, ) = msg.sender.call{value: amount}("");
+        require(ok);
+        balances[msg.sender] = 0; // want
+        total -= amount; // want
+    }
+
+    function withdrawSafe() public {
+        uint256 amount = balances[msg.sender];
+        balances[msg.sender] = 0;
+        (bool ok, ) = msg.sender.call{value: amount}("");
+        require(ok);
+    }
+
+    function withdrawGuarded() public nonReentrant {
+        uint256 amount = balances[msg.sender];
+        (bool ok, ) = msg.sender.call{value: amount}("");
+        require(ok);
+        balances[msg.sender] = 0;
+    }
+
+    function withdrawToken(uint256 amount) public {
+        if (token.balanceOf(address(this)) > amount) {
+            total = 0;
+        }
+        token.transfer(msg.sender, amount);
+        delete balances[msg.sender]; // want
+    }
+}
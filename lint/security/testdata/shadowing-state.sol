@@ -0,0 +1,19 @@
+pragma solidity ^0.8.0;
+
+contract Base {
+    address owner;
+    uint256 fee;
+}
+
+contract Middle is Base {
+    uint256 rate;
+}
+
+contract Derived is Middle {
+    address owner; // want
+    uint256 price;
+
+    function setFee(uint256 fee) public {
+        price = fee;
+    }
+}
@@ -0,0 +1,20 @@
+pragma solidity ^0.8.0;
+
+contract Wallet {
+    address owner;
+
+    function transfer(address payable to, uint256 amount) public {
+        require(tx.origin == owner); // want
+        to.transfer(amount);
+    }
+
+    function withdraw() public {
+        if (owner != tx.origin) { // want
+            revert();
+        }
+    }
+
+    function noContracts() public view {
+        require(tx.origin == msg.sender);
+    }
+}
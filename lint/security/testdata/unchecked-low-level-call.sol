@@ -0,0 +1,35 @@
+pragma solidity ^0.8.0;
+
+contract Sender {
+    function a(address to) public {
+        to.call(""); // want
+    }
+
+    function b(address payable to) public {
+        to.send(1); // want
+    }
+
+    function c(address to) public {
+        (bool ok, ) = to.call("");
+        require(ok);
+    }
+
+    function d(address to) public {
+        (bool ok, ) = to.call(""); // want
+    }
+
+    function e(address to) public returns (bytes memory data) {
+        bool ok;
+        (, data) = to.call(""); // want
+        (ok, data) = to.call("");
+        require(ok);
+    }
+
+    function f(address payable to) public {
+        require(to.send(1));
+        bool sent = to.send(1);
+        if (!sent) {
+            revert();
+        }
+    }
+}
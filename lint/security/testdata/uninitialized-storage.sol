@@ -0,0 +1,36 @@
+pragma solidity ^0.4.24;
+
+contract Registry {
+    struct Entry {
+        address owner;
+        uint256 value;
+    }
+
+    Entry[] entries;
+    uint256[] values;
+
+    function add(uint256 value) public {
+        Entry entry; // want
+        entry.owner = msg.sender;
+        entry.value = value;
+        entries.push(entry);
+    }
+
+    function explicit() public {
+        Entry storage entry; // want
+        entry.value = 1;
+    }
+
+    function initialized() public {
+        Entry storage entry = entries[0];
+        Entry memory copy;
+        uint256 count;
+        entry.value = count;
+        copy.value = 1;
+    }
+
+    function array() public {
+        uint256[] list; // want
+        list.push(1);
+    }
+}
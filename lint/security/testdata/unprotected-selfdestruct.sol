@@ -0,0 +1,39 @@
+pragma solidity ^0.8.0;
+
+contract Owned {
+    address owner;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner);
+        _;
+    }
+}
+
+contract Killable is Owned {
+    function kill() public {
+        selfdestruct(payable(msg.sender)); // want
+    }
+
+    function killOwner() public onlyOwner {
+        selfdestruct(payable(owner));
+    }
+
+    function killChecked() public {
+        if (msg.sender != owner) {
+            revert();
+        }
+        selfdestruct(payable(owner));
+    }
+
+    function destroy() internal {
+        selfdestruct(payable(owner)); // want
+    }
+
+    function close() public {
+        destroy();
+    }
+
+    function adminClose() public onlyOwner {
+        destroy();
+    }
+}
@@ -0,0 +1,21 @@
+pragma solidity ^0.8.0;
+
+contract Lottery {
+    function draw(uint256 players) public view returns (uint256) {
+        return block.timestamp % players; // want
+    }
+
+    function drawHash(uint256 players) public view returns (uint256) {
+        uint256 seed = uint256(keccak256(abi.encodePacked(blockhash(block.number - 1))));
+        uint256 value = seed + 1;
+        return value % players; // want
+    }
+
+    function index(uint256 a, uint256 players) public pure returns (uint256) {
+        return a % players;
+    }
+
+    function expiry() public view returns (uint256) {
+        return block.timestamp + 100;
+    }
+}
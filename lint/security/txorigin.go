@@ -0,0 +1,37 @@
+package security
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// txOrigin reports the comparisons with tx.origin used for authorization
+type txOrigin struct{}
+
+func (txOrigin) ID() string              { return "tx-origin" }
+func (txOrigin) Severity() lint.Severity { return lint.SeverityWarning }
+func (txOrigin) Doc() string {
+	return "tx.origin should not be used for authorization, a contract called by the owner can impersonate it"
+}
+
+func (txOrigin) Check(pass *lint.Pass) {
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		obj, ok := node.(*solcparser.BinaryOperation)
+		if !ok || (obj.Operator != "==" && obj.Operator != "!=") {
+			return true
+		}
+		left, right := isGlobal(pass, obj.Left, "tx", "origin"), isGlobal(pass, obj.Right, "tx", "origin")
+		if !left && !right {
+			return true
+		}
+		// tx.origin == msg.sender checks that the caller is not a contract
+		other := obj.Right
+		if right {
+			other = obj.Left
+		}
+		if !isGlobal(pass, other, "msg", "sender") {
+			pass.Report(obj, "tx.origin used for authorization")
+		}
+		return true
+	})
+}
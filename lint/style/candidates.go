@@ -0,0 +1,267 @@
+package style
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/constant"
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// stateWrites are the places where the state variables of the project are written
+type stateWrites struct {
+	// constructor are the assignments in the constructor of the contract that
+	// declares the variable, at the top level of the body
+	constructor map[*solcparser.StateVariableDeclarationVariable][]*solcparser.BinaryOperation
+
+	// other are the variables written anywhere else (or in inline assembly)
+	other map[*solcparser.StateVariableDeclarationVariable]bool
+
+	// constructorReads are the variables read in the constructor of their contract
+	constructorReads map[*solcparser.StateVariableDeclarationVariable]bool
+}
+
+func isAssignment(op string) bool {
+	switch op {
+	case "=", "+=", "-=", "*=", "/=", "%=", "|=", "&=", "^=", "<<=", ">>=":
+		return true
+	}
+	return false
+}
+
+// rootVariable returns the state variable at the root of an lvalue
+func rootVariable(info *types.Info, expr interface{}) *solcparser.StateVariableDeclarationVariable {
+	for {
+		switch obj := expr.(type) {
+		case *solcparser.Identifier:
+			v, _ := info.RefOf(obj).(*solcparser.StateVariableDeclarationVariable)
+			return v
+		case *solcparser.IndexAccess:
+			expr = obj.Base
+		case *solcparser.MemberAccess:
+			if v, ok := info.RefOf(obj).(*solcparser.StateVariableDeclarationVariable); ok {
+				return v
+			}
+			expr = obj.Expression
+		case *solcparser.TupleExpression:
+			if len(obj.Components) != 1 {
+				return nil
+			}
+			expr = obj.Components[0]
+		default:
+			return nil
+		}
+	}
+}
+
+// collectWrites finds the writes of the state variables in the whole project
+// since the derived contracts of other files can write them too
+func collectWrites(pass *lint.Pass) *stateWrites {
+	return pass.Memo("style.writes", func() interface{} {
+		res := &stateWrites{
+			constructor:      map[*solcparser.StateVariableDeclarationVariable][]*solcparser.BinaryOperation{},
+			other:            map[*solcparser.StateVariableDeclarationVariable]bool{},
+			constructorReads: map[*solcparser.StateVariableDeclarationVariable]bool{},
+		}
+
+		// the names used in inline assembly are written conservatively
+		asmNames := map[string]bool{}
+		for _, file := range pass.Project.Files() {
+			solcparser.Inspect(pass.Project.Units[file], func(node interface{}) bool {
+				if _, ok := node.(*solcparser.InlineAssemblyStatement); !ok {
+					return true
+				}
+				solcparser.Inspect(node, func(n interface{}) bool {
+					switch obj := n.(type) {
+					case *solcparser.AssemblyCall:
+						asmNames[obj.FunctionName] = true
+					case *solcparser.Identifier:
+						asmNames[obj.Name] = true
+					}
+					return true
+				})
+				return false
+			})
+		}
+
+		for _, c := range pass.Project.Contracts() {
+			declared := map[*solcparser.StateVariableDeclarationVariable]bool{}
+			for _, v := range stateVariables(c) {
+				declared[v] = true
+				if asmNames[v.Name] {
+					res.other[v] = true
+				}
+			}
+
+			for _, node := range c.SubNodes {
+				var body interface{}
+				isConstructor := false
+				switch obj := node.(type) {
+				case *solcparser.FunctionDefinition:
+					body, isConstructor = obj.Body, obj.IsConstructor
+				case *solcparser.ModifierDefinition:
+					body = obj.Body
+				default:
+					continue
+				}
+
+				// top level assignments of the constructor
+				topLevel := map[interface{}]bool{}
+				if block, ok := body.(*solcparser.Block); ok && isConstructor {
+					for _, stmt := range block.Statements {
+						if expr, ok := stmt.(*solcparser.ExpressionStatement); ok {
+							topLevel[expr.Expression] = true
+						}
+					}
+				}
+				lhs := map[interface{}]bool{}
+
+				write := func(v *solcparser.StateVariableDeclarationVariable, obj *solcparser.BinaryOperation) {
+					if v == nil {
+						return
+					}
+					if obj != nil && obj.Operator == "=" && topLevel[obj] && declared[v] {
+						res.constructor[v] = append(res.constructor[v], obj)
+					} else {
+						res.other[v] = true
+					}
+				}
+				solcparser.Inspect(body, func(n interface{}) bool {
+					switch obj := n.(type) {
+					case *solcparser.BinaryOperation:
+						if isAssignment(obj.Operator) {
+							lhs[obj.Left] = true
+							write(rootVariable(pass.Info, obj.Left), obj)
+						}
+					case *solcparser.UnaryOperation:
+						switch obj.Operator {
+						case "++", "--", "delete":
+							write(rootVariable(pass.Info, obj.SubExpression), nil)
+						}
+					case *solcparser.FunctionCall:
+						if ma, ok := obj.Expression.(*solcparser.MemberAccess); ok && (ma.MemberName == "push" || ma.MemberName == "pop") {
+							write(rootVariable(pass.Info, ma.Expression), nil)
+						}
+					case *solcparser.Identifier:
+						if v, ok := pass.Info.RefOf(obj).(*solcparser.StateVariableDeclarationVariable); ok && isConstructor && declared[v] && !lhs[obj] {
+							res.constructorReads[v] = true
+						}
+					}
+					return true
+				})
+			}
+		}
+		return res
+	}).(*stateWrites)
+}
+
+// isValueType returns true for the types that can be immutable
+func isValueType(typ types.Type) bool {
+	switch typ.(type) {
+	case *types.IntType, *types.AddressType, *types.BoolType, *types.FixedBytesType,
+		*types.EnumType, *types.UserDefinedValueType, *types.ContractType:
+		return true
+	}
+	return false
+}
+
+// isConstant returns true if the initial value of the variable is a compile time constant
+func isConstant(pass *lint.Pass, c *solcparser.ContractDefinition, v *solcparser.StateVariableDeclarationVariable) bool {
+	if v.Expression == nil {
+		return false
+	}
+	_, err := constant.Eval(v.Expression, constant.WithProject(pass.Project), constant.WithContract(c))
+	return err == nil
+}
+
+// candidates calls fn for the mutable state variables of the file
+func candidates(pass *lint.Pass, fn func(c *solcparser.ContractDefinition, v *solcparser.StateVariableDeclarationVariable, writes *stateWrites)) {
+	for _, c := range contracts(pass.Unit) {
+		for _, v := range stateVariables(c) {
+			if v.IsDeclaredConst || v.IsInmutable {
+				continue
+			}
+			fn(c, v, collectWrites(pass))
+		}
+	}
+}
+
+// constantCandidate reports the state variables that are never written and
+// whose value is known at compile time. There is no fix, a constant is not in
+// storage and the slots of the next variables would change (i.e. in the
+// upgradeable contracts).
+type constantCandidate struct{}
+
+func (constantCandidate) ID() string              { return "constant-candidate" }
+func (constantCandidate) Severity() lint.Severity { return lint.SeverityInfo }
+func (constantCandidate) Doc() string {
+	return "the state variables that are never written and have a constant value should be constant to save gas"
+}
+
+func (constantCandidate) Check(pass *lint.Pass) {
+	candidates(pass, func(c *solcparser.ContractDefinition, v *solcparser.StateVariableDeclarationVariable, writes *stateWrites) {
+		if writes.other[v] || len(writes.constructor[v]) != 0 {
+			return
+		}
+		switch pass.Info.TypeOf(v).(type) {
+		case *types.StringType, *types.BytesType:
+		default:
+			if !isValueType(pass.Info.TypeOf(v)) {
+				return
+			}
+		}
+		if !isConstant(pass, c, v) {
+			return
+		}
+		pass.Report(v, "state variable %s is never written and could be constant", v.Name)
+	})
+}
+
+// immutableCandidate reports the state variables that are only set by the
+// constructor, there is no fix for the same reason as constantCandidate
+type immutableCandidate struct{}
+
+func (immutableCandidate) ID() string              { return "immutable-candidate" }
+func (immutableCandidate) Severity() lint.Severity { return lint.SeverityInfo }
+func (immutableCandidate) Doc() string {
+	return "the state variables that are only set in the declaration or the constructor should be immutable to save gas"
+}
+
+func (immutableCandidate) Check(pass *lint.Pass) {
+	// immutable variables were introduced in 0.6.5
+	if !versionAtLeast(pass.Unit, [3]int{0, 6, 5}) {
+		return
+	}
+	candidates(pass, func(c *solcparser.ContractDefinition, v *solcparser.StateVariableDeclarationVariable, writes *stateWrites) {
+		if writes.other[v] || !isValueType(pass.Info.TypeOf(v)) {
+			return
+		}
+		switch len(writes.constructor[v]) {
+		case 0:
+			// set only in the declaration with a value computed at deployment
+			if v.Expression == nil || isConstant(pass, c, v) || readsState(pass, v.Expression) {
+				return
+			}
+		case 1:
+			// set once by the constructor, it cannot be read there before
+			if v.Expression != nil || writes.constructorReads[v] {
+				return
+			}
+		default:
+			return
+		}
+		pass.Report(v, "state variable %s is only set during the deployment and could be immutable", v.Name)
+	})
+}
+
+// readsState returns true if the expression reads a state variable or calls a function of the contract
+func readsState(pass *lint.Pass, expr interface{}) bool {
+	found := false
+	solcparser.Inspect(expr, func(node interface{}) bool {
+		switch pass.Info.RefOf(node).(type) {
+		case *solcparser.StateVariableDeclarationVariable, *solcparser.FunctionDefinition:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
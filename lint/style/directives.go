@@ -0,0 +1,208 @@
+package style
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// stateVisibility reports the state variables without an explicit visibility
+type stateVisibility struct{}
+
+func (stateVisibility) ID() string              { return "state-visibility" }
+func (stateVisibility) Severity() lint.Severity { return lint.SeverityWarning }
+func (stateVisibility) Doc() string {
+	return "the visibility of the state variables must be explicit"
+}
+
+func (stateVisibility) Check(pass *lint.Pass) {
+	for _, c := range contracts(pass.Unit) {
+		for _, v := range stateVariables(c) {
+			if v.Visibility != "default" {
+				continue
+			}
+			// internal is the default visibility
+			fix := insertAfter(v.TypeName, " internal", "make the visibility internal")
+			pass.ReportFix(v, fix, "state variable %s has no explicit visibility", v.Name)
+		}
+	}
+}
+
+// noGlobalImport reports the imports that bring all the symbols of the file into scope
+type noGlobalImport struct{}
+
+func (noGlobalImport) ID() string              { return "no-global-import" }
+func (noGlobalImport) Severity() lint.Severity { return lint.SeverityWarning }
+func (noGlobalImport) Doc() string {
+	return "the imports must name the symbols they use (import {A} from \"a.sol\")"
+}
+
+func (noGlobalImport) Check(pass *lint.Pass) {
+	for _, child := range pass.Unit.Children {
+		imp, ok := child.(*solcparser.ImportDirective)
+		if !ok || len(imp.SymbolAliases) != 0 || imp.UnitAlias != "" {
+			continue
+		}
+		var fix *solcparser.SuggestedFix
+		if symbols := importedSymbols(pass, imp); len(symbols) != 0 && imp.Loc != nil {
+			fix = &solcparser.SuggestedFix{
+				Message: "import the used symbols",
+				Edits: []solcparser.TextEdit{{
+					Start:   imp.Loc.Start.Offset,
+					End:     imp.Loc.End.Offset,
+					NewText: "import {" + strings.Join(symbols, ", ") + "} from \"" + imp.Path + "\";",
+				}},
+			}
+		}
+		pass.ReportFix(imp, fix, "global import of %s", imp.Path)
+	}
+}
+
+// resolveImport returns the source unit of the project imported by the file
+func resolveImport(p *solcparser.Project, file, importPath string) (string, bool) {
+	name := importPath
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		name = path.Join(path.Dir(file), importPath)
+	}
+	_, ok := p.Units[name]
+	return name, ok
+}
+
+// topLevelNames returns the names declared at the top level of a source unit
+func topLevelNames(unit *solcparser.SourceUnit) map[string]bool {
+	res := map[string]bool{}
+	for _, child := range unit.Children {
+		switch obj := child.(type) {
+		case *solcparser.ContractDefinition:
+			res[obj.Name] = true
+		case *solcparser.StructDefinition:
+			res[obj.Name] = true
+		case *solcparser.EnumDefinition:
+			res[obj.Name] = true
+		case *solcparser.FunctionDefinition:
+			res[obj.Name] = true
+		case *solcparser.FileLevelConstant:
+			res[obj.Name] = true
+		case *solcparser.CustomErrorDefinition:
+			res[obj.Name] = true
+		case *solcparser.TypeDefinition:
+			res[obj.Name] = true
+		}
+	}
+	return res
+}
+
+// usedNames returns the names referenced by the source unit
+func usedNames(unit *solcparser.SourceUnit) map[string]bool {
+	res := map[string]bool{}
+	solcparser.Inspect(unit, func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *solcparser.Identifier:
+			res[obj.Name] = true
+		case *solcparser.UserDefinedTypeName:
+			res[strings.Split(obj.NamePath, ".")[0]] = true
+		case *solcparser.UsingForDeclaration:
+			res[strings.Split(obj.LibraryName, ".")[0]] = true
+		case *solcparser.ModifierInvocation:
+			res[obj.Name] = true
+		}
+		return true
+	})
+	return res
+}
+
+// importedSymbols returns the symbols of the imported file used by the source
+// unit. It returns nil if the file or one of the symbols used by the unit
+// cannot be resolved to a direct import, the named import would break the unit.
+func importedSymbols(pass *lint.Pass, imp *solcparser.ImportDirective) []string {
+	target, ok := resolveImport(pass.Project, pass.File, imp.Path)
+	if !ok {
+		return nil
+	}
+
+	local := topLevelNames(pass.Unit)
+	direct := map[string]bool{}
+	for _, child := range pass.Unit.Children {
+		other, ok := child.(*solcparser.ImportDirective)
+		if !ok {
+			continue
+		}
+		if len(other.SymbolAliases) != 0 {
+			for _, alias := range other.SymbolAliases {
+				if alias[1] != "" {
+					direct[alias[1]] = true
+				} else {
+					direct[alias[0]] = true
+				}
+			}
+			continue
+		}
+		if other.UnitAlias != "" {
+			direct[other.UnitAlias] = true
+			continue
+		}
+		name, ok := resolveImport(pass.Project, pass.File, other.Path)
+		if !ok {
+			return nil
+		}
+		for symbol := range topLevelNames(pass.Project.Units[name]) {
+			direct[symbol] = true
+		}
+	}
+
+	declared := topLevelNames(pass.Project.Units[target])
+	res := []string{}
+	for name := range usedNames(pass.Unit) {
+		if local[name] {
+			continue
+		}
+		if declared[name] {
+			res = append(res, name)
+			continue
+		}
+		// a symbol of the project that is not declared by a direct import
+		for _, file := range pass.Project.Files() {
+			if file != pass.File && topLevelNames(pass.Project.Units[file])[name] && !direct[name] {
+				return nil
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// floatingPragma reports the solidity pragmas that accept more than one version
+type floatingPragma struct{}
+
+func (floatingPragma) ID() string              { return "floating-pragma" }
+func (floatingPragma) Severity() lint.Severity { return lint.SeverityWarning }
+func (floatingPragma) Doc() string {
+	return "the contracts must be deployed with the compiler version they were tested with"
+}
+
+var exactVersionRe = regexp.MustCompile(`^=?\d+\.\d+\.\d+$`)
+
+func (floatingPragma) Check(pass *lint.Pass) {
+	for _, child := range pass.Unit.Children {
+		pragma, ok := child.(*solcparser.PragmaDirective)
+		if !ok || pragma.Name != "solidity" || exactVersionRe.MatchString(pragma.Value) {
+			continue
+		}
+		// ^0.8.0 is fixed to the lowest version it accepts
+		var fix *solcparser.SuggestedFix
+		if loc := pragma.Loc; loc != nil && loc.End.Offset <= len(pass.Source) && exactVersionRe.MatchString(strings.TrimPrefix(pragma.Value, "^")) {
+			if indx := strings.Index(pass.Source[loc.Start.Offset:loc.End.Offset], "^"); indx != -1 {
+				offset := loc.Start.Offset + indx
+				fix = &solcparser.SuggestedFix{
+					Message: "lock the version to " + strings.TrimPrefix(pragma.Value, "^"),
+					Edits:   []solcparser.TextEdit{{Start: offset, End: offset + 1}},
+				}
+			}
+		}
+		pass.ReportFix(pragma, fix, "floating pragma solidity %s", pragma.Value)
+	}
+}
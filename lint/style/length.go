@@ -0,0 +1,73 @@
+package style
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// maxLineLength reports the lines longer than the 'max' option (120 by default)
+type maxLineLength struct{}
+
+func (maxLineLength) ID() string              { return "max-line-length" }
+func (maxLineLength) Severity() lint.Severity { return lint.SeverityInfo }
+func (maxLineLength) Doc() string {
+	return "the lines must not be longer than the limit (option: max, 120 by default)"
+}
+
+func (maxLineLength) Check(pass *lint.Pass) {
+	max := pass.IntOption("max", 120)
+	offset := 0
+	for i, line := range strings.Split(pass.Source, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if length := utf8.RuneCountInString(line); length > max {
+			start := solcparser.Position{Offset: offset, Line: i + 1}
+			end := solcparser.Position{Offset: offset + len(line), Line: i + 1, Column: len(line)}
+			pass.ReportAt(&solcparser.Location{Start: start, End: end}, "line length %d exceeds %d", length, max)
+		}
+		offset += len(line) + 1
+	}
+}
+
+// functionMaxLines reports the functions longer than the 'max' option (50 lines by default)
+type functionMaxLines struct{}
+
+func (functionMaxLines) ID() string              { return "function-max-lines" }
+func (functionMaxLines) Severity() lint.Severity { return lint.SeverityInfo }
+func (functionMaxLines) Doc() string {
+	return "the functions must not be longer than the limit of lines (option: max, 50 by default)"
+}
+
+func (functionMaxLines) Check(pass *lint.Pass) {
+	max := pass.IntOption("max", 50)
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		var name string
+		var body interface{}
+		switch obj := node.(type) {
+		case *solcparser.FunctionDefinition:
+			name, body = obj.Name, obj.Body
+			if name == "" {
+				name = "constructor"
+				if obj.IsFallback {
+					name = "fallback"
+				} else if obj.IsReceiveEther {
+					name = "receive"
+				}
+			}
+		case *solcparser.ModifierDefinition:
+			name, body = obj.Name, obj.Body
+		default:
+			return true
+		}
+		loc := locOf(body)
+		if loc == nil {
+			return false
+		}
+		if lines := loc.End.Line - loc.Start.Line + 1; lines > max {
+			pass.Report(node, "%s has %d lines (max %d)", name, lines, max)
+		}
+		return false
+	})
+}
@@ -0,0 +1,201 @@
+package style
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+var (
+	capWordsRe  = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	mixedCaseRe = regexp.MustCompile(`^_*[a-z][a-zA-Z0-9]*$`)
+	upperCaseRe = regexp.MustCompile(`^_*[A-Z][A-Z0-9_]*$`)
+)
+
+// namingConvention reports the names that do not follow the style guide:
+//
+//   - CapWords for the contracts, structs, enums, events, errors and user types
+//   - mixedCase for the functions, modifiers and state variables
+//   - UPPER_CASE for the constants and (by default) the immutables
+//   - a leading underscore for the private functions and state variables
+//
+// The private members are renamed by the fix since all their references are
+// in the same file.
+type namingConvention struct{}
+
+func (namingConvention) ID() string              { return "naming-convention" }
+func (namingConvention) Severity() lint.Severity { return lint.SeverityWarning }
+func (namingConvention) Doc() string {
+	return "names must follow the conventions of the style guide (options: immutables 'upper' or 'mixed', private-underscore)"
+}
+
+func (namingConvention) Check(pass *lint.Pass) {
+	immutablesUpper := pass.StringOption("immutables", "upper") == "upper"
+	privateUnderscore := pass.BoolOption("private-underscore", true)
+
+	capWords := func(node interface{}, kind, name string) {
+		if name != "" && !capWordsRe.MatchString(name) {
+			pass.Report(node, "%s name %s must be in CapWords", kind, name)
+		}
+	}
+
+	for _, child := range pass.Unit.Children {
+		switch obj := child.(type) {
+		case *solcparser.FileLevelConstant:
+			if !upperCaseRe.MatchString(obj.Name) {
+				pass.Report(obj, "constant name %s must be in UPPER_CASE", obj.Name)
+			}
+		case *solcparser.FunctionDefinition:
+			if obj.Name != "" && !mixedCaseRe.MatchString(obj.Name) {
+				pass.Report(obj, "function name %s must be in mixedCase", obj.Name)
+			}
+		}
+	}
+
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *solcparser.ContractDefinition:
+			capWords(obj, obj.Kind, obj.Name)
+		case *solcparser.StructDefinition:
+			capWords(obj, "struct", obj.Name)
+		case *solcparser.EnumDefinition:
+			capWords(obj, "enum", obj.Name)
+		case *solcparser.EventDefinition:
+			capWords(obj, "event", obj.Name)
+		case *solcparser.CustomErrorDefinition:
+			capWords(obj, "error", obj.Name)
+		case *solcparser.TypeDefinition:
+			capWords(obj, "type", obj.Name)
+		}
+		return true
+	})
+
+	for _, c := range contracts(pass.Unit) {
+		for _, node := range c.SubNodes {
+			switch obj := node.(type) {
+			case *solcparser.ModifierDefinition:
+				if !mixedCaseRe.MatchString(obj.Name) {
+					pass.Report(obj, "modifier name %s must be in mixedCase", obj.Name)
+				}
+
+			case *solcparser.FunctionDefinition:
+				if obj.Name == "" {
+					// constructor, fallback and receive
+					continue
+				}
+				private := obj.Visibility == "private" && privateUnderscore
+				name := fixName(obj.Name, mixedCaseRe, toMixedCase, private)
+				if name == obj.Name {
+					continue
+				}
+				var fix *solcparser.SuggestedFix
+				if obj.Visibility == "private" && obj.Loc != nil {
+					if offset, ok := nameOffset(pass.Source, obj.Loc.Start.Offset, "function", obj.Name); ok {
+						fix = renameFix(pass, obj, offset, obj.Name, name)
+					}
+				}
+				pass.ReportFix(obj, fix, "function name %s must be %s", obj.Name, name)
+			}
+		}
+
+		for _, v := range stateVariables(c) {
+			re, convert, kind := mixedCaseRe, toMixedCase, "state variable"
+			if v.IsDeclaredConst {
+				re, convert, kind = upperCaseRe, toUpperCase, "constant"
+			} else if v.IsInmutable && immutablesUpper {
+				re, convert, kind = upperCaseRe, toUpperCase, "immutable"
+			}
+			private := v.Visibility == "private" && privateUnderscore
+			name := fixName(v.Name, re, convert, private)
+			if name == v.Name {
+				continue
+			}
+			var fix *solcparser.SuggestedFix
+			if ident, ok := v.Identifier.(*solcparser.Identifier); ok && v.Visibility == "private" && ident.Loc != nil {
+				fix = renameFix(pass, v, ident.Loc.Start.Offset, v.Name, name)
+			}
+			pass.ReportFix(v, fix, "%s name %s must be %s", kind, v.Name, name)
+		}
+	}
+}
+
+// fixName returns the name that follows the convention of the regexp
+func fixName(name string, re *regexp.Regexp, convert func(words []string) string, private bool) string {
+	res := name
+	if !re.MatchString(name) {
+		prefix := name[:len(name)-len(strings.TrimLeft(name, "_"))]
+		res = prefix + convert(splitWords(name))
+	}
+	if private && !strings.HasPrefix(res, "_") {
+		res = "_" + res
+	}
+	return res
+}
+
+// splitWords splits a name in words by the underscores and the case changes
+func splitWords(name string) []string {
+	words := []string{}
+	for _, part := range strings.Split(name, "_") {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i]) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			words = append(words, string(runes[start:]))
+		}
+	}
+	return words
+}
+
+func toMixedCase(words []string) string {
+	res := ""
+	for i, word := range words {
+		if strings.ToUpper(word) == word {
+			word = strings.ToLower(word)
+		}
+		if i == 0 {
+			res += strings.ToLower(word[:1]) + word[1:]
+		} else {
+			res += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return res
+}
+
+func toUpperCase(words []string) string {
+	return strings.ToUpper(strings.Join(words, "_"))
+}
+
+// renameFix returns the fix that renames the declaration (whose name is at the
+// offset) and its references in the file. It returns nil if the new name is
+// already used in the file.
+func renameFix(pass *lint.Pass, decl interface{}, offset int, name, newName string) *solcparser.SuggestedFix {
+	if isUsed(pass.Source, newName) {
+		return nil
+	}
+	fix := &solcparser.SuggestedFix{
+		Message: "rename " + name + " to " + newName,
+		Edits:   []solcparser.TextEdit{{Start: offset, End: offset + len(name), NewText: newName}},
+	}
+	solcparser.Inspect(pass.Unit, func(node interface{}) bool {
+		loc := locOf(node)
+		if loc == nil || pass.Info.RefOf(node) != decl {
+			return true
+		}
+		switch node.(type) {
+		case *solcparser.Identifier:
+			fix.Edits = append(fix.Edits, solcparser.TextEdit{Start: loc.Start.Offset, End: loc.End.Offset, NewText: newName})
+		case *solcparser.MemberAccess:
+			fix.Edits = append(fix.Edits, solcparser.TextEdit{Start: loc.End.Offset - len(name), End: loc.End.Offset, NewText: newName})
+		}
+		return true
+	})
+	return fix
+}
@@ -0,0 +1,159 @@
+package style
+
+import (
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// natspec reports the public and external functions without a complete NatSpec
+// comment: a description, a @param for each named parameter and a @return for
+// each return value. The tags without a description are not complete, solc
+// rejects them.
+type natspec struct{}
+
+func (natspec) ID() string              { return "natspec" }
+func (natspec) Severity() lint.Severity { return lint.SeverityInfo }
+func (natspec) Doc() string {
+	return "the public and external functions must be documented with NatSpec"
+}
+
+func (natspec) Check(pass *lint.Pass) {
+	for _, c := range contracts(pass.Unit) {
+		for _, node := range c.SubNodes {
+			fn, ok := node.(*solcparser.FunctionDefinition)
+			if !ok || fn.Name == "" || fn.Loc == nil || fn.Loc.Start.Offset > len(pass.Source) {
+				continue
+			}
+			switch fn.Visibility {
+			case "public", "external", "default":
+			default:
+				continue
+			}
+			checkNatspec(pass, fn)
+		}
+	}
+}
+
+// docComment is a NatSpec comment
+type docComment struct {
+	// text is true if there is a description without tag (an implicit @notice)
+	text bool
+	tags map[string]int
+
+	// params are the names of the @param tags, true if the tag has a
+	// description
+	params map[string]bool
+
+	// returns are the @return tags in order, true if the tag has a description
+	returns []bool
+}
+
+// parseDoc parses the NatSpec comment that precedes the offset of the source
+func parseDoc(src string, offset int) *docComment {
+	lineStart := strings.LastIndex(src[:offset], "\n") + 1
+	lines := strings.Split(src[:lineStart], "\n")
+	// the source before the declaration ends with a new line
+	lines = lines[:len(lines)-1]
+
+	// collect the comment from the bottom
+	content := []string{}
+	doc := &docComment{params: map[string]bool{}, tags: map[string]int{}}
+	i := len(lines) - 1
+	for ; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "///") {
+			break
+		}
+		content = append([]string{strings.TrimPrefix(line, "///")}, content...)
+	}
+	if len(content) == 0 {
+		// a '/** ... */' comment
+		if i < 0 || !strings.HasSuffix(strings.TrimSpace(lines[i]), "*/") {
+			return nil
+		}
+		for j := i; j >= 0; j-- {
+			line := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(line, "/**") {
+				content = append([]string{strings.TrimPrefix(line, "/**")}, content...)
+				break
+			}
+			if strings.HasPrefix(line, "/*") || j == 0 {
+				// a regular comment
+				return nil
+			}
+			content = append([]string{line}, content...)
+		}
+		last := len(content) - 1
+		content[last] = strings.TrimSuffix(strings.TrimSpace(content[last]), "*/")
+	}
+
+	tagged := false
+	for _, line := range content {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(fields[0], "@") {
+			if !tagged {
+				doc.text = true
+			}
+			continue
+		}
+		tagged = true
+		doc.tags[fields[0]]++
+		switch fields[0] {
+		case "@param":
+			if len(fields) > 1 {
+				doc.params[fields[1]] = len(fields) > 2
+			}
+		case "@return":
+			doc.returns = append(doc.returns, len(fields) > 1)
+		}
+	}
+	return doc
+}
+
+func checkNatspec(pass *lint.Pass, fn *solcparser.FunctionDefinition) {
+	doc := parseDoc(pass.Source, fn.Loc.Start.Offset)
+	if doc == nil {
+		pass.Report(fn, "function %s has no NatSpec comment", fn.Name)
+		return
+	}
+	if doc.tags["@inheritdoc"] != 0 {
+		return
+	}
+	if !doc.text && doc.tags["@notice"] == 0 && doc.tags["@dev"] == 0 {
+		pass.Report(fn, "NatSpec of function %s has no description", fn.Name)
+	}
+
+	missing := []string{}
+	for _, p := range fn.Parameters {
+		decl, ok := p.(*solcparser.VariableDeclaration)
+		if !ok || decl.Name == "" {
+			continue
+		}
+		if described, ok := doc.params[decl.Name]; !ok {
+			missing = append(missing, "@param "+decl.Name)
+		} else if !described {
+			missing = append(missing, "a description for @param "+decl.Name)
+		}
+	}
+	returns, _ := fn.ReturnParameters.([]interface{})
+	for i := range returns {
+		tag := "@return"
+		if decl, ok := returns[i].(*solcparser.VariableDeclaration); ok && decl.Name != "" {
+			tag += " " + decl.Name
+		}
+		if i >= len(doc.returns) {
+			missing = append(missing, tag)
+		} else if !doc.returns[i] {
+			missing = append(missing, "a description for "+tag)
+		}
+	}
+	if len(missing) != 0 {
+		pass.Report(fn, "NatSpec of function %s is missing %s", fn.Name, strings.Join(missing, ", "))
+	}
+}
@@ -0,0 +1,93 @@
+package style
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// ordering reports the members of a contract that are not in the order of the
+// style guide. There is no fix since moving the code may move the comments
+// around it too.
+type ordering struct{}
+
+func (ordering) ID() string              { return "ordering" }
+func (ordering) Severity() lint.Severity { return lint.SeverityInfo }
+func (ordering) Doc() string {
+	return "the members of a contract must be ordered: using for, types, state variables, events, errors, modifiers, constructor, receive, fallback and the external, public, internal and private functions"
+}
+
+func (ordering) Check(pass *lint.Pass) {
+	for _, c := range contracts(pass.Unit) {
+		prev, prevRank := "", -1
+		for _, node := range c.SubNodes {
+			name, rank := memberRank(node)
+			if rank == -1 {
+				continue
+			}
+			if rank < prevRank {
+				pass.Report(node, "%s must come before %s", name, prev)
+				continue
+			}
+			prev, prevRank = name, rank
+		}
+	}
+}
+
+// memberRank returns the description of a contract member and its position in the order
+func memberRank(node interface{}) (string, int) {
+	switch obj := node.(type) {
+	case *solcparser.UsingForDeclaration:
+		return "using for", 0
+	case *solcparser.StructDefinition:
+		return "struct " + obj.Name, 1
+	case *solcparser.EnumDefinition:
+		return "enum " + obj.Name, 1
+	case *solcparser.TypeDefinition:
+		return "type " + obj.Name, 1
+	case *solcparser.StateVariableDeclaration:
+		name := "state variable"
+		if len(obj.Variables) == 1 {
+			if v, ok := obj.Variables[0].(*solcparser.StateVariableDeclarationVariable); ok {
+				name += " " + v.Name
+			}
+		}
+		return name, 2
+	case *solcparser.EventDefinition:
+		return "event " + obj.Name, 3
+	case *solcparser.CustomErrorDefinition:
+		return "error " + obj.Name, 4
+	case *solcparser.ModifierDefinition:
+		return "modifier " + obj.Name, 5
+	case *solcparser.FunctionDefinition:
+		switch {
+		case obj.IsConstructor:
+			return "constructor", 6
+		case obj.IsReceiveEther:
+			return "receive function", 7
+		case obj.IsFallback:
+			return "fallback function", 8
+		}
+		rank := 9
+		switch obj.Visibility {
+		case "public", "default":
+			rank = 12
+		case "internal":
+			rank = 15
+		case "private":
+			rank = 18
+		}
+		// view and pure functions go after the others of the same visibility
+		switch obj.StateMutability {
+		case "view":
+			rank++
+		case "pure":
+			rank += 2
+		}
+		visibility := obj.Visibility
+		if visibility == "default" {
+			visibility = "public"
+		}
+		return visibility + " function " + obj.Name, rank
+	}
+	return "", -1
+}
@@ -0,0 +1,152 @@
+// Package style implements lint rules for the conventions of the Solidity
+// style guide. The rules are registered in the lint registry when the package
+// is imported and they suggest a fix when the change is safe.
+package style
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+var rules = []lint.Rule{
+	&namingConvention{},
+	&ordering{},
+	&stateVisibility{},
+	&noGlobalImport{},
+	&floatingPragma{},
+	&maxLineLength{},
+	&functionMaxLines{},
+	&natspec{},
+	&constantCandidate{},
+	&immutableCandidate{},
+}
+
+func init() {
+	for _, rule := range rules {
+		lint.Register(rule)
+	}
+}
+
+// Rules returns the style rules
+func Rules() []lint.Rule {
+	return append([]lint.Rule{}, rules...)
+}
+
+// contracts returns the contracts declared in the source unit
+func contracts(unit *solcparser.SourceUnit) []*solcparser.ContractDefinition {
+	res := []*solcparser.ContractDefinition{}
+	for _, child := range unit.Children {
+		if c, ok := child.(*solcparser.ContractDefinition); ok {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// stateVariables returns the state variables declared in the contract
+func stateVariables(c *solcparser.ContractDefinition) []*solcparser.StateVariableDeclarationVariable {
+	res := []*solcparser.StateVariableDeclarationVariable{}
+	for _, node := range c.SubNodes {
+		decl, ok := node.(*solcparser.StateVariableDeclaration)
+		if !ok {
+			continue
+		}
+		for _, v := range decl.Variables {
+			if obj, ok := v.(*solcparser.StateVariableDeclarationVariable); ok {
+				res = append(res, obj)
+			}
+		}
+	}
+	return res
+}
+
+func locOf(node interface{}) *solcparser.Location {
+	if n, ok := node.(solcparser.INode); ok {
+		return n.GetLoc()
+	}
+	return nil
+}
+
+// insertAfter returns the fix that inserts the text after the node
+func insertAfter(node interface{}, text, message string) *solcparser.SuggestedFix {
+	loc := locOf(node)
+	if loc == nil {
+		return nil
+	}
+	return &solcparser.SuggestedFix{
+		Message: message,
+		Edits:   []solcparser.TextEdit{{Start: loc.End.Offset, End: loc.End.Offset, NewText: text}},
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// nameOffset returns the offset of the name declared after the keyword
+// (i.e. 'function name') in the source starting at the offset
+func nameOffset(src string, offset int, keyword, name string) (int, bool) {
+	if offset < 0 || offset > len(src) {
+		return 0, false
+	}
+	indx := strings.Index(src[offset:], keyword)
+	if indx == -1 {
+		return 0, false
+	}
+	pos := offset + indx + len(keyword)
+	for pos < len(src) && strings.ContainsRune(" \t\r\n", rune(src[pos])) {
+		pos++
+	}
+	end := pos + len(name)
+	if !strings.HasPrefix(src[pos:], name) || (end < len(src) && isIdentChar(src[end])) {
+		return 0, false
+	}
+	return pos, true
+}
+
+// isUsed returns true if the name is used as a word anywhere in the source
+func isUsed(src, name string) bool {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`).MatchString(src)
+}
+
+var versionRe = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// minVersion returns the first compiler version in the solidity pragma of the
+// source unit as [major, minor, patch]
+func minVersion(unit *solcparser.SourceUnit) ([3]int, bool) {
+	var res [3]int
+	for _, child := range unit.Children {
+		pragma, ok := child.(*solcparser.PragmaDirective)
+		if !ok || pragma.Name != "solidity" {
+			continue
+		}
+		match := versionRe.FindStringSubmatch(pragma.Value)
+		if match == nil {
+			return res, false
+		}
+		for i := 0; i < 3; i++ {
+			res[i], _ = strconv.Atoi(match[i+1])
+		}
+		return res, true
+	}
+	return res, false
+}
+
+// versionAtLeast returns true if the pragma of the unit requires at least the version
+func versionAtLeast(unit *solcparser.SourceUnit, version [3]int) bool {
+	min, ok := minVersion(unit)
+	if !ok {
+		// without pragma assume a recent compiler
+		return true
+	}
+	for i := 0; i < 3; i++ {
+		if min[i] != version[i] {
+			return min[i] > version[i]
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package style
+
+import (
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/lint/linttest"
+)
+
+func TestRules(t *testing.T) {
+	linttest.Run(t, "testdata", Rules())
+}
+
+func TestCandidatesWithoutFixes(t *testing.T) {
+	// making a variable constant or immutable changes the storage layout
+	src := `contract A {
+    uint256 a = 1;
+    uint256 b = block.number;
+    uint256 c;
+}`
+	diags, err := lint.NewWithRules(nil, constantCandidate{}, immutableCandidate{}).Lint(map[string]string{"a.sol": src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 2 {
+		t.Fatalf("two diagnostics expected but found %v", diags)
+	}
+	for _, d := range diags {
+		if len(d.Fixes) != 0 {
+			t.Fatalf("unexpected fix for %s", d)
+		}
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	cases := []struct {
+		name  string
+		mixed string
+		upper string
+	}{
+		{"max_supply", "maxSupply", "MAX_SUPPLY"},
+		{"MaxSupply", "maxSupply", "MAX_SUPPLY"},
+		{"MAX_SUPPLY", "maxSupply", "MAX_SUPPLY"},
+		{"feeRate", "feeRate", "FEE_RATE"},
+		{"Total_amount", "totalAmount", "TOTAL_AMOUNT"},
+	}
+	for _, c := range cases {
+		words := splitWords(c.name)
+		if mixed := toMixedCase(words); mixed != c.mixed {
+			t.Fatalf("bad mixedCase of %s: %s", c.name, mixed)
+		}
+		if upper := toUpperCase(words); upper != c.upper {
+			t.Fatalf("bad UPPER_CASE of %s: %s", c.name, upper)
+		}
+	}
+}
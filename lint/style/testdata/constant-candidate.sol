@@ -0,0 +1,29 @@
+pragma solidity 0.8.19;
+
+contract Config {
+    uint256 public fee = 3 * 10; // want
+    bytes32 public role = keccak256("ADMIN"); // want
+    string public name = "config"; // want
+    uint256 public constant MAX = 100;
+    uint256 public counter = 1;
+    address public owner = msg.sender;
+    uint256 internal limit = 10;
+    uint256[] public values;
+    uint256 slotValue = 5;
+
+    function bump() public {
+        counter++;
+    }
+
+    function read() public view returns (uint256 v) {
+        assembly {
+            v := sload(slotValue.slot)
+        }
+    }
+}
+
+contract Derived is Config {
+    function setLimit(uint256 l) public {
+        limit = l;
+    }
+}
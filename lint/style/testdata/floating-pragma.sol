@@ -0,0 +1,6 @@
+pragma solidity ^0.8.0; // want
+pragma solidity >=0.6.0 <0.9.0; // want
+pragma solidity 0.8.19;
+pragma solidity =0.8.19;
+
+contract A {}
@@ -0,0 +1,27 @@
+pragma solidity 0.8.19;
+
+contract Token {
+    address public owner; // want
+    uint256 public deployedAt = block.timestamp; // want
+    uint256 public supply;
+    uint256 public cap;
+    uint256 public rate;
+    uint256 public constant DECIMALS = 18;
+    uint256 public fee = 1;
+    uint256 public doubled; // want
+
+    constructor(uint256 c, uint256 r) {
+        owner = msg.sender;
+        supply = c;
+        supply = supply + 1;
+        if (c > 10) {
+            cap = c;
+        }
+        rate = r;
+        doubled = rate * 2;
+    }
+
+    function setRate(uint256 r) public {
+        rate = r;
+    }
+}
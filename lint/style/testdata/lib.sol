@@ -0,0 +1,9 @@
+pragma solidity 0.8.19;
+
+uint256 constant MAX = 10;
+
+contract Base {}
+
+library Math {}
+
+contract Unused {}
@@ -0,0 +1,9 @@
+pragma solidity 0.8.19;
+
+contract Long {
+    function f() public pure returns (uint256) {
+        return 1 + 2 + 3 + 4 + 5 + 6 + 7 + 8 + 9 + 10 + 11 + 12 + 13 + 14 + 15 + 16 + 17 + 18 + 19 + 20 + 21 + 22 + 23; // want
+    }
+
+    // a short comment
+}
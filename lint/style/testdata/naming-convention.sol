@@ -0,0 +1,43 @@
+pragma solidity 0.8.19;
+
+uint256 constant maxSupply = 100; // want
+
+contract token_sale { // want
+    struct order { // want
+        uint256 amount;
+    }
+
+    event transfer(address to); // want
+    error Unauthorized();
+
+    uint256 private counter; // want
+    uint256 internal Total_amount; // want
+    uint256 public constant feeRate = 3; // want
+    uint256 public immutable CAP;
+    address private _owner;
+
+    modifier OnlyOwner() { // want
+        _;
+    }
+
+    constructor(uint256 cap) {
+        CAP = cap;
+    }
+
+    function Increment() public { // want
+        counter++;
+        _bump(counter);
+    }
+
+    function _bump(uint256 by) internal {
+        Total_amount += by;
+    }
+
+    function compute_value() private view returns (uint256) { // want
+        return counter + this.rate();
+    }
+
+    function rate() public pure returns (uint256) {
+        return feeRate;
+    }
+}
@@ -0,0 +1,54 @@
+pragma solidity 0.8.19;
+
+interface IVault {
+    /// @notice Deposits the tokens
+    /// @param amount The amount of tokens
+    function deposit(uint256 amount) external;
+
+    function withdraw(uint256 amount) external; // want
+
+    /// @notice Returns the balance
+    function balanceOf(address who) external view returns (uint256); // want
+
+    /**
+     * @dev Moves the tokens
+     * @param to The receiver
+     */
+    function transfer(address to, uint256 amount) external returns (bool ok); // want
+
+    /// @param to The receiver
+    function approve(address to) external; // want
+}
+
+contract Vault is IVault {
+    /// @inheritdoc IVault
+    function deposit(uint256 amount) external override {}
+
+    /// Withdraws the tokens
+    /// @param amount The amount
+    function withdraw(uint256 amount) external override {}
+
+    /// Returns the balance
+    /// @param who The account
+    /// @return The balance
+    function balanceOf(address who) external view override returns (uint256) {
+        return 0;
+    }
+
+    /// @notice Moves the tokens
+    /// @param to The receiver
+    /// @param amount The amount
+    /// @return ok True on success
+    function transfer(address to, uint256 amount) external override returns (bool ok) {}
+
+    /// @notice Approves
+    /// @param to The spender
+    function approve(address to) external override {}
+
+    /// @notice Pauses the vault
+    /// @param until
+    /// @return
+    function pause(uint256 until) external returns (bool) {} // want
+
+    function _internal(uint256 a) internal {}
+}
@@ -0,0 +1,12 @@
+pragma solidity 0.8.19;
+
+import "./lib.sol"; // want
+import {Other} from "./other.sol";
+
+contract UsesLib is Base {
+    using Math for uint256;
+
+    function f(Other o) public pure returns (uint256) {
+        return MAX;
+    }
+}
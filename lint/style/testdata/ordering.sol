@@ -0,0 +1,49 @@
+pragma solidity 0.8.19;
+
+contract Ordered {
+    using Lib for uint256;
+
+    struct Item {
+        uint256 id;
+    }
+
+    uint256 public total;
+
+    event Added(uint256 id);
+
+    modifier onlyOwner() {
+        _;
+    }
+
+    constructor() {}
+
+    receive() external payable {}
+
+    function add() external {}
+
+    function get() external view returns (uint256) {
+        return total;
+    }
+
+    function _helper() internal {}
+}
+
+contract Unordered {
+    function add() public {}
+
+    uint256 public total; // want
+
+    function get() external view returns (uint256) { // want
+        return total;
+    }
+
+    event Added(uint256 id); // want
+
+    function _helper() private {}
+
+    function pure1() private pure {}
+
+    function inner() internal {} // want
+}
+
+library Lib {}
@@ -0,0 +1,9 @@
+pragma solidity 0.8.19;
+
+contract Storage {
+    uint256 value; // want
+    mapping(address => uint256) balances; // want
+    address public owner;
+    uint256 private secret;
+    bool constant FLAG = true; // want
+}
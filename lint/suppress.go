@@ -0,0 +1,139 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+var suppressRegexp = regexp.MustCompile(`^(?://|/\*)\s*sollint-(disable-next-line|disable-line|disable|enable)\b([^*\n]*)`)
+
+// suppression disables some rules (all of them if ids is empty) between two lines
+type suppression struct {
+	ids []string
+
+	// except are the rules enabled again in a range that disables all of them
+	except []string
+
+	start int
+
+	// end is the last line, zero if it lasts until the end of the file
+	end int
+}
+
+func (s *suppression) matches(rule string, line int) bool {
+	if line < s.start || (s.end != 0 && line > s.end) {
+		return false
+	}
+	if len(s.ids) == 0 {
+		return !containsID(s.except, rule)
+	}
+	return containsID(s.ids, rule)
+}
+
+// suppressions are the suppression comments of a source
+type suppressions []*suppression
+
+// parseSuppressions returns the suppressions of the comments of a source, the
+// directives in the string literals or in the middle of a comment are ignored
+func parseSuppressions(src string) suppressions {
+	res := suppressions{}
+	open := []*suppression{}
+
+	it := solcparser.NewIterator(src)
+	for {
+		tok, ok := it.Next()
+		if !ok {
+			break
+		}
+		if tok.Kind != solcparser.TokenComment && tok.Kind != solcparser.TokenLineComment {
+			continue
+		}
+		match := suppressRegexp.FindStringSubmatch(tok.Text)
+		if match == nil {
+			continue
+		}
+
+		num := tok.Loc.Start.Line
+		ids := parseIDs(match[2])
+		switch match[1] {
+		case "disable-next-line":
+			next := tok.Loc.End.Line + 1
+			res = append(res, &suppression{ids: ids, start: next, end: next})
+		case "disable-line":
+			res = append(res, &suppression{ids: ids, start: num, end: num})
+		case "disable":
+			s := &suppression{ids: ids, start: num}
+			res = append(res, s)
+			open = append(open, s)
+		case "enable":
+			// close the ranges of the rules, the ranges that disable other
+			// rules too continue without the enabled ones
+			remaining := []*suppression{}
+			for _, s := range open {
+				var next *suppression
+				if len(ids) == 0 {
+					// all the rules are enabled again
+				} else if len(s.ids) == 0 {
+					next = &suppression{except: append(append([]string{}, s.except...), ids...)}
+				} else {
+					left := []string{}
+					for _, id := range s.ids {
+						if !containsID(ids, id) {
+							left = append(left, id)
+						}
+					}
+					if len(left) == len(s.ids) {
+						remaining = append(remaining, s)
+						continue
+					}
+					if len(left) != 0 {
+						next = &suppression{ids: left}
+					}
+				}
+
+				s.end = num
+				if next != nil {
+					next.start = num + 1
+					res = append(res, next)
+					remaining = append(remaining, next)
+				}
+			}
+			open = remaining
+		}
+	}
+	return res
+}
+
+func parseIDs(str string) []string {
+	// a description can follow the ids after '--'
+	if indx := strings.Index(str, "--"); indx != -1 {
+		str = str[:indx]
+	}
+	res := []string{}
+	for _, id := range strings.FieldsFunc(str, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\r'
+	}) {
+		res = append(res, id)
+	}
+	return res
+}
+
+func containsID(ids []string, id string) bool {
+	for _, item := range ids {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s suppressions) suppressed(rule string, line int) bool {
+	for _, item := range s {
+		if item.matches(rule, line) {
+			return true
+		}
+	}
+	return false
+}
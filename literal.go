@@ -0,0 +1,506 @@
+package solcparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// literalError is an invalid part of the text of a literal, the offsets are
+// relative to the text
+type literalError struct {
+	start, end int
+	msg        string
+}
+
+func (e *literalError) Error() string {
+	return e.msg
+}
+
+// syntaxError returns the syntax error of the literal that starts at an offset
+func (e *literalError) syntaxError(src *sourceMap, offset int) *SyntaxError {
+	err := &SyntaxError{
+		msg: e.msg,
+		loc: &Location{
+			Start: src.position(offset + e.start),
+			End:   src.position(offset + e.end),
+		},
+	}
+	err.line, err.column = err.loc.Start.Line, err.loc.Start.Column
+	return err
+}
+
+// sortErrors sorts the syntax errors by their position
+func sortErrors(errs []*SyntaxError) []*SyntaxError {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].loc != nil && errs[j].loc != nil && errs[i].loc.Start.Offset < errs[j].loc.Start.Offset
+	})
+	return errs
+}
+
+// StringFragment is a string literal token (i.e. unicode'a\n' or hex"00ff")
+type StringFragment struct {
+	// Prefix is "unicode", "hex" or empty
+	Prefix string
+
+	// Quote is the quote of the literal, '"' or '\''
+	Quote byte
+
+	// Raw is the text between the quotes as it is in the source, the escape
+	// sequences are not decoded
+	Raw string
+
+	// Value is the decoded text, the bytes of the digits for the hex literals
+	Value string
+}
+
+// parseStringFragment parses the text of a string literal token. The
+// fragment is decoded even if it has errors, it is nil only if the text is
+// not quoted.
+func parseStringFragment(text string) (*StringFragment, *literalError) {
+	f := &StringFragment{}
+	for _, prefix := range []string{"unicode", "hex"} {
+		if strings.HasPrefix(text, prefix) {
+			f.Prefix = prefix
+		}
+	}
+	quoted := text[len(f.Prefix):]
+	if len(quoted) < 2 || (quoted[0] != '"' && quoted[0] != '\'') || quoted[len(quoted)-1] != quoted[0] {
+		return nil, &literalError{start: 0, end: len(text), msg: "invalid string literal"}
+	}
+	f.Quote = quoted[0]
+	f.Raw = quoted[1 : len(quoted)-1]
+
+	var err *literalError
+	switch f.Prefix {
+	case "hex":
+		f.Value, err = decodeHex(f.Raw)
+	case "unicode":
+		f.Value, err = decodeString(f.Raw)
+		if err == nil && !utf8.ValidString(f.Value) {
+			err = &literalError{start: 0, end: len(f.Raw), msg: "invalid UTF-8 sequence in unicode literal"}
+		}
+	default:
+		f.Value, err = decodeString(f.Raw)
+		for i := 0; i < len(f.Raw) && err == nil; i++ {
+			if f.Raw[i] >= utf8.RuneSelf {
+				_, size := utf8.DecodeRuneInString(f.Raw[i:])
+				err = &literalError{start: i, end: i + size, msg: "invalid character in string literal, the characters that are not ASCII need a unicode literal (i.e. unicode\"...\")"}
+			}
+		}
+	}
+	if err != nil {
+		// the offsets of the error are relative to the token
+		offset := len(f.Prefix) + 1
+		err.start, err.end = err.start+offset, err.end+offset
+	}
+	return f, err
+}
+
+// newStringLiteral builds the literal of the texts of consecutive string
+// fragments (i.e. 'a' unicode"b"), the errors of the fragments are returned
+// by the index of the fragment
+func newStringLiteral(texts []string) (*StringLiteral, map[int]*literalError) {
+	decl := &StringLiteral{
+		Parts:     []string{},
+		IsUnicode: []bool{},
+	}
+	errs := map[int]*literalError{}
+	for i, text := range texts {
+		f, err := parseStringFragment(text)
+		if err != nil {
+			errs[i] = err
+		}
+		if f == nil {
+			continue
+		}
+		decl.Value += f.Value
+		decl.Raw += f.Raw
+		decl.Parts = append(decl.Parts, f.Raw)
+		decl.IsUnicode = append(decl.IsUnicode, f.Prefix == "unicode")
+	}
+	return decl, errs
+}
+
+// newHexLiteral builds the literal of the texts of consecutive hex fragments
+// (i.e. hex"00" hex'ff'), the errors of the fragments are returned by the
+// index of the fragment
+func newHexLiteral(texts []string) (*HexLiteral, map[int]*literalError) {
+	decl := &HexLiteral{
+		Parts: []string{},
+	}
+	data := []byte{}
+	errs := map[int]*literalError{}
+	for i, text := range texts {
+		f, err := parseStringFragment(text)
+		if err != nil {
+			errs[i] = err
+		}
+		if f == nil {
+			continue
+		}
+		decl.Parts = append(decl.Parts, f.Raw)
+		data = append(data, f.Value...)
+	}
+	decl.Value = strings.Join(decl.Parts, "")
+	if len(errs) == 0 {
+		decl.Data = data
+	}
+	return decl, errs
+}
+
+// decodeString decodes the escape sequences of the text of a string literal:
+// \\, \', \", \n, \r, \t, \xNN, \uNNNN and the line continuations. The
+// invalid escape sequences are kept as they are and the first one is returned
+// as an error.
+func decodeString(raw string) (string, *literalError) {
+	var b strings.Builder
+	var err *literalError
+	for i := 0; i < len(raw); {
+		if raw[i] != '\\' {
+			b.WriteByte(raw[i])
+			i++
+			continue
+		}
+		if i+1 == len(raw) {
+			if err == nil {
+				err = &literalError{start: i, end: i + 1, msg: "invalid escape sequence at the end of the string"}
+			}
+			b.WriteByte('\\')
+			break
+		}
+
+		start := i
+		c := raw[i+1]
+		i += 2
+		switch c {
+		case '\\', '\'', '"':
+			b.WriteByte(c)
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '\n':
+			// a line continuation
+		case '\r':
+			if i < len(raw) && raw[i] == '\n' {
+				i++
+			}
+		case 'x', 'u':
+			size := 2
+			if c == 'u' {
+				size = 4
+			}
+			if i+size > len(raw) || !isHexDigits(raw[i:i+size]) {
+				if err == nil {
+					end := i + size
+					if end > len(raw) {
+						end = len(raw)
+					}
+					err = &literalError{start: start, end: end, msg: fmt.Sprintf("invalid escape sequence '\\%c', expected %d hex digits", c, size)}
+				}
+				b.WriteString(raw[start:i])
+				continue
+			}
+			n, _ := strconv.ParseUint(raw[i:i+size], 16, 32)
+			if c == 'x' {
+				b.WriteByte(byte(n))
+			} else {
+				b.WriteRune(rune(n))
+			}
+			i += size
+		default:
+			r, size := utf8.DecodeRuneInString(raw[i-1:])
+			if err == nil {
+				err = &literalError{start: start, end: i - 1 + size, msg: fmt.Sprintf("invalid escape sequence '\\%c'", r)}
+			}
+			// the invalid sequence is kept as it is
+			b.WriteString(raw[start : i-1+size])
+			i += size - 1
+		}
+	}
+	return b.String(), err
+}
+
+// decodeHex decodes the digits of a hex literal, the digits can be separated
+// by single underscores between the bytes
+func decodeHex(raw string) (string, *literalError) {
+	digits := []byte{}
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c == '_' {
+			if i == 0 || i == len(raw)-1 || raw[i+1] == '_' || len(digits)%2 != 0 {
+				return "", &literalError{start: i, end: i + 1, msg: "misplaced underscore in hex literal"}
+			}
+			continue
+		}
+		if !isHexDigits(string(c)) {
+			return "", &literalError{start: i, end: i + 1, msg: fmt.Sprintf("invalid hex digit '%c'", c)}
+		}
+		digits = append(digits, c)
+	}
+	if len(digits)%2 != 0 {
+		return "", &literalError{start: 0, end: len(raw), msg: "hex literal with an odd number of digits"}
+	}
+
+	value := make([]byte, len(digits)/2)
+	for i := range value {
+		n, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		value[i] = byte(n)
+	}
+	return string(value), nil
+}
+
+func isHexDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// SubDenomination is the unit of a number literal (i.e. 'ether' or 'days')
+type SubDenomination int
+
+const (
+	NoSubDenomination SubDenomination = iota
+	Wei
+	Gwei
+	Szabo
+	Finney
+	Ether
+	Seconds
+	Minutes
+	Hours
+	Days
+	Weeks
+	Years
+)
+
+var subDenominations = map[SubDenomination]struct {
+	name string
+	mult int64
+}{
+	Wei:     {"wei", 1},
+	Gwei:    {"gwei", 1e9},
+	Szabo:   {"szabo", 1e12},
+	Finney:  {"finney", 1e15},
+	Ether:   {"ether", 1e18},
+	Seconds: {"seconds", 1},
+	Minutes: {"minutes", 60},
+	Hours:   {"hours", 3600},
+	Days:    {"days", 86400},
+	Weeks:   {"weeks", 604800},
+	Years:   {"years", 31536000},
+}
+
+// ParseSubDenomination returns the sub-denomination of a unit (i.e. "ether"),
+// an empty unit is NoSubDenomination
+func ParseSubDenomination(unit string) (SubDenomination, bool) {
+	if unit == "" {
+		return NoSubDenomination, true
+	}
+	for d, s := range subDenominations {
+		if s.name == unit {
+			return d, true
+		}
+	}
+	return NoSubDenomination, false
+}
+
+// String returns the unit as it is in the source, it is empty without a
+// sub-denomination
+func (d SubDenomination) String() string {
+	if d == NoSubDenomination {
+		return ""
+	}
+	if s, ok := subDenominations[d]; ok {
+		return s.name
+	}
+	return fmt.Sprintf("SubDenomination(%d)", int(d))
+}
+
+// Multiplier returns the value of one unit (i.e. 1e18 for ether or 86400 for
+// days), it is 1 without a sub-denomination
+func (d SubDenomination) Multiplier() *big.Int {
+	if s, ok := subDenominations[d]; ok {
+		return big.NewInt(s.mult)
+	}
+	return big.NewInt(1)
+}
+
+// MarshalJSON encodes the unit as a string, or null without a sub-denomination
+func (d SubDenomination) MarshalJSON() ([]byte, error) {
+	if d == NoSubDenomination {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// maxExponent bounds the exponent of the numbers in scientific notation,
+// big.Rat does not bound it
+const maxExponent = 4096
+
+// ParseNumber returns the exact value of the text of a decimal, hexadecimal
+// or scientific notation number (i.e. 1_000, 0xff or 1.5e18) with its
+// sub-denomination applied
+func ParseNumber(number string, unit SubDenomination) (*big.Rat, error) {
+	x, err := parseNumber(number, unit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s: %s", number, err.msg)
+	}
+	return x, nil
+}
+
+// newNumberLiteral builds the literal of a number and its unit, the offsets
+// of the error are relative to the number
+func newNumberLiteral(number, unit string) (*NumberLiteral, *literalError) {
+	decl := &NumberLiteral{
+		Number: number,
+	}
+	d, ok := ParseSubDenomination(unit)
+	if !ok {
+		return decl, &literalError{start: 0, end: len(number), msg: fmt.Sprintf("unknown sub-denomination '%s'", unit)}
+	}
+	decl.SubDenomination = d
+
+	x, err := parseNumber(number, d)
+	if err != nil {
+		return decl, err
+	}
+	decl.Value = x
+	return decl, nil
+}
+
+// parseNumber parses the text of a number literal, the underscores can only
+// separate two digits
+func parseNumber(number string, unit SubDenomination) (*big.Rat, *literalError) {
+	var x *big.Rat
+	if strings.HasPrefix(number, "0x") || strings.HasPrefix(number, "0X") {
+		if unit != NoSubDenomination {
+			return nil, &literalError{start: 0, end: len(number), msg: fmt.Sprintf("hexadecimal numbers cannot have a sub-denomination, use an expression like '%s * 1 %s'", number, unit)}
+		}
+		digits, err := numberDigits(number, 2, len(number), isHexDigits)
+		if err != nil {
+			return nil, err
+		}
+		num, _ := new(big.Int).SetString(digits, 16)
+		x = new(big.Rat).SetInt(num)
+	} else {
+		var err *literalError
+		if x, err = parseDecimal(number); err != nil {
+			return nil, err
+		}
+	}
+	return x.Mul(x, new(big.Rat).SetInt(unit.Multiplier())), nil
+}
+
+// parseDecimal parses a decimal number with an optional fraction and exponent
+// (i.e. 1_000, .5 or 1.5e18)
+func parseDecimal(number string) (*big.Rat, *literalError) {
+	end := len(number)
+	exp := int64(0)
+	if idx := strings.IndexAny(number, "eE"); idx != -1 {
+		start := idx + 1
+		if start < len(number) && number[start] == '-' {
+			start++
+		}
+		digits, err := numberDigits(number, start, len(number), isDecimalDigits)
+		if err != nil {
+			return nil, err
+		}
+		digits = strings.TrimLeft(digits, "0")
+		if len(digits) > 5 {
+			// it does not fit in the limit and it could overflow an int64
+			digits = "99999"
+		}
+		exp, _ = strconv.ParseInt("0"+digits, 10, 64)
+		if exp > maxExponent {
+			return nil, &literalError{start: idx, end: len(number), msg: fmt.Sprintf("the exponent of the number is out of range, the limit is %d", maxExponent)}
+		}
+		if start != idx+1 {
+			exp = -exp
+		}
+		end = idx
+	}
+
+	integer, fraction := "", ""
+	if idx := strings.IndexByte(number[:end], '.'); idx != -1 {
+		if idx != 0 {
+			var err *literalError
+			if integer, err = numberDigits(number, 0, idx, isDecimalDigits); err != nil {
+				return nil, err
+			}
+		}
+		var err *literalError
+		if fraction, err = numberDigits(number, idx+1, end, isDecimalDigits); err != nil {
+			return nil, err
+		}
+	} else {
+		var err *literalError
+		if integer, err = numberDigits(number, 0, end, isDecimalDigits); err != nil {
+			return nil, err
+		}
+	}
+	if len(integer) > 1 && integer[0] == '0' {
+		return nil, &literalError{start: 0, end: end, msg: "leading zeros are not allowed, octal numbers are not supported"}
+	}
+
+	num, _ := new(big.Int).SetString(integer+fraction, 10)
+	exp -= int64(len(fraction))
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(abs(exp)), nil)
+	if exp < 0 {
+		return new(big.Rat).SetFrac(num, scale), nil
+	}
+	return new(big.Rat).SetInt(num.Mul(num, scale)), nil
+}
+
+// numberDigits returns the digits of a part of a number without the
+// underscores, the underscores can only separate two digits
+func numberDigits(number string, start, end int, isDigits func(string) bool) (string, *literalError) {
+	if start == end {
+		return "", &literalError{start: 0, end: len(number), msg: "missing digits in number"}
+	}
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		c := number[i]
+		if c != '_' {
+			if !isDigits(string(c)) {
+				return "", &literalError{start: i, end: i + 1, msg: fmt.Sprintf("invalid digit '%c' in number", c)}
+			}
+			b.WriteByte(c)
+			continue
+		}
+		switch {
+		case i == end-1:
+			return "", &literalError{start: i, end: i + 1, msg: "trailing underscore in number"}
+		case i == start || number[i+1] == '_':
+			return "", &literalError{start: i, end: i + 1, msg: "misplaced underscore in number, the underscores can only separate two digits"}
+		}
+	}
+	return b.String(), nil
+}
+
+func isDecimalDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,269 @@
+package solcparser
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeString(t *testing.T) {
+	cases := []struct {
+		raw   string
+		value string
+		err   string
+	}{
+		{`abc`, "abc", ""},
+		{`a\\b\'c\"d`, `a\b'c"d`, ""},
+		{`\n\r\t`, "\n\r\t", ""},
+		{`\x41\x00\xff`, "A\x00\xff", ""},
+		{`é€`, "é€", ""},
+		{"a\\\nb", "ab", ""},
+		{"a\\\r\nb", "ab", ""},
+		{`\q\n`, "\\q\n", "invalid escape sequence '\\q'"},
+		{`\x4`, `\x4`, "invalid escape sequence '\\x', expected 2 hex digits"},
+		{`\u00g0`, `\u00g0`, "invalid escape sequence '\\u', expected 4 hex digits"},
+		{`a\`, `a\`, "invalid escape sequence at the end of the string"},
+	}
+
+	for _, c := range cases {
+		// the value of an invalid string keeps the invalid escape sequences
+		value, err := decodeString(c.raw)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Fatalf("bad error for '%s': %v", c.raw, err)
+			}
+		} else if err != nil {
+			t.Fatalf("unexpected error for '%s': %v", c.raw, err)
+		}
+		if value != c.value {
+			t.Fatalf("bad value for '%s': %q", c.raw, value)
+		}
+	}
+}
+
+func TestDecodeHex(t *testing.T) {
+	cases := []struct {
+		raw   string
+		value string
+		err   string
+	}{
+		{"", "", ""},
+		{"00ff", "\x00\xff", ""},
+		{"00_ff_AB", "\x00\xff\xab", ""},
+		{"0", "", "hex literal with an odd number of digits"},
+		{"_00", "", "misplaced underscore in hex literal"},
+		{"00_", "", "misplaced underscore in hex literal"},
+		{"0_0", "", "misplaced underscore in hex literal"},
+		{"00__ff", "", "misplaced underscore in hex literal"},
+		{"0g", "", "invalid hex digit 'g'"},
+	}
+
+	for _, c := range cases {
+		value, err := decodeHex(c.raw)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Fatalf("bad error for '%s': %v", c.raw, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for '%s': %v", c.raw, err)
+		}
+		if value != c.value {
+			t.Fatalf("bad value for '%s': %q", c.raw, value)
+		}
+	}
+}
+
+func TestStringLiteral(t *testing.T) {
+	cases := []struct {
+		literal string
+		value   string
+		raw     string
+		err     string
+		column  int
+	}{
+		{`"a\x41\u00e9\n" 'b\''`, "aAé\nb'", `a\x41\u00e9\nb\'`, "", 0},
+		{`unicode"é"`, "é", "é", "", 0},
+		{`"a\qb"`, `a\qb`, `a\qb`, "invalid escape sequence '\\q'", 26},
+		{`"a" "é"`, "aé", "aé", "invalid character in string literal, the characters that are not ASCII need a unicode literal (i.e. unicode\"...\")", 29},
+		{`unicode"\xff"`, "\xff", `\xff`, "invalid UTF-8 sequence in unicode literal", 32},
+	}
+
+	for _, c := range cases {
+		src := "contract A { string s = " + c.literal + "; }"
+		for _, backend := range []Backend{ANTLR, TreeSitter} {
+			// the tree-sitter grammar does not have the unicode literals
+			if backend == TreeSitter && c.literal[0] == 'u' {
+				continue
+			}
+
+			p := Parse(src, WithBackend(backend))
+			lit := p.Result.(*SourceUnit).Children[0].(*ContractDefinition).SubNodes[0].(*StateVariableDeclaration).Variables[0].(*StateVariableDeclarationVariable).Expression.(*StringLiteral)
+			if lit.Value != c.value || lit.Raw != c.raw {
+				t.Fatalf("bad literal for '%s' (%d): %q %q", c.literal, backend, lit.Value, lit.Raw)
+			}
+			if c.err == "" {
+				if len(p.Errors) != 0 {
+					t.Fatalf("unexpected errors for '%s' (%d): %v", c.literal, backend, p.Errors)
+				}
+				continue
+			}
+			if len(p.Errors) != 1 || p.Errors[0].Error() != c.err || p.Errors[0].Loc().Start.Column != c.column {
+				t.Fatalf("bad errors for '%s' (%d): %v", c.literal, backend, p.Errors)
+			}
+		}
+	}
+}
+
+func TestHexLiteralErrors(t *testing.T) {
+	p := Parse("contract A { bytes s = hex\"abc\"; }")
+	if len(p.Errors) != 1 || p.Errors[0].Error() != "hex literal with an odd number of digits" {
+		t.Fatalf("bad errors %v", p.Errors)
+	}
+	if loc := p.Errors[0].Loc(); loc.Start.Column != 27 || loc.End.Column != 30 {
+		t.Fatalf("bad location %v", loc)
+	}
+}
+
+func TestHexLiteral(t *testing.T) {
+	cases := []struct {
+		literal string
+		data    []byte
+	}{
+		{`hex"00" hex'ff_ee'`, []byte{0x00, 0xff, 0xee}},
+		{`hex""`, []byte{}},
+		// the invalid literals have no data
+		{`hex"abc"`, nil},
+	}
+	for _, c := range cases {
+		src := "contract A { bytes s = " + c.literal + "; }"
+		for _, backend := range []Backend{ANTLR, TreeSitter} {
+			p := Parse(src, WithBackend(backend))
+			lit := p.Result.(*SourceUnit).Children[0].(*ContractDefinition).SubNodes[0].(*StateVariableDeclaration).Variables[0].(*StateVariableDeclarationVariable).Expression.(*HexLiteral)
+			if !reflect.DeepEqual(lit.Data, c.data) {
+				t.Fatalf("bad data for '%s' (%d): %v", c.literal, backend, lit.Data)
+			}
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	cases := []struct {
+		number string
+		unit   SubDenomination
+		value  string
+		err    string
+	}{
+		{"1", NoSubDenomination, "1", ""},
+		{"0", NoSubDenomination, "0", ""},
+		{"1_000_000", NoSubDenomination, "1000000", ""},
+		{"0xff_FF", NoSubDenomination, "65535", ""},
+		{"1.5e18", NoSubDenomination, "1500000000000000000", ""},
+		{"2.3e5", NoSubDenomination, "230000", ""},
+		{"1e-2", NoSubDenomination, "1/100", ""},
+		{".5", NoSubDenomination, "1/2", ""},
+		{"0.5", Ether, "500000000000000000", ""},
+		{"1e0_0_3", NoSubDenomination, "1000", ""},
+		{"2", Weeks, "1209600", ""},
+		{"1", Years, "31536000", ""},
+		{"0123", NoSubDenomination, "", "leading zeros are not allowed, octal numbers are not supported"},
+		{"00.5", NoSubDenomination, "", "leading zeros are not allowed, octal numbers are not supported"},
+		{"1_", NoSubDenomination, "", "trailing underscore in number"},
+		{"1__0", NoSubDenomination, "", "misplaced underscore in number, the underscores can only separate two digits"},
+		{"1_.5", NoSubDenomination, "", "trailing underscore in number"},
+		{"1e_5", NoSubDenomination, "", "misplaced underscore in number, the underscores can only separate two digits"},
+		{"0x_1", NoSubDenomination, "", "misplaced underscore in number, the underscores can only separate two digits"},
+		{"0x", NoSubDenomination, "", "missing digits in number"},
+		{"0xfg", NoSubDenomination, "", "invalid digit 'g' in number"},
+		{"0x1", Ether, "", "hexadecimal numbers cannot have a sub-denomination, use an expression like '0x1 * 1 ether'"},
+		{"1e5000", NoSubDenomination, "", "the exponent of the number is out of range, the limit is 4096"},
+		{"1e-10000000000000000000", NoSubDenomination, "", "the exponent of the number is out of range, the limit is 4096"},
+	}
+	for _, c := range cases {
+		x, err := parseNumber(c.number, c.unit)
+		if c.err != "" {
+			if err == nil || err.msg != c.err {
+				t.Fatalf("bad error for '%s': %v", c.number, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for '%s': %v", c.number, err)
+		}
+		if x.RatString() != c.value {
+			t.Fatalf("bad value for '%s': %s", c.number, x.RatString())
+		}
+	}
+}
+
+func TestSubDenomination(t *testing.T) {
+	for d := Wei; d <= Years; d++ {
+		unit, ok := ParseSubDenomination(d.String())
+		if !ok || unit != d {
+			t.Fatalf("bad sub-denomination %s", d)
+		}
+	}
+	if _, ok := ParseSubDenomination("month"); ok {
+		t.Fatal("it should fail")
+	}
+	if Days.Multiplier().Int64() != 86400 || NoSubDenomination.Multiplier().Int64() != 1 {
+		t.Fatal("bad multiplier")
+	}
+
+	data, err := json.Marshal([]SubDenomination{NoSubDenomination, Gwei})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[null,"gwei"]` {
+		t.Fatalf("bad json %s", data)
+	}
+}
+
+func TestNumberLiteral(t *testing.T) {
+	cases := []struct {
+		literal string
+		value   string
+		err     string
+		column  int
+	}{
+		{"1.5 ether", "1500000000000000000", "", 0},
+		{"0x10", "16", "", 0},
+		{"0123", "", "leading zeros are not allowed, octal numbers are not supported", 22},
+		{"0x1 ether", "", "hexadecimal numbers cannot have a sub-denomination, use an expression like '0x1 * 1 ether'", 22},
+	}
+
+	for _, c := range cases {
+		src := "contract A { uint a = " + c.literal + "; }"
+		for _, backend := range []Backend{ANTLR, TreeSitter} {
+			// the tree-sitter grammar does not have the hex numbers with units
+			if backend == TreeSitter && c.literal == "0x1 ether" {
+				continue
+			}
+
+			p := Parse(src, WithBackend(backend))
+			lit := p.Result.(*SourceUnit).Children[0].(*ContractDefinition).SubNodes[0].(*StateVariableDeclaration).Variables[0].(*StateVariableDeclarationVariable).Expression.(*NumberLiteral)
+			if c.err == "" {
+				if len(p.Errors) != 0 {
+					t.Fatalf("unexpected errors for '%s' (%d): %v", c.literal, backend, p.Errors)
+				}
+				if lit.Value == nil || lit.Value.RatString() != c.value {
+					t.Fatalf("bad value for '%s' (%d): %v", c.literal, backend, lit.Value)
+				}
+				if n, ok := lit.Int(); !ok || n.String() != c.value {
+					t.Fatalf("bad int for '%s' (%d)", c.literal, backend)
+				}
+				continue
+			}
+			if lit.Value != nil {
+				t.Fatalf("unexpected value for '%s' (%d)", c.literal, backend)
+			}
+			if len(p.Errors) != 1 || p.Errors[0].Error() != c.err {
+				t.Fatalf("bad errors for '%s' (%d): %v", c.literal, backend, p.Errors)
+			}
+			if loc := p.Errors[0].Loc(); loc.Start.Column != c.column {
+				t.Fatalf("bad location for '%s' (%d): %v", c.literal, backend, loc)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package solcparser
+
+import (
+	"sort"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// Position is a point in the source code. Offset and Column are measured
+// in bytes, Line starts at 1 and Column starts at 0.
+type Position struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Location is the range of the source code covered by a node. End is exclusive.
+type Location struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Option is a configuration option for Parse
+type Option func(*config)
+
+type config struct {
+	locations bool
+	cst       bool
+	backend   Backend
+}
+
+// Backend is the parser that builds the AST
+type Backend int
+
+const (
+	// ANTLR parses the source with the antlr grammar, it is the default
+	ANTLR Backend = iota
+
+	// TreeSitter parses the source with the tree-sitter grammar
+	TreeSitter
+)
+
+// WithBackend selects the parser that builds the AST
+func WithBackend(b Backend) Option {
+	return func(c *config) {
+		c.backend = b
+	}
+}
+
+// WithLocations populates the location of the nodes of the AST
+func WithLocations() Option {
+	return func(c *config) {
+		c.locations = true
+	}
+}
+
+// sourceMap converts the rune indexes used by antlr into byte positions
+type sourceMap struct {
+	// runeOffsets is the byte offset of each rune (plus the end of the input)
+	runeOffsets []int
+
+	// lineStarts is the byte offset at which each line starts
+	lineStarts []int
+}
+
+func newSourceMap(src string) *sourceMap {
+	m := &sourceMap{
+		runeOffsets: make([]int, 0, len(src)+1),
+		lineStarts:  []int{0},
+	}
+	for offset, r := range src {
+		m.runeOffsets = append(m.runeOffsets, offset)
+		if r == '\n' {
+			m.lineStarts = append(m.lineStarts, offset+1)
+		}
+	}
+	m.runeOffsets = append(m.runeOffsets, len(src))
+	return m
+}
+
+// position returns the position of the byte offset
+func (m *sourceMap) position(offset int) Position {
+	line := sort.Search(len(m.lineStarts), func(i int) bool {
+		return m.lineStarts[i] > offset
+	}) - 1
+	return Position{
+		Offset: offset,
+		Line:   line + 1,
+		Column: offset - m.lineStarts[line],
+	}
+}
+
+// runePosition returns the position of the rune index
+func (m *sourceMap) runePosition(indx int) Position {
+	if indx < 0 {
+		indx = 0
+	}
+	if indx >= len(m.runeOffsets) {
+		indx = len(m.runeOffsets) - 1
+	}
+	return m.position(m.runeOffsets[indx])
+}
+
+// linePosition returns the position of the rune column of a line (starting at 1)
+func (m *sourceMap) linePosition(line, column int) Position {
+	if line < 1 {
+		line = 1
+	}
+	if line > len(m.lineStarts) {
+		line = len(m.lineStarts)
+	}
+	indx := sort.SearchInts(m.runeOffsets, m.lineStarts[line-1])
+	return m.runePosition(indx + column)
+}
+
+// tokenLoc returns the location covered by the tokens from start to stop (inclusive)
+func (m *sourceMap) tokenLoc(start, stop antlr.Token) *Location {
+	if start == nil {
+		return nil
+	}
+	loc := &Location{
+		Start: m.runePosition(start.GetStart()),
+	}
+	if stop == nil || stop.GetStop() < start.GetStart() {
+		loc.End = loc.Start
+	} else {
+		loc.End = m.runePosition(stop.GetStop() + 1)
+	}
+	return loc
+}
+
+// ruleLoc returns the location covered by a parser rule
+func (m *sourceMap) ruleLoc(ctx antlr.ParserRuleContext) *Location {
+	return m.tokenLoc(ctx.GetStart(), ctx.GetStop())
+}
+
+// locate sets the location of a node built from the given rule if locations are enabled
+func (e *exampleListener) locate(node INode, ctx antlr.Tree) {
+	if e.src == nil {
+		return
+	}
+	if rule, ok := ctx.(antlr.ParserRuleContext); ok {
+		node.SetLoc(e.src.ruleLoc(rule))
+	}
+}
@@ -0,0 +1,79 @@
+package solcparser
+
+import (
+	"testing"
+)
+
+func TestParserLocations(t *testing.T) {
+	code := "contract A {\n  uint a;\n  function foo() public { /* ü */ a = 1; }\n}"
+
+	p := Parse(code, WithLocations())
+	if len(p.Errors) != 0 {
+		t.Fatal(p.Errors)
+	}
+	contract := p.Result.(*SourceUnit).Children[0].(*ContractDefinition)
+
+	text := func(node INode) string {
+		loc := node.GetLoc()
+		if loc == nil {
+			t.Fatalf("location not found for %s", node.GetType())
+		}
+		return code[loc.Start.Offset:loc.End.Offset]
+	}
+
+	if text(contract) != code {
+		t.Fatal("bad contract location")
+	}
+
+	variable := contract.SubNodes[0].(*StateVariableDeclaration).Variables[0].(*StateVariableDeclarationVariable)
+	if str := text(variable); str != "uint a;" {
+		t.Fatalf("bad variable location '%s'", str)
+	}
+	if loc := variable.GetLoc(); loc.Start.Line != 2 || loc.Start.Column != 2 {
+		t.Fatalf("bad variable position %v", loc.Start)
+	}
+
+	// the offsets are in bytes even with multi-byte characters in the source
+	fn := contract.SubNodes[1].(*FunctionDefinition)
+	assign := fn.Body.(*Block).Statements[0].(*ExpressionStatement).Expression.(*BinaryOperation)
+	if str := text(assign); str != "a = 1" {
+		t.Fatalf("bad expression location '%s'", str)
+	}
+	if loc := assign.GetLoc(); loc.Start.Line != 3 || loc.End.Column != 40 {
+		t.Fatalf("bad expression position %v", loc)
+	}
+
+	// locations are not tracked by default
+	p = Parse(code)
+	if p.Result.(*SourceUnit).Loc != nil {
+		t.Fatal("locations not expected")
+	}
+}
+
+func TestSyntaxErrorLocation(t *testing.T) {
+	cases := []struct {
+		src  string
+		text string
+		line int
+	}{
+		// the unexpected token
+		{"contract A {\n  function f() { uint x = ; }\n}", ";", 2},
+		// the token before a missing one
+		{"contract A {\n  uint x\n}", "}", 3},
+		// the lexer errors have no token
+		{"contract A { uint x = 1 # 2; }", "", 1},
+	}
+	for _, c := range cases {
+		p := Parse(c.src)
+		if len(p.Errors) == 0 {
+			t.Fatalf("%q should fail", c.src)
+		}
+		loc := p.Errors[0].Loc()
+		if loc == nil {
+			t.Fatalf("%q: no location", c.src)
+		}
+		if text := c.src[loc.Start.Offset:loc.End.Offset]; text != c.text || loc.Start.Line != c.line {
+			t.Fatalf("%q: bad location %v '%s'", c.src, loc, text)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package lsp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/types"
+)
+
+// snapshot is the analysis of the files of the workspace at some point
+type snapshot struct {
+	// files are the files by name
+	files   map[string]*file
+	project *solcparser.Project
+	info    *types.Info
+
+	// owner is the file of each node
+	owner map[interface{}]*file
+}
+
+func newSnapshot(files []*file) *snapshot {
+	s := &snapshot{
+		files:   map[string]*file{},
+		project: solcparser.NewProject(),
+		owner:   map[interface{}]*file{},
+	}
+
+	names := []string{}
+	for _, f := range files {
+		s.files[f.name] = f
+		names = append(names, f.name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := s.files[name]
+		if f.unit == nil {
+			continue
+		}
+		s.project.Add(name, f.unit)
+		solcparser.Inspect(f.unit, func(node interface{}) bool {
+			s.owner[node] = f
+			return true
+		})
+	}
+	s.info = check(s.project)
+	return s
+}
+
+// check type checks the project. The ASTs of the files with syntax errors can
+// miss nodes the checker expects, a failure leaves the project without types.
+func check(p *solcparser.Project) (info *types.Info) {
+	defer func() {
+		if err := recover(); err != nil {
+			info = &types.Info{
+				Types: map[solcparser.INode]types.Type{},
+				Refs:  map[solcparser.INode]interface{}{},
+			}
+		}
+	}()
+	return types.Check(p)
+}
+
+// path returns the nodes of the file that contain the offset, from the outer
+// to the inner one
+func (s *snapshot) path(f *file, offset int) []interface{} {
+	res := []interface{}{}
+	if f.unit == nil {
+		return res
+	}
+	solcparser.Inspect(f.unit, func(node interface{}) bool {
+		n, ok := node.(solcparser.INode)
+		if !ok || n.GetLoc() == nil {
+			return true
+		}
+		loc := n.GetLoc()
+		if offset < loc.Start.Offset || offset > loc.End.Offset {
+			return false
+		}
+		res = append(res, node)
+		return true
+	})
+	return res
+}
+
+// span is a range of bytes of a file
+type span struct {
+	file  *file
+	start int
+	end   int
+}
+
+func (s span) contains(offset int) bool {
+	return s.start <= offset && offset <= s.end
+}
+
+func (s span) location() Location {
+	return Location{URI: s.file.uri, Range: s.file.rangeOf(s.start, s.end)}
+}
+
+// target returns the declaration referenced or declared at the offset of the
+// file and the span of the name at the offset
+func (s *snapshot) target(f *file, offset int) (interface{}, span, bool) {
+	path := s.path(f, offset)
+	for i := len(path) - 1; i >= 0; i-- {
+		node := path[i]
+		name, ok := nameSpan(f, node)
+		if !ok || !name.contains(offset) {
+			continue
+		}
+		if isDecl(node) {
+			return node, name, true
+		}
+		if decl := s.info.RefOf(node); decl != nil {
+			return decl, name, true
+		}
+		// the name of a parameter or a local variable
+		if i > 0 && isDecl(path[i-1]) {
+			if v, ok := path[i-1].(*solcparser.VariableDeclaration); ok && v.Identifier == node {
+				return v, name, true
+			}
+			if v, ok := path[i-1].(*solcparser.StateVariableDeclarationVariable); ok && v.Identifier == node {
+				return v, name, true
+			}
+		}
+		return nil, name, false
+	}
+	return nil, span{}, false
+}
+
+// references returns the spans of the names that reference the declaration
+// and the span of the name of the declaration
+func (s *snapshot) references(decl interface{}) []span {
+	res := []span{}
+	seen := map[span]bool{}
+	add := func(sp span) {
+		if !seen[sp] {
+			seen[sp] = true
+			res = append(res, sp)
+		}
+	}
+
+	if f := s.owner[decl]; f != nil {
+		if name, ok := nameSpan(f, decl); ok {
+			add(name)
+		}
+	}
+	for _, name := range s.project.Files() {
+		f := s.files[name]
+		solcparser.Inspect(f.unit, func(node interface{}) bool {
+			if s.info.RefOf(node) == decl {
+				if name, ok := nameSpan(f, node); ok {
+					add(name)
+				}
+			}
+			return true
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].file.name != res[j].file.name {
+			return res[i].file.name < res[j].file.name
+		}
+		return res[i].start < res[j].start
+	})
+	return res
+}
+
+// definition returns the span of the name of a declaration
+func (s *snapshot) definition(decl interface{}) (span, bool) {
+	f := s.owner[decl]
+	if f == nil {
+		return span{}, false
+	}
+	return nameSpan(f, decl)
+}
+
+// keyword is the keyword at the start of a declaration
+var keyword = regexp.MustCompile(`^\w+`)
+
+// nameSpan returns the span of the name of a declaration or of a reference
+// to a declaration (i.e. the member name of a member access)
+func nameSpan(f *file, node interface{}) (span, bool) {
+	n, ok := node.(solcparser.INode)
+	if !ok || n.GetLoc() == nil || n.GetLoc().End.Offset > len(f.text) {
+		return span{}, false
+	}
+	start, end := n.GetLoc().Start.Offset, n.GetLoc().End.Offset
+
+	switch obj := node.(type) {
+	case *solcparser.Identifier, *solcparser.EnumValue:
+		return span{f, start, end}, true
+
+	case *solcparser.MemberAccess:
+		return span{f, end - len(obj.MemberName), end}, true
+
+	case *solcparser.UserDefinedTypeName:
+		// the last element of the path
+		name := obj.NamePath[strings.LastIndex(obj.NamePath, ".")+1:]
+		return span{f, end - len(name), end}, true
+
+	case *solcparser.ModifierInvocation:
+		return span{f, start, start + len(obj.Name)}, true
+
+	case *solcparser.VariableDeclaration:
+		return nameSpan(f, obj.Identifier)
+
+	case *solcparser.StateVariableDeclarationVariable:
+		return nameSpan(f, obj.Identifier)
+
+	case *solcparser.FunctionDefinition:
+		if obj.Name == "" {
+			// constructor, fallback or receive
+			word := keyword.FindString(f.text[start:end])
+			return span{f, start, start + len(word)}, word != ""
+		}
+	}
+
+	name := declName(node)
+	if name == "" {
+		return span{}, false
+	}
+	// the first occurrence of the name after the keyword of the declaration
+	loc := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`).FindStringIndex(f.text[start:end])
+	if loc == nil {
+		return span{}, false
+	}
+	return span{f, start + loc[0], start + loc[1]}, true
+}
+
+// isDecl returns true if the node is a declaration with a name
+func isDecl(node interface{}) bool {
+	switch node.(type) {
+	case *solcparser.ContractDefinition, *solcparser.FunctionDefinition, *solcparser.ModifierDefinition,
+		*solcparser.EventDefinition, *solcparser.CustomErrorDefinition, *solcparser.StructDefinition,
+		*solcparser.EnumDefinition, *solcparser.EnumValue, *solcparser.TypeDefinition,
+		*solcparser.StateVariableDeclarationVariable, *solcparser.VariableDeclaration, *solcparser.FileLevelConstant:
+		return true
+	}
+	return false
+}
+
+// declName returns the name of a declaration
+func declName(node interface{}) string {
+	switch obj := node.(type) {
+	case *solcparser.ContractDefinition:
+		return obj.Name
+	case *solcparser.FunctionDefinition:
+		return obj.Name
+	case *solcparser.ModifierDefinition:
+		return obj.Name
+	case *solcparser.EventDefinition:
+		return obj.Name
+	case *solcparser.CustomErrorDefinition:
+		return obj.Name
+	case *solcparser.StructDefinition:
+		return obj.Name
+	case *solcparser.EnumDefinition:
+		return obj.Name
+	case *solcparser.EnumValue:
+		return obj.Name
+	case *solcparser.TypeDefinition:
+		return obj.Name
+	case *solcparser.StateVariableDeclarationVariable:
+		return obj.Name
+	case *solcparser.VariableDeclaration:
+		return obj.Name
+	case *solcparser.FileLevelConstant:
+		return obj.Name
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// file is a Solidity file of the workspace, either open in the editor or
+// loaded from disk because an open file imports it
+type file struct {
+	uri  string
+	name string
+
+	// open is true if the file is open in the editor and version is its version
+	open    bool
+	version int
+
+	text string
+
+	// lines is the byte offset at which each line starts
+	lines []int
+
+	// unit is the AST of the file, nil if the syntax errors prevent building it
+	unit   *solcparser.SourceUnit
+	errors []*solcparser.SyntaxError
+}
+
+func newFile(uri, text string) *file {
+	f := &file{uri: uri, name: uriToPath(uri)}
+	f.setText(text)
+	return f
+}
+
+// setText replaces the text of the file and parses it again
+func (f *file) setText(text string) {
+	f.text = text
+	f.lines = lineStarts(text)
+
+	res := solcparser.Parse(text, solcparser.WithLocations())
+	f.unit, _ = res.Result.(*solcparser.SourceUnit)
+	f.errors = res.Errors
+}
+
+// applyChanges replaces the text of the file with the text of the last change.
+// The server syncs the whole text of the files, each change has all the text
+// since the file is parsed again on each change anyway.
+func (f *file) applyChanges(changes []TextDocumentContentChangeEvent) {
+	if len(changes) == 0 {
+		return
+	}
+	f.setText(changes[len(changes)-1].Text)
+}
+
+// lineStarts returns the byte offset at which each line starts
+func lineStarts(text string) []int {
+	lines := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// position converts a byte offset to a position
+func (f *file) position(offset int) Position {
+	if offset > len(f.text) {
+		offset = len(f.text)
+	}
+	line := sort.Search(len(f.lines), func(i int) bool {
+		return f.lines[i] > offset
+	}) - 1
+	return Position{Line: line, Character: utf16Len(f.text[f.lines[line]:offset])}
+}
+
+// offset converts a position to a byte offset. The positions after the end of
+// a line are moved to the end of the line.
+func (f *file) offset(pos Position) int {
+	if pos.Line < 0 {
+		return 0
+	}
+	if pos.Line >= len(f.lines) {
+		return len(f.text)
+	}
+	start, end := f.lines[pos.Line], len(f.text)
+	if pos.Line+1 < len(f.lines) {
+		end = f.lines[pos.Line+1] - 1
+	}
+	offset, units := start, 0
+	for offset < end && units < pos.Character {
+		r, size := utf8.DecodeRuneInString(f.text[offset:])
+		units += utf16.RuneLen(r)
+		if units > pos.Character {
+			break
+		}
+		offset += size
+	}
+	return offset
+}
+
+// rangeOf returns the range between two byte offsets
+func (f *file) rangeOf(start, end int) Range {
+	return Range{Start: f.position(start), End: f.position(end)}
+}
+
+// locRange returns the range of a location of the AST
+func (f *file) locRange(loc *solcparser.Location) Range {
+	return f.rangeOf(loc.Start.Offset, loc.End.Offset)
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// uriToPath returns the file path of a file URI or the URI itself
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		// /c:/file on windows
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+// pathToURI returns the file URI of a path
+func pathToURI(path string) string {
+	if strings.Contains(path, "://") {
+		return path
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,59 @@
+package lsp
+
+import (
+	"sort"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// foldingRanges returns the ranges of the brackets and the comments that span
+// more than one line. The ranges of the brackets end in the line before the
+// closing bracket so that it remains visible.
+func foldingRanges(f *file) []FoldingRange {
+	res := []FoldingRange{}
+	line := func(offset int) int {
+		return f.position(offset).Line
+	}
+
+	tokens := scan(f.text)
+	stack := []token{}
+	for i, t := range tokens {
+		switch {
+		case t.isOpen():
+			stack = append(stack, t)
+
+		case t.isClose():
+			if len(stack) == 0 {
+				continue
+			}
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if start, end := line(open.start), line(t.start)-1; end > start {
+				res = append(res, FoldingRange{StartLine: start, EndLine: end})
+			}
+
+		case t.kind == solcparser.TokenComment:
+			if start, end := line(t.start), line(t.end-1); end > start {
+				res = append(res, FoldingRange{StartLine: start, EndLine: end, Kind: "comment"})
+			}
+
+		case t.kind == solcparser.TokenLineComment:
+			// the first of the line comments of consecutive lines
+			if i > 0 && tokens[i-1].kind == solcparser.TokenLineComment && line(tokens[i-1].start) == line(t.start)-1 {
+				continue
+			}
+			end := i
+			for end+1 < len(tokens) && tokens[end+1].kind == solcparser.TokenLineComment && line(tokens[end+1].start) == line(tokens[end].start)+1 {
+				end++
+			}
+			if end > i {
+				res = append(res, FoldingRange{StartLine: line(t.start), EndLine: line(tokens[end].start), Kind: "comment"})
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].StartLine < res[j].StartLine
+	})
+	return res
+}
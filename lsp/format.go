@@ -0,0 +1,137 @@
+package lsp
+
+import (
+	"strings"
+)
+
+// format returns the edits that indent the lines of the file by the depth of
+// their brackets, remove the trailing whitespace and end the file with a
+// newline. The lines inside multi-line comments and strings are kept as is.
+func format(f *file, options FormattingOptions) []TextEdit {
+	indent := "\t"
+	if options.InsertSpaces {
+		size := options.TabSize
+		if size <= 0 {
+			size = 4
+		}
+		indent = strings.Repeat(" ", size)
+	}
+
+	lines := strings.Split(f.text, "\n")
+	formatted := reindent(f.text, indent)
+
+	edits := []TextEdit{}
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if formatted[i] == line {
+			continue
+		}
+		edits = append(edits, TextEdit{
+			Range: Range{
+				Start: Position{Line: i},
+				End:   Position{Line: i, Character: utf16Len(line)},
+			},
+			NewText: formatted[i],
+		})
+	}
+	if f.text != "" && !strings.HasSuffix(f.text, "\n") {
+		end := f.position(len(f.text))
+		edits = append(edits, TextEdit{Range: Range{Start: end, End: end}, NewText: "\n"})
+	}
+	return edits
+}
+
+// reindent returns the formatted lines of the text (without the line breaks)
+func reindent(text string, indent string) []string {
+	lines := strings.Split(text, "\n")
+	starts := lineStarts(text)
+	tokens := scan(text)
+
+	// open is a bracket that is not closed yet
+	type open struct {
+		line     int
+		curly    bool
+		assembly bool
+	}
+	stack := []open{}
+
+	// last is the last token that is not a comment and assembly is true
+	// between the assembly keyword and its block
+	var last *token
+	assembly := false
+
+	res := make([]string, len(lines))
+	indx := 0
+	for i := range lines {
+		line := strings.TrimRight(lines[i], " \t\r")
+		end := len(text)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+
+		if indx > 0 && tokens[indx-1].end > starts[i] {
+			// the line starts inside a comment or a string spanning lines
+			res[i] = line
+			if !tokens[indx-1].isComment() {
+				res[i] = strings.TrimSuffix(lines[i], "\r")
+			}
+		} else if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" {
+			// the closing brackets at the start of the line are not indented
+			closing := 0
+			for j := indx; j < len(tokens) && tokens[j].start < end && tokens[j].isClose(); j++ {
+				closing++
+			}
+			if closing > len(stack) {
+				closing = len(stack)
+			}
+			outer := stack[:len(stack)-closing]
+
+			// the brackets opened in the same line add one level
+			depth := 0
+			for k := range outer {
+				if k == 0 || outer[k].line != outer[k-1].line {
+					depth++
+				}
+			}
+
+			// the statements that continue in the next line are indented once
+			if last != nil && closing == 0 && indx < len(tokens) && !tokens[indx].isComment() && tokens[indx].text != "{" {
+				inStatement := len(outer) == 0 || outer[len(outer)-1].curly && !outer[len(outer)-1].assembly
+				switch last.text {
+				case ";", "{", "}", ",":
+				default:
+					if inStatement {
+						depth++
+					}
+				}
+			}
+			res[i] = strings.Repeat(indent, depth) + trimmed
+		}
+
+		for ; indx < len(tokens) && tokens[indx].start < end; indx++ {
+			t := &tokens[indx]
+			if t.isComment() {
+				continue
+			}
+			switch {
+			case t.isOpen():
+				top := open{line: i, curly: t.text == "{"}
+				if top.curly {
+					top.assembly = assembly || len(stack) != 0 && stack[len(stack)-1].assembly
+					assembly = false
+				}
+				stack = append(stack, top)
+			case t.isClose():
+				if len(stack) != 0 {
+					stack = stack[:len(stack)-1]
+				}
+			case t.text == "assembly":
+				assembly = true
+			case t.text == ";":
+				assembly = false
+			}
+			last = t
+		}
+	}
+	return res
+}
@@ -0,0 +1,175 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// hover returns the signature and the NatSpec documentation of the
+// declaration at the offset, or the type of the expression at the offset
+func (s *snapshot) hover(f *file, offset int) *Hover {
+	decl, name, ok := s.target(f, offset)
+	if ok {
+		df := s.owner[decl]
+		if df == nil {
+			return nil
+		}
+		value := "```solidity\n" + declSignature(df, decl) + "\n```"
+		if doc := natspec(df, decl); doc != "" {
+			value += "\n\n" + doc
+		}
+		rng := name.location().Range
+		return &Hover{
+			Contents: MarkupContent{Kind: "markdown", Value: value},
+			Range:    &rng,
+		}
+	}
+
+	// the innermost expression with a type (i.e. msg.sender)
+	path := s.path(f, offset)
+	for i := len(path) - 1; i >= 0; i-- {
+		typ := s.info.TypeOf(path[i])
+		if typ == nil {
+			continue
+		}
+		text := nodeText(f, path[i])
+		if strings.Contains(text, "\n") {
+			return nil
+		}
+		rng := f.locRange(path[i].(solcparser.INode).GetLoc())
+		return &Hover{
+			Contents: MarkupContent{Kind: "markdown", Value: fmt.Sprintf("```solidity\n%s: %s\n```", text, typ)},
+			Range:    &rng,
+		}
+	}
+	return nil
+}
+
+// declSignature returns the text of the declaration shown in the hover
+func declSignature(f *file, decl interface{}) string {
+	switch obj := decl.(type) {
+	case *solcparser.VariableDeclaration:
+		text := nodeText(f, obj)
+		if i := strings.Index(text, "="); i != -1 {
+			text = text[:i]
+		}
+		return strings.TrimSpace(spaces.ReplaceAllString(text, " "))
+
+	case *solcparser.StateVariableDeclarationVariable:
+		return strings.TrimSpace(strings.SplitN(signature(f, obj), "=", 2)[0])
+
+	case *solcparser.EnumValue:
+		return obj.Name
+	}
+	return signature(f, decl)
+}
+
+// natspec returns the documentation comment before a declaration formatted
+// as markdown
+func natspec(f *file, decl interface{}) string {
+	n, ok := decl.(solcparser.INode)
+	if !ok || n.GetLoc() == nil {
+		return ""
+	}
+	lines := docComment(f.text, n.GetLoc().Start.Offset)
+	if len(lines) == 0 {
+		return ""
+	}
+
+	// the text of each tag, the untagged text is a notice
+	type tag struct {
+		name string
+		text string
+	}
+	tags := []*tag{}
+	for _, line := range lines {
+		if strings.HasPrefix(line, "@") {
+			parts := strings.SplitN(line, " ", 2)
+			t := &tag{name: parts[0][1:]}
+			if len(parts) == 2 {
+				t.text = strings.TrimSpace(parts[1])
+			}
+			tags = append(tags, t)
+			continue
+		}
+		if len(tags) == 0 {
+			tags = append(tags, &tag{name: "notice"})
+		}
+		last := tags[len(tags)-1]
+		if line == "" {
+			last.text += "\n"
+		} else if last.text == "" || strings.HasSuffix(last.text, "\n") {
+			last.text += line
+		} else {
+			last.text += " " + line
+		}
+	}
+
+	paragraphs := []string{}
+	for _, t := range tags {
+		text := strings.TrimSpace(t.text)
+		switch t.name {
+		case "notice", "dev":
+			paragraphs = append(paragraphs, text)
+		case "param":
+			// the first word is the name of the parameter
+			if parts := strings.SplitN(text, " ", 2); len(parts) == 2 {
+				text = "`" + parts[0] + "` " + parts[1]
+			}
+			paragraphs = append(paragraphs, "*@param* "+text)
+		default:
+			paragraphs = append(paragraphs, "*@"+t.name+"* "+text)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// docComment returns the lines of the NatSpec comment (/// or /** */) that
+// ends right before the offset, without the comment markers
+func docComment(text string, offset int) []string {
+	before := strings.TrimRight(text[:offset], " \t\r\n")
+
+	if strings.HasSuffix(before, "*/") {
+		start := strings.LastIndex(before, "/**")
+		if start == -1 || strings.LastIndex(before, "/*") != start {
+			// not a doc comment
+			return nil
+		}
+		body := before[start+3 : len(before)-2]
+		lines := []string{}
+		for _, line := range strings.Split(body, "\n") {
+			line = strings.TrimSpace(line)
+			line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+			lines = append(lines, line)
+		}
+		return trimEmpty(lines)
+	}
+
+	// the consecutive /// lines
+	lines := []string{}
+	for {
+		i := strings.LastIndex(before, "\n")
+		line := strings.TrimSpace(before[i+1:])
+		if !strings.HasPrefix(line, "///") {
+			break
+		}
+		lines = append([]string{strings.TrimSpace(strings.TrimPrefix(line, "///"))}, lines...)
+		if i == -1 {
+			break
+		}
+		before = strings.TrimRight(before[:i], " \t\r")
+	}
+	return trimEmpty(lines)
+}
+
+func trimEmpty(lines []string) []string {
+	for len(lines) != 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,67 @@
+package lsp
+
+import (
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// token is a token of the source, including the comments. Start and End are
+// byte offsets.
+type token struct {
+	kind  solcparser.TokenKind
+	text  string
+	start int
+	end   int
+}
+
+// scan returns the tokens of the source without the whitespace. The
+// characters the lexer does not recognize are skipped.
+func scan(text string) []token {
+	tokens := []token{}
+	for _, t := range solcparser.Tokenize(text) {
+		if t.Kind == solcparser.TokenWhitespace || t.Kind == solcparser.TokenIllegal {
+			continue
+		}
+		tokens = append(tokens, token{
+			kind:  t.Kind,
+			text:  t.Text,
+			start: t.Loc.Start.Offset,
+			end:   t.Loc.End.Offset,
+		})
+	}
+	return tokens
+}
+
+// isComment returns true if the token is a comment
+func (t token) isComment() bool {
+	return t.kind == solcparser.TokenComment || t.kind == solcparser.TokenLineComment
+}
+
+// isIdentifier returns true if the token is an identifier
+func (t token) isIdentifier() bool {
+	return t.kind == solcparser.TokenIdentifier
+}
+
+// isKeyword returns true if the token is a keyword (i.e. 'contract' or 'public'),
+// the booleans and the units of the numbers included
+func (t token) isKeyword() bool {
+	switch t.kind {
+	case solcparser.TokenKeyword, solcparser.TokenBoolean, solcparser.TokenNumberUnit:
+		return true
+	}
+	return false
+}
+
+// isType returns true if the token is an elementary type name
+func (t token) isType() bool {
+	return t.kind == solcparser.TokenElementaryType
+}
+
+// isOpen returns true if the token opens a bracket
+func (t token) isOpen() bool {
+	return t.kind == solcparser.TokenPunctuation && (t.text == "{" || t.text == "(" || t.text == "[")
+}
+
+// isClose returns true if the token closes a bracket
+func (t token) isClose() bool {
+	return t.kind == solcparser.TokenPunctuation && (t.text == "}" || t.text == ")" || t.text == "]")
+}
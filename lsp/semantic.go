@@ -0,0 +1,199 @@
+package lsp
+
+import (
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// tokenTypes is the legend of the types of the semantic tokens
+var tokenTypes = []string{
+	"namespace", "type", "class", "enum", "interface", "struct", "typeParameter",
+	"parameter", "variable", "property", "enumMember", "event", "function",
+	"method", "modifier", "keyword", "comment", "string", "number", "operator",
+}
+
+// tokenModifiers is the legend of the modifiers of the semantic tokens
+var tokenModifiers = []string{"declaration", "readonly", "defaultLibrary"}
+
+const (
+	modDeclaration = 1 << iota
+	modReadonly
+)
+
+// class is the type and the modifiers of a semantic token
+type class struct {
+	typ  string
+	mods int
+}
+
+// semanticTokens classifies the tokens of the file. The identifiers are
+// classified by the declaration they declare or reference and the rest of
+// the tokens by their kind.
+func (s *snapshot) semanticTokens(f *file) *SemanticTokens {
+	names := s.classifyNames(f)
+
+	data := []int{}
+	prevLine, prevChar := 0, 0
+	for _, t := range scan(f.text) {
+		c, ok := names[t.start]
+		if !ok || !t.isIdentifier() {
+			if c, ok = classifyToken(t); !ok {
+				continue
+			}
+		}
+		typ := indexOf(tokenTypes, c.typ)
+
+		// the tokens that span lines are split by line
+		start := t.start
+		for start < t.end {
+			end := t.end
+			if i := strings.IndexByte(f.text[start:t.end], '\n'); i != -1 {
+				end = start + i
+			}
+			if length := utf16Len(strings.TrimSuffix(f.text[start:end], "\r")); length != 0 {
+				pos := f.position(start)
+				if pos.Line != prevLine {
+					prevChar = 0
+				}
+				data = append(data, pos.Line-prevLine, pos.Character-prevChar, length, typ, c.mods)
+				prevLine, prevChar = pos.Line, pos.Character
+			}
+			start = end + 1
+		}
+	}
+	return &SemanticTokens{Data: data}
+}
+
+// classifyToken returns the class of a token by its kind
+func classifyToken(t token) (class, bool) {
+	switch {
+	case t.isComment():
+		return class{typ: "comment"}, true
+	case t.isType():
+		return class{typ: "type"}, true
+	case t.isKeyword():
+		return class{typ: "keyword"}, true
+	}
+	switch t.kind {
+	case solcparser.TokenString, solcparser.TokenHexString:
+		return class{typ: "string"}, true
+	case solcparser.TokenNumber:
+		return class{typ: "number"}, true
+	}
+	return class{}, false
+}
+
+// classifyNames returns the classes of the names of the declarations and of
+// the references of the file by offset
+func (s *snapshot) classifyNames(f *file) map[int]class {
+	res := map[int]class{}
+	if f.unit == nil {
+		return res
+	}
+
+	// the parameters and the struct members of all the files are not
+	// classified as variables
+	kinds := map[interface{}]string{}
+	for _, name := range s.project.Files() {
+		solcparser.Inspect(s.project.Units[name], func(node interface{}) bool {
+			var params []interface{}
+			switch obj := node.(type) {
+			case *solcparser.FunctionDefinition:
+				params = []interface{}{obj.Parameters, obj.ReturnParameters}
+			case *solcparser.ModifierDefinition:
+				params = []interface{}{obj.Parameters}
+			case *solcparser.EventDefinition:
+				params = []interface{}{obj.Parameters}
+			case *solcparser.CustomErrorDefinition:
+				params = []interface{}{obj.Parameters}
+			case *solcparser.StructDefinition:
+				for _, member := range obj.Members {
+					kinds[member] = "property"
+				}
+			}
+			solcparser.Inspect(params, func(param interface{}) bool {
+				if _, ok := param.(*solcparser.VariableDeclaration); ok {
+					kinds[param] = "parameter"
+				}
+				return true
+			})
+			return true
+		})
+	}
+
+	solcparser.Inspect(f.unit, func(node interface{}) bool {
+		decl, mods := node, modDeclaration
+		if !isDecl(node) {
+			decl, mods = s.info.RefOf(node), 0
+		}
+		if decl == nil {
+			return true
+		}
+		c, ok := classifyDecl(decl, kinds)
+		if !ok {
+			return true
+		}
+		if name, ok := nameSpan(f, node); ok {
+			c.mods |= mods
+			res[name.start] = c
+		}
+		return true
+	})
+	return res
+}
+
+// classifyDecl returns the class of the names of a declaration
+func classifyDecl(decl interface{}, kinds map[interface{}]string) (class, bool) {
+	switch obj := decl.(type) {
+	case *solcparser.ContractDefinition:
+		switch obj.Kind {
+		case "interface":
+			return class{typ: "interface"}, true
+		case "library":
+			return class{typ: "namespace"}, true
+		}
+		return class{typ: "class"}, true
+	case *solcparser.FunctionDefinition:
+		if obj.Name == "" {
+			// the keyword of the constructor, fallback or receive
+			return class{}, false
+		}
+		return class{typ: "function"}, true
+	case *solcparser.ModifierDefinition:
+		return class{typ: "modifier"}, true
+	case *solcparser.EventDefinition, *solcparser.CustomErrorDefinition:
+		return class{typ: "event"}, true
+	case *solcparser.StructDefinition:
+		return class{typ: "struct"}, true
+	case *solcparser.EnumDefinition:
+		return class{typ: "enum"}, true
+	case *solcparser.EnumValue:
+		return class{typ: "enumMember", mods: modReadonly}, true
+	case *solcparser.TypeDefinition:
+		return class{typ: "type"}, true
+	case *solcparser.StateVariableDeclarationVariable:
+		c := class{typ: "property"}
+		if obj.IsDeclaredConst || obj.IsInmutable {
+			c.mods = modReadonly
+		}
+		return c, true
+	case *solcparser.FileLevelConstant:
+		return class{typ: "variable", mods: modReadonly}, true
+	case *solcparser.VariableDeclaration:
+		if kind, ok := kinds[obj]; ok {
+			return class{typ: kind}, true
+		}
+		return class{typ: "variable"}, true
+	}
+	return class{}, false
+}
+
+func indexOf(list []string, str string) int {
+	for i := range list {
+		if list[i] == str {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,642 @@
+// Package lsp is a language server for Solidity. It implements the Language
+// Server Protocol over a stream (i.e. stdin and stdout) with the parser, the
+// type checker and the lint rules of the module.
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/lint"
+)
+
+// configFiles are the lint configs looked up in the root of the workspace
+var configFiles = []string{".sollint.yaml", ".sollint.yml", ".sollint.json"}
+
+// Server is the language server. It handles the messages one at a time.
+type Server struct {
+	conn *conn
+
+	// root is the directory of the workspace
+	root   string
+	config *lint.Config
+
+	// files are the files of the workspace by URI, the open ones and the
+	// ones they import
+	files map[string]*file
+
+	// snap is the analysis of the files, nil if a file changed since
+	snap *snapshot
+
+	// published are the last diagnostics sent for each URI
+	published map[string][]Diagnostic
+
+	shutdown bool
+}
+
+// NewServer creates a server that reads the messages from in and writes the
+// responses to out
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		conn:      newConn(in, out),
+		config:    lint.DefaultConfig(),
+		files:     map[string]*file{},
+		published: map[string][]Diagnostic{},
+	}
+}
+
+// Run handles the messages until the exit notification or the end of the
+// input
+func (s *Server) Run() error {
+	for {
+		msg, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if rerr, ok := err.(*rpcError); ok {
+			if err := s.conn.reply(nil, nil, rerr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit before shutdown")
+			}
+			return nil
+		}
+		if msg.Method == "" {
+			// a response, the server sends no requests
+			continue
+		}
+
+		result, err := s.handle(msg)
+		if msg.ID == nil {
+			// the notifications have no response
+			continue
+		}
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) (interface{}, error) {
+	if s.shutdown && msg.ID != nil {
+		return nil, errorf(codeInvalidRequest, "server is shut down")
+	}
+
+	switch msg.Method {
+	case "initialize":
+		var params InitializeParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(&params), nil
+
+	case "initialized":
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didOpen(&params)
+
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didChange(&params)
+
+	case "textDocument/didSave":
+		var params DidSaveTextDocumentParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didSave(&params)
+
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.didClose(&params)
+
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return documentSymbols(f), nil
+
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return s.snapshot().hover(f, f.offset(params.Position)), nil
+
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return s.definition(f, f.offset(params.Position)), nil
+
+	case "textDocument/references":
+		var params ReferenceParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return s.references(f, f.offset(params.Position), params.Context.IncludeDeclaration), nil
+
+	case "textDocument/prepareRename":
+		var params TextDocumentPositionParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return s.prepareRename(f, f.offset(params.Position))
+
+	case "textDocument/rename":
+		var params RenameParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return s.rename(f, f.offset(params.Position), params.NewName)
+
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return format(f, params.Options), nil
+
+	case "textDocument/foldingRange":
+		var params FoldingRangeParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return foldingRanges(f), nil
+
+	case "textDocument/semanticTokens/full":
+		var params SemanticTokensParams
+		if err := decode(msg, &params); err != nil {
+			return nil, err
+		}
+		f, err := s.file(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return s.snapshot().semanticTokens(f), nil
+	}
+
+	if strings.HasPrefix(msg.Method, "$/") {
+		// the optional notifications and requests (i.e. $/cancelRequest)
+		return nil, nil
+	}
+	return nil, errorf(codeMethodNotFound, "method '%s' not found", msg.Method)
+}
+
+func decode(msg *message, params interface{}) error {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return errorf(codeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+func (s *Server) initialize(params *InitializeParams) *InitializeResult {
+	root := params.RootURI
+	if root == "" && len(params.WorkspaceFolders) != 0 {
+		root = params.WorkspaceFolders[0].URI
+	}
+	if root != "" {
+		s.root = uriToPath(root)
+	}
+	s.config = loadConfig(s.root)
+
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    SyncFull,
+				Save:      SaveOptions{IncludeText: true},
+			},
+			DocumentSymbolProvider:     true,
+			HoverProvider:              true,
+			DefinitionProvider:         true,
+			ReferencesProvider:         true,
+			RenameProvider:             RenameOptions{PrepareProvider: true},
+			DocumentFormattingProvider: true,
+			FoldingRangeProvider:       true,
+			SemanticTokensProvider: SemanticTokensOptions{
+				Legend: SemanticTokensLegend{
+					TokenTypes:     tokenTypes,
+					TokenModifiers: tokenModifiers,
+				},
+				Full: true,
+			},
+		},
+		ServerInfo: ServerInfo{Name: "solls"},
+	}
+}
+
+// loadConfig loads the lint config of the root directory or enables all the
+// rules if there is none
+func loadConfig(root string) *lint.Config {
+	if root == "" {
+		return lint.DefaultConfig()
+	}
+	for _, name := range configFiles {
+		path := filepath.Join(root, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if config, err := lint.LoadConfig(path); err == nil {
+			return config
+		}
+	}
+	return lint.DefaultConfig()
+}
+
+// file returns the open file of the URI
+func (s *Server) file(uri string) (*file, error) {
+	f, ok := s.files[uri]
+	if !ok || !f.open {
+		return nil, errorf(codeInvalidParams, "document '%s' is not open", uri)
+	}
+	return f, nil
+}
+
+func (s *Server) didOpen(params *DidOpenTextDocumentParams) error {
+	doc := params.TextDocument
+	f, ok := s.files[doc.URI]
+	if ok {
+		f.setText(doc.Text)
+	} else {
+		f = newFile(doc.URI, doc.Text)
+		s.files[doc.URI] = f
+	}
+	f.open = true
+	f.version = doc.Version
+	return s.update()
+}
+
+func (s *Server) didChange(params *DidChangeTextDocumentParams) error {
+	f, err := s.file(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	f.applyChanges(params.ContentChanges)
+	f.version = params.TextDocument.Version
+	return s.update()
+}
+
+func (s *Server) didSave(params *DidSaveTextDocumentParams) error {
+	f, err := s.file(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	if params.Text != nil && *params.Text != f.text {
+		f.setText(*params.Text)
+		return s.update()
+	}
+	return nil
+}
+
+func (s *Server) didClose(params *DidCloseTextDocumentParams) error {
+	f, err := s.file(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	f.open = false
+
+	// the file can still be imported by the open ones, with the text on disk
+	if data, err := ioutil.ReadFile(f.name); err == nil {
+		f.setText(string(data))
+	}
+	return s.update()
+}
+
+// update loads the imports of the files and publishes the new diagnostics
+func (s *Server) update() error {
+	s.loadImports()
+	s.snap = nil
+
+	diags := s.diagnostics()
+	for uri, f := range s.files {
+		list := diags[uri]
+		if !f.open {
+			// the diagnostics of the closed files are cleared
+			list = []Diagnostic{}
+		}
+		prev, ok := s.published[uri]
+		if ok && equalDiagnostics(prev, list) || !ok && len(list) == 0 {
+			continue
+		}
+		s.published[uri] = list
+		params := &PublishDiagnosticsParams{URI: uri, Diagnostics: list}
+		if f.open {
+			params.Version = f.version
+		}
+		if err := s.conn.notify("textDocument/publishDiagnostics", params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadImports loads from disk the files imported by the files of the
+// workspace that are not loaded yet
+func (s *Server) loadImports() {
+	queue := []*file{}
+	for _, f := range s.files {
+		queue = append(queue, f)
+	}
+	for len(queue) != 0 {
+		f := queue[0]
+		queue = queue[1:]
+		if f.unit == nil {
+			continue
+		}
+		for _, child := range f.unit.Children {
+			imp, ok := child.(*solcparser.ImportDirective)
+			if !ok {
+				continue
+			}
+			path, ok := s.resolveImport(f, imp.Path)
+			if !ok {
+				continue
+			}
+			uri := pathToURI(path)
+			if _, ok := s.files[uri]; ok {
+				continue
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			imported := newFile(uri, string(data))
+			s.files[uri] = imported
+			queue = append(queue, imported)
+		}
+	}
+}
+
+// resolveImport returns the path of an import. The relative paths are
+// relative to the file and the rest are looked up in the root of the
+// workspace and its node_modules.
+func (s *Server) resolveImport(f *file, path string) (string, bool) {
+	candidates := []string{}
+	if strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") {
+		candidates = append(candidates, filepath.Join(filepath.Dir(f.name), path))
+	} else if s.root != "" {
+		candidates = append(candidates,
+			filepath.Join(s.root, path),
+			filepath.Join(s.root, "node_modules", path),
+		)
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return filepath.Clean(candidate), true
+		}
+	}
+	return "", false
+}
+
+// snapshot returns the analysis of the current files
+func (s *Server) snapshot() *snapshot {
+	if s.snap == nil {
+		files := []*file{}
+		for _, f := range s.files {
+			files = append(files, f)
+		}
+		s.snap = newSnapshot(files)
+	}
+	return s.snap
+}
+
+// diagnostics returns the syntax errors and the lint diagnostics of the files
+// by URI
+func (s *Server) diagnostics() map[string][]Diagnostic {
+	res := map[string][]Diagnostic{}
+	byName := map[string]*file{}
+
+	// the rules run only over the files without syntax errors
+	project := solcparser.NewProject()
+	sources := map[string]string{}
+
+	uris := []string{}
+	for uri := range s.files {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		f := s.files[uri]
+		byName[f.name] = f
+		res[uri] = []Diagnostic{}
+		for _, err := range f.errors {
+			d := Diagnostic{
+				Severity: SeverityError,
+				Source:   "solls",
+				Message:  err.Error(),
+			}
+			if loc := err.Loc(); loc != nil {
+				d.Range = f.locRange(loc)
+			}
+			res[uri] = append(res[uri], d)
+		}
+		if f.unit != nil && len(f.errors) == 0 {
+			project.Add(f.name, f.unit)
+			sources[f.name] = f.text
+		}
+	}
+
+	for _, d := range runLint(lint.New(s.config), project, sources) {
+		f := byName[d.File]
+		if f == nil {
+			continue
+		}
+		diag := Diagnostic{
+			Severity: lintSeverity(d.Severity),
+			Code:     d.Rule,
+			Source:   "sollint",
+			Message:  d.Message,
+		}
+		if d.Loc != nil {
+			diag.Range = f.locRange(d.Loc)
+		}
+		res[f.uri] = append(res[f.uri], diag)
+	}
+	return res
+}
+
+// runLint runs the linter over the project, a failure of the type checker
+// leaves the project without lint diagnostics
+func runLint(l *lint.Linter, p *solcparser.Project, sources map[string]string) (res []*lint.Diagnostic) {
+	defer func() {
+		if err := recover(); err != nil {
+			res = nil
+		}
+	}()
+	return l.Run(p, sources)
+}
+
+func lintSeverity(severity lint.Severity) int {
+	switch severity {
+	case lint.SeverityError:
+		return SeverityError
+	case lint.SeverityWarning:
+		return SeverityWarning
+	}
+	return SeverityInformation
+}
+
+func equalDiagnostics(a, b []Diagnostic) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// definition returns the location of the declaration referenced at the offset
+func (s *Server) definition(f *file, offset int) []Location {
+	snap := s.snapshot()
+	decl, _, ok := snap.target(f, offset)
+	if !ok {
+		return []Location{}
+	}
+	name, ok := snap.definition(decl)
+	if !ok {
+		return []Location{}
+	}
+	return []Location{name.location()}
+}
+
+// references returns the locations of the references to the declaration at
+// the offset
+func (s *Server) references(f *file, offset int, includeDecl bool) []Location {
+	snap := s.snapshot()
+	decl, _, ok := snap.target(f, offset)
+	if !ok {
+		return []Location{}
+	}
+	def, hasDef := snap.definition(decl)
+
+	res := []Location{}
+	for _, ref := range snap.references(decl) {
+		if !includeDecl && hasDef && ref == def {
+			continue
+		}
+		res = append(res, ref.location())
+	}
+	return res
+}
+
+func (s *Server) prepareRename(f *file, offset int) (interface{}, error) {
+	snap := s.snapshot()
+	decl, name, ok := snap.target(f, offset)
+	if !ok {
+		return nil, nil
+	}
+	if !s.renamable(decl) {
+		return nil, errorf(codeRequestFailed, "'%s' cannot be renamed", declName(decl))
+	}
+	return &PrepareRenameResult{
+		Range:       name.location().Range,
+		Placeholder: f.text[name.start:name.end],
+	}, nil
+}
+
+// renamable returns true if the declaration is declared in one of the files
+// of the workspace and has a name
+func (s *Server) renamable(decl interface{}) bool {
+	if declName(decl) == "" {
+		return false
+	}
+	_, ok := s.snapshot().definition(decl)
+	return ok
+}
+
+func (s *Server) rename(f *file, offset int, newName string) (*WorkspaceEdit, error) {
+	if !isIdentifier(newName) {
+		return nil, errorf(codeInvalidParams, "'%s' is not a valid identifier", newName)
+	}
+	snap := s.snapshot()
+	decl, _, ok := snap.target(f, offset)
+	if !ok {
+		return nil, errorf(codeRequestFailed, "no declaration to rename")
+	}
+	if !s.renamable(decl) {
+		return nil, errorf(codeRequestFailed, "'%s' cannot be renamed", declName(decl))
+	}
+
+	edit := &WorkspaceEdit{Changes: map[string][]TextEdit{}}
+	for _, ref := range snap.references(decl) {
+		edit.Changes[ref.file.uri] = append(edit.Changes[ref.file.uri], TextEdit{
+			Range:   ref.location().Range,
+			NewText: newName,
+		})
+	}
+	return edit, nil
+}
+
+// isIdentifier returns true if the name is a valid identifier that is not a
+// keyword
+func isIdentifier(name string) bool {
+	tokens := scan(name)
+	return len(tokens) == 1 && tokens[0].end == len(name) && tokens[0].isIdentifier()
+}
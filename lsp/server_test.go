@@ -0,0 +1,502 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/umbracle/solidity-parser-go/lint"
+	"github.com/umbracle/solidity-parser-go/lint/linttest"
+)
+
+func init() {
+	lint.Register(linttest.FuncRule{})
+}
+
+// client talks to a server running in the background
+type client struct {
+	t    *testing.T
+	conn *conn
+	id   int
+
+	// msgs are the messages of the server
+	msgs chan *message
+}
+
+func newClient(t *testing.T) *client {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	srv := NewServer(inR, outW)
+	go func() {
+		srv.Run()
+		outW.Close()
+	}()
+
+	c := &client{t: t, conn: newConn(outR, inW), msgs: make(chan *message, 100)}
+	go func() {
+		for {
+			msg, err := c.conn.read()
+			if err != nil {
+				close(c.msgs)
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+	t.Cleanup(func() {
+		inW.Close()
+	})
+	return c
+}
+
+// call sends a request and decodes the result of its response
+func (c *client) call(method string, params interface{}, result interface{}) error {
+	c.id++
+	id := json.RawMessage(strings.TrimSpace(string(mustMarshal(c.id))))
+	if err := c.conn.write(map[string]interface{}{"jsonrpc": "2.0", "id": &id, "method": method, "params": params}); err != nil {
+		c.t.Fatal(err)
+	}
+	for msg := range c.msgs {
+		if msg.ID == nil || string(*msg.ID) != string(id) {
+			continue
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatal(err)
+			}
+		}
+		return nil
+	}
+	c.t.Fatal("connection closed")
+	return nil
+}
+
+func (c *client) notify(method string, params interface{}) {
+	if err := c.conn.notify(method, params); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// diagnostics waits for the next diagnostics of the URI
+func (c *client) diagnostics(uri string) []Diagnostic {
+	for msg := range c.msgs {
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatal(err)
+		}
+		if params.URI == uri {
+			return params.Diagnostics
+		}
+	}
+	c.t.Fatal("connection closed")
+	return nil
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// open initializes the server and opens a document
+func (c *client) open(uri, text string) {
+	var res InitializeResult
+	if err := c.call("initialize", &InitializeParams{}, &res); err != nil {
+		c.t.Fatal(err)
+	}
+	c.notify("initialized", struct{}{})
+	c.notify("textDocument/didOpen", &DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "solidity", Version: 1, Text: text},
+	})
+}
+
+// at returns the position of the nth (from 1) occurrence of the marker
+func at(text, marker string, n int) Position {
+	offset := -1
+	for i := 0; i < n; i++ {
+		offset += strings.Index(text[offset+1:], marker) + 1
+	}
+	return newFile("", text).position(offset)
+}
+
+const tokenSrc = `pragma solidity ^0.8.0;
+
+/// @title A token
+contract Token {
+    mapping(address => uint256) public balances;
+
+    event Transfer(address indexed from, address indexed to, uint256 value);
+
+    /// @notice Moves tokens
+    /// to another account
+    /// @param to The receiver
+    /// @return ok True on success
+    function transfer(address to, uint256 amount) public returns (bool ok) {
+        balances[msg.sender] -= amount;
+        balances[to] += amount;
+        emit Transfer(msg.sender, to, amount);
+        return true;
+    }
+}
+`
+
+const tokenURI = "file:///work/token.sol"
+
+func TestServerInitialize(t *testing.T) {
+	c := newClient(t)
+
+	var res InitializeResult
+	if err := c.call("initialize", &InitializeParams{RootURI: "file:///work"}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Capabilities.TextDocumentSync.Change != SyncFull {
+		t.Fatal("expected full sync")
+	}
+	if !res.Capabilities.RenameProvider.PrepareProvider || !res.Capabilities.SemanticTokensProvider.Full {
+		t.Fatal("bad capabilities")
+	}
+
+	if err := c.call("unknown/method", struct{}{}, nil); err == nil || err.(*rpcError).Code != codeMethodNotFound {
+		t.Fatalf("expected method not found but found %v", err)
+	}
+	if err := c.call("shutdown", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	c.notify("exit", nil)
+	if _, ok := <-c.msgs; ok {
+		t.Fatal("expected the server to exit")
+	}
+}
+
+func TestServerDiagnostics(t *testing.T) {
+	c := newClient(t)
+
+	src := "contract A {\n    function f() public {\n        uint a = 1\n    }\n}\n"
+	c.open(tokenURI, src)
+
+	// the lint rules do not run over the files with syntax errors
+	diags := c.diagnostics(tokenURI)
+	if len(diags) != 1 {
+		t.Fatalf("expected a syntax error but found %v", diags)
+	}
+	if diags[0].Source != "solls" || diags[0].Severity != SeverityError || diags[0].Range.Start.Line != 3 {
+		t.Fatalf("bad syntax error %v", diags[0])
+	}
+
+	// insert the missing semicolon
+	c.notify("textDocument/didChange", &DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: tokenURI, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: strings.Replace(src, "= 1", "= 1;", 1)}},
+	})
+	diags = c.diagnostics(tokenURI)
+	if len(diags) != 1 || diags[0].Code != "test-func" || diags[0].Source != "sollint" || diags[0].Severity != SeverityWarning {
+		t.Fatalf("expected only the lint diagnostic but found %v", diags)
+	}
+	if diags[0].Range.Start != (Position{Line: 1, Character: 4}) {
+		t.Fatalf("bad range %v", diags[0].Range)
+	}
+
+	c.notify("textDocument/didClose", &DidCloseTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: tokenURI}})
+	if diags := c.diagnostics(tokenURI); len(diags) != 0 {
+		t.Fatalf("expected the diagnostics to be cleared but found %v", diags)
+	}
+}
+
+func TestServerNavigation(t *testing.T) {
+	c := newClient(t)
+	c.open(tokenURI, tokenSrc)
+	c.diagnostics(tokenURI)
+
+	pos := func(marker string, n int) TextDocumentPositionParams {
+		return TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: tokenURI},
+			Position:     at(tokenSrc, marker, n),
+		}
+	}
+	// the range of the name at the start of the marker
+	rng := func(marker string, n int) Range {
+		start := at(tokenSrc, marker, n)
+		size := strings.IndexFunc(marker, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+		})
+		if size == -1 {
+			size = len(marker)
+		}
+		return Range{Start: start, End: Position{Line: start.Line, Character: start.Character + size}}
+	}
+
+	// definition of a parameter used in the body
+	var locs []Location
+	if err := c.call("textDocument/definition", pos("to]", 1), &locs); err != nil {
+		t.Fatal(err)
+	}
+	if len(locs) != 1 || locs[0].Range != rng("to, uint256 amount", 1) {
+		t.Fatalf("bad definition %v", locs)
+	}
+
+	// definition of an event
+	if err := c.call("textDocument/definition", pos("Transfer(msg", 1), &locs); err != nil {
+		t.Fatal(err)
+	}
+	if len(locs) != 1 || locs[0].Range != rng("Transfer", 1) {
+		t.Fatalf("bad definition %v", locs)
+	}
+
+	// references of a state variable
+	refs := ReferenceParams{TextDocumentPositionParams: pos("balances", 1), Context: ReferenceContext{IncludeDeclaration: true}}
+	if err := c.call("textDocument/references", refs, &locs); err != nil {
+		t.Fatal(err)
+	}
+	expected := []Range{rng("balances", 1), rng("balances", 2), rng("balances", 3)}
+	if len(locs) != len(expected) {
+		t.Fatalf("bad references %v", locs)
+	}
+	for i := range locs {
+		if locs[i].URI != tokenURI || locs[i].Range != expected[i] {
+			t.Fatalf("bad reference %d: %v", i, locs[i])
+		}
+	}
+	refs.Context.IncludeDeclaration = false
+	if err := c.call("textDocument/references", refs, &locs); err != nil {
+		t.Fatal(err)
+	}
+	if len(locs) != 2 {
+		t.Fatalf("bad references %v", locs)
+	}
+
+	// rename of a parameter
+	var prepare PrepareRenameResult
+	if err := c.call("textDocument/prepareRename", pos("amount;", 1), &prepare); err != nil {
+		t.Fatal(err)
+	}
+	if prepare.Placeholder != "amount" {
+		t.Fatalf("bad placeholder %s", prepare.Placeholder)
+	}
+	var edit WorkspaceEdit
+	rename := RenameParams{TextDocumentPositionParams: pos("amount;", 1), NewName: "value"}
+	if err := c.call("textDocument/rename", rename, &edit); err != nil {
+		t.Fatal(err)
+	}
+	if edits := edit.Changes[tokenURI]; len(edits) != 4 || edits[0].Range != rng("amount", 1) || edits[0].NewText != "value" {
+		t.Fatalf("bad rename %v", edit)
+	}
+	rename.NewName = "contract"
+	if err := c.call("textDocument/rename", rename, &edit); err == nil {
+		t.Fatal("expected an error for a keyword")
+	}
+
+	// hover with the natspec of the function
+	var hover Hover
+	if err := c.call("textDocument/hover", pos("transfer", 1), &hover); err != nil {
+		t.Fatal(err)
+	}
+	expectedHover := "```solidity\nfunction transfer(address to, uint256 amount) public returns (bool ok)\n```\n\n" +
+		"Moves tokens to another account\n\n*@param* `to` The receiver\n\n*@return* ok True on success"
+	if hover.Contents.Value != expectedHover {
+		t.Fatalf("bad hover %q", hover.Contents.Value)
+	}
+}
+
+func TestServerDocument(t *testing.T) {
+	c := newClient(t)
+	c.open(tokenURI, tokenSrc)
+	c.diagnostics(tokenURI)
+	doc := TextDocumentIdentifier{URI: tokenURI}
+
+	var symbols []DocumentSymbol
+	if err := c.call("textDocument/documentSymbol", &DocumentSymbolParams{TextDocument: doc}, &symbols); err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 1 || symbols[0].Name != "Token" || symbols[0].Kind != SymbolClass {
+		t.Fatalf("bad symbols %v", symbols)
+	}
+	children := []string{}
+	for _, child := range symbols[0].Children {
+		children = append(children, child.Name+" "+child.Detail)
+	}
+	expected := []string{
+		"balances mapping(address => uint256)",
+		"Transfer (address indexed from, address indexed to, uint256 value)",
+		"transfer (address to, uint256 amount) returns (bool ok)",
+	}
+	if !reflect.DeepEqual(children, expected) {
+		t.Fatalf("bad children %q", children)
+	}
+
+	var folds []FoldingRange
+	if err := c.call("textDocument/foldingRange", &FoldingRangeParams{TextDocument: doc}, &folds); err != nil {
+		t.Fatal(err)
+	}
+	expectedFolds := []FoldingRange{
+		{StartLine: 3, EndLine: 17},
+		{StartLine: 8, EndLine: 11, Kind: "comment"},
+		{StartLine: 12, EndLine: 16},
+	}
+	if !reflect.DeepEqual(folds, expectedFolds) {
+		t.Fatalf("bad folding ranges %v", folds)
+	}
+
+	var tokens SemanticTokens
+	if err := c.call("textDocument/semanticTokens/full", &SemanticTokensParams{TextDocument: doc}, &tokens); err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens.Data)%5 != 0 {
+		t.Fatal("bad encoding")
+	}
+	// decode the tokens of the contract name and the parameter 'to'
+	found := map[string]string{}
+	line, char := 0, 0
+	f := newFile("", tokenSrc)
+	for i := 0; i < len(tokens.Data); i += 5 {
+		if tokens.Data[i] != 0 {
+			char = 0
+		}
+		line += tokens.Data[i]
+		char += tokens.Data[i+1]
+		start := f.offset(Position{Line: line, Character: char})
+		found[tokenSrc[start:start+tokens.Data[i+2]]] = tokenTypes[tokens.Data[i+3]]
+	}
+	for text, typ := range map[string]string{
+		"Token": "class", "to": "parameter", "balances": "property", "Transfer": "event",
+		"transfer": "function", "contract": "keyword", "uint256": "type", "/// @title A token": "comment",
+	} {
+		if found[text] != typ {
+			t.Fatalf("expected %s to be %s but found %s", text, typ, found[text])
+		}
+	}
+
+	// formatting
+	c.notify("textDocument/didChange", &DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: tokenURI, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "contract A {\nuint a;  \n}"}},
+	})
+	var edits []TextEdit
+	opts := FormattingOptions{TabSize: 2, InsertSpaces: true}
+	if err := c.call("textDocument/formatting", &DocumentFormattingParams{TextDocument: doc, Options: opts}, &edits); err != nil {
+		t.Fatal(err)
+	}
+	expectedEdits := []TextEdit{
+		{Range: Range{Start: Position{Line: 1}, End: Position{Line: 1, Character: 9}}, NewText: "  uint a;"},
+		{Range: Range{Start: Position{Line: 2, Character: 1}, End: Position{Line: 2, Character: 1}}, NewText: "\n"},
+	}
+	if !reflect.DeepEqual(edits, expectedEdits) {
+		t.Fatalf("bad edits %v", edits)
+	}
+}
+
+func TestReindent(t *testing.T) {
+	cases := []struct {
+		src      string
+		expected string
+	}{
+		{
+			"contract A {\nfunction f() public {\nif (a) {\nb();\n}\n}\n}",
+			"contract A {\n\tfunction f() public {\n\t\tif (a) {\n\t\t\tb();\n\t\t}\n\t}\n}",
+		},
+		{
+			// continuation lines
+			"function f()\npublic\nreturns (uint)\n{\nreturn a +\nb;\n}",
+			"function f()\n\tpublic\n\treturns (uint)\n{\n\treturn a +\n\t\tb;\n}",
+		},
+		{
+			// brackets opened in the same line
+			"x = f({\na: 1,\nb: 2\n});",
+			"x = f({\n\ta: 1,\n\tb: 2\n});",
+		},
+		{
+			// multi-line comments are kept
+			"contract A {\n/**\n   * doc   \n   */\nuint a;\n}",
+			"contract A {\n\t/**\n   * doc\n   */\n\tuint a;\n}",
+		},
+		{
+			// assembly statements have no semicolons
+			"assembly {\nlet a := 1\nmstore(0, a)\n}",
+			"assembly {\n\tlet a := 1\n\tmstore(0, a)\n}",
+		},
+		{
+			// enum members
+			"enum E {\nA,\nB\n}",
+			"enum E {\n\tA,\n\tB\n}",
+		},
+	}
+	for _, c := range cases {
+		found := strings.Join(reindent(c.src, "\t"), "\n")
+		if found != c.expected {
+			t.Fatalf("bad format of %q:\n%s", c.src, found)
+		}
+	}
+}
+
+func TestFilePositions(t *testing.T) {
+	// 'é' is 2 bytes and 1 UTF-16 unit, '𝄞' is 4 bytes and 2 units
+	f := newFile("", "a é𝄞 b\nsecond\r\n")
+
+	cases := []struct {
+		offset int
+		pos    Position
+	}{
+		{0, Position{0, 0}},
+		{2, Position{0, 2}},
+		{4, Position{0, 3}},
+		{8, Position{0, 5}},
+		{11, Position{1, 0}},
+		{17, Position{1, 6}},
+		{len(f.text), Position{2, 0}},
+	}
+	for _, c := range cases {
+		if pos := f.position(c.offset); pos != c.pos {
+			t.Fatalf("bad position of %d: %v", c.offset, pos)
+		}
+		if offset := f.offset(c.pos); offset != c.offset {
+			t.Fatalf("bad offset of %v: %d", c.pos, offset)
+		}
+	}
+
+	// the positions past the end of a line are moved to the end of the line
+	if offset := f.offset(Position{0, 100}); offset != 10 {
+		t.Fatalf("bad offset %d", offset)
+	}
+
+	// the changes have the whole text of the file
+	f.applyChanges([]TextDocumentContentChangeEvent{
+		{Text: "a é𝄞 b\n2nd\r\n"},
+		{Text: "A é𝄞 b\n2nd\r\n"},
+	})
+	if f.text != "A é𝄞 b\n2nd\r\n" || f.offset(Position{1, 3}) != 14 {
+		t.Fatalf("bad text %q", f.text)
+	}
+}
+
+func TestDocComment(t *testing.T) {
+	cases := []struct {
+		src      string
+		expected []string
+	}{
+		{"/// a\n/// b\ncontract", []string{"a", "b"}},
+		{"/**\n * a\n * b\n */\ncontract", []string{"a", "b"}},
+		{"/** a */ contract", []string{"a"}},
+		{"// a\ncontract", nil},
+		{"/* a */\ncontract", nil},
+		{"/** a */ uint x; /* b */\ncontract", nil},
+	}
+	for _, c := range cases {
+		found := docComment(c.src, strings.Index(c.src, "contract"))
+		if len(found) == 0 && len(c.expected) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(found, c.expected) {
+			t.Fatalf("bad comment of %q: %q", c.src, found)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package lsp
+
+import (
+	"regexp"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// documentSymbols returns the outline of the file: the declarations of the
+// file and of its contracts
+func documentSymbols(f *file) []DocumentSymbol {
+	res := []DocumentSymbol{}
+	if f.unit == nil {
+		return res
+	}
+	for _, child := range f.unit.Children {
+		res = append(res, symbols(f, child)...)
+	}
+	return res
+}
+
+// symbols returns the symbols of a declaration, the state variable
+// declarations have one symbol per variable
+func symbols(f *file, node interface{}) []DocumentSymbol {
+	if obj, ok := node.(*solcparser.StateVariableDeclaration); ok {
+		res := []DocumentSymbol{}
+		for _, v := range obj.Variables {
+			res = append(res, symbols(f, v)...)
+		}
+		return res
+	}
+
+	name := declName(node)
+	n, ok := node.(solcparser.INode)
+	if !ok || n.GetLoc() == nil {
+		return nil
+	}
+	sym := DocumentSymbol{
+		Name:  name,
+		Range: f.locRange(n.GetLoc()),
+	}
+	if sel, ok := nameSpan(f, node); ok {
+		sym.SelectionRange = sel.location().Range
+	} else {
+		sym.SelectionRange = sym.Range
+	}
+
+	switch obj := node.(type) {
+	case *solcparser.ContractDefinition:
+		switch obj.Kind {
+		case "interface":
+			sym.Kind = SymbolInterface
+		case "library":
+			sym.Kind = SymbolNamespace
+		default:
+			sym.Kind = SymbolClass
+		}
+		sym.Detail = obj.Kind
+		if obj.IsAbstract {
+			sym.Detail = "abstract " + obj.Kind
+		}
+		for _, sub := range obj.SubNodes {
+			sym.Children = append(sym.Children, symbols(f, sub)...)
+		}
+
+	case *solcparser.FunctionDefinition:
+		switch {
+		case obj.IsConstructor:
+			sym.Kind = SymbolConstructor
+			sym.Name = "constructor"
+		case obj.IsReceiveEther:
+			sym.Kind = SymbolMethod
+			sym.Name = "receive"
+		case obj.IsFallback && obj.Name == "":
+			sym.Kind = SymbolMethod
+			sym.Name = "fallback"
+		case isMember(f, obj):
+			sym.Kind = SymbolMethod
+		default:
+			sym.Kind = SymbolFunction
+		}
+		sym.Detail = parameters(f, obj)
+
+	case *solcparser.ModifierDefinition:
+		sym.Kind = SymbolMethod
+		sym.Detail = "modifier"
+
+	case *solcparser.EventDefinition:
+		sym.Kind = SymbolEvent
+		sym.Detail = parameters(f, obj)
+
+	case *solcparser.CustomErrorDefinition:
+		sym.Kind = SymbolEvent
+		sym.Detail = "error" + parameters(f, obj)
+
+	case *solcparser.StructDefinition:
+		sym.Kind = SymbolStruct
+		for _, member := range obj.Members {
+			child := symbols(f, member)
+			for i := range child {
+				child[i].Kind = SymbolField
+			}
+			sym.Children = append(sym.Children, child...)
+		}
+
+	case *solcparser.EnumDefinition:
+		sym.Kind = SymbolEnum
+		for _, member := range obj.Members {
+			sym.Children = append(sym.Children, symbols(f, member)...)
+		}
+
+	case *solcparser.EnumValue:
+		sym.Kind = SymbolEnumMember
+
+	case *solcparser.TypeDefinition:
+		sym.Kind = SymbolTypeParameter
+		sym.Detail = nodeText(f, obj.Definition)
+
+	case *solcparser.StateVariableDeclarationVariable:
+		sym.Kind = SymbolField
+		if obj.IsDeclaredConst {
+			sym.Kind = SymbolConstant
+		}
+		sym.Detail = nodeText(f, obj.TypeName)
+
+	case *solcparser.VariableDeclaration:
+		sym.Kind = SymbolVariable
+		sym.Detail = nodeText(f, obj.TypeName)
+
+	case *solcparser.FileLevelConstant:
+		sym.Kind = SymbolConstant
+		sym.Detail = nodeText(f, obj.TypeName)
+
+	default:
+		return nil
+	}
+	return []DocumentSymbol{sym}
+}
+
+// isMember returns true if the function is declared inside a contract
+func isMember(f *file, fn *solcparser.FunctionDefinition) bool {
+	for _, child := range f.unit.Children {
+		if obj, ok := child.(*solcparser.FunctionDefinition); ok && obj == fn {
+			return false
+		}
+	}
+	return true
+}
+
+// parameters returns the parameter list of a declaration with the return
+// parameters (i.e. "(address to) returns (bool)")
+func parameters(f *file, node interface{}) string {
+	header := signature(f, node)
+	if i := strings.Index(header, "("); i != -1 {
+		params := header[i:]
+		if j := strings.Index(params, " returns"); j != -1 {
+			// only the returns clause after the parameters
+			return params[:strings.Index(params, ")")+1] + params[j:]
+		}
+		return params[:strings.LastIndex(params, ")")+1]
+	}
+	return ""
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// signature returns the text of a declaration up to its body with the
+// whitespace collapsed
+func signature(f *file, node interface{}) string {
+	n, ok := node.(solcparser.INode)
+	if !ok || n.GetLoc() == nil {
+		return ""
+	}
+	start, end := n.GetLoc().Start.Offset, n.GetLoc().End.Offset
+
+	var body interface{}
+	switch obj := node.(type) {
+	case *solcparser.FunctionDefinition:
+		body = obj.Body
+	case *solcparser.ModifierDefinition:
+		body = obj.Body
+	case *solcparser.ContractDefinition:
+		if i := strings.Index(f.text[start:end], "{"); i != -1 {
+			end = start + i
+		}
+	}
+	if b, ok := body.(solcparser.INode); ok && b.GetLoc() != nil && b.GetLoc().Start.Offset > start {
+		end = b.GetLoc().Start.Offset
+	}
+
+	text := f.text[start:end]
+	text = strings.TrimSpace(spaces.ReplaceAllString(text, " "))
+	text = strings.TrimSuffix(text, ";")
+	text = strings.ReplaceAll(text, "( ", "(")
+	return strings.ReplaceAll(text, " )", ")")
+}
+
+// nodeText returns the source of a node
+func nodeText(f *file, node interface{}) string {
+	n, ok := node.(solcparser.INode)
+	if !ok || n.GetLoc() == nil || n.GetLoc().End.Offset > len(f.text) {
+		return ""
+	}
+	return f.text[n.GetLoc().Start.Offset:n.GetLoc().End.Offset]
+}
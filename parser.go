@@ -3,6 +3,7 @@ package solcparser
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
 
@@ -10,10 +11,24 @@ import (
 	solAntlr "github.com/umbracle/solidity-parser-go/antlr"
 )
 
+// the antlr parser is generated from the grammar vendored in antlr-sol
+//go:generate ./scripts/build-grammar.sh
+
 // exampleListener is an event-driven callback for the parser.
 type exampleListener struct {
+	// invalid is true if the source has syntax errors
+	invalid bool
+
 	service reflect.Value
 	funcMap map[string]*funcData
+
+	// src is only set if the locations of the nodes are tracked
+	src *sourceMap
+
+	// errSrc is the source map of the errors of the literals (i.e. an invalid
+	// escape sequence)
+	errSrc *sourceMap
+	errors []*SyntaxError
 }
 
 type funcData struct {
@@ -96,6 +111,13 @@ func validateFunc(funcName string, fv reflect.Value, isMethod bool) (inNum int,
 }
 
 func (e *exampleListener) Visit(i antlr.Tree) INode {
+	// the trees of the invalid sources miss the nodes that failed to parse,
+	// they are not built and the syntax errors report them
+	switch i.(type) {
+	case nil, antlr.ErrorNode:
+		return nil
+	}
+
 	funcName := reflect.TypeOf(i).String()
 	if funcName == "*antlr.TerminalNodeImpl" {
 		return nil
@@ -106,7 +128,7 @@ func (e *exampleListener) Visit(i antlr.Tree) INode {
 
 	fd, ok := e.funcMap[funcName]
 	if !ok {
-		panic(fmt.Sprintf("BUG: visit not found %s", funcName))
+		return e.incomplete(fmt.Sprintf("BUG: visit not found %s", funcName))
 	}
 
 	inArgs := make([]reflect.Value, fd.inNum)
@@ -122,6 +144,9 @@ func (e *exampleListener) Visit(i antlr.Tree) INode {
 	if !skipNode(xx) {
 		ii.SetTypeName(xx)
 	}
+	if ii.GetLoc() == nil {
+		e.locate(ii, i)
+	}
 	return ii
 }
 
@@ -136,6 +161,8 @@ func skipNode(i string) bool {
 		"PrimaryExpression",
 		"Parameter",
 		"FunctionTypeParameter",
+		"AssemblyItem",
+		"AssemblyExpression",
 	}
 	for _, j := range skip {
 		if j == i {
@@ -146,7 +173,8 @@ func skipNode(i string) bool {
 }
 
 type Node struct {
-	Type string `json:"type"`
+	Type string    `json:"type"`
+	Loc  *Location `json:"loc,omitempty"`
 }
 
 func (n *Node) IsNode() {}
@@ -159,10 +187,20 @@ func (n *Node) GetType() string {
 	return n.Type
 }
 
+func (n *Node) SetLoc(loc *Location) {
+	n.Loc = loc
+}
+
+func (n *Node) GetLoc() *Location {
+	return n.Loc
+}
+
 type INode interface {
 	GetType() string
 	IsNode()
 	SetTypeName(s string)
+	SetLoc(loc *Location)
+	GetLoc() *Location
 }
 
 // SourceUnit
@@ -205,12 +243,24 @@ type ContractDefinition struct {
 	SubNodes      []interface{}
 	BaseContracts []interface{}
 	Kind          string
+	IsAbstract    bool
 }
 
 func (e *exampleListener) VisitContractDefinition(ctx *solAntlr.ContractDefinitionContext) INode {
+	// the 'abstract' keyword is optional and precedes the contract kind
+	kindIndx := 0
+	isAbstract := toText(ctx.GetChild(0)) == "abstract"
+	if isAbstract {
+		kindIndx = 1
+	}
+	if kindIndx >= ctx.GetChildCount() {
+		return e.incomplete("contract without kind")
+	}
+
 	decl := &ContractDefinition{
 		Name:          toText(ctx.Identifier()),
-		Kind:          toText(ctx.GetChild(0)),
+		Kind:          toText(ctx.GetChild(kindIndx)),
+		IsAbstract:    isAbstract,
 		SubNodes:      []interface{}{},
 		BaseContracts: []interface{}{},
 	}
@@ -237,7 +287,9 @@ func (e *exampleListener) VisitInheritanceSpecifier(ctx *solAntlr.InheritanceSpe
 		Arguments: []interface{}{},
 	}
 	if expr := ctx.ExpressionList(); expr != nil {
-		decl.Arguments = append(decl.Arguments, e.Visit(expr))
+		for _, p := range expr.(*solAntlr.ExpressionListContext).AllExpression() {
+			decl.Arguments = append(decl.Arguments, e.Visit(p))
+		}
 	}
 	return decl
 }
@@ -351,6 +403,7 @@ func (e *exampleListener) VisitStateVariableDeclaration(ctx *solAntlr.StateVaria
 		},
 		IsInmutable: hasElem(ctx.AllImmutableKeyword()),
 	}
+	e.locate(vv, ctx)
 	if ctx.Expression() != nil {
 		vv.Expression = e.Visit(ctx.Expression())
 	}
@@ -376,6 +429,7 @@ type FunctionDefinition struct {
 	IsReceiveEther   bool
 	IsFallback       bool
 	IsVirtual        bool
+	IsOverride       bool
 	Override         []interface{}
 }
 
@@ -407,6 +461,10 @@ func (e *exampleListener) VisitFunctionDefinition(ctx *solAntlr.FunctionDefiniti
 
 	var name string
 	var returnParameters interface{}
+	if ctxRet := ctx.ReturnParameters(); ctxRet != nil {
+		returnParameters = e.VisitReturnParameters(ctxRet.(*solAntlr.ReturnParametersContext))
+	}
+
 	switch toText(ctx.FunctionDescriptor().(*solAntlr.FunctionDescriptorContext).GetChild(0)) {
 	case "constructor":
 
@@ -433,11 +491,6 @@ func (e *exampleListener) VisitFunctionDefinition(ctx *solAntlr.FunctionDefiniti
 			name = toText(ident)
 		}
 
-		ctxRet := ctx.ReturnParameters()
-		if ctxRet != nil {
-			returnParameters = e.VisitReturnParameters(ctxRet.(*solAntlr.ReturnParametersContext))
-		}
-
 		if hasElem(modList.AllExternalKeyword()) {
 			visibility = "external"
 		} else if hasElem(modList.AllInternalKeyword()) {
@@ -455,9 +508,15 @@ func (e *exampleListener) VisitFunctionDefinition(ctx *solAntlr.FunctionDefiniti
 		isVirtual = true
 	}
 
+	var stateMutability string
+	if mut := modList.AllStateMutability(); len(mut) != 0 {
+		stateMutability = toText(mut[0])
+	}
+
 	var override []interface{}
 	overrideSpec := modList.AllOverrideSpecifier()
-	if len(overrideSpec) != 0 {
+	isOverride := len(overrideSpec) != 0
+	if isOverride {
 		for _, o := range overrideSpec[0].(*solAntlr.OverrideSpecifierContext).AllUserDefinedTypeName() {
 			override = append(override, e.Visit(o))
 		}
@@ -468,9 +527,11 @@ func (e *exampleListener) VisitFunctionDefinition(ctx *solAntlr.FunctionDefiniti
 		Body:             block,
 		Parameters:       parameters,
 		ReturnParameters: returnParameters,
+		StateMutability:  stateMutability,
 		IsConstructor:    isConstructor,
 		IsFallback:       isFallback,
 		IsVirtual:        isVirtual,
+		IsOverride:       isOverride,
 		IsReceiveEther:   isReceiveEther,
 		Visibility:       visibility,
 		Modifiers:        modifiers,
@@ -590,8 +651,21 @@ type BinaryOperation struct {
 type NumberLiteral struct {
 	Node
 
+	// Number is the text of the number as it is in the source
 	Number          string
-	SubDenomination interface{}
+	SubDenomination SubDenomination
+
+	// Value is the exact value of the number with the sub-denomination
+	// applied, it is nil if the number is invalid
+	Value *big.Rat
+}
+
+// Int returns the value of the number if it is an integer
+func (n *NumberLiteral) Int() (*big.Int, bool) {
+	if n.Value == nil || !n.Value.IsInt() {
+		return nil, false
+	}
+	return new(big.Int).Set(n.Value.Num()), true
 }
 
 type IndexRangeAccess struct {
@@ -737,7 +811,10 @@ func (e *exampleListener) VisitExpression(ctx *solAntlr.ExpressionContext) INode
 		if toText(ctx.GetChild(1)) == "(" && toText(ctx.GetChild(3)) == ")" {
 			var names, args, identifiers []interface{}
 
-			ctxArgs := ctx.FunctionCallArguments().(*solAntlr.FunctionCallArgumentsContext)
+			ctxArgs, ok := ctx.FunctionCallArguments().(*solAntlr.FunctionCallArgumentsContext)
+			if !ok {
+				return e.incomplete("function call without arguments")
+			}
 			if expr := ctxArgs.ExpressionList(); expr != nil {
 				for _, p := range expr.(*solAntlr.ExpressionListContext).AllExpression() {
 					args = append(args, e.Visit(p))
@@ -832,7 +909,7 @@ func (e *exampleListener) VisitExpression(ctx *solAntlr.ExpressionContext) INode
 		}
 	}
 
-	panic("TODO")
+	return e.incomplete("TODO")
 }
 
 type Mapping struct {
@@ -857,7 +934,7 @@ func (e *exampleListener) VisitMappingKey(ctx *solAntlr.MappingKeyContext) INode
 	if elem := ctx.UserDefinedTypeName(); elem != nil {
 		return e.Visit(elem)
 	}
-	panic("BUG")
+	return e.incomplete("BUG")
 }
 
 type NameValueList struct {
@@ -886,11 +963,11 @@ func (e *exampleListener) VisitNameValueList(ctx *solAntlr.NameValueListContext)
 func (e *exampleListener) VisitTupleExpression(ctx *solAntlr.TupleExpressionContext) INode {
 	count := ctx.GetChildCount()
 	if count < 2 {
-		panic("bad")
+		return e.incomplete("bad")
 	}
 
 	childs := ctx.GetChildren()[1 : count-1]
-	childs = mapCommasToNulls(childs)
+	childs = e.mapCommasToNulls(childs)
 
 	components := []interface{}{}
 	for _, child := range childs {
@@ -908,7 +985,10 @@ func (e *exampleListener) VisitTupleExpression(ctx *solAntlr.TupleExpressionCont
 	return decl
 }
 
-func mapCommasToNulls(tree []antlr.Tree) []antlr.Tree {
+// mapCommasToNulls returns the elements of a comma separated list with nil
+// for the empty ones. The list of an invalid source ends at the first element
+// not followed by a comma.
+func (e *exampleListener) mapCommasToNulls(tree []antlr.Tree) []antlr.Tree {
 	res := []antlr.Tree{}
 	if len(tree) == 0 {
 		return res
@@ -925,6 +1005,9 @@ func mapCommasToNulls(tree []antlr.Tree) []antlr.Tree {
 			}
 		} else {
 			if toText(child) != "," {
+				if e.invalid {
+					return res
+				}
 				panic("Comma expected")
 			}
 			comma = true
@@ -960,12 +1043,13 @@ func (e *exampleListener) VisitTypeNameExpression(ctx *solAntlr.TypeNameExpressi
 }
 
 func (e *exampleListener) VisitNumberLiteral(ctx *solAntlr.NumberLiteralContext) INode {
-	decl := &NumberLiteral{
-		Number:          toText(ctx.GetChild(0)),
-		SubDenomination: nil,
-	}
+	unit := ""
 	if ctx.GetChildCount() == 2 {
-		decl.SubDenomination = toText(ctx.GetChild(1))
+		unit = toText(ctx.GetChild(1))
+	}
+	decl, err := newNumberLiteral(toText(ctx.GetChild(0)), unit)
+	if err != nil {
+		e.literalError(ctx.GetStart(), err)
 	}
 	return decl
 }
@@ -973,25 +1057,35 @@ func (e *exampleListener) VisitNumberLiteral(ctx *solAntlr.NumberLiteralContext)
 type HexLiteral struct {
 	Node
 
+	// Value is the text of the literal without the quotes (the hex digits
+	// and the underscores)
 	Value string
 	Parts []string
+
+	// Data is the decoded bytes of the literal, it is nil if the literal is invalid
+	Data []byte
 }
 
 func (e *exampleListener) VisitHexLiteral(ctx *solAntlr.HexLiteralContext) INode {
-	decl := &HexLiteral{
-		Parts: []string{},
+	fragments := ctx.AllHexLiteralFragment()
+	texts := []string{}
+	for _, f := range fragments {
+		texts = append(texts, toText(f))
 	}
-
-	for _, p := range ctx.AllHexLiteralFragment() {
-		hex := toText(p)
-		hex = strings.TrimPrefix(hex, "hex")
-		hex = strings.Trim(hex, "\"")
-		decl.Parts = append(decl.Parts, hex)
+	decl, errs := newHexLiteral(texts)
+	for i, err := range errs {
+		e.literalError(fragments[i].GetSymbol(), err)
 	}
-	decl.Value = strings.Join(decl.Parts, "")
 	return decl
 }
 
+// literalError adds the error of a literal token
+func (e *exampleListener) literalError(t antlr.Token, err *literalError) {
+	if e.errSrc != nil {
+		e.errors = append(e.errors, err.syntaxError(e.errSrc, e.errSrc.runePosition(t.GetStart()).Offset))
+	}
+}
+
 type BooleanLiteral struct {
 	Node
 
@@ -1012,30 +1106,16 @@ func (e *exampleListener) VisitPrimaryExpression(ctx *solAntlr.PrimaryExpression
 	}
 
 	if expr := ctx.StringLiteral(); expr != nil {
-		decl := &StringLiteral{
-			Node:      Node{Type: "StringLiteral"},
-			Parts:     []string{},
-			IsUnicode: []bool{},
+		fragments := expr.(*solAntlr.StringLiteralContext).AllStringLiteralFragment()
+		texts := []string{}
+		for _, f := range fragments {
+			texts = append(texts, toText(f))
 		}
-
-		values := []string{}
-		for _, g := range expr.(*solAntlr.StringLiteralContext).AllStringLiteralFragment() {
-			text := toText(g)
-
-			isUnicode := strings.HasPrefix(text, "unicode")
-			if isUnicode {
-				text = strings.TrimPrefix(text, "unicode")
-			}
-			// isSingleQuotes := string(text[0]) == "'"
-
-			textWithoutQuotes := text[1 : len(text)-1]
-			value := textWithoutQuotes
-			values = append(values, value)
-
-			decl.Parts = append(decl.Parts, value)
-			decl.IsUnicode = append(decl.IsUnicode, isUnicode)
+		decl, errs := newStringLiteral(texts)
+		decl.Type = "StringLiteral"
+		for i, err := range errs {
+			e.literalError(fragments[i].GetSymbol(), err)
 		}
-		decl.Value = strings.Join(values, "")
 		return decl
 	}
 
@@ -1123,11 +1203,12 @@ func (e *exampleListener) VisitVariableDeclarationStatement(ctx *solAntlr.Variab
 func (e *exampleListener) buildIdentifierList(ctx *solAntlr.IdentifierListContext) []interface{} {
 	count := ctx.GetChildCount()
 	if count < 2 {
-		panic("bad")
+		e.incomplete("bad")
+		return nil
 	}
 
 	childs := ctx.GetChildren()[1 : count-1]
-	childs = mapCommasToNulls(childs)
+	childs = e.mapCommasToNulls(childs)
 
 	identifiers := ctx.AllIdentifier()
 
@@ -1138,21 +1219,27 @@ func (e *exampleListener) buildIdentifierList(ctx *solAntlr.IdentifierListContex
 		if child == nil {
 			variables = append(variables, nil)
 		} else {
+			if indx == len(identifiers) {
+				// an error node of an invalid source
+				break
+			}
 			iden := identifiers[indx]
 			indx++
 
-			variables = append(variables, &VariableDeclaration{
+			varDecl := &VariableDeclaration{
 				Node:       Node{Type: "VariableDeclaration"},
 				Name:       toText(iden),
 				Identifier: e.Visit(iden),
-			})
+			}
+			e.locate(varDecl, iden)
+			variables = append(variables, varDecl)
 		}
 	}
 	return variables
 }
 
 func (e *exampleListener) buildVariableDeclarationList(ctx *solAntlr.VariableDeclarationListContext) []interface{} {
-	childs := mapCommasToNulls(ctx.GetChildren())
+	childs := e.mapCommasToNulls(ctx.GetChildren())
 	variableDeclarations := ctx.AllVariableDeclaration()
 
 	indx := 0
@@ -1162,6 +1249,10 @@ func (e *exampleListener) buildVariableDeclarationList(ctx *solAntlr.VariableDec
 		if child == nil {
 			variables = append(variables, nil)
 		} else {
+			if indx == len(variableDeclarations) {
+				// an error node of an invalid source
+				break
+			}
 			decl := variableDeclarations[indx].(*solAntlr.VariableDeclarationContext)
 			indx++
 
@@ -1172,13 +1263,15 @@ func (e *exampleListener) buildVariableDeclarationList(ctx *solAntlr.VariableDec
 				storageLocation = toText(decl.StorageLocation())
 			}
 
-			variables = append(variables, &VariableDeclaration{
+			varDecl := &VariableDeclaration{
 				Node:            Node{Type: "VariableDeclaration"},
 				Name:            toText(iden),
 				Identifier:      e.Visit(iden),
 				TypeName:        e.Visit(decl.TypeName()),
 				StorageLocation: storageLocation,
-			})
+			}
+			e.locate(varDecl, decl)
+			variables = append(variables, varDecl)
 		}
 	}
 	return variables
@@ -1239,14 +1332,16 @@ func (e *exampleListener) VisitIfStatement(ctx *solAntlr.IfStatementContext) INo
 type EventDefinition struct {
 	Node
 
-	Name       string
-	Parameters []interface{}
+	Name        string
+	Parameters  []interface{}
+	IsAnonymous bool
 }
 
 func (e *exampleListener) VisitEventDefinition(ctx *solAntlr.EventDefinitionContext) INode {
 	decl := &EventDefinition{
-		Name:       toText(ctx.Identifier()),
-		Parameters: []interface{}{},
+		Name:        toText(ctx.Identifier()),
+		Parameters:  []interface{}{},
+		IsAnonymous: ctx.AnonymousKeyword() != nil,
 	}
 
 	paramsList := ctx.EventParameterList().(*solAntlr.EventParameterListContext).AllEventParameter()
@@ -1270,6 +1365,7 @@ func (e *exampleListener) VisitEventDefinition(ctx *solAntlr.EventDefinitionCont
 		if param.IndexedKeyword() != nil {
 			varDecl.IsIndexed = true
 		}
+		e.locate(varDecl, param)
 		decl.Parameters = append(decl.Parameters, varDecl)
 	}
 
@@ -1293,7 +1389,14 @@ type ImportDirective struct {
 type StringLiteral struct {
 	Node
 
-	Value     string
+	// Value is the decoded text of the literal
+	Value string
+
+	// Raw is the text of the literal without the quotes, the escape
+	// sequences are not decoded
+	Raw string
+
+	// Parts are the raw texts of the fragments of the literal (i.e. 'a' "b")
 	Parts     []string
 	IsUnicode []bool
 }
@@ -1355,6 +1458,7 @@ func (e *exampleListener) VisitImportDirective(ctx *solAntlr.ImportDirectiveCont
 		PathLiteral: &StringLiteral{
 			Node:      Node{Type: "StringLiteral"},
 			Value:     path,
+			Raw:       path,
 			Parts:     []string{path},
 			IsUnicode: []bool{false},
 		},
@@ -1446,8 +1550,7 @@ type ModifierDefinition struct {
 
 func (e *exampleListener) VisitModifierDefinition(ctx *solAntlr.ModifierDefinitionContext) INode {
 	decl := &ModifierDefinition{
-		Name: toText(ctx.Identifier()),
-		//Parameters: []interface{}{},
+		Name:      toText(ctx.Identifier()),
 		IsVirtual: false,
 		Override:  []interface{}{},
 	}
@@ -1460,9 +1563,12 @@ func (e *exampleListener) VisitModifierDefinition(ctx *solAntlr.ModifierDefiniti
 	if len(ctx.AllVirtualKeyword()) > 0 {
 		decl.IsVirtual = true
 	}
-	//if param := ctx.ParameterList(); param != nil {
-	// decl.Parameters = e.VisitParameterList(param.(*solAntlr.ParameterListContext))
-	//}
+	if param := ctx.ParameterList(); param != nil {
+		// an empty parameter list is equivalent to no parameter list
+		if params := e.VisitParameterList(param.(*solAntlr.ParameterListContext)); params != nil {
+			decl.Parameters = params
+		}
+	}
 	if body := ctx.Block(); body != nil {
 		decl.Body = e.Visit(body)
 	}
@@ -1584,10 +1690,12 @@ func (e *exampleListener) VisitTypeName(ctx *solAntlr.TypeNameContext) INode {
 	if elem := ctx.FunctionTypeName(); elem != nil {
 		return e.Visit(elem)
 	}
-	panic("TODO")
+	return e.incomplete("TODO")
 }
 
 type InlineAssemblyStatement struct {
+	Node
+
 	Body interface{}
 }
 
@@ -1599,6 +1707,8 @@ func (e *exampleListener) VisitInlineAssemblyStatement(ctx *solAntlr.InlineAssem
 }
 
 type AssemblyBlock struct {
+	Node
+
 	Operations []interface{}
 }
 
@@ -1621,12 +1731,16 @@ func (e *exampleListener) VisitAssemblyExpression(ctx *solAntlr.AssemblyExpressi
 }
 
 type AssemblyCall struct {
-	Arguments []interface{}
+	Node
+
+	FunctionName string
+	Arguments    []interface{}
 }
 
 func (e *exampleListener) VisitAssemblyCall(ctx *solAntlr.AssemblyCallContext) interface{} {
 	decl := &AssemblyCall{
-		Arguments: []interface{}{},
+		FunctionName: ctx.GetStart().GetText(),
+		Arguments:    []interface{}{},
 	}
 	for _, i := range ctx.AllAssemblyExpression() {
 		decl.Arguments = append(decl.Arguments, e.Visit(i))
@@ -1635,6 +1749,7 @@ func (e *exampleListener) VisitAssemblyCall(ctx *solAntlr.AssemblyCallContext) i
 }
 
 type AssemblyLiteral struct {
+	Node
 }
 
 func (e *exampleListener) VisitAssemblyLiteral(ctx *solAntlr.AssemblyLiteralContext) interface{} {
@@ -1643,6 +1758,8 @@ func (e *exampleListener) VisitAssemblyLiteral(ctx *solAntlr.AssemblyLiteralCont
 }
 
 type AssemblySwitch struct {
+	Node
+
 	Expression interface{}
 	Cases      []interface{}
 }
@@ -1659,6 +1776,8 @@ func (e *exampleListener) VisitAssemblySwitch(ctx *solAntlr.AssemblySwitchContex
 }
 
 type AssemblyCase struct {
+	Node
+
 	Block interface{}
 }
 
@@ -1670,6 +1789,8 @@ func (e *exampleListener) VisitAssemblyCase(ctx *solAntlr.AssemblyCaseContext) i
 }
 
 type AssemblyLocalDefinition struct {
+	Node
+
 	Expression interface{}
 }
 
@@ -1682,17 +1803,23 @@ func (e *exampleListener) VisitAssemblyLocalDefinition(ctx *solAntlr.AssemblyLoc
 }
 
 type AssemblyFunctionDefinition struct {
+	Node
+
+	Name string
 	Body interface{}
 }
 
 func (e *exampleListener) VisitAssemblyFunctionDefinition(ctx *solAntlr.AssemblyFunctionDefinitionContext) interface{} {
 	decl := &AssemblyFunctionDefinition{
+		Name: toText(ctx.Identifier()),
 		Body: e.Visit(ctx.AssemblyBlock()),
 	}
 	return decl
 }
 
 type AssemblyAssignment struct {
+	Node
+
 	Expression interface{}
 }
 
@@ -1704,6 +1831,8 @@ func (e *exampleListener) VisitAssemblyAssignment(ctx *solAntlr.AssemblyAssignme
 }
 
 type AssemblyFor struct {
+	Node
+
 	Pre       interface{}
 	Condition interface{}
 	Post      interface{}
@@ -1721,6 +1850,8 @@ func (e *exampleListener) VisitAssemblyFor(ctx *solAntlr.AssemblyForContext) int
 }
 
 type AssemblyIf struct {
+	Node
+
 	Condition interface{}
 	Body      interface{}
 }
@@ -1734,6 +1865,8 @@ func (e *exampleListener) VisitAssemblyIf(ctx *solAntlr.AssemblyIfContext) inter
 }
 
 type AssemblyMember struct {
+	Node
+
 	Expression interface{}
 	MemberName interface{}
 }
@@ -1785,7 +1918,10 @@ func (e *exampleListener) VisitFunctionCall(ctx *solAntlr.FunctionCallContext) I
 		Names:       []interface{}{},
 	}
 
-	ctxArgs := ctx.FunctionCallArguments().(*solAntlr.FunctionCallArgumentsContext)
+	ctxArgs, ok := ctx.FunctionCallArguments().(*solAntlr.FunctionCallArgumentsContext)
+	if !ok {
+		return e.incomplete("function call without arguments")
+	}
 	ctxArgsExpr := ctxArgs.ExpressionList()
 	ctxArgsName := ctxArgs.NameValueList()
 
@@ -1863,7 +1999,7 @@ func (e *exampleListener) VisitCatchClause(ctx *solAntlr.CatchClauseContext) INo
 	if iden := ctx.Identifier(); iden != nil {
 		kind = toText(iden)
 		if kind != "Error" && kind != "Panic" {
-			panic("Expected 'Error' or 'Panic'")
+			return e.incomplete("Expected 'Error' or 'Panic'")
 		}
 	}
 
@@ -1978,11 +2114,25 @@ func (e *exampleListener) VisitRevertStatement(ctx *solAntlr.RevertStatementCont
 	return decl
 }
 
+// incomplete is the node of a context that misses the children the AST needs.
+// The contexts of an invalid source can miss them, the node is not built and
+// the syntax errors report it. It panics for a valid source.
+func (e *exampleListener) incomplete(msg string) INode {
+	if e.invalid {
+		return nil
+	}
+	panic(msg)
+}
+
 type antlrToText interface {
 	GetText() string
 }
 
 func toText(i interface{}) string {
+	if i == nil {
+		// a token missing in an invalid source
+		return ""
+	}
 	if obj, ok := i.(antlrToText); ok {
 		return obj.GetText()
 	}
@@ -1990,8 +2140,13 @@ func toText(i interface{}) string {
 }
 
 type Parser struct {
+	// Result is the AST. The nodes that the syntax errors break are missing
+	// from the AST of an invalid source.
 	Result INode
 	Errors []*SyntaxError
+
+	// CST is the concrete syntax tree, it is only built with WithCST
+	CST *SyntaxNode
 }
 
 func (p *Parser) Json() (string, error) {
@@ -2002,29 +2157,63 @@ func (p *Parser) Json() (string, error) {
 	return string(data), nil
 }
 
-func Parse(s string) *Parser {
+// Parse parses a Solidity source. The syntax errors of the lexer and the
+// parser are returned in Errors, they are not printed.
+func Parse(s string, opts ...Option) *Parser {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.backend == TreeSitter {
+		return parseTreeSitter(s, cfg)
+	}
+
 	// Setup the input
 	is := antlr.NewInputStream(s)
 
+	// the errors of the lexer and the parser are collected instead of printed
+	parserErrors := &CustomErrorListener{src: newSourceMap(s)}
+
 	// Create the Lexer
 	lexer := solAntlr.NewSolidityLexer(is)
-	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(parserErrors)
+	var source antlr.Lexer = lexer
+	if cfg.cst {
+		// the whitespace is a token of the concrete syntax tree
+		source = &keepLexer{SolidityLexer: lexer}
+	}
+	stream := antlr.NewCommonTokenStream(source, antlr.TokenDefaultChannel)
 
 	// Create the Parser
-	parserErrors := &CustomErrorListener{}
 	p := solAntlr.NewSolidityParser(stream)
 	p.BuildParseTrees = true
+	p.RemoveErrorListeners()
 	p.AddErrorListener(parserErrors)
 
 	tree := p.SourceUnit()
-	lis := &exampleListener{}
+	lis := &exampleListener{
+		errSrc: parserErrors.src,
+	}
 	lis.init()
+	if cfg.locations {
+		lis.src = newSourceMap(s)
+	}
 
+	lis.invalid = len(parserErrors.Errors) != 0
 	result := lis.Visit(tree)
 
 	pp := &Parser{
 		Result: result,
-		Errors: parserErrors.Errors,
+		Errors: sortErrors(append(parserErrors.Errors, lis.errors...)),
+	}
+	if cfg.cst {
+		b := &cstBuilder{
+			tokens:    stream,
+			ruleNames: p.GetRuleNames(),
+			srcMap:    newSourceMap(s),
+		}
+		pp.CST = b.build(tree)
 	}
 	return pp
 }
@@ -2032,21 +2221,47 @@ func Parse(s string) *Parser {
 type SyntaxError struct {
 	line, column int
 	msg          string
+	loc          *Location
+	fixes        []*SuggestedFix
 }
 
 func (c *SyntaxError) Error() string {
 	return c.msg
 }
 
+// Loc returns the location of the token that caused the error
+func (c *SyntaxError) Loc() *Location {
+	return c.loc
+}
+
+// Fixes returns the suggested fixes of the error (i.e. insert a missing ';')
+func (c *SyntaxError) Fixes() []*SuggestedFix {
+	return c.fixes
+}
+
 type CustomErrorListener struct {
 	*antlr.DefaultErrorListener
 	Errors []*SyntaxError
+
+	src *sourceMap
 }
 
 func (c *CustomErrorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
-	c.Errors = append(c.Errors, &SyntaxError{
+	err := &SyntaxError{
 		line:   line,
 		column: column,
 		msg:    msg,
-	})
+	}
+	if c.src != nil {
+		if token, ok := offendingSymbol.(antlr.Token); ok {
+			err.loc = c.src.tokenLoc(token, token)
+		} else {
+			pos := c.src.linePosition(line, column)
+			err.loc = &Location{Start: pos, End: pos}
+		}
+	}
+	if fix := syntaxFix(c.src, recognizer, msg); fix != nil {
+		err.fixes = []*SuggestedFix{fix}
+	}
+	c.Errors = append(c.Errors, err)
 }
@@ -2,6 +2,9 @@ package solcparser
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -12,6 +15,11 @@ type parserCase []struct {
 }
 
 func TestParser(t *testing.T) {
+	testSolidityCase(t, parserCases(t))
+}
+
+// parserCases parses the corpus of the parser tests
+func parserCases(t *testing.T) parserCase {
 	cases := parserCase{
 		{
 			parseNode(t, "enum Hello { A, B, C }"),
@@ -126,6 +134,61 @@ func TestParser(t *testing.T) {
 				Kind: "contract",
 			},
 		},
+		{
+			parseContract(t, "contract test is foo(1) {}"),
+			&ContractDefinition{
+				Node:     Node{Type: "ContractDefinition"},
+				Name:     "test",
+				SubNodes: []interface{}{},
+				BaseContracts: []interface{}{
+					&InheritanceSpecifier{
+						Node: Node{Type: "InheritanceSpecifier"},
+						BaseName: &UserDefinedTypeName{
+							Node:     Node{Type: "UserDefinedTypeName"},
+							NamePath: "foo",
+						},
+						Arguments: []interface{}{
+							&NumberLiteral{
+								Node:   Node{Type: "NumberLiteral"},
+								Number: "1",
+								Value:  big.NewRat(1, 1),
+							},
+						},
+					},
+				},
+				Kind: "contract",
+			},
+		},
+		{
+			// each argument of the base constructor is an expression
+			parseContract(t, "contract test is foo(1, a) {}"),
+			&ContractDefinition{
+				Node:     Node{Type: "ContractDefinition"},
+				Name:     "test",
+				SubNodes: []interface{}{},
+				BaseContracts: []interface{}{
+					&InheritanceSpecifier{
+						Node: Node{Type: "InheritanceSpecifier"},
+						BaseName: &UserDefinedTypeName{
+							Node:     Node{Type: "UserDefinedTypeName"},
+							NamePath: "foo",
+						},
+						Arguments: []interface{}{
+							&NumberLiteral{
+								Node:   Node{Type: "NumberLiteral"},
+								Number: "1",
+								Value:  big.NewRat(1, 1),
+							},
+							&Identifier{
+								Node: Node{Type: "Identifier"},
+								Name: "a",
+							},
+						},
+					},
+				},
+				Kind: "contract",
+			},
+		},
 		{
 			parseContract(t, "library test {}"),
 			&ContractDefinition{
@@ -146,6 +209,17 @@ func TestParser(t *testing.T) {
 				Kind:          "interface",
 			},
 		},
+		{
+			parseContract(t, "abstract contract test {}"),
+			&ContractDefinition{
+				Node:          Node{Type: "ContractDefinition"},
+				Name:          "test",
+				SubNodes:      []interface{}{},
+				BaseContracts: []interface{}{},
+				Kind:          "contract",
+				IsAbstract:    true,
+			},
+		},
 
 		{
 			parseStatement(t, "return;"),
@@ -161,6 +235,7 @@ func TestParser(t *testing.T) {
 				Expression: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "2",
+					Value:  big.NewRat(2, 1),
 				},
 			},
 		},
@@ -336,6 +411,32 @@ func TestParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			parseNode(t, "modifier onlyRole(bytes32 role) {}"),
+			&ModifierDefinition{
+				Node: Node{Type: "ModifierDefinition"},
+				Name: "onlyRole",
+				Parameters: []interface{}{
+					&VariableDeclaration{
+						Node: Node{Type: "VariableDeclaration"},
+						Name: "role",
+						TypeName: &ElementaryTypeName{
+							Node: Node{Type: "ElementaryTypeName"},
+							Name: "bytes32",
+						},
+						Identifier: &Identifier{
+							Node: Node{Type: "Identifier"},
+							Name: "role",
+						},
+					},
+				},
+				Body: &Block{
+					Node:       Node{Type: "Block"},
+					Statements: []interface{}{},
+				},
+				Override: []interface{}{},
+			},
+		},
 
 		// unchecked blocks
 
@@ -368,6 +469,7 @@ func TestParser(t *testing.T) {
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "3",
+							Value:  big.NewRat(3, 1),
 						},
 					},
 					Expression: &Identifier{
@@ -388,6 +490,7 @@ func TestParser(t *testing.T) {
 				PathLiteral: &StringLiteral{
 					Node:      Node{Type: "StringLiteral"},
 					Value:     "./abc.sol",
+					Raw:       "./abc.sol",
 					Parts:     []string{"./abc.sol"},
 					IsUnicode: []bool{false},
 				},
@@ -401,6 +504,7 @@ func TestParser(t *testing.T) {
 				PathLiteral: &StringLiteral{
 					Node:      Node{Type: "StringLiteral"},
 					Value:     "./abc.sol",
+					Raw:       "./abc.sol",
 					Parts:     []string{"./abc.sol"},
 					IsUnicode: []bool{false},
 				},
@@ -419,6 +523,7 @@ func TestParser(t *testing.T) {
 				PathLiteral: &StringLiteral{
 					Node:      Node{Type: "StringLiteral"},
 					Value:     "./abc.sol",
+					Raw:       "./abc.sol",
 					Parts:     []string{"./abc.sol"},
 					IsUnicode: []bool{false},
 				},
@@ -437,6 +542,7 @@ func TestParser(t *testing.T) {
 				PathLiteral: &StringLiteral{
 					Node:      Node{Type: "StringLiteral"},
 					Value:     "./abc.sol",
+					Raw:       "./abc.sol",
 					Parts:     []string{"./abc.sol"},
 					IsUnicode: []bool{false},
 				},
@@ -544,7 +650,8 @@ func TestParser(t *testing.T) {
 						Right: &NumberLiteral{
 							Node:            Node{Type: "NumberLiteral"},
 							Number:          "0",
-							SubDenomination: nil,
+							SubDenomination: NoSubDenomination,
+							Value:           big.NewRat(0, 1),
 						},
 					},
 				},
@@ -558,7 +665,8 @@ func TestParser(t *testing.T) {
 					Right: &NumberLiteral{
 						Node:            Node{Type: "NumberLiteral"},
 						Number:          "10",
-						SubDenomination: nil,
+						SubDenomination: NoSubDenomination,
+						Value:           big.NewRat(10, 1),
 					},
 				},
 				LoopExpression: &ExpressionStatement{
@@ -690,10 +798,11 @@ func TestParser(t *testing.T) {
 					Node:       Node{Type: "Block"},
 					Statements: []interface{}{},
 				},
-				IsFallback: true,
-				Modifiers:  []interface{}{},
-				IsVirtual:  true,
-				Visibility: "external",
+				IsFallback:      true,
+				Modifiers:       []interface{}{},
+				IsVirtual:       true,
+				Visibility:      "external",
+				StateMutability: "payable",
 			},
 		},
 
@@ -706,10 +815,11 @@ func TestParser(t *testing.T) {
 					Node:       Node{Type: "Block"},
 					Statements: []interface{}{},
 				},
-				IsReceiveEther: true,
-				Modifiers:      []interface{}{},
-				IsVirtual:      true,
-				Visibility:     "external",
+				IsReceiveEther:  true,
+				Modifiers:       []interface{}{},
+				IsVirtual:       true,
+				Visibility:      "external",
+				StateMutability: "payable",
 			},
 		},
 
@@ -737,9 +847,10 @@ func TestParser(t *testing.T) {
 					Node:       Node{Type: "Block"},
 					Statements: []interface{}{},
 				},
-				Modifiers:      []interface{}{},
-				IsReceiveEther: true,
-				Visibility:     "external",
+				Modifiers:       []interface{}{},
+				IsReceiveEther:  true,
+				Visibility:      "external",
+				StateMutability: "payable",
 			},
 		},
 
@@ -755,6 +866,7 @@ func TestParser(t *testing.T) {
 				},
 				Modifiers:  []interface{}{},
 				Visibility: "public",
+				IsOverride: true,
 			},
 		},
 
@@ -769,6 +881,7 @@ func TestParser(t *testing.T) {
 					Statements: []interface{}{},
 				},
 				Visibility: "public",
+				IsOverride: true,
 				Modifiers:  []interface{}{},
 				Override: []interface{}{
 					&UserDefinedTypeName{
@@ -790,6 +903,7 @@ func TestParser(t *testing.T) {
 					Statements: []interface{}{},
 				},
 				Visibility: "public",
+				IsOverride: true,
 				Modifiers:  []interface{}{},
 				Override: []interface{}{
 					&UserDefinedTypeName{
@@ -827,8 +941,9 @@ func TestParser(t *testing.T) {
 					Node:       Node{Type: "Block"},
 					Statements: []interface{}{},
 				},
-				Visibility: "default",
-				Modifiers:  []interface{}{},
+				Visibility:      "default",
+				Modifiers:       []interface{}{},
+				StateMutability: "pure",
 			},
 		},
 
@@ -881,6 +996,115 @@ func TestParser(t *testing.T) {
 					Node:       Node{Type: "Block"},
 					Statements: []interface{}{},
 				},
+				Visibility:      "default",
+				Modifiers:       []interface{}{},
+				StateMutability: "pure",
+			},
+		},
+
+		{
+			parseNode(t, "function foo() external view returns (uint256);"),
+			&FunctionDefinition{
+				Node: Node{Type: "FunctionDefinition"},
+				Name: "foo",
+				ReturnParameters: []interface{}{
+					&VariableDeclaration{
+						Node: Node{Type: "VariableDeclaration"},
+						TypeName: &ElementaryTypeName{
+							Node: Node{Type: "ElementaryTypeName"},
+							Name: "uint256",
+						},
+					},
+				},
+				Visibility:      "external",
+				Modifiers:       []interface{}{},
+				StateMutability: "view",
+			},
+		},
+
+		{
+			parseNode(t, "function foo() public constant {}"),
+			&FunctionDefinition{
+				Node: Node{Type: "FunctionDefinition"},
+				Name: "foo",
+				Body: &Block{
+					Node:       Node{Type: "Block"},
+					Statements: []interface{}{},
+				},
+				Visibility:      "public",
+				Modifiers:       []interface{}{},
+				StateMutability: "constant",
+			},
+		},
+
+		{
+			parseNode(t, "fallback (bytes calldata input) external returns (bytes memory output) {}"),
+			&FunctionDefinition{
+				Node: Node{Type: "FunctionDefinition"},
+				Parameters: []interface{}{
+					&VariableDeclaration{
+						Node: Node{Type: "VariableDeclaration"},
+						Name: "input",
+						TypeName: &ElementaryTypeName{
+							Node: Node{Type: "ElementaryTypeName"},
+							Name: "bytes",
+						},
+						Identifier: &Identifier{
+							Node: Node{Type: "Identifier"},
+							Name: "input",
+						},
+						StorageLocation: "calldata",
+					},
+				},
+				ReturnParameters: []interface{}{
+					&VariableDeclaration{
+						Node: Node{Type: "VariableDeclaration"},
+						Name: "output",
+						TypeName: &ElementaryTypeName{
+							Node: Node{Type: "ElementaryTypeName"},
+							Name: "bytes",
+						},
+						Identifier: &Identifier{
+							Node: Node{Type: "Identifier"},
+							Name: "output",
+						},
+						StorageLocation: "memory",
+					},
+				},
+				Body: &Block{
+					Node:       Node{Type: "Block"},
+					Statements: []interface{}{},
+				},
+				IsFallback: true,
+				Modifiers:  []interface{}{},
+				Visibility: "external",
+			},
+		},
+
+		{
+			parseNode(t, "function foo(address payable a) {}"),
+			&FunctionDefinition{
+				Node: Node{Type: "FunctionDefinition"},
+				Name: "foo",
+				Parameters: []interface{}{
+					&VariableDeclaration{
+						Node: Node{Type: "VariableDeclaration"},
+						Name: "a",
+						TypeName: &ElementaryTypeName{
+							Node:            Node{Type: "ElementaryTypeName"},
+							Name:            "address",
+							StateMutability: "payable",
+						},
+						Identifier: &Identifier{
+							Node: Node{Type: "Identifier"},
+							Name: "a",
+						},
+					},
+				},
+				Body: &Block{
+					Node:       Node{Type: "Block"},
+					Statements: []interface{}{},
+				},
 				Visibility: "default",
 				Modifiers:  []interface{}{},
 			},
@@ -924,10 +1148,12 @@ func TestParser(t *testing.T) {
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "1",
+							Value:  big.NewRat(1, 1),
 						},
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "2",
+							Value:  big.NewRat(2, 1),
 						},
 					},
 				},
@@ -990,10 +1216,12 @@ func TestParser(t *testing.T) {
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "1",
+							Value:  big.NewRat(1, 1),
 						},
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "2",
+							Value:  big.NewRat(2, 1),
 						},
 					},
 				},
@@ -1081,10 +1309,12 @@ func TestParser(t *testing.T) {
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "1",
+							Value:  big.NewRat(1, 1),
 						},
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "2",
+							Value:  big.NewRat(2, 1),
 						},
 					},
 				},
@@ -1170,10 +1400,12 @@ func TestParser(t *testing.T) {
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "1",
+							Value:  big.NewRat(1, 1),
 						},
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "2",
+							Value:  big.NewRat(2, 1),
 						},
 					},
 				},
@@ -1269,6 +1501,33 @@ func TestParser(t *testing.T) {
 			},
 		},
 
+		{
+			parseStatement(t, "try f() {} catch {}"),
+			&TryStatement{
+				Node: Node{Type: "TryStatement"},
+				Expression: &FunctionCall{
+					Node: Node{Type: "FunctionCall"},
+					Expression: &Identifier{
+						Node: Node{Type: "Identifier"},
+						Name: "f",
+					},
+				},
+				Body: &Block{
+					Node:       Node{Type: "Block"},
+					Statements: []interface{}{},
+				},
+				CatchClause: []interface{}{
+					&CatchClause{
+						Node: Node{Type: "CatchClause"},
+						Body: &Block{
+							Node:       Node{Type: "Block"},
+							Statements: []interface{}{},
+						},
+					},
+				},
+			},
+		},
+
 		// expressions
 
 		{
@@ -1293,6 +1552,7 @@ func TestParser(t *testing.T) {
 				IndexStart: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "3",
+					Value:  big.NewRat(3, 1),
 				},
 			},
 		},
@@ -1308,6 +1568,7 @@ func TestParser(t *testing.T) {
 				IndexEnd: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "20",
+					Value:  big.NewRat(20, 1),
 				},
 			},
 		},
@@ -1323,10 +1584,12 @@ func TestParser(t *testing.T) {
 				IndexStart: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "0",
+					Value:  big.NewRat(0, 1),
 				},
 				IndexEnd: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "4",
+					Value:  big.NewRat(4, 1),
 				},
 			},
 		},
@@ -1347,7 +1610,8 @@ func TestParser(t *testing.T) {
 					Right: &NumberLiteral{
 						Node:            Node{Type: "NumberLiteral"},
 						Number:          "1",
-						SubDenomination: "wei",
+						SubDenomination: Wei,
+						Value:           big.NewRat(1, 1),
 					},
 				},
 			},
@@ -1366,7 +1630,8 @@ func TestParser(t *testing.T) {
 					Right: &NumberLiteral{
 						Node:            Node{Type: "NumberLiteral"},
 						Number:          "1",
-						SubDenomination: "gwei",
+						SubDenomination: Gwei,
+						Value:           big.NewRat(1000000000, 1),
 					},
 				},
 			},
@@ -1385,7 +1650,8 @@ func TestParser(t *testing.T) {
 					Right: &NumberLiteral{
 						Node:            Node{Type: "NumberLiteral"},
 						Number:          "1",
-						SubDenomination: "seconds",
+						SubDenomination: Seconds,
+						Value:           big.NewRat(1, 1),
 					},
 				},
 			},
@@ -1428,6 +1694,29 @@ func TestParser(t *testing.T) {
 			},
 		},
 
+		{
+			parseNode(t, "event Foo(uint a) anonymous;"),
+			&EventDefinition{
+				Node: Node{Type: "EventDefinition"},
+				Name: "Foo",
+				Parameters: []interface{}{
+					&VariableDeclaration{
+						Node: Node{Type: "VariableDeclaration"},
+						Name: "a",
+						TypeName: &ElementaryTypeName{
+							Node: Node{Type: "ElementaryTypeName"},
+							Name: "uint",
+						},
+						Identifier: &Identifier{
+							Node: Node{Type: "Identifier"},
+							Name: "a",
+						},
+					},
+				},
+				IsAnonymous: true,
+			},
+		},
+
 		// throw
 
 		{
@@ -1563,6 +1852,7 @@ func TestParser(t *testing.T) {
 				InitialValue: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "10",
+					Value:  big.NewRat(10, 1),
 				},
 				Name: "EXPONENT",
 				TypeName: &ElementaryTypeName{
@@ -1580,6 +1870,7 @@ func TestParser(t *testing.T) {
 				InitialValue: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "0",
+					Value:  big.NewRat(0, 1),
 				},
 				Variables: []interface{}{
 					&VariableDeclaration{
@@ -1610,6 +1901,7 @@ func TestParser(t *testing.T) {
 				InitialValue: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "0",
+					Value:  big.NewRat(0, 1),
 				},
 				Variables: []interface{}{
 					&VariableDeclaration{
@@ -1756,6 +2048,7 @@ func TestParser(t *testing.T) {
 				Length: &NumberLiteral{
 					Node:   Node{Type: "NumberLiteral"},
 					Number: "2",
+					Value:  big.NewRat(2, 1),
 				},
 			},
 		},
@@ -1844,6 +2137,44 @@ func TestParser(t *testing.T) {
 			},
 		},
 
+		{
+			parseNode(t, "function (uint) external payable a;").(*StateVariableDeclaration).Variables[0].(*StateVariableDeclarationVariable).TypeName,
+			&FunctionTypeName{
+				Node: Node{Type: "FunctionTypeName"},
+				ParameterTypes: []interface{}{
+					&VariableDeclaration{
+						Node: Node{Type: "VariableDeclaration"},
+						TypeName: &ElementaryTypeName{
+							Node: Node{Type: "ElementaryTypeName"},
+							Name: "uint",
+						},
+					},
+				},
+				ReturnTypes:     []interface{}{},
+				Visibility:      "external",
+				StateMutability: "payable",
+			},
+		},
+
+		{
+			parseNode(t, "function () internal view returns (uint) a;").(*StateVariableDeclaration).Variables[0].(*StateVariableDeclarationVariable).TypeName,
+			&FunctionTypeName{
+				Node:           Node{Type: "FunctionTypeName"},
+				ParameterTypes: []interface{}{},
+				ReturnTypes: []interface{}{
+					&VariableDeclaration{
+						Node: Node{Type: "VariableDeclaration"},
+						TypeName: &ElementaryTypeName{
+							Node: Node{Type: "ElementaryTypeName"},
+							Name: "uint",
+						},
+					},
+				},
+				Visibility:      "internal",
+				StateMutability: "view",
+			},
+		},
+
 		{
 			parseStatement(t, "emit EventCalled(1);"),
 			&EmitStatement{
@@ -1854,6 +2185,7 @@ func TestParser(t *testing.T) {
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "1",
+							Value:  big.NewRat(1, 1),
 						},
 					},
 					Expression: &Identifier{
@@ -1875,6 +2207,7 @@ func TestParser(t *testing.T) {
 						&NumberLiteral{
 							Node:   Node{Type: "NumberLiteral"},
 							Number: "1",
+							Value:  big.NewRat(1, 1),
 						},
 					},
 					Names: []interface{}{"x"},
@@ -2023,7 +2356,8 @@ func TestParser(t *testing.T) {
 			&NumberLiteral{
 				Node:            Node{Type: "NumberLiteral"},
 				Number:          "2",
-				SubDenomination: "ether",
+				SubDenomination: Ether,
+				Value:           big.NewRat(2000000000000000000, 1),
 			},
 		},
 		{
@@ -2031,6 +2365,7 @@ func TestParser(t *testing.T) {
 			&NumberLiteral{
 				Node:   Node{Type: "NumberLiteral"},
 				Number: "2.3e5",
+				Value:  big.NewRat(230000, 1),
 			},
 		},
 		{
@@ -2038,6 +2373,7 @@ func TestParser(t *testing.T) {
 			&NumberLiteral{
 				Node:   Node{Type: "NumberLiteral"},
 				Number: ".1",
+				Value:  big.NewRat(1, 10),
 			},
 		},
 		{
@@ -2045,6 +2381,7 @@ func TestParser(t *testing.T) {
 			&NumberLiteral{
 				Node:   Node{Type: "NumberLiteral"},
 				Number: "1_000_000",
+				Value:  big.NewRat(1000000, 1),
 			},
 		},
 
@@ -2055,6 +2392,7 @@ func TestParser(t *testing.T) {
 			&StringLiteral{
 				Node:      Node{Type: "StringLiteral"},
 				Value:     "Hello",
+				Raw:       "Hello",
 				Parts:     []string{"Hello"},
 				IsUnicode: []bool{false},
 			},
@@ -2064,6 +2402,7 @@ func TestParser(t *testing.T) {
 			&StringLiteral{
 				Node:      Node{Type: "StringLiteral"},
 				Value:     "Hello",
+				Raw:       "Hello",
 				Parts:     []string{"Hello"},
 				IsUnicode: []bool{false},
 			},
@@ -2075,6 +2414,7 @@ func TestParser(t *testing.T) {
 				Node:  Node{Type: "HexLiteral"},
 				Value: "fafafa",
 				Parts: []string{"fafafa"},
+				Data:  []byte{0xfa, 0xfa, 0xfa},
 			},
 		},
 
@@ -2084,6 +2424,7 @@ func TestParser(t *testing.T) {
 				Node:  Node{Type: "HexLiteral"},
 				Value: "",
 				Parts: []string{""},
+				Data:  []byte{},
 			},
 		},
 
@@ -2184,10 +2525,12 @@ func TestParser(t *testing.T) {
 					&NumberLiteral{
 						Node:   Node{Type: "NumberLiteral"},
 						Number: "1",
+						Value:  big.NewRat(1, 1),
 					},
 					&NumberLiteral{
 						Node:   Node{Type: "NumberLiteral"},
 						Number: "2",
+						Value:  big.NewRat(2, 1),
 					},
 				},
 			},
@@ -2233,6 +2576,7 @@ func TestParser(t *testing.T) {
 							&NumberLiteral{
 								Node:   Node{Type: "NumberLiteral"},
 								Number: "10",
+								Value:  big.NewRat(10, 1),
 							},
 						},
 					},
@@ -2241,10 +2585,12 @@ func TestParser(t *testing.T) {
 					&NumberLiteral{
 						Node:   Node{Type: "NumberLiteral"},
 						Number: "1",
+						Value:  big.NewRat(1, 1),
 					},
 					&NumberLiteral{
 						Node:   Node{Type: "NumberLiteral"},
 						Number: "2",
+						Value:  big.NewRat(2, 1),
 					},
 				},
 			},
@@ -2262,10 +2608,12 @@ func TestParser(t *testing.T) {
 					&NumberLiteral{
 						Node:   Node{Type: "NumberLiteral"},
 						Number: "1",
+						Value:  big.NewRat(1, 1),
 					},
 					&NumberLiteral{
 						Node:   Node{Type: "NumberLiteral"},
 						Number: "2",
+						Value:  big.NewRat(2, 1),
 					},
 				},
 				Names: []interface{}{"x", "y"},
@@ -2368,9 +2716,57 @@ func TestParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			parseStatement(t, "assembly { function inc(a) -> b { b := add(a, 1) } let x := inc(1) }"),
+			&InlineAssemblyStatement{
+				Node: Node{Type: "InlineAssemblyStatement"},
+				Body: &AssemblyBlock{
+					Node: Node{Type: "AssemblyBlock"},
+					Operations: []interface{}{
+						&AssemblyFunctionDefinition{
+							Node: Node{Type: "AssemblyFunctionDefinition"},
+							Name: "inc",
+							Body: &AssemblyBlock{
+								Node: Node{Type: "AssemblyBlock"},
+								Operations: []interface{}{
+									&AssemblyAssignment{
+										Node: Node{Type: "AssemblyAssignment"},
+										Expression: &AssemblyCall{
+											Node:         Node{Type: "AssemblyCall"},
+											FunctionName: "add",
+											Arguments: []interface{}{
+												&AssemblyCall{
+													Node:         Node{Type: "AssemblyCall"},
+													FunctionName: "a",
+													Arguments:    []interface{}{},
+												},
+												&AssemblyLiteral{
+													Node: Node{Type: "AssemblyLiteral"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						&AssemblyLocalDefinition{
+							Node: Node{Type: "AssemblyLocalDefinition"},
+							Expression: &AssemblyCall{
+								Node:         Node{Type: "AssemblyCall"},
+								FunctionName: "inc",
+								Arguments: []interface{}{
+									&AssemblyLiteral{
+										Node: Node{Type: "AssemblyLiteral"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
-
-	testSolidityCase(t, cases)
+	return cases
 }
 
 func testSolidityCase(t *testing.T, cases parserCase) {
@@ -2393,7 +2789,11 @@ func testSolidityCase(t *testing.T, cases parserCase) {
 	}
 }
 
+// corpus are the sources parsed by the helpers
+var corpus []string
+
 func parse(t *testing.T, source string) interface{} {
+	corpus = append(corpus, source)
 	p := Parse(source)
 	return p.Result
 }
@@ -2416,3 +2816,147 @@ func parseExpression(t *testing.T, source string) interface{} {
 	p := parseNode(t, "function () { "+source+"; }")
 	return p.(*FunctionDefinition).Body.(*Block).Statements[0].(*ExpressionStatement).Expression
 }
+
+// treeSitterUnsupported are the sources of the corpus that the bundled
+// tree-sitter grammar does not parse like the antlr one
+var treeSitterUnsupported = map[string]string{
+	"contract test { function () { do {} while (true); } }":                                       "do-while requires no semicolon",
+	"contract test { function () { revert MyCustomError(); } }":                                   "revert statement",
+	"contract test { function () { revert MyCustomError(3); } }":                                  "revert statement",
+	"contract test { fallback (bytes calldata input) external returns (bytes memory output) {} }": "fallback with parameters",
+	"contract test { function () { a[:]; } }":                                                     "slice without bounds",
+	"contract test { function () { a[3:]; } }":                                                    "slice without end",
+	"contract test { function () { a[:20]; } }":                                                   "slice without start",
+	"contract test { function () { (uint a,, uint b) = 0; } }":                                    "empty tuple declaration component",
+	"contract test { function (uint, uint) returns(bool) a; }":                                    "function type variable",
+	"contract test { function (uint) external payable a; }":                                       "function type variable",
+	"contract test { function () internal view returns (uint) a; }":                               "function type variable parsed as a fallback",
+	"contract test { error MyCustomError(); }":                                                    "custom error",
+	"contract test { error MyCustomError(uint a); }":                                              "custom error",
+	"contract test { error MyCustomError(string); }":                                              "custom error",
+	"contract test { function () { 1_000_000; } }":                                                "number with underscores",
+	"contract test { type Price is uint128; }":                                                    "user defined value type",
+	"contract test { function () { unchecked { } } }":                                             "unchecked block",
+	"contract test { function () { unchecked { x++; } } }":                                        "unchecked block",
+}
+
+func TestTreeSitterBackend(t *testing.T) {
+	corpus = []string{
+		"contract A {\n  uint a;\n  function foo() public { /* ü */ a = 1; }\n}",
+	}
+	parserCases(t)
+
+	for _, source := range corpus {
+		for _, opts := range [][]Option{nil, {WithLocations()}} {
+			expected := Parse(source, opts...)
+			found := Parse(source, append(opts, WithBackend(TreeSitter))...)
+
+			// both backends report the syntax errors of the invalid sources
+			equal := len(found.Errors) == len(expected.Errors) && reflect.DeepEqual(expected.Result, found.Result)
+			if reason, ok := treeSitterUnsupported[source]; ok {
+				if equal {
+					t.Fatalf("unsupported source '%s' (%s) is parsed now", source, reason)
+				}
+				continue
+			}
+			if !equal {
+				for _, err := range found.Errors {
+					t.Log(err)
+				}
+				expectedData, _ := json.Marshal(expected.Result)
+				foundData, _ := json.Marshal(found.Result)
+				t.Fatalf("source '%s'\nantlr:       %s\ntree-sitter: %s", source, expectedData, foundData)
+			}
+		}
+	}
+}
+
+func TestTreeSitterBackendErrors(t *testing.T) {
+	p := Parse("contract A {\n  function f() { uint x = ; }\n}", WithBackend(TreeSitter))
+	if len(p.Errors) == 0 {
+		t.Fatal("syntax error expected")
+	}
+	if loc := p.Errors[0].Loc(); loc == nil || loc.Start.Line != 2 {
+		t.Fatalf("bad error location %v", loc)
+	}
+}
+
+func TestParseDoesNotPrintErrors(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() {
+		os.Stderr = stderr
+	}()
+
+	// a parser error and a lexer error
+	p := Parse("contract A { uint x = 1 # 2 }")
+	w.Close()
+	os.Stderr = stderr
+
+	output, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output) != 0 {
+		t.Fatalf("unexpected output: %s", output)
+	}
+	if len(p.Errors) != 2 {
+		t.Fatalf("two errors expected but found %v", p.Errors)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	// the trees of these sources miss nodes the AST needs, the AST is built
+	// without them and the errors are reported
+	cases := []string{
+		"contract A {\n  uint é = ;\n}",
+		"contract A { function f() public { x = ; } }",
+		"contract A { function f() public { for (;;) } }",
+		"contract A { mapping( => uint) m; }",
+		"contract A { struct S { uint } }",
+		"contract A { modifier m( { _; } }",
+		"import ",
+		"abstract",
+		"contract A { function f() public { revert E()",
+		"contract A { function f() public { try f(1, 2 returns (uint a) {} catch (bytes memory a) {} } }",
+		"contract A { function f() public { try f(1, 2) returns (uint a) {} catch E",
+		"contract A { function f() public { a[",
+		"contract A { function f() public { assembly { function inc(a) -> b { b :",
+	}
+	for _, c := range cases {
+		for _, opts := range [][]Option{nil, {WithLocations()}} {
+			p := Parse(c, opts...)
+			if _, ok := p.Result.(*SourceUnit); !ok {
+				t.Fatalf("%q: AST expected", c)
+			}
+			if len(p.Errors) == 0 {
+				t.Fatalf("%q: errors expected", c)
+			}
+		}
+	}
+
+	// the declarations without errors are kept
+	p := Parse("contract A { uint a; function f() public { x = ; } function g() public {} }")
+	if len(p.Errors) == 0 {
+		t.Fatal("errors expected")
+	}
+	contract := p.Result.(*SourceUnit).Children[0].(*ContractDefinition)
+	if contract.Name != "A" || len(contract.SubNodes) != 3 {
+		t.Fatalf("bad contract %v", contract)
+	}
+	if _, ok := contract.SubNodes[0].(*StateVariableDeclaration); !ok {
+		t.Fatalf("state variable expected but found %T", contract.SubNodes[0])
+	}
+	if fn, ok := contract.SubNodes[2].(*FunctionDefinition); !ok || fn.Name != "g" {
+		t.Fatalf("function g expected but found %v", contract.SubNodes[2])
+	}
+
+	// the sources with errors that do not break the tree keep the AST
+	if p := Parse("contract A { function f( }"); p.Result == nil || len(p.Errors) == 0 {
+		t.Fatal("the AST and the errors expected")
+	}
+}
@@ -0,0 +1,188 @@
+package solcparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Project is a set of parsed source units that can reference each other.
+// Contracts are looked up by name across all the units of the project.
+type Project struct {
+	Units map[string]*SourceUnit
+
+	names []string
+
+	// sources are the source codes of the units added with AddSource
+	sources map[string]string
+}
+
+func NewProject() *Project {
+	return &Project{
+		Units:   map[string]*SourceUnit{},
+		sources: map[string]string{},
+	}
+}
+
+// AddSource parses the source code (tracking the node locations) and adds it to
+// the project under the given name
+func (p *Project) AddSource(name string, src string) error {
+	res := Parse(src, WithLocations())
+	if len(res.Errors) != 0 {
+		return fmt.Errorf("failed to parse %s: %v", name, res.Errors[0])
+	}
+	p.Add(name, res.Result.(*SourceUnit))
+	p.sources[name] = src
+	return nil
+}
+
+// Add adds an already parsed source unit to the project
+func (p *Project) Add(name string, unit *SourceUnit) {
+	if _, ok := p.Units[name]; !ok {
+		p.names = append(p.names, name)
+	}
+	p.Units[name] = unit
+	delete(p.sources, name)
+}
+
+// Source returns the source code of a unit, it is only known for the units
+// added with AddSource
+func (p *Project) Source(name string) (string, bool) {
+	src, ok := p.sources[name]
+	return src, ok
+}
+
+// Files returns the names of the source units in the order they were added
+func (p *Project) Files() []string {
+	return append([]string{}, p.names...)
+}
+
+// Contracts returns all the contracts, interfaces and libraries of the project
+func (p *Project) Contracts() []*ContractDefinition {
+	res := []*ContractDefinition{}
+	for _, name := range p.names {
+		for _, child := range p.Units[name].Children {
+			if c, ok := child.(*ContractDefinition); ok {
+				res = append(res, c)
+			}
+		}
+	}
+	return res
+}
+
+// Contract returns the contract with the given name. If the name is a path
+// (i.e. Lib.Contract) only the last element is used.
+func (p *Project) Contract(name string) (*ContractDefinition, bool) {
+	if indx := strings.LastIndex(name, "."); indx != -1 {
+		name = name[indx+1:]
+	}
+	for _, c := range p.Contracts() {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// SourceOf returns the name of the source unit that defines the contract
+func (p *Project) SourceOf(c *ContractDefinition) string {
+	for _, name := range p.names {
+		for _, child := range p.Units[name].Children {
+			if child == c {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// Linearize returns the C3 linearization of the contract, starting with the
+// contract itself and ending with the most base-like contract.
+func (p *Project) Linearize(c *ContractDefinition) ([]*ContractDefinition, error) {
+	return p.linearize(c, map[*ContractDefinition]bool{})
+}
+
+func (p *Project) linearize(c *ContractDefinition, visiting map[*ContractDefinition]bool) ([]*ContractDefinition, error) {
+	if visiting[c] {
+		return nil, fmt.Errorf("cyclic inheritance in contract %s", c.Name)
+	}
+	visiting[c] = true
+	defer delete(visiting, c)
+
+	bases := []*ContractDefinition{}
+	for _, b := range c.BaseContracts {
+		spec, ok := b.(*InheritanceSpecifier)
+		if !ok {
+			continue
+		}
+		baseName, ok := spec.BaseName.(*UserDefinedTypeName)
+		if !ok {
+			continue
+		}
+		base, ok := p.Contract(baseName.NamePath)
+		if !ok {
+			return nil, fmt.Errorf("base contract %s of %s not found", baseName.NamePath, c.Name)
+		}
+		bases = append(bases, base)
+	}
+
+	// the bases are listed from the most base-like to the most derived,
+	// the merge expects them in the opposite order.
+	seqs := [][]*ContractDefinition{}
+	for i := len(bases) - 1; i >= 0; i-- {
+		lin, err := p.linearize(bases[i], visiting)
+		if err != nil {
+			return nil, err
+		}
+		seqs = append(seqs, lin)
+	}
+	reversed := []*ContractDefinition{}
+	for i := len(bases) - 1; i >= 0; i-- {
+		reversed = append(reversed, bases[i])
+	}
+	seqs = append(seqs, reversed)
+
+	res := []*ContractDefinition{c}
+	for {
+		seqs = removeEmpty(seqs)
+		if len(seqs) == 0 {
+			return res, nil
+		}
+		var head *ContractDefinition
+		for _, seq := range seqs {
+			if !inTail(seq[0], seqs) {
+				head = seq[0]
+				break
+			}
+		}
+		if head == nil {
+			return nil, fmt.Errorf("linearization of inheritance graph impossible for contract %s", c.Name)
+		}
+		res = append(res, head)
+		for i, seq := range seqs {
+			if seq[0] == head {
+				seqs[i] = seq[1:]
+			}
+		}
+	}
+}
+
+func removeEmpty(seqs [][]*ContractDefinition) [][]*ContractDefinition {
+	res := [][]*ContractDefinition{}
+	for _, seq := range seqs {
+		if len(seq) != 0 {
+			res = append(res, seq)
+		}
+	}
+	return res
+}
+
+func inTail(c *ContractDefinition, seqs [][]*ContractDefinition) bool {
+	for _, seq := range seqs {
+		for _, i := range seq[1:] {
+			if i == c {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package solcparser
+
+import (
+	"testing"
+)
+
+func TestProjectLinearize(t *testing.T) {
+	cases := []struct {
+		code     string
+		contract string
+		result   []string
+		err      bool
+	}{
+		{
+			"contract A {}",
+			"A",
+			[]string{"A"},
+			false,
+		},
+		{
+			"contract A {} contract B is A {} contract C is A {} contract D is B, C {}",
+			"D",
+			[]string{"D", "C", "B", "A"},
+			false,
+		},
+		{
+			"contract X {} contract A is X {} contract C is A, X {}",
+			"C",
+			nil,
+			true,
+		},
+		{
+			"contract X {} contract A is X {} contract C is X, A {}",
+			"C",
+			[]string{"C", "A", "X"},
+			false,
+		},
+		{
+			"contract A is B {}",
+			"A",
+			nil,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		p := NewProject()
+		if err := p.AddSource("a.sol", c.code); err != nil {
+			t.Fatal(err)
+		}
+		contract, ok := p.Contract(c.contract)
+		if !ok {
+			t.Fatalf("contract %s not found", c.contract)
+		}
+		lin, err := p.Linearize(contract)
+		if c.err {
+			if err == nil {
+				t.Fatalf("expected an error for %s", c.code)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, l := range lin {
+			names = append(names, l.Name)
+		}
+		if len(names) != len(c.result) {
+			t.Fatalf("bad linearization %v, expected %v", names, c.result)
+		}
+		for i := range names {
+			if names[i] != c.result[i] {
+				t.Fatalf("bad linearization %v, expected %v", names, c.result)
+			}
+		}
+	}
+}
+
+func TestProjectSourceOf(t *testing.T) {
+	p := NewProject()
+	if err := p.AddSource("a.sol", "contract A {}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddSource("b.sol", "import './a.sol'; contract B is A {}"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, ok := p.Contract("B")
+	if !ok {
+		t.Fatal("contract B not found")
+	}
+	if name := p.SourceOf(b); name != "b.sol" {
+		t.Fatalf("bad source %s", name)
+	}
+	lin, err := p.Linearize(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := p.SourceOf(lin[1]); name != "a.sol" {
+		t.Fatalf("bad source %s", name)
+	}
+}
+
+func TestProjectSource(t *testing.T) {
+	p := NewProject()
+	if err := p.AddSource("a.sol", "contract A {}"); err != nil {
+		t.Fatal(err)
+	}
+	if src, ok := p.Source("a.sol"); !ok || src != "contract A {}" {
+		t.Fatalf("bad source '%s'", src)
+	}
+
+	// the source of a unit added already parsed is not known
+	p.Add("a.sol", Parse("contract B {}").Result.(*SourceUnit))
+	if _, ok := p.Source("a.sol"); ok {
+		t.Fatal("the source should not be known")
+	}
+}
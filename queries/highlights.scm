@@ -0,0 +1,175 @@
+; Syntax highlighting of Solidity, the capture names follow the conventions
+; of tree-sitter highlight. The later patterns have priority.
+
+(identifier) @variable
+(yul_identifier) @variable
+
+; Builtin variables and constants
+((identifier) @variable.builtin
+  (#match? @variable.builtin "^(this|super|msg|block|tx|abi|now)$"))
+
+((identifier) @constant
+  (#match? @constant "^[A-Z][A-Z0-9_]+$"))
+
+; Types
+(primitive_type) @type.builtin
+(type_name (identifier) @type)
+(type_alias (identifier) @type)
+(new_expression (type_name (identifier) @type))
+(inheritance_specifier ancestor: (identifier) @type)
+(override_specifier (identifier) @type)
+
+; Definitions
+(contract_declaration name: (identifier) @type)
+(interface_declaration name: (identifier) @type)
+(library_declaration name: (identifier) @type)
+(struct_declaration struct_name: (identifier) @type)
+(enum_declaration enum_type_name: (identifier) @type)
+(event_definition name: (identifier) @type)
+(enum_value) @constant
+
+(function_definition function_name: (identifier) @function)
+(modifier_definition name: (identifier) @function)
+(yul_function_definition . (yul_identifier) @function)
+
+(parameter name: (identifier) @variable.parameter)
+(event_paramater name: (identifier) @variable.parameter)
+(struct_member name: (identifier) @property)
+(state_variable_declaration name: (identifier) @property)
+(constant_variable_declaration name: (identifier) @constant)
+
+; Calls
+(call_expression . (identifier) @function.call)
+(call_expression . (member_expression property: (property_identifier) @function.call))
+(modifier_invocation . (identifier) @function.call)
+(emit_statement . (identifier) @type)
+(yul_function_call function: (yul_identifier) @function.call)
+(yul_function_call function: (yul_evm_builtin) @function.builtin)
+
+(member_expression property: (property_identifier) @property)
+
+; Literals
+(comment) @comment
+(string_literal) @string
+(unicode_string_literal) @string
+(hex_string_literal) @string
+(yul_string_literal) @string
+(import_directive source: (string) @string)
+(number_literal) @number
+(yul_decimal_number) @number
+(yul_hex_number) @number
+(boolean_literal) @boolean
+(yul_boolean) @boolean
+(number_unit) @keyword
+
+; Pragmas
+(solidity_version) @number
+(solidity_version_comparison_operator) @operator
+
+; Keywords
+(virtual) @keyword
+(immutable) @keyword
+(constant) @keyword
+
+[
+  "pragma"
+  "import"
+  "as"
+  "from"
+  "contract"
+  "interface"
+  "library"
+  "abstract"
+  "is"
+  "struct"
+  "enum"
+  "event"
+  "using"
+  "function"
+  "modifier"
+  "constructor"
+  "fallback"
+  "receive"
+  "returns"
+  "override"
+  "anonymous"
+  "indexed"
+  "mapping"
+  "memory"
+  "storage"
+  "calldata"
+  "payable"
+  "public"
+  "internal"
+  "external"
+  "private"
+  "pure"
+  "view"
+  "var"
+  "new"
+  "delete"
+  "emit"
+  "assembly"
+  "let"
+] @keyword
+
+[
+  "if"
+  "else"
+  "for"
+  "while"
+  "do"
+  "return"
+  "break"
+  "continue"
+  "try"
+  "catch"
+  "switch"
+  "case"
+  "default"
+] @keyword.control
+
+; Operators and punctuation
+[
+  "="
+  "+="
+  "-="
+  "*="
+  "/="
+  "%="
+  "|="
+  "&="
+  "^="
+  "<<="
+  ">>="
+  "+"
+  "-"
+  "*"
+  "/"
+  "%"
+  "**"
+  "++"
+  "--"
+  "=="
+  "!="
+  "<"
+  "<="
+  ">"
+  ">="
+  "&&"
+  "||"
+  "!"
+  "&"
+  "|"
+  "^"
+  "~"
+  "<<"
+  ">>"
+  "?"
+  ":="
+  "=>"
+  "->"
+] @operator
+
+["(" ")" "[" "]" "{" "}"] @punctuation.bracket
+["." "," ":"] @punctuation.delimiter
@@ -0,0 +1,29 @@
+; Scopes, definitions and references of the local variables, the capture
+; names follow the conventions of tree-sitter highlight.
+
+; Scopes
+[
+  (contract_body)
+  (function_definition)
+  (constructor_definition)
+  (fallback_receive_definition)
+  (modifier_definition)
+  (block_statement)
+  (for_statement)
+  (catch_clause)
+  (assembly_statement)
+  (yul_block)
+  (yul_function_definition)
+] @local.scope
+
+; Definitions
+(parameter name: (identifier) @local.definition)
+(variable_declaration name: (identifier) @local.definition)
+(variable_declaration_tuple (identifier) @local.definition)
+(state_variable_declaration name: (identifier) @local.definition)
+(yul_variable_declaration left: (yul_identifier) @local.definition)
+(yul_function_definition (yul_identifier) @local.definition)
+
+; References
+(identifier) @local.reference
+(yul_identifier) @local.reference
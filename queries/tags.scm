@@ -0,0 +1,22 @@
+; Definitions and references for symbol indexing, the capture names follow
+; the conventions of tree-sitter tags.
+
+(contract_declaration name: (identifier) @name) @definition.class
+(interface_declaration name: (identifier) @name) @definition.interface
+(library_declaration name: (identifier) @name) @definition.module
+(struct_declaration struct_name: (identifier) @name) @definition.class
+(enum_declaration enum_type_name: (identifier) @name) @definition.enum
+(event_definition name: (identifier) @name) @definition.event
+
+(source_file (function_definition function_name: (identifier) @name) @definition.function)
+(contract_body (function_definition function_name: (identifier) @name) @definition.method)
+(modifier_definition name: (identifier) @name) @definition.method
+(contract_body (state_variable_declaration name: (identifier) @name) @definition.field)
+(constant_variable_declaration name: (identifier) @name) @definition.constant
+
+(call_expression . (identifier) @name) @reference.call
+(call_expression . (member_expression property: (property_identifier) @name)) @reference.call
+(modifier_invocation . (identifier) @name) @reference.call
+(emit_statement . (identifier) @name) @reference.call
+(new_expression (type_name (identifier) @name)) @reference.class
+(inheritance_specifier . ancestor: (identifier) @name) @reference.implementation
@@ -0,0 +1,281 @@
+package solcparser
+
+import (
+	// embed the bundled queries
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	treesitter "github.com/umbracle/solidity-parser-go/tree-sitter"
+)
+
+// HighlightsQuery is the bundled query for syntax highlighting (i.e. @keyword, @function)
+//
+//go:embed queries/highlights.scm
+var HighlightsQuery string
+
+// TagsQuery is the bundled query for symbol indexing (i.e. @definition.class and @name)
+//
+//go:embed queries/tags.scm
+var TagsQuery string
+
+// LocalsQuery is the bundled query of the scopes, definitions and references of the local variables
+//
+//go:embed queries/locals.scm
+var LocalsQuery string
+
+// Query is a compiled tree-sitter query. Its patterns are S-expressions that
+// match nodes of the syntax tree and capture them by name (i.e.
+// '(function_definition function_name: (identifier) @name)'). The #eq?,
+// #match? and #any-of? predicates and their negations filter the matches,
+// the other predicates are ignored.
+type Query struct {
+	query      *sitter.Query
+	predicates [][]*queryPredicate
+}
+
+// QueryMatch is a match of a pattern of a query
+type QueryMatch struct {
+	// Pattern is the index of the pattern in the query
+	Pattern  int
+	Captures []*QueryCapture
+}
+
+// QueryCapture is a node captured by a query
+type QueryCapture struct {
+	Name string
+	Node *sitter.Node
+	Text string
+	Loc  *Location
+}
+
+// queryPredicate is a predicate of a pattern (i.e. #eq? @name "foo")
+type queryPredicate struct {
+	name string
+	args []queryPredicateArg
+	re   *regexp.Regexp
+}
+
+// queryPredicateArg is either a capture or a string
+type queryPredicateArg struct {
+	capture string
+	value   string
+}
+
+// NewQuery compiles a query for the Solidity grammar
+func NewQuery(pattern string) (*Query, error) {
+	query, err := sitter.NewQuery([]byte(pattern), treesitter.GetLanguage())
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+
+	q := &Query{
+		query: query,
+	}
+	for i := uint32(0); i < query.PatternCount(); i++ {
+		predicates, err := q.parsePredicates(i)
+		if err != nil {
+			query.Close()
+			return nil, err
+		}
+		q.predicates = append(q.predicates, predicates)
+	}
+	return q, nil
+}
+
+func (q *Query) parsePredicates(pattern uint32) ([]*queryPredicate, error) {
+	res := []*queryPredicate{}
+	var pred *queryPredicate
+	for _, step := range q.query.PredicatesForPattern(pattern) {
+		switch step.Type {
+		case sitter.QueryPredicateStepTypeDone:
+			if err := pred.validate(); err != nil {
+				return nil, err
+			}
+			// the directives (i.e. #set!) are not predicates
+			if strings.HasSuffix(pred.name, "?") {
+				res = append(res, pred)
+			}
+			pred = nil
+
+		case sitter.QueryPredicateStepTypeCapture:
+			pred.args = append(pred.args, queryPredicateArg{capture: q.query.CaptureNameForId(step.ValueId)})
+
+		case sitter.QueryPredicateStepTypeString:
+			value := q.query.StringValueForId(step.ValueId)
+			if pred == nil {
+				pred = &queryPredicate{name: value}
+			} else {
+				pred.args = append(pred.args, queryPredicateArg{value: value})
+			}
+		}
+	}
+	return res, nil
+}
+
+func (p *queryPredicate) validate() error {
+	switch p.name {
+	case "eq?", "not-eq?":
+		if len(p.args) != 2 || p.args[0].capture == "" {
+			return fmt.Errorf("#%s expects a capture and a capture or a string", p.name)
+		}
+
+	case "match?", "not-match?":
+		if len(p.args) != 2 || p.args[0].capture == "" || p.args[1].capture != "" {
+			return fmt.Errorf("#%s expects a capture and a regular expression", p.name)
+		}
+		re, err := regexp.Compile(p.args[1].value)
+		if err != nil {
+			return fmt.Errorf("#%s: %v", p.name, err)
+		}
+		p.re = re
+
+	case "any-of?", "not-any-of?":
+		if len(p.args) < 2 || p.args[0].capture == "" {
+			return fmt.Errorf("#%s expects a capture and strings", p.name)
+		}
+
+	default:
+		if strings.HasSuffix(p.name, "?") {
+			return fmt.Errorf("unknown predicate #%s", p.name)
+		}
+	}
+	return nil
+}
+
+// eval returns whether the captured texts satisfy the predicate
+func (p *queryPredicate) eval(captures []*QueryCapture) bool {
+	texts := func(name string) []string {
+		res := []string{}
+		for _, c := range captures {
+			if c.Name == name {
+				res = append(res, c.Text)
+			}
+		}
+		return res
+	}
+
+	negated := strings.HasPrefix(p.name, "not-")
+	for _, text := range texts(p.args[0].capture) {
+		var ok bool
+		switch strings.TrimPrefix(p.name, "not-") {
+		case "eq?":
+			other := []string{p.args[1].value}
+			if p.args[1].capture != "" {
+				other = texts(p.args[1].capture)
+			}
+			for _, o := range other {
+				ok = ok || text == o
+			}
+
+		case "match?":
+			ok = p.re.MatchString(text)
+
+		case "any-of?":
+			for _, arg := range p.args[1:] {
+				ok = ok || text == arg.value
+			}
+		}
+		if ok == negated {
+			return false
+		}
+	}
+	return true
+}
+
+// CaptureNames returns the names of the captures of the query
+func (q *Query) CaptureNames() []string {
+	res := []string{}
+	for i := uint32(0); i < q.query.CaptureCount(); i++ {
+		res = append(res, q.query.CaptureNameForId(i))
+	}
+	return res
+}
+
+// Matches returns the matches of the query in a node of the syntax tree of
+// the source, ordered by the position of their first capture
+func (q *Query) Matches(n *sitter.Node, src string) []*QueryMatch {
+	e := q.exec(n, src)
+	defer e.cursor.Close()
+
+	res := []*QueryMatch{}
+	for {
+		m, ok := e.cursor.NextMatch()
+		if !ok {
+			return res
+		}
+		if match := e.match(m); match != nil {
+			res = append(res, match)
+		}
+	}
+}
+
+// Captures returns the captures of all the matches of the query in a node of
+// the syntax tree of the source, ordered by their position
+func (q *Query) Captures(n *sitter.Node, src string) []*QueryCapture {
+	e := q.exec(n, src)
+	defer e.cursor.Close()
+
+	res := []*QueryCapture{}
+	for {
+		m, index, ok := e.cursor.NextCapture()
+		if !ok {
+			return res
+		}
+		if match := e.match(m); match != nil {
+			res = append(res, match.Captures[index])
+		}
+	}
+}
+
+// Close frees the query
+func (q *Query) Close() {
+	q.query.Close()
+}
+
+// queryExec is the execution of a query in a source
+type queryExec struct {
+	q      *Query
+	cursor *sitter.QueryCursor
+	src    []byte
+	srcMap *sourceMap
+}
+
+func (q *Query) exec(n *sitter.Node, src string) *queryExec {
+	e := &queryExec{
+		q:      q,
+		cursor: sitter.NewQueryCursor(),
+		src:    []byte(src),
+		srcMap: newSourceMap(src),
+	}
+	e.cursor.Exec(q.query, n)
+	return e
+}
+
+// match converts a match of the cursor, it returns nil if the predicates of
+// the pattern are not satisfied
+func (e *queryExec) match(m *sitter.QueryMatch) *QueryMatch {
+	match := &QueryMatch{
+		Pattern:  int(m.PatternIndex),
+		Captures: []*QueryCapture{},
+	}
+	for _, c := range m.Captures {
+		match.Captures = append(match.Captures, &QueryCapture{
+			Name: e.q.query.CaptureNameForId(c.Index),
+			Node: c.Node,
+			Text: c.Node.Content(e.src),
+			Loc: &Location{
+				Start: e.srcMap.position(int(c.Node.StartByte())),
+				End:   e.srcMap.position(int(c.Node.EndByte())),
+			},
+		})
+	}
+	for _, pred := range e.q.predicates[match.Pattern] {
+		if !pred.eval(match.Captures) {
+			return nil
+		}
+	}
+	return match
+}
@@ -0,0 +1,175 @@
+package solcparser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const querySource = `pragma solidity ^0.8.0;
+
+contract Token is Ownable {
+    uint256 public constant MAX_SUPPLY = 100;
+    mapping(address => uint) balances;
+
+    event Transfer(address indexed from, uint amount);
+
+    function transfer(address to, uint amount) public onlyOwner returns (bool) {
+        uint fee = amount / 100; // the fee
+        balances[msg.sender] -= amount;
+        emit Transfer(msg.sender, amount);
+        token.send(to);
+        assembly { let x := add(fee, 1) }
+        return true;
+    }
+}
+`
+
+// queryCaptures returns the captures of a query as 'line:column name text'
+func queryCaptures(t *testing.T, pattern string, names ...string) []string {
+	q, err := NewQuery(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	root, err := NewTreeSitterCtx(context.Background(), querySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := []string{}
+	for _, c := range q.Captures(root, querySource) {
+		for _, name := range names {
+			if c.Name == name {
+				res = append(res, fmt.Sprintf("%d:%d %s %s", c.Loc.Start.Line, c.Loc.Start.Column, c.Name, c.Text))
+			}
+		}
+	}
+	return res
+}
+
+func TestQueryHighlights(t *testing.T) {
+	found := queryCaptures(t, HighlightsQuery, "function", "function.call", "function.builtin", "constant", "variable.builtin", "comment")
+	expected := []string{
+		"4:28 constant MAX_SUPPLY",
+		"9:13 function transfer",
+		"9:54 function.call onlyOwner",
+		"10:33 comment // the fee",
+		"11:17 variable.builtin msg",
+		"12:22 variable.builtin msg",
+		"13:14 function.call send",
+		"14:28 function.builtin add",
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Fatalf("bad highlights %v", found)
+	}
+}
+
+func TestQueryTags(t *testing.T) {
+	q, err := NewQuery(TagsQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	// the kind of the tag and the name of the symbol
+	root, err := NewTreeSitterCtx(context.Background(), querySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := []string{}
+	for _, m := range q.Matches(root, querySource) {
+		var kind, name string
+		for _, c := range m.Captures {
+			if c.Name == "name" {
+				name = c.Text
+			} else {
+				kind = c.Name
+			}
+		}
+		found = append(found, kind+" "+name)
+	}
+	expected := []string{
+		"definition.class Token",
+		"reference.implementation Ownable",
+		"definition.field MAX_SUPPLY",
+		"definition.field balances",
+		"definition.event Transfer",
+		"definition.method transfer",
+		"reference.call onlyOwner",
+		"reference.call Transfer",
+		"reference.call send",
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Fatalf("bad tags %v", found)
+	}
+}
+
+func TestQueryLocals(t *testing.T) {
+	found := queryCaptures(t, LocalsQuery, "local.definition")
+	expected := []string{
+		"4:28 local.definition MAX_SUPPLY",
+		"5:29 local.definition balances",
+		"9:30 local.definition to",
+		"9:39 local.definition amount",
+		"10:13 local.definition fee",
+		"14:23 local.definition x",
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Fatalf("bad definitions %v", found)
+	}
+	if scopes := queryCaptures(t, LocalsQuery, "local.scope"); len(scopes) != 3 {
+		t.Fatalf("bad scopes %v", scopes)
+	}
+}
+
+func TestQueryPredicates(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected []string
+	}{
+		{
+			`((identifier) @id (#eq? @id "amount"))`,
+			[]string{"7:46 id amount", "9:39 id amount", "10:19 id amount", "11:32 id amount", "12:34 id amount"},
+		},
+		{
+			`(parameter name: (identifier) @id (#not-eq? @id "amount"))`,
+			[]string{"9:30 id to"},
+		},
+		{
+			`((property_identifier) @id (#match? @id "^s"))`,
+			[]string{"11:21 id sender", "12:26 id sender", "13:14 id send"},
+		},
+		{
+			`((primitive_type) @id (#any-of? @id "bool" "address") (#not-match? @id "^a"))`,
+			[]string{"9:73 id bool"},
+		},
+		{
+			// the same text in two captures
+			`(binary_expression left: (_) @id right: (_) @other (#eq? @id @other))`,
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		if found := queryCaptures(t, c.query, "id"); !reflect.DeepEqual(found, c.expected) {
+			t.Fatalf("bad captures for %s: %v", c.query, found)
+		}
+	}
+}
+
+func TestQueryInvalid(t *testing.T) {
+	cases := []string{
+		`(identifier`,
+		`(unknown_node) @a`,
+		`((identifier) @a (#unknown? @a))`,
+		`((identifier) @a (#match? @a "("))`,
+		`((identifier) @a (#eq? @a))`,
+	}
+	for _, c := range cases {
+		if _, err := NewQuery(c); err == nil {
+			t.Fatalf("query %s should fail", c)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package solcparser
+
+import (
+	"fmt"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	solAntlr "github.com/umbracle/solidity-parser-go/antlr"
+)
+
+// Rewriter edits a source by the tokens of the nodes of its AST with the
+// antlr TokenStreamRewriter. The text that the edits do not touch is kept as
+// it is, the whitespace and the comments included. The nodes must have their
+// locations (i.e. Parse(src, WithLocations())).
+type Rewriter struct {
+	tokens   *antlr.CommonTokenStream
+	rewriter *antlr.TokenStreamRewriter
+
+	// starts and stops are the indexes of the tokens that start and end at
+	// each byte offset
+	starts map[int]int
+	stops  map[int]int
+}
+
+// NewRewriter creates a rewriter of a source
+func NewRewriter(src string) *Rewriter {
+	lexer := solAntlr.NewSolidityLexer(antlr.NewInputStream(src))
+	lexer.RemoveErrorListeners()
+
+	r := &Rewriter{
+		tokens: antlr.NewCommonTokenStream(&keepLexer{SolidityLexer: lexer}, antlr.TokenDefaultChannel),
+		starts: map[int]int{},
+		stops:  map[int]int{},
+	}
+	r.tokens.Fill()
+	r.rewriter = antlr.NewTokenStreamRewriter(r.tokens)
+
+	srcMap := newSourceMap(src)
+	for _, t := range r.tokens.GetAllTokens() {
+		if t.GetTokenType() == antlr.TokenEOF || t.GetChannel() != antlr.TokenDefaultChannel {
+			continue
+		}
+		r.starts[srcMap.runePosition(t.GetStart()).Offset] = t.GetTokenIndex()
+		r.stops[srcMap.runePosition(t.GetStop()+1).Offset] = t.GetTokenIndex()
+	}
+	return r
+}
+
+// tokenRange returns the indexes of the first and the last token of a node
+func (r *Rewriter) tokenRange(node interface{}) (int, int, error) {
+	n, ok := node.(INode)
+	if !ok || n.GetLoc() == nil {
+		return 0, 0, fmt.Errorf("node %T without location", node)
+	}
+	loc := n.GetLoc()
+	start, ok := r.starts[loc.Start.Offset]
+	if !ok {
+		return 0, 0, fmt.Errorf("no token starts at %d:%d", loc.Start.Line, loc.Start.Column)
+	}
+	stop, ok := r.stops[loc.End.Offset]
+	if !ok || stop < start {
+		return 0, 0, fmt.Errorf("no token ends at %d:%d", loc.End.Line, loc.End.Column)
+	}
+	return start, stop, nil
+}
+
+// InsertBefore inserts a text before a node
+func (r *Rewriter) InsertBefore(node interface{}, text string) error {
+	start, _, err := r.tokenRange(node)
+	if err != nil {
+		return err
+	}
+	r.rewriter.InsertBeforeDefault(start, text)
+	return nil
+}
+
+// InsertAfter inserts a text after a node
+func (r *Rewriter) InsertAfter(node interface{}, text string) error {
+	_, stop, err := r.tokenRange(node)
+	if err != nil {
+		return err
+	}
+	r.rewriter.InsertAfterDefault(stop, text)
+	return nil
+}
+
+// Replace replaces the text of a node
+func (r *Rewriter) Replace(node interface{}, text string) error {
+	start, stop, err := r.tokenRange(node)
+	if err != nil {
+		return err
+	}
+	r.rewriter.ReplaceDefault(start, stop, text)
+	return nil
+}
+
+// Delete deletes the text of a node, the whitespace around it is kept
+func (r *Rewriter) Delete(node interface{}) error {
+	start, stop, err := r.tokenRange(node)
+	if err != nil {
+		return err
+	}
+	r.rewriter.DeleteDefault(start, stop)
+	return nil
+}
+
+// Text returns the source with the edits. It fails if two replacements
+// overlap without one containing the other or if there is an insertion
+// inside a replaced node.
+func (r *Rewriter) Text() (text string, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+	return r.rewriter.GetTextDefault(), nil
+}
@@ -0,0 +1,103 @@
+package solcparser
+
+import (
+	"testing"
+)
+
+func TestRewriter(t *testing.T) {
+	src := `contract A {
+    // the balance
+    uint  a;
+    uint b; /* unused */
+
+    function f(uint x) public {
+        a = x ;
+    }
+}
+`
+	expected := `contract A {
+    // the balance
+    uint  a;
+     /* unused */
+
+    /// @notice sets a
+    function f(uint x) public onlyOwner {
+        a = y ;
+        emit Set(y);
+    }
+}
+`
+
+	p := Parse(src, WithLocations())
+	if len(p.Errors) != 0 {
+		t.Fatal(p.Errors)
+	}
+	contract := p.Result.(*SourceUnit).Children[0].(*ContractDefinition)
+	fn := contract.SubNodes[2].(*FunctionDefinition)
+	stmt := fn.Body.(*Block).Statements[0].(*ExpressionStatement)
+	assign := stmt.Expression.(*BinaryOperation)
+
+	r := NewRewriter(src)
+	if err := r.Delete(contract.SubNodes[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.InsertBefore(fn, "/// @notice sets a\n    "); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.InsertBefore(fn.Body, "onlyOwner "); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Replace(assign.Right, "y"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.InsertAfter(stmt, "\n        emit Set(y);"); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := r.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != expected {
+		t.Fatalf("bad text\n%s", found)
+	}
+
+	// the source without edits
+	if found, _ := NewRewriter(src).Text(); found != src {
+		t.Fatalf("bad text\n%s", found)
+	}
+}
+
+func TestRewriterErrors(t *testing.T) {
+	src := "contract A { function f() public { a = 1; } }"
+
+	// the nodes need their locations
+	p := Parse(src)
+	fn := p.Result.(*SourceUnit).Children[0].(*ContractDefinition).SubNodes[0]
+	if err := NewRewriter(src).Delete(fn); err == nil {
+		t.Fatal("it should fail")
+	}
+	if err := NewRewriter(src).Delete("a"); err == nil {
+		t.Fatal("it should fail")
+	}
+
+	// a node of another source
+	p = Parse("  contract B {}", WithLocations())
+	if err := NewRewriter(src).Delete(p.Result.(*SourceUnit).Children[0]); err == nil {
+		t.Fatal("it should fail")
+	}
+
+	// an insertion inside a replaced node
+	p = Parse(src, WithLocations())
+	fn = p.Result.(*SourceUnit).Children[0].(*ContractDefinition).SubNodes[0]
+	r := NewRewriter(src)
+	if err := r.Replace(fn, "function g() public {}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.InsertAfter(fn.(*FunctionDefinition).Body.(*Block).Statements[0], " a = 2;"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Text(); err == nil {
+		t.Fatal("it should fail")
+	}
+}
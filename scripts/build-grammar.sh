@@ -1,5 +1,19 @@
 #!/bin/bash
 
+set -e
+
+# the vendored grammar is the one of the record
+expected=$(sed -n "s/^source-sha256: *//p" antlr-sol/UPSTREAM)
+if [ -z "$expected" ] || [ "$(sha256sum antlr-sol/Solidity.g4 | cut -d ' ' -f 1)" != "$expected" ]; then
+    echo "antlr-sol/Solidity.g4 does not match the source-sha256 of antlr-sol/UPSTREAM"
+    exit 1
+fi
+
+if ! command -v docker > /dev/null; then
+    echo "docker not found, it runs the antlr tool"
+    exit 1
+fi
+
 dir=./antlr
 rm -rf $dir
 mkdir $dir
@@ -15,3 +29,6 @@ docker run --rm -u $(id -u ${USER}):$(id -g ${USER}) \
     -no-listener \
     -no-visitor \
     ${dir}/Solidity.g4
+
+# record the checksum of the generated parser
+sed -i "s/^generated-sha256:.*/generated-sha256: $(sha256sum $dir/solidity_parser.go | cut -d ' ' -f 1)/" antlr-sol/UPSTREAM
@@ -1,5 +1,24 @@
 #!/bin/bash
 
+set -e
+
+if [ ! -f tree-sitter-sol/grammar.js ]; then
+    echo "tree-sitter-sol/grammar.js not found, vendor it with scripts/vendor-grammars.sh"
+    exit 1
+fi
+
+# the vendored grammar is the one of the record
+expected=$(sed -n "s/^source-sha256: *//p" tree-sitter-sol/UPSTREAM)
+if [ -z "$expected" ] || [ "$(sha256sum tree-sitter-sol/grammar.js | cut -d ' ' -f 1)" != "$expected" ]; then
+    echo "tree-sitter-sol/grammar.js does not match the source-sha256 of tree-sitter-sol/UPSTREAM"
+    exit 1
+fi
+
+if ! command -v tree-sitter > /dev/null; then
+    echo "tree-sitter not found, install the tree-sitter cli"
+    exit 1
+fi
+
 # Create the grammar
 dir=tree-sitter-src
 mkdir -p $dir
@@ -15,3 +34,6 @@ cp $dir/src/parser.c $godir
 cp $dir/src/tree_sitter/parser.h $godir/tree_sitter/
 
 rm -rf $dir
+
+# record the checksum of the generated parser
+sed -i "s/^generated-sha256:.*/generated-sha256: $(sha256sum $godir/parser.c | cut -d ' ' -f 1)/" tree-sitter-sol/UPSTREAM
@@ -0,0 +1,21 @@
+#!/bin/bash
+
+# Download the grammars at the versions of their UPSTREAM files
+set -e
+
+field() {
+    sed -n "s/^$2: *//p" $1/UPSTREAM
+}
+
+for dir in antlr-sol tree-sitter-sol; do
+    repository=$(field $dir repository)
+    version=$(field $dir version)
+    source=$(field $dir source)
+    if [ "$version" == "unknown" ]; then
+        echo "$dir: the version of $repository is unknown, set it in $dir/UPSTREAM"
+        exit 1
+    fi
+
+    curl -sSfL $repository/raw/$version/$source -o $dir/$source
+    sed -i "s/^source-sha256:.*/source-sha256: $(sha256sum $dir/$source | cut -d ' ' -f 1)/" $dir/UPSTREAM
+done
@@ -0,0 +1,333 @@
+package solcparser
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	solAntlr "github.com/umbracle/solidity-parser-go/antlr"
+)
+
+// TokenKind is the kind of a token. The values are stable, they do not depend
+// on the token types of the grammar and the new kinds are added at the end.
+type TokenKind int
+
+const (
+	// TokenIllegal is a text that the lexer does not recognize
+	TokenIllegal TokenKind = iota
+
+	// TokenWhitespace is a sequence of spaces, tabs and line breaks
+	TokenWhitespace
+
+	// TokenComment is a block comment (i.e. '/* ... */')
+	TokenComment
+
+	// TokenLineComment is a comment until the end of the line (i.e. '// ...')
+	TokenLineComment
+
+	// TokenKeyword is a keyword, including the reserved ones (i.e. 'contract' or 'public')
+	TokenKeyword
+
+	// TokenElementaryType is the name of an elementary type (i.e. 'uint256' or 'address')
+	TokenElementaryType
+
+	// TokenIdentifier is a name (i.e. 'balance')
+	TokenIdentifier
+
+	// TokenBoolean is 'true' or 'false'
+	TokenBoolean
+
+	// TokenNumber is a decimal or an hexadecimal number
+	TokenNumber
+
+	// TokenNumberUnit is the unit of a number (i.e. 'ether' or 'days')
+	TokenNumberUnit
+
+	// TokenString is a string literal, including the unicode ones
+	TokenString
+
+	// TokenHexString is an hexadecimal string literal (i.e. hex"00ff")
+	TokenHexString
+
+	// TokenVersion is a version of a pragma (i.e. '0.8.0')
+	TokenVersion
+
+	// TokenPunctuation is a bracket, a semicolon, a comma or a dot
+	TokenPunctuation
+
+	// TokenOperator is any other symbol (i.e. '+=' or '=>')
+	TokenOperator
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenIllegal:
+		return "illegal"
+	case TokenWhitespace:
+		return "whitespace"
+	case TokenComment:
+		return "comment"
+	case TokenLineComment:
+		return "line-comment"
+	case TokenKeyword:
+		return "keyword"
+	case TokenElementaryType:
+		return "elementary-type"
+	case TokenIdentifier:
+		return "identifier"
+	case TokenBoolean:
+		return "boolean"
+	case TokenNumber:
+		return "number"
+	case TokenNumberUnit:
+		return "number-unit"
+	case TokenString:
+		return "string"
+	case TokenHexString:
+		return "hex-string"
+	case TokenVersion:
+		return "version"
+	case TokenPunctuation:
+		return "punctuation"
+	case TokenOperator:
+		return "operator"
+	}
+	return fmt.Sprintf("TokenKind(%d)", int(k))
+}
+
+// Channel is the channel of a token, the parser only reads the tokens of the
+// default channel
+type Channel int
+
+const (
+	DefaultChannel Channel = iota
+
+	// HiddenChannel has the comments and the whitespace
+	HiddenChannel
+)
+
+func (c Channel) String() string {
+	switch c {
+	case DefaultChannel:
+		return "default"
+	case HiddenChannel:
+		return "hidden"
+	}
+	return fmt.Sprintf("Channel(%d)", int(c))
+}
+
+// Token is a token of a source
+type Token struct {
+	Kind    TokenKind
+	Text    string
+	Loc     *Location
+	Channel Channel
+}
+
+// Tokenize returns all the tokens of a source, the comments and the
+// whitespace included. The texts of the tokens are the whole source.
+func Tokenize(src string) []*Token {
+	tokens := []*Token{}
+	it := NewIterator(src)
+	for {
+		t, ok := it.Next()
+		if !ok {
+			return tokens
+		}
+		tokens = append(tokens, t)
+	}
+}
+
+// Iterator returns the tokens of a source one at a time, it is Tokenize
+// without building the list of all the tokens
+type Iterator struct {
+	src    string
+	srcMap *sourceMap
+	lexer  *solAntlr.SolidityLexer
+
+	// offset is the byte offset of the end of the last token
+	offset  int
+	pending []*Token
+	done    bool
+}
+
+// NewIterator creates an iterator over the tokens of a source
+func NewIterator(src string) *Iterator {
+	lexer := solAntlr.NewSolidityLexer(antlr.NewInputStream(src))
+	lexer.RemoveErrorListeners()
+	return &Iterator{
+		src:    src,
+		srcMap: newSourceMap(src),
+		lexer:  lexer,
+	}
+}
+
+// Next returns the next token, it returns false at the end of the source
+func (it *Iterator) Next() (*Token, bool) {
+	for len(it.pending) == 0 {
+		if it.done {
+			return nil, false
+		}
+		it.lex()
+	}
+	t := it.pending[0]
+	it.pending = it.pending[1:]
+	return t, true
+}
+
+// lex reads the next token of the lexer and the text that the lexer skipped
+// before it (the whitespace and the characters it does not recognize)
+func (it *Iterator) lex() {
+	t := it.lexer.NextToken()
+	if t.GetTokenType() == antlr.TokenEOF {
+		it.skipped(len(it.src))
+		it.done = true
+		return
+	}
+
+	start, end := it.srcMap.runePosition(t.GetStart()), it.srcMap.runePosition(t.GetStop()+1)
+	it.skipped(start.Offset)
+
+	channel := DefaultChannel
+	if t.GetChannel() == antlr.TokenHiddenChannel {
+		channel = HiddenChannel
+	}
+	kind := tokenKind(t.GetTokenType(), t.GetText())
+	if kind == TokenWhitespace {
+		channel = HiddenChannel
+	}
+	it.pending = append(it.pending, &Token{
+		Kind:    kind,
+		Text:    it.src[start.Offset:end.Offset],
+		Loc:     &Location{Start: start, End: end},
+		Channel: channel,
+	})
+	it.offset = end.Offset
+}
+
+// skipped adds the tokens of the text from the end of the last token to an offset
+func (it *Iterator) skipped(offset int) {
+	for it.offset < offset {
+		start := it.offset
+		space := isSpace(it.src[start])
+		for it.offset < offset && isSpace(it.src[it.offset]) == space {
+			it.offset++
+		}
+
+		t := &Token{
+			Kind: TokenIllegal,
+			Text: it.src[start:it.offset],
+			Loc: &Location{
+				Start: it.srcMap.position(start),
+				End:   it.srcMap.position(it.offset),
+			},
+		}
+		if space {
+			t.Kind, t.Channel = TokenWhitespace, HiddenChannel
+		}
+		it.pending = append(it.pending, t)
+	}
+}
+
+// keepLexer is the lexer with the text it skips as tokens of the hidden
+// channel, the whitespace is a WS token and the characters that the lexer does
+// not recognize are invalid tokens. The text of the source is the text of
+// the tokens.
+type keepLexer struct {
+	*solAntlr.SolidityLexer
+
+	pending []antlr.Token
+
+	// index is the rune index after the last token
+	index int
+}
+
+func (l *keepLexer) NextToken() antlr.Token {
+	if len(l.pending) == 0 {
+		t := l.SolidityLexer.NextToken()
+		start := t.GetStart()
+		if t.GetTokenType() == antlr.TokenEOF {
+			start = l.GetInputStream().Size()
+		}
+		if start > l.index {
+			skipped := []rune(l.GetInputStream().GetText(l.index, start-1))
+			for i := 0; i < len(skipped); {
+				j, space := i, isSpaceRune(skipped[i])
+				for j < len(skipped) && isSpaceRune(skipped[j]) == space {
+					j++
+				}
+				typ := antlr.TokenInvalidType
+				if space {
+					typ = solAntlr.SolidityLexerWS
+				}
+				l.pending = append(l.pending, l.GetTokenFactory().Create(t.GetSource(), typ, "", antlr.TokenHiddenChannel, l.index+i, l.index+j-1, 0, 0))
+				i = j
+			}
+		}
+		l.pending = append(l.pending, t)
+	}
+
+	t := l.pending[0]
+	l.pending = l.pending[1:]
+	if t.GetTokenType() != antlr.TokenEOF {
+		l.index = t.GetStop() + 1
+	}
+	return t
+}
+
+func isSpaceRune(r rune) bool {
+	return r < utf8.RuneSelf && isSpace(byte(r))
+}
+
+// isSpace returns true for the characters of the whitespace of the grammar
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f'
+}
+
+// tokenKind returns the kind of a token type of the lexer
+func tokenKind(typ int, text string) TokenKind {
+	switch typ {
+	case antlr.TokenInvalidType:
+		return TokenIllegal
+	case solAntlr.SolidityLexerWS:
+		return TokenWhitespace
+	case solAntlr.SolidityLexerCOMMENT:
+		return TokenComment
+	case solAntlr.SolidityLexerLINE_COMMENT:
+		return TokenLineComment
+	case solAntlr.SolidityLexerIdentifier:
+		return TokenIdentifier
+	case solAntlr.SolidityLexerInt, solAntlr.SolidityLexerUint, solAntlr.SolidityLexerByte,
+		solAntlr.SolidityLexerFixed, solAntlr.SolidityLexerUfixed:
+		return TokenElementaryType
+	case solAntlr.SolidityLexerBooleanLiteral:
+		return TokenBoolean
+	case solAntlr.SolidityLexerDecimalNumber, solAntlr.SolidityLexerHexNumber:
+		return TokenNumber
+	case solAntlr.SolidityLexerNumberUnit:
+		return TokenNumberUnit
+	case solAntlr.SolidityLexerStringLiteralFragment:
+		return TokenString
+	case solAntlr.SolidityLexerHexLiteralFragment:
+		return TokenHexString
+	case solAntlr.SolidityLexerVersionLiteral:
+		return TokenVersion
+	case solAntlr.SolidityLexerReservedKeyword:
+		return TokenKeyword
+	}
+	if typ >= solAntlr.SolidityLexerAnonymousKeyword && typ <= solAntlr.SolidityLexerReceiveKeyword {
+		return TokenKeyword
+	}
+
+	// the other tokens have a fixed text (i.e. 'pragma' or '+=')
+	switch text {
+	case "address", "bool", "string", "byte", "var":
+		return TokenElementaryType
+	case "(", ")", "{", "}", "[", "]", ";", ",", ".":
+		return TokenPunctuation
+	}
+	if c := text[0]; c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+		return TokenKeyword
+	}
+	return TokenOperator
+}
@@ -0,0 +1,110 @@
+package solcparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	src := "pragma solidity ^0.8.0;\n// ü\ncontract A {\n  uint256 a = 1 ether; /* b */\n  string s = hex\"00\";\n  bool b = true # x;\n}"
+
+	tokens := Tokenize(src)
+
+	// the tokens are the whole source
+	texts := []string{}
+	for _, tok := range tokens {
+		texts = append(texts, tok.Text)
+		if src[tok.Loc.Start.Offset:tok.Loc.End.Offset] != tok.Text {
+			t.Fatalf("bad location for '%s'", tok.Text)
+		}
+	}
+	if strings.Join(texts, "") != src {
+		t.Fatal("the tokens are not the source")
+	}
+
+	cases := []struct {
+		text    string
+		kind    TokenKind
+		channel Channel
+		line    int
+		column  int
+	}{
+		{"pragma", TokenKeyword, DefaultChannel, 1, 0},
+		{"^", TokenOperator, DefaultChannel, 1, 16},
+		{"0.8.0", TokenVersion, DefaultChannel, 1, 17},
+		{";", TokenPunctuation, DefaultChannel, 1, 22},
+		{"\n", TokenWhitespace, HiddenChannel, 1, 23},
+		{"// ü", TokenLineComment, HiddenChannel, 2, 0},
+		{"contract", TokenKeyword, DefaultChannel, 3, 0},
+		{"A", TokenIdentifier, DefaultChannel, 3, 9},
+		{"uint256", TokenElementaryType, DefaultChannel, 4, 2},
+		{"1", TokenNumber, DefaultChannel, 4, 14},
+		{"ether", TokenNumberUnit, DefaultChannel, 4, 16},
+		{"/* b */", TokenComment, HiddenChannel, 4, 23},
+		{"string", TokenElementaryType, DefaultChannel, 5, 2},
+		{"hex\"00\"", TokenHexString, DefaultChannel, 5, 13},
+		{"true", TokenBoolean, DefaultChannel, 6, 11},
+		{"#", TokenIllegal, DefaultChannel, 6, 16},
+	}
+
+	for _, c := range cases {
+		var found *Token
+		for _, tok := range tokens {
+			if tok.Text == c.text {
+				found = tok
+				break
+			}
+		}
+		if found == nil {
+			t.Fatalf("token '%s' not found", c.text)
+		}
+		if found.Kind != c.kind || found.Channel != c.channel {
+			t.Fatalf("bad token '%s': %s %s", c.text, found.Kind, found.Channel)
+		}
+		if found.Loc.Start.Line != c.line || found.Loc.Start.Column != c.column {
+			t.Fatalf("bad position for '%s': %d:%d", c.text, found.Loc.Start.Line, found.Loc.Start.Column)
+		}
+	}
+}
+
+func TestIterator(t *testing.T) {
+	src := "contract A { function f() public { a += 1; } }"
+
+	tokens := Tokenize(src)
+	it := NewIterator(src)
+	for i := 0; ; i++ {
+		tok, ok := it.Next()
+		if !ok {
+			if i != len(tokens) {
+				t.Fatalf("expected %d tokens but found %d", len(tokens), i)
+			}
+			break
+		}
+		if *tok.Loc != *tokens[i].Loc || tok.Kind != tokens[i].Kind {
+			t.Fatalf("bad token %d '%s'", i, tok.Text)
+		}
+	}
+	if _, ok := it.Next(); ok {
+		t.Fatal("no more tokens expected")
+	}
+
+	if len(Tokenize("")) != 0 {
+		t.Fatal("no tokens expected")
+	}
+}
+
+func TestTokenKindString(t *testing.T) {
+	// the values of the kinds are stable
+	names := []string{
+		"illegal", "whitespace", "comment", "line-comment", "keyword", "elementary-type", "identifier", "boolean",
+		"number", "number-unit", "string", "hex-string", "version", "punctuation", "operator",
+	}
+	for i, name := range names {
+		if TokenKind(i).String() != name {
+			t.Fatalf("bad kind %d: %s", i, TokenKind(i))
+		}
+	}
+	if TokenKind(len(names)).String() != "TokenKind(15)" {
+		t.Fatal("unknown kind expected")
+	}
+}
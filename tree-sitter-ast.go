@@ -0,0 +1,1509 @@
+package solcparser
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	treesitter "github.com/umbracle/solidity-parser-go/tree-sitter"
+)
+
+// parseTreeSitter builds the AST from the syntax tree of tree-sitter. The
+// nodes are the same that the antlr visitor builds for the same source.
+func parseTreeSitter(s string, cfg *config) *Parser {
+	src := []byte(s)
+	root, err := sitter.ParseCtx(context.Background(), src, treesitter.GetLanguage())
+	if err != nil {
+		pos := newSourceMap(s).position(0)
+		return &Parser{
+			Errors: []*SyntaxError{{line: 1, msg: err.Error(), loc: &Location{Start: pos, End: pos}}},
+		}
+	}
+	return treeSitterResult(src, root, cfg)
+}
+
+// treeSitterResult converts the syntax tree of a source into the AST
+func treeSitterResult(src []byte, root *sitter.Node, cfg *config) *Parser {
+	srcMap := newSourceMap(string(src))
+	b := &treeSitterBuilder{src: src, errSrc: srcMap}
+	if cfg.locations {
+		b.srcMap = srcMap
+	}
+	errs := treeSitterErrors(src, srcMap, root)
+
+	var result INode
+	func() {
+		defer func() {
+			// the tree of an invalid source can miss the nodes the AST needs
+			if err := recover(); err != nil {
+				if len(errs) == 0 {
+					panic(err)
+				}
+				result = nil
+			}
+		}()
+		result = b.sourceUnit(root)
+	}()
+
+	return &Parser{
+		Result: result,
+		Errors: sortErrors(append(errs, b.errors...)),
+	}
+}
+
+// treeSitterBuilder converts the syntax tree of tree-sitter into the AST
+type treeSitterBuilder struct {
+	src []byte
+
+	// srcMap is only set if the locations of the nodes are tracked
+	srcMap *sourceMap
+
+	// errSrc is the source map of the errors of the literals (i.e. an invalid
+	// escape sequence)
+	errSrc *sourceMap
+	errors []*SyntaxError
+}
+
+// set sets the type of a node and its location if locations are enabled
+func (b *treeSitterBuilder) set(node INode, typ string, n *sitter.Node) {
+	node.SetTypeName(typ)
+	b.locate(node, n.StartByte(), n.EndByte())
+}
+
+// locate sets the location of a node from the start and end bytes
+func (b *treeSitterBuilder) locate(node INode, start, end uint32) {
+	if b.srcMap == nil {
+		return
+	}
+	node.SetLoc(&Location{
+		Start: b.srcMap.position(int(start)),
+		End:   b.srcMap.position(int(end)),
+	})
+}
+
+func (b *treeSitterBuilder) text(n *sitter.Node) string {
+	return n.Content(b.src)
+}
+
+// allChildren returns the children of a node
+func allChildren(n *sitter.Node) []*sitter.Node {
+	res := []*sitter.Node{}
+	c := sitter.NewTreeCursor(n)
+	defer c.Close()
+	if !c.GoToFirstChild() {
+		return res
+	}
+	for {
+		res = append(res, c.CurrentNode())
+		if !c.GoToNextSibling() {
+			return res
+		}
+	}
+}
+
+// children returns the children of a node without the comments
+func children(n *sitter.Node) []*sitter.Node {
+	res := []*sitter.Node{}
+	for _, child := range allChildren(n) {
+		if child.Type() != "comment" {
+			res = append(res, child)
+		}
+	}
+	return res
+}
+
+// namedChildren returns the named children of a node without the comments
+// and the errors
+func namedChildren(n *sitter.Node) []*sitter.Node {
+	res := []*sitter.Node{}
+	for _, child := range children(n) {
+		if child.IsNamed() && child.Type() != "ERROR" {
+			res = append(res, child)
+		}
+	}
+	return res
+}
+
+// fieldChildren returns the children of a node with the given field name
+func fieldChildren(n *sitter.Node, field string) []*sitter.Node {
+	res := []*sitter.Node{}
+	c := sitter.NewTreeCursor(n)
+	defer c.Close()
+	if !c.GoToFirstChild() {
+		return res
+	}
+	for {
+		if c.CurrentFieldName() == field {
+			res = append(res, c.CurrentNode())
+		}
+		if !c.GoToNextSibling() {
+			return res
+		}
+	}
+}
+
+// childOf returns the first child of a node with the given type
+func childOf(n *sitter.Node, typ string) *sitter.Node {
+	for _, child := range children(n) {
+		if child.Type() == typ {
+			return child
+		}
+	}
+	return nil
+}
+
+// between returns the children of a node after the first child of type open
+// and before the next child of type close
+func between(n *sitter.Node, open, close string) []*sitter.Node {
+	res := []*sitter.Node{}
+	inside := false
+	for _, child := range children(n) {
+		if !inside {
+			inside = child.Type() == open
+			continue
+		}
+		if child.Type() == close {
+			break
+		}
+		res = append(res, child)
+	}
+	return res
+}
+
+// commaList returns the elements of a comma separated list with a nil for
+// the missing ones (i.e. 'a,,b' is a, nil, b) like mapCommasToNulls
+func commaList(nodes []*sitter.Node) []*sitter.Node {
+	res := []*sitter.Node{}
+	if len(nodes) == 0 {
+		return res
+	}
+	comma := true
+	for _, n := range nodes {
+		if n.Type() == "ERROR" {
+			continue
+		}
+		if n.Type() == "," {
+			if comma {
+				res = append(res, nil)
+			}
+			comma = true
+		} else {
+			res = append(res, n)
+			comma = false
+		}
+	}
+	if comma {
+		res = append(res, nil)
+	}
+	return res
+}
+
+func (b *treeSitterBuilder) sourceUnit(n *sitter.Node) INode {
+	decl := &SourceUnit{
+		Children: []interface{}{},
+	}
+	b.set(decl, "SourceUnit", n)
+	for _, child := range namedChildren(n) {
+		decl.Children = append(decl.Children, b.declaration(child))
+	}
+	// the antlr tree ends with the EOF token which is visited as nil
+	decl.Children = append(decl.Children, nil)
+	return decl
+}
+
+// declaration builds the top level declarations and the contract parts
+func (b *treeSitterBuilder) declaration(n *sitter.Node) interface{} {
+	switch n.Type() {
+	case "pragma_directive":
+		return b.pragmaDirective(n)
+	case "import_directive":
+		return b.importDirective(n)
+	case "contract_declaration", "interface_declaration", "library_declaration":
+		return b.contractDefinition(n)
+	case "struct_declaration":
+		return b.structDefinition(n)
+	case "enum_declaration":
+		return b.enumDefinition(n)
+	case "event_definition":
+		return b.eventDefinition(n)
+	case "function_definition", "constructor_definition", "fallback_receive_definition":
+		return b.functionDefinition(n)
+	case "modifier_definition":
+		return b.modifierDefinition(n)
+	case "state_variable_declaration":
+		return b.stateVariableDeclaration(n)
+	case "constant_variable_declaration":
+		return b.fileLevelConstant(n)
+	case "using_directive":
+		return b.usingForDeclaration(n)
+	}
+	return nil
+}
+
+func (b *treeSitterBuilder) pragmaDirective(n *sitter.Node) INode {
+	// antlr drops the whitespace of the value (i.e. '>=0.4.0<0.9.0')
+	var parts []string
+	for _, child := range namedChildren(n) {
+		parts = append(parts, strings.Fields(b.text(child))...)
+	}
+	decl := &PragmaDirective{}
+	if len(parts) != 0 {
+		decl.Name = parts[0]
+		decl.Value = strings.TrimSuffix(strings.Join(parts[1:], ""), ";")
+	}
+	b.set(decl, "PragmaDirective", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) identifier(n *sitter.Node) *Identifier {
+	decl := &Identifier{
+		Name: b.text(n),
+	}
+	b.set(decl, "Identifier", n)
+	return decl
+}
+
+// optIdentifier returns the identifier of a node if it exists
+func (b *treeSitterBuilder) optIdentifier(n *sitter.Node) interface{} {
+	if n == nil {
+		return nil
+	}
+	return b.identifier(n)
+}
+
+func (b *treeSitterBuilder) importDirective(n *sitter.Node) INode {
+	var path string
+	if source := n.ChildByFieldName("source"); source != nil {
+		path = strings.Trim(b.text(source), "\"")
+	}
+
+	decl := &ImportDirective{
+		Path: path,
+		PathLiteral: &StringLiteral{
+			Node:      Node{Type: "StringLiteral"},
+			Value:     path,
+			Raw:       path,
+			Parts:     []string{path},
+			IsUnicode: []bool{false},
+		},
+	}
+
+	if origins := fieldChildren(n, "import_origin"); len(origins) != 0 {
+		// the alias of a symbol follows it
+		for _, origin := range origins {
+			var aliasIdentifier *Identifier
+			alias := ""
+			if next := origin.NextNamedSibling(); next != nil && next.Type() == "identifier" && !isField(n, next, "import_origin") {
+				alias = b.text(next)
+				aliasIdentifier = b.identifier(next)
+			}
+			decl.SymbolAliases = append(decl.SymbolAliases, []string{b.text(origin), alias})
+			decl.SymbolAliasesIdentifiers = append(decl.SymbolAliasesIdentifiers, []*Identifier{
+				b.identifier(origin), aliasIdentifier,
+			})
+		}
+	} else {
+		for _, child := range namedChildren(n) {
+			if child.Type() == "identifier" {
+				decl.UnitAlias = b.text(child)
+				decl.UnitAliasIdentifier = b.identifier(child)
+			}
+		}
+	}
+	b.set(decl, "ImportDirective", n)
+	return decl
+}
+
+// isField returns whether the child of a node has the given field name
+func isField(n, child *sitter.Node, field string) bool {
+	for _, c := range fieldChildren(n, field) {
+		if c.Equal(child) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *treeSitterBuilder) contractDefinition(n *sitter.Node) INode {
+	decl := &ContractDefinition{
+		SubNodes:      []interface{}{},
+		BaseContracts: []interface{}{},
+	}
+	for _, child := range children(n) {
+		switch child.Type() {
+		case "abstract":
+			decl.IsAbstract = true
+		case "contract", "interface", "library":
+			decl.Kind = child.Type()
+		case "inheritance_specifier":
+			decl.BaseContracts = append(decl.BaseContracts, b.inheritanceSpecifier(child))
+		}
+	}
+	if name := n.ChildByFieldName("name"); name != nil {
+		decl.Name = b.text(name)
+	}
+	if body := n.ChildByFieldName("body"); body != nil {
+		for _, child := range namedChildren(body) {
+			decl.SubNodes = append(decl.SubNodes, b.declaration(child))
+		}
+	}
+	b.set(decl, "ContractDefinition", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) inheritanceSpecifier(n *sitter.Node) INode {
+	decl := &InheritanceSpecifier{
+		Arguments: []interface{}{},
+	}
+	if ancestor := fieldChildren(n, "ancestor"); len(ancestor) != 0 {
+		decl.BaseName = b.userDefinedTypeName(ancestor[0], ancestor[len(ancestor)-1])
+	}
+	for _, arg := range fieldChildren(n, "ancestor_arguments") {
+		if arg.IsNamed() {
+			decl.Arguments = append(decl.Arguments, b.expression(arg))
+		}
+	}
+	b.set(decl, "InheritanceSpecifier", n)
+	return decl
+}
+
+// userDefinedTypeName builds the type name that spans from the start of the
+// first node to the end of the last one (i.e. Foo.Bar)
+func (b *treeSitterBuilder) userDefinedTypeName(first, last *sitter.Node) INode {
+	decl := &UserDefinedTypeName{
+		NamePath: strings.Join(strings.Fields(string(b.src[first.StartByte():last.EndByte()])), ""),
+	}
+	decl.SetTypeName("UserDefinedTypeName")
+	b.locate(decl, first.StartByte(), last.EndByte())
+	return decl
+}
+
+func (b *treeSitterBuilder) structDefinition(n *sitter.Node) INode {
+	decl := &StructDefinition{
+		Members: []interface{}{},
+	}
+	if name := n.ChildByFieldName("struct_name"); name != nil {
+		decl.Name = b.text(name)
+	}
+	for _, child := range namedChildren(n) {
+		if child.Type() == "struct_member" {
+			decl.Members = append(decl.Members, b.variableDeclaration(child))
+		}
+	}
+	b.set(decl, "StructDefinition", n)
+	return decl
+}
+
+// variableDeclaration builds the declaration of a struct member or a local variable
+func (b *treeSitterBuilder) variableDeclaration(n *sitter.Node) INode {
+	decl := &VariableDeclaration{}
+	for _, child := range namedChildren(n) {
+		if child.Type() == "type_name" {
+			decl.TypeName = b.typeName(child)
+			break
+		}
+	}
+	if name := n.ChildByFieldName("name"); name != nil {
+		decl.Name = b.text(name)
+		decl.Identifier = b.identifier(name)
+	}
+	if loc := n.ChildByFieldName("storage_location"); loc != nil {
+		decl.StorageLocation = b.text(loc)
+	}
+	decl.SetTypeName("VariableDeclaration")
+
+	// the members of a struct end before their semicolon
+	end := n.EndByte()
+	if nodes := namedChildren(n); len(nodes) != 0 && strings.HasSuffix(b.text(n), ";") {
+		end = nodes[len(nodes)-1].EndByte()
+	}
+	b.locate(decl, n.StartByte(), end)
+	return decl
+}
+
+func (b *treeSitterBuilder) enumDefinition(n *sitter.Node) INode {
+	decl := &EnumDefinition{
+		Members: []interface{}{},
+	}
+	if name := n.ChildByFieldName("enum_type_name"); name != nil {
+		decl.Name = b.text(name)
+	}
+	for _, child := range namedChildren(n) {
+		if child.Type() == "enum_value" {
+			value := &EnumValue{
+				Name: b.text(child),
+			}
+			b.set(value, "EnumValue", child)
+			decl.Members = append(decl.Members, value)
+		}
+	}
+	b.set(decl, "EnumDefinition", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) eventDefinition(n *sitter.Node) INode {
+	decl := &EventDefinition{
+		Parameters:  []interface{}{},
+		IsAnonymous: childOf(n, "anonymous") != nil,
+	}
+	if name := n.ChildByFieldName("name"); name != nil {
+		decl.Name = b.text(name)
+	}
+	for _, child := range namedChildren(n) {
+		if child.Type() != "event_paramater" {
+			continue
+		}
+		param := &VariableDeclaration{
+			IsIndexed: childOf(child, "indexed") != nil,
+		}
+		if typ := child.ChildByFieldName("type"); typ != nil {
+			param.TypeName = b.typeName(typ)
+		}
+		if name := child.ChildByFieldName("name"); name != nil {
+			param.Name = b.text(name)
+			param.Identifier = b.identifier(name)
+		}
+		b.set(param, "VariableDeclaration", child)
+		decl.Parameters = append(decl.Parameters, param)
+	}
+	b.set(decl, "EventDefinition", n)
+	return decl
+}
+
+// parameters returns the parameters that are children of a node
+func (b *treeSitterBuilder) parameters(n *sitter.Node) []interface{} {
+	var params []interface{}
+	for _, child := range namedChildren(n) {
+		if child.Type() == "parameter" {
+			params = append(params, b.parameter(child))
+		}
+	}
+	return params
+}
+
+func (b *treeSitterBuilder) parameter(n *sitter.Node) INode {
+	decl := &VariableDeclaration{}
+	if typ := n.ChildByFieldName("type"); typ != nil {
+		decl.TypeName = b.typeName(typ)
+	}
+	if loc := n.ChildByFieldName("storage_location"); loc != nil {
+		decl.StorageLocation = b.text(loc)
+	}
+	if name := n.ChildByFieldName("name"); name != nil {
+		decl.Name = b.text(name)
+		decl.Identifier = b.identifier(name)
+	}
+	b.set(decl, "VariableDeclaration", n)
+	return decl
+}
+
+// overrides returns the base contracts of an override specifier
+func (b *treeSitterBuilder) overrides(n *sitter.Node) []interface{} {
+	var res []interface{}
+	for _, child := range namedChildren(n) {
+		res = append(res, b.userDefinedTypeName(child, child))
+	}
+	return res
+}
+
+func (b *treeSitterBuilder) functionDefinition(n *sitter.Node) INode {
+	decl := &FunctionDefinition{
+		Modifiers:  []interface{}{},
+		Visibility: "default",
+	}
+
+	kind := ""
+	if first := n.Child(0); first != nil {
+		kind = first.Type()
+	}
+	switch n.Type() {
+	case "constructor_definition":
+		decl.IsConstructor = true
+	case "fallback_receive_definition":
+		switch kind {
+		case "fallback":
+			decl.Visibility = "external"
+			decl.IsFallback = true
+		case "receive":
+			decl.Visibility = "external"
+			decl.IsReceiveEther = true
+		default:
+			// the unnamed function of the old versions
+			decl.IsFallback = true
+		}
+	case "function_definition":
+		if name := n.ChildByFieldName("function_name"); name != nil {
+			decl.Name = b.text(name)
+		}
+		decl.IsFallback = decl.Name == ""
+	}
+
+	for _, child := range children(n) {
+		switch child.Type() {
+		case "parameter":
+			decl.Parameters = append(decl.Parameters, b.parameter(child))
+
+		case "visibility", "public", "internal", "external", "private":
+			if decl.IsFallback && kind != "function" || decl.IsReceiveEther {
+				// the visibility of the fallback and receive functions is external
+				continue
+			}
+			vis := b.text(child)
+			if decl.IsConstructor && vis != "public" && vis != "internal" {
+				continue
+			}
+			decl.Visibility = vis
+
+		case "state_mutability", "payable":
+			if decl.StateMutability == "" {
+				decl.StateMutability = b.text(child)
+			}
+
+		case "virtual":
+			decl.IsVirtual = true
+
+		case "override_specifier":
+			decl.IsOverride = true
+			decl.Override = b.overrides(child)
+
+		case "modifier_invocation":
+			// 'constant' is a state mutability in antlr
+			if b.text(child) == "constant" {
+				if decl.StateMutability == "" {
+					decl.StateMutability = "constant"
+				}
+				continue
+			}
+			decl.Modifiers = append(decl.Modifiers, b.modifierInvocation(child))
+
+		case "return_type_definition":
+			decl.ReturnParameters = b.parameters(child)
+
+		case "function_body":
+			decl.Body = b.block(child)
+		}
+	}
+	b.set(decl, "FunctionDefinition", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) modifierInvocation(n *sitter.Node) INode {
+	decl := &ModifierInvocation{}
+	nodes := namedChildren(n)
+	if len(nodes) != 0 {
+		decl.Name = b.text(nodes[0])
+	}
+	if childOf(n, "(") != nil {
+		decl.Arguments = []interface{}{}
+		for _, arg := range nodes[1:] {
+			decl.Arguments = append(decl.Arguments, b.expression(arg))
+		}
+	}
+	b.set(decl, "ModifierInvocation", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) modifierDefinition(n *sitter.Node) INode {
+	decl := &ModifierDefinition{
+		Override: []interface{}{},
+	}
+	if name := n.ChildByFieldName("name"); name != nil {
+		decl.Name = b.text(name)
+	}
+	for _, child := range namedChildren(n) {
+		switch child.Type() {
+		case "virtual":
+			decl.IsVirtual = true
+		case "override_specifier":
+			decl.Override = append(decl.Override, b.overrides(child)...)
+		case "function_body":
+			decl.Body = b.block(child)
+		}
+	}
+	// an empty parameter list is equivalent to no parameter list
+	if params := b.parameters(n); params != nil {
+		decl.Parameters = params
+	}
+	b.set(decl, "ModifierDefinition", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) stateVariableDeclaration(n *sitter.Node) INode {
+	vv := &StateVariableDeclarationVariable{
+		VariableDeclaration: VariableDeclaration{
+			Visibility: "default",
+			IsStateVar: true,
+		},
+	}
+	for _, child := range children(n) {
+		switch child.Type() {
+		case "constant":
+			vv.IsDeclaredConst = true
+		case "immutable":
+			vv.IsInmutable = true
+		case "visibility":
+			switch vis := b.text(child); vis {
+			case "internal", "public", "private":
+				vv.Visibility = vis
+			}
+		case "override_specifier":
+			vv.Override = b.overrides(child)
+		}
+	}
+	if typ := n.ChildByFieldName("type"); typ != nil {
+		vv.TypeName = b.typeName(typ)
+	}
+	if name := n.ChildByFieldName("name"); name != nil {
+		vv.Name = b.text(name)
+		vv.Identifier = b.identifier(name)
+	}
+	if value := n.ChildByFieldName("value"); value != nil {
+		vv.Expression = b.expression(value)
+	}
+	b.set(vv, "VariableDeclaration", n)
+
+	decl := &StateVariableDeclaration{
+		Variables: []interface{}{vv},
+	}
+	b.set(decl, "StateVariableDeclaration", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) fileLevelConstant(n *sitter.Node) INode {
+	decl := &FileLevelConstant{
+		IsDeclaredConst: true,
+	}
+	if name := n.ChildByFieldName("name"); name != nil {
+		decl.Name = b.text(name)
+	}
+	if typ := n.ChildByFieldName("type"); typ != nil {
+		decl.TypeName = b.typeName(typ)
+	}
+	if value := n.ChildByFieldName("value"); value != nil {
+		decl.InitialValue = b.expression(value)
+	}
+	b.set(decl, "FileLevelConstant", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) usingForDeclaration(n *sitter.Node) INode {
+	decl := &UsingForDeclaration{}
+	if alias := childOf(n, "type_alias"); alias != nil {
+		decl.LibraryName = b.text(alias)
+	}
+	if source := n.ChildByFieldName("source"); source != nil && source.Type() != "any_source_type" {
+		decl.TypeName = b.typeName(source)
+	}
+	b.set(decl, "UsingForDeclaration", n)
+	return decl
+}
+
+// typeName builds the type names
+func (b *treeSitterBuilder) typeName(n *sitter.Node) interface{} {
+	switch n.Type() {
+	case "primitive_type":
+		return b.elementaryTypeName(n)
+	case "identifier":
+		return b.userDefinedTypeName(n, n)
+	case "type_name":
+	default:
+		return nil
+	}
+
+	nodes := namedChildren(n)
+	switch {
+	case childOf(n, "mapping") != nil:
+		decl := &Mapping{}
+		if len(nodes) == 2 {
+			decl.KeyType = b.typeName(nodes[0])
+			decl.ValueType = b.typeName(nodes[1])
+		}
+		b.set(decl, "Mapping", n)
+		return decl
+
+	case childOf(n, "[") != nil:
+		decl := &ArrayTypeName{}
+		if len(nodes) != 0 {
+			decl.BaseTypeName = b.typeName(nodes[0])
+		}
+		if len(nodes) > 1 {
+			decl.Length = b.expression(nodes[1])
+		}
+		b.set(decl, "ArrayTypeName", n)
+		return decl
+
+	case childOf(n, "function") != nil:
+		return b.functionTypeName(n)
+
+	case len(nodes) == 1 && nodes[0].Type() != "identifier":
+		return b.typeName(nodes[0])
+
+	case len(nodes) != 0:
+		// the path of a user defined type (i.e. Foo.Bar)
+		return b.userDefinedTypeName(n, n)
+	}
+	return nil
+}
+
+func (b *treeSitterBuilder) elementaryTypeName(n *sitter.Node) INode {
+	decl := &ElementaryTypeName{
+		Name: b.text(n),
+	}
+	if childOf(n, "payable") != nil {
+		decl.Name = "address"
+		decl.StateMutability = "payable"
+	}
+	b.set(decl, "ElementaryTypeName", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) functionTypeName(n *sitter.Node) INode {
+	decl := &FunctionTypeName{
+		ParameterTypes: []interface{}{},
+		ReturnTypes:    []interface{}{},
+		Visibility:     "default",
+	}
+	returns := false
+	for _, child := range children(n) {
+		switch child.Type() {
+		case "returns", "return_type_definition":
+			returns = true
+		case "visibility", "internal", "external":
+			switch vis := b.text(child); vis {
+			case "internal", "external":
+				decl.Visibility = vis
+			}
+		case "state_mutability":
+			if decl.StateMutability == "" {
+				decl.StateMutability = b.text(child)
+			}
+		}
+		params := []*sitter.Node{child}
+		if child.Type() == "return_type_definition" {
+			params = namedChildren(child)
+		}
+		for _, p := range params {
+			if p.Type() != "parameter" {
+				continue
+			}
+			param := &VariableDeclaration{}
+			if typ := p.ChildByFieldName("type"); typ != nil {
+				param.TypeName = b.typeName(typ)
+			}
+			if loc := p.ChildByFieldName("storage_location"); loc != nil {
+				param.StorageLocation = b.text(loc)
+			}
+			b.set(param, "VariableDeclaration", p)
+			if returns {
+				decl.ReturnTypes = append(decl.ReturnTypes, param)
+			} else {
+				decl.ParameterTypes = append(decl.ParameterTypes, param)
+			}
+		}
+	}
+	b.set(decl, "FunctionTypeName", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) block(n *sitter.Node) INode {
+	decl := &Block{
+		Statements: []interface{}{},
+	}
+	for _, child := range namedChildren(n) {
+		decl.Statements = append(decl.Statements, b.statement(child))
+	}
+	b.set(decl, "Block", n)
+	return decl
+}
+
+// statement builds the statements
+func (b *treeSitterBuilder) statement(n *sitter.Node) interface{} {
+	nodes := namedChildren(n)
+
+	switch n.Type() {
+	case "block_statement", "function_body":
+		return b.block(n)
+
+	case "expression_statement":
+		if len(nodes) == 1 && nodes[0].Type() == "identifier" && b.text(nodes[0]) == "throw" {
+			decl := &ThrowStatement{}
+			b.set(decl, "ThrowStatement", n)
+			return decl
+		}
+		decl := &ExpressionStatement{}
+		if len(nodes) != 0 {
+			decl.Expression = b.expression(nodes[0])
+		}
+		b.set(decl, "ExpressionStatement", n)
+		return decl
+
+	case "variable_declaration_statement":
+		return b.variableDeclarationStatement(n)
+
+	case "if_statement":
+		decl := &IfStatement{}
+		if len(nodes) > 1 {
+			decl.Condition = b.expression(nodes[0])
+			decl.TrueBody = b.statement(nodes[1])
+		}
+		if len(nodes) > 2 {
+			decl.FalseBody = b.statement(nodes[2])
+		}
+		b.set(decl, "IfStatement", n)
+		return decl
+
+	case "for_statement":
+		return b.forStatement(n)
+
+	case "while_statement":
+		decl := &WhileStatement{}
+		if len(nodes) == 2 {
+			decl.Condition = b.expression(nodes[0])
+			decl.Body = b.statement(nodes[1])
+		}
+		b.set(decl, "WhileStatement", n)
+		return decl
+
+	case "do_while_statement":
+		decl := &DoWhileStatement{}
+		if len(nodes) == 2 {
+			decl.Body = b.statement(nodes[0])
+			decl.Condition = b.expression(nodes[1])
+		}
+		b.set(decl, "DoWhileStatement", n)
+		return decl
+
+	case "return_statement":
+		decl := &ReturnStatement{}
+		if len(nodes) != 0 {
+			decl.Expression = b.expression(nodes[0])
+		}
+		b.set(decl, "ReturnStatement", n)
+		return decl
+
+	case "break_statement":
+		decl := &BreakStatement{}
+		b.set(decl, "BreakStatement", n)
+		return decl
+
+	case "continue_statement":
+		decl := &ContinueStatement{}
+		b.set(decl, "ContinueStatement", n)
+		return decl
+
+	case "emit_statement":
+		decl := &EmitStatement{}
+		if len(nodes) != 0 {
+			call := &FunctionCall{
+				Expression:  b.expression(nodes[0]),
+				Arguments:   []interface{}{},
+				Names:       []interface{}{},
+				Identifiers: []interface{}{},
+			}
+			b.callArguments(call, n)
+			call.SetTypeName("FunctionCall")
+			b.locate(call, nodes[0].StartByte(), n.EndByte())
+			if closing := lastChildOf(n, ")"); closing != nil {
+				b.locate(call, nodes[0].StartByte(), closing.EndByte())
+			}
+			decl.EventCall = call
+		}
+		b.set(decl, "EmitStatement", n)
+		return decl
+
+	case "try_statement":
+		return b.tryStatement(n)
+
+	case "assembly_statement":
+		decl := &InlineAssemblyStatement{
+			Body: b.assemblyBlock(n),
+		}
+		b.set(decl, "InlineAssemblyStatement", n)
+		return decl
+	}
+	return nil
+}
+
+// lastChildOf returns the last child of a node with the given type
+func lastChildOf(n *sitter.Node, typ string) *sitter.Node {
+	var res *sitter.Node
+	for _, child := range children(n) {
+		if child.Type() == typ {
+			res = child
+		}
+	}
+	return res
+}
+
+func (b *treeSitterBuilder) variableDeclarationStatement(n *sitter.Node) INode {
+	decl := &VariableDeclarationStatement{}
+	for _, child := range children(n) {
+		switch child.Type() {
+		case "variable_declaration":
+			decl.Variables = []interface{}{b.variableDeclaration(child)}
+
+		case "variable_declaration_tuple":
+			isVar := childOf(child, "var") != nil
+			decl.Variables = []interface{}{}
+			for _, elem := range commaList(between(child, "(", ")")) {
+				if elem == nil {
+					decl.Variables = append(decl.Variables, nil)
+					continue
+				}
+				if isVar {
+					// the identifiers of a 'var' declaration have no type
+					varDecl := &VariableDeclaration{
+						Name:       b.text(elem),
+						Identifier: b.identifier(elem),
+					}
+					b.set(varDecl, "VariableDeclaration", elem)
+					decl.Variables = append(decl.Variables, varDecl)
+				} else {
+					decl.Variables = append(decl.Variables, b.variableDeclaration(elem))
+				}
+			}
+
+		default:
+			if child.IsNamed() && child.Type() != "ERROR" {
+				decl.InitialValue = b.expression(child)
+			}
+		}
+	}
+	b.set(decl, "VariableDeclarationStatement", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) forStatement(n *sitter.Node) INode {
+	decl := &ForStatement{}
+	loop := &ExpressionStatement{
+		Node: Node{Type: "ExpressionStatement"},
+	}
+
+	// the init statement, the condition and the loop expression are
+	// optional and the semicolons are not in the tree, the slot of each
+	// part is found counting the semicolons that precede it
+	slot := 0
+	var pos uint32
+	inside := false
+	for _, child := range children(n) {
+		if !inside {
+			if child.Type() == "(" {
+				inside = true
+				pos = child.EndByte()
+			}
+			continue
+		}
+		if child.Type() == ")" {
+			break
+		}
+		slot += strings.Count(string(b.src[pos:child.StartByte()]), ";")
+		pos = child.EndByte()
+		if !child.IsNamed() || child.Type() == "ERROR" {
+			continue
+		}
+
+		switch slot {
+		case 0:
+			decl.InitExpression = b.statement(child)
+		case 1:
+			if stmt, ok := b.statement(child).(*ExpressionStatement); ok {
+				decl.ConditionExpression = stmt.Expression
+			}
+		default:
+			loop.Expression = b.expression(child)
+		}
+		if strings.HasSuffix(b.text(child), ";") {
+			slot++
+		}
+	}
+	decl.LoopExpression = loop
+
+	if nodes := namedChildren(n); len(nodes) != 0 {
+		decl.Body = b.statement(nodes[len(nodes)-1])
+	}
+	b.set(decl, "ForStatement", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) tryStatement(n *sitter.Node) INode {
+	decl := &TryStatement{
+		CatchClause: []interface{}{},
+	}
+	for _, child := range children(n) {
+		switch child.Type() {
+		case "returns":
+			decl.ReturnParameters = b.parameters(n)
+		case "block_statement":
+			decl.Body = b.block(child)
+		case "catch_clause":
+			decl.CatchClause = append(decl.CatchClause, b.catchClause(child))
+		default:
+			if decl.Expression == nil && child.IsNamed() && child.Type() != "ERROR" && child.Type() != "parameter" {
+				decl.Expression = b.expression(child)
+			}
+		}
+	}
+	b.set(decl, "TryStatement", n)
+	return decl
+}
+
+func (b *treeSitterBuilder) catchClause(n *sitter.Node) INode {
+	decl := &CatchClause{}
+	if kind := childOf(n, "identifier"); kind != nil {
+		decl.Kind = b.text(kind)
+	}
+	decl.IsReasonType = decl.Kind == "Error"
+	if childOf(n, "(") != nil {
+		decl.Parameters = b.parameters(n)
+	}
+	if body := childOf(n, "block_statement"); body != nil {
+		decl.Body = b.block(body)
+	}
+	b.set(decl, "CatchClause", n)
+	return decl
+}
+
+// expression builds the expressions
+func (b *treeSitterBuilder) expression(n *sitter.Node) interface{} {
+	nodes := namedChildren(n)
+
+	switch n.Type() {
+	case "identifier":
+		return b.identifier(n)
+
+	case "number_literal":
+		number, unit := b.text(n), ""
+		if u := childOf(n, "number_unit"); u != nil {
+			number = strings.TrimSpace(string(b.src[n.StartByte():u.StartByte()]))
+			unit = b.text(u)
+		}
+		decl, err := newNumberLiteral(number, unit)
+		if err != nil {
+			b.literalError(int(n.StartByte()), err)
+		}
+		b.set(decl, "NumberLiteral", n)
+		return decl
+
+	case "boolean_literal":
+		decl := &BooleanLiteral{
+			Value: b.text(n) == "true",
+		}
+		b.set(decl, "BooleanLiteral", n)
+		return decl
+
+	case "string_literal", "unicode_string_literal":
+		texts, offsets := literalFragments(b.text(n))
+		decl, errs := newStringLiteral(texts)
+		for i, err := range errs {
+			b.literalError(int(n.StartByte())+offsets[i], err)
+		}
+		b.set(decl, "StringLiteral", n)
+		return decl
+
+	case "hex_string_literal":
+		texts, offsets := literalFragments(b.text(n))
+		decl, errs := newHexLiteral(texts)
+		for i, err := range errs {
+			b.literalError(int(n.StartByte())+offsets[i], err)
+		}
+		b.set(decl, "HexLiteral", n)
+		return decl
+
+	case "parenthesized_expression":
+		decl := &TupleExpression{
+			Components: []interface{}{},
+		}
+		if len(nodes) != 0 {
+			decl.Components = append(decl.Components, b.expression(nodes[0]))
+		}
+		b.set(decl, "TupleExpression", n)
+		return decl
+
+	case "tuple_expression", "inline_array_expression":
+		open, close := "(", ")"
+		if n.Type() == "inline_array_expression" {
+			open, close = "[", "]"
+		}
+		decl := &TupleExpression{
+			Components: []interface{}{},
+			IsArray:    open == "[",
+		}
+		for _, elem := range commaList(between(n, open, close)) {
+			if elem == nil {
+				decl.Components = append(decl.Components, nil)
+			} else {
+				decl.Components = append(decl.Components, b.expression(elem))
+			}
+		}
+		b.set(decl, "TupleExpression", n)
+		return decl
+
+	case "unary_expression", "update_expression":
+		decl := &UnaryOperation{
+			IsPrefix: true,
+		}
+		op, arg := n.ChildByFieldName("operator"), n.ChildByFieldName("argument")
+		if op != nil {
+			decl.Operator = b.text(op)
+		}
+		if arg != nil {
+			decl.SubExpression = b.expression(arg)
+			decl.IsPrefix = op == nil || op.StartByte() < arg.StartByte()
+		}
+		b.set(decl, "UnaryOperation", n)
+		return decl
+
+	case "binary_expression", "assignment_expression", "augmented_assignment_expression":
+		decl := &BinaryOperation{}
+		if op := n.ChildByFieldName("operator"); op != nil {
+			decl.Operator = b.text(op)
+		} else {
+			// the operator of the assignments has no field
+			for _, child := range children(n) {
+				if !child.IsNamed() {
+					decl.Operator = child.Type()
+					break
+				}
+			}
+		}
+		if left := n.ChildByFieldName("left"); left != nil {
+			decl.Left = b.expression(left)
+		}
+		if right := n.ChildByFieldName("right"); right != nil {
+			decl.Right = b.expression(right)
+		}
+		b.set(decl, "BinaryOperation", n)
+		return decl
+
+	case "ternary_expression":
+		decl := &Conditional{}
+		if len(nodes) == 3 {
+			decl.Condition = b.expression(nodes[0])
+			decl.TrueExpression = b.expression(nodes[1])
+			decl.FalseExpression = b.expression(nodes[2])
+		}
+		b.set(decl, "Conditional", n)
+		return decl
+
+	case "member_expression":
+		decl := &MemberAccess{}
+		if object := n.ChildByFieldName("object"); object != nil {
+			decl.Expression = b.expression(object)
+		}
+		if property := n.ChildByFieldName("property"); property != nil {
+			decl.MemberName = b.text(property)
+		}
+		b.set(decl, "MemberAccess", n)
+		return decl
+
+	case "array_access":
+		decl := &IndexAccess{}
+		if base := n.ChildByFieldName("base"); base != nil {
+			decl.Base = b.expression(base)
+		}
+		if index := n.ChildByFieldName("index"); index != nil {
+			decl.Index = b.expression(index)
+		}
+		b.set(decl, "IndexAccess", n)
+		return decl
+
+	case "slice_access":
+		decl := &IndexRangeAccess{}
+		if base := n.ChildByFieldName("base"); base != nil {
+			decl.Base = b.expression(base)
+		}
+		if from := n.ChildByFieldName("from"); from != nil {
+			decl.IndexStart = b.expression(from)
+		}
+		if to := n.ChildByFieldName("to"); to != nil {
+			decl.IndexEnd = b.expression(to)
+		}
+		b.set(decl, "IndexRangeAccess", n)
+		return decl
+
+	case "call_expression":
+		decl := &FunctionCall{}
+		if len(nodes) != 0 {
+			decl.Expression = b.expression(nodes[0])
+		}
+		b.callArguments(decl, n)
+		b.set(decl, "FunctionCall", n)
+		return decl
+
+	case "struct_expression":
+		decl := &NameValueExpression{}
+		if len(nodes) != 0 {
+			decl.Expression = b.expression(nodes[0])
+		}
+		list := &NameValueList{
+			Names:       []string{},
+			Identifiers: []interface{}{},
+			Args:        []interface{}{},
+		}
+		args := between(n, "{", "}")
+		for i := 0; i+2 < len(args); i += 4 {
+			list.Names = append(list.Names, b.text(args[i]))
+			list.Identifiers = append(list.Identifiers, b.identifier(args[i]))
+			list.Args = append(list.Args, b.expression(args[i+2]))
+		}
+		list.SetTypeName("NameValueList")
+		if len(args) != 0 {
+			b.locate(list, args[0].StartByte(), args[len(args)-1].EndByte())
+		}
+		decl.Arguments = list
+		b.set(decl, "NameValueExpression", n)
+		return decl
+
+	case "new_expression":
+		decl := &NewExpression{}
+		if len(nodes) != 0 {
+			decl.TypeName = b.typeName(nodes[0])
+		}
+		b.set(decl, "NewExpression", n)
+		return decl
+
+	case "type_cast_expression", "payable_conversion_expression", "meta_type_expression":
+		// the conversions and type(...) are calls in antlr
+		decl := &FunctionCall{}
+		first := n.Child(0)
+		switch n.Type() {
+		case "type_cast_expression":
+			expr := &TypeNameExpression{
+				TypeName: b.elementaryTypeName(first),
+			}
+			b.set(expr, "TypeNameExpression", first)
+			decl.Expression = expr
+			nodes = nodes[1:]
+		default:
+			decl.Expression = b.identifier(first)
+		}
+		for _, arg := range nodes {
+			decl.Arguments = append(decl.Arguments, b.expression(arg))
+		}
+		b.set(decl, "FunctionCall", n)
+		return decl
+
+	case "type_name":
+		// a type used as an expression (i.e. the argument of type(...))
+		typ := b.typeName(n)
+		if name, ok := typ.(*UserDefinedTypeName); ok && !strings.Contains(name.NamePath, ".") {
+			return b.identifier(n)
+		}
+		if name, ok := typ.(*ElementaryTypeName); ok {
+			expr := &TypeNameExpression{
+				TypeName: name,
+			}
+			b.set(expr, "TypeNameExpression", n)
+			return expr
+		}
+		return typ
+
+	case "primitive_type":
+		expr := &TypeNameExpression{
+			TypeName: b.elementaryTypeName(n),
+		}
+		b.set(expr, "TypeNameExpression", n)
+		return expr
+	}
+	return nil
+}
+
+// callArguments sets the arguments of a call, they are either a list of
+// expressions or a list of names and values between braces
+func (b *treeSitterBuilder) callArguments(decl *FunctionCall, n *sitter.Node) {
+	args := between(n, "(", ")")
+	if len(args) != 0 && args[0].Type() == "{" {
+		args = between(n, "{", "}")
+		for i := 0; i+2 < len(args); i += 4 {
+			decl.Arguments = append(decl.Arguments, b.expression(args[i+2]))
+			decl.Names = append(decl.Names, b.text(args[i]))
+			decl.Identifiers = append(decl.Identifiers, b.identifier(args[i]))
+		}
+		return
+	}
+	for _, arg := range args {
+		if arg.IsNamed() && arg.Type() != "ERROR" {
+			decl.Arguments = append(decl.Arguments, b.expression(arg))
+		}
+	}
+}
+
+// literalFragments splits consecutive string or hex literals into their
+// fragments with their prefix and quotes (i.e. unicode"a" or hex"00") and
+// returns the offsets of the fragments in the text
+func literalFragments(text string) ([]string, []int) {
+	res, offsets := []string{}, []int{}
+	for i := 0; i < len(text); {
+		start := i
+		for i < len(text) && text[i] >= 'a' && text[i] <= 'z' {
+			i++
+		}
+		if i == len(text) || (text[i] != '"' && text[i] != '\'') {
+			i++
+			continue
+		}
+		quote := text[i]
+		for i++; i < len(text) && text[i] != quote; i++ {
+			if text[i] == '\\' {
+				i++
+			}
+		}
+		if i < len(text) {
+			i++
+		}
+		res = append(res, text[start:i])
+		offsets = append(offsets, start)
+	}
+	return res, offsets
+}
+
+// literalError adds the error of a literal fragment that starts at an offset
+func (b *treeSitterBuilder) literalError(offset int, err *literalError) {
+	if b.errSrc != nil {
+		b.errors = append(b.errors, err.syntaxError(b.errSrc, offset))
+	}
+}
+
+// assemblyBlock builds the block of an assembly statement or a yul block
+func (b *treeSitterBuilder) assemblyBlock(n *sitter.Node) INode {
+	decl := &AssemblyBlock{
+		Operations: []interface{}{},
+	}
+	for _, child := range namedChildren(n) {
+		decl.Operations = append(decl.Operations, b.assemblyItem(child))
+	}
+	decl.SetTypeName("AssemblyBlock")
+	start, end := n.StartByte(), n.EndByte()
+	if open := childOf(n, "{"); open != nil {
+		start = open.StartByte()
+	}
+	b.locate(decl, start, end)
+	return decl
+}
+
+// assemblyItem builds the yul statements and expressions
+func (b *treeSitterBuilder) assemblyItem(n *sitter.Node) interface{} {
+	nodes := namedChildren(n)
+
+	switch n.Type() {
+	case "yul_block":
+		return b.assemblyBlock(n)
+
+	case "yul_variable_declaration":
+		decl := &AssemblyLocalDefinition{}
+		if right := n.ChildByFieldName("right"); right != nil {
+			decl.Expression = b.assemblyItem(right)
+		}
+		b.set(decl, "AssemblyLocalDefinition", n)
+		return decl
+
+	case "yul_assignment":
+		decl := &AssemblyAssignment{}
+		if len(nodes) > 1 {
+			decl.Expression = b.assemblyItem(nodes[len(nodes)-1])
+		}
+		b.set(decl, "AssemblyAssignment", n)
+		return decl
+
+	case "yul_function_call":
+		decl := &AssemblyCall{
+			Arguments: []interface{}{},
+		}
+		if function := n.ChildByFieldName("function"); function != nil {
+			decl.FunctionName = b.text(function)
+		}
+		for _, arg := range between(n, "(", ")") {
+			if arg.IsNamed() && arg.Type() != "ERROR" {
+				decl.Arguments = append(decl.Arguments, b.assemblyItem(arg))
+			}
+		}
+		b.set(decl, "AssemblyCall", n)
+		return decl
+
+	case "yul_path", "yul_identifier":
+		if len(nodes) > 1 {
+			decl := &AssemblyMember{
+				Expression: b.identifier(nodes[0]),
+				MemberName: b.identifier(nodes[1]),
+			}
+			b.set(decl, "AssemblyMember", n)
+			return decl
+		}
+		// an identifier is a call without arguments in antlr
+		decl := &AssemblyCall{
+			FunctionName: b.text(n),
+			Arguments:    []interface{}{},
+		}
+		b.set(decl, "AssemblyCall", n)
+		return decl
+
+	case "yul_decimal_number", "yul_hex_number", "yul_string_literal", "yul_boolean":
+		decl := &AssemblyLiteral{}
+		b.set(decl, "AssemblyLiteral", n)
+		return decl
+
+	case "yul_function_definition":
+		decl := &AssemblyFunctionDefinition{}
+		if len(nodes) != 0 {
+			decl.Name = b.text(nodes[0])
+		}
+		if body := childOf(n, "yul_block"); body != nil {
+			decl.Body = b.assemblyBlock(body)
+		}
+		b.set(decl, "AssemblyFunctionDefinition", n)
+		return decl
+
+	case "yul_if_statement":
+		decl := &AssemblyIf{}
+		if len(nodes) == 2 {
+			decl.Condition = b.assemblyItem(nodes[0])
+			decl.Body = b.assemblyBlock(nodes[1])
+		}
+		b.set(decl, "AssemblyIf", n)
+		return decl
+
+	case "yul_for_statement":
+		decl := &AssemblyFor{}
+		if len(nodes) == 4 {
+			decl.Pre = b.assemblyItem(nodes[0])
+			decl.Condition = b.assemblyItem(nodes[1])
+			decl.Post = b.assemblyItem(nodes[2])
+			decl.Body = b.assemblyItem(nodes[3])
+		}
+		b.set(decl, "AssemblyFor", n)
+		return decl
+
+	case "yul_switch_statement":
+		decl := &AssemblySwitch{
+			Cases: []interface{}{},
+		}
+		if len(nodes) != 0 {
+			decl.Expression = b.assemblyItem(nodes[0])
+		}
+		// each case starts at its keyword and ends with its block
+		var start *sitter.Node
+		for _, child := range children(n) {
+			switch child.Type() {
+			case "case", "default":
+				start = child
+			case "yul_block":
+				c := &AssemblyCase{
+					Block: b.assemblyBlock(child),
+				}
+				c.SetTypeName("AssemblyCase")
+				if start != nil {
+					b.locate(c, start.StartByte(), child.EndByte())
+				}
+				decl.Cases = append(decl.Cases, c)
+			}
+		}
+		b.set(decl, "AssemblySwitch", n)
+		return decl
+
+	case "yul_leave":
+		// 'leave' is an identifier in antlr
+		return b.identifier(n)
+	}
+	// break and continue are tokens in antlr
+	return nil
+}
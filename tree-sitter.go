@@ -2,12 +2,240 @@ package solcparser
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
 	treesitter "github.com/umbracle/solidity-parser-go/tree-sitter"
 )
 
+// the tree-sitter parser is generated from the grammar vendored in tree-sitter-sol
+//go:generate ./scripts/build-tree-sitter.sh
+
+// NewTreeSitter parses a source with tree-sitter. The tree of an invalid
+// source has ERROR and MISSING nodes, TreeSitterErrors reports them. It
+// returns nil if tree-sitter fails, NewTreeSitterCtx returns the error.
 func NewTreeSitter(code string) *sitter.Node {
-	n, _ := sitter.ParseCtx(context.Background(), []byte(code), treesitter.GetLanguage())
+	n, _ := NewTreeSitterCtx(context.Background(), code)
 	return n
 }
+
+// NewTreeSitterCtx is NewTreeSitter with a context, it returns the error of
+// tree-sitter (i.e. the parse was cancelled)
+func NewTreeSitterCtx(ctx context.Context, code string) (*sitter.Node, error) {
+	return sitter.ParseCtx(ctx, []byte(code), treesitter.GetLanguage())
+}
+
+// TreeSitterErrors returns a syntax error for each ERROR and MISSING node of
+// the tree-sitter tree of a source, ordered by their position. The messages
+// name the construct that was expected (i.e. "expected an expression after
+// '='" or "missing ';' in state variable declaration").
+func TreeSitterErrors(root *sitter.Node, src string) []*SyntaxError {
+	return treeSitterErrors([]byte(src), newSourceMap(src), root)
+}
+
+func treeSitterErrors(src []byte, srcMap *sourceMap, root *sitter.Node) []*SyntaxError {
+	errs := []*SyntaxError{}
+	if !root.HasError() {
+		return errs
+	}
+
+	newError := func(start, end int, msg string) *SyntaxError {
+		err := &SyntaxError{
+			msg: msg,
+			loc: &Location{
+				Start: srcMap.position(start),
+				End:   srcMap.position(end),
+			},
+		}
+		err.line, err.column = err.loc.Start.Line, err.loc.Start.Column
+		return err
+	}
+	missing := func(n *sitter.Node, typ string, offset int) {
+		token := tokenName(typ)
+		err := newError(offset, offset, fmt.Sprintf("missing %s in %s", token, constructName(n.Type())))
+		if strings.HasPrefix(token, "'") {
+			text := strings.Trim(token, "'")
+			err.fixes = []*SuggestedFix{{
+				Message: fmt.Sprintf("insert '%s'", text),
+				Edits:   []TextEdit{{Start: offset, End: offset, NewText: text}},
+			}}
+		}
+		errs = append(errs, err)
+	}
+
+	// the parent of the MISSING nodes is not reliable in the bindings, it is
+	// passed down the walk instead
+	var walk func(n, parent *sitter.Node)
+	walk = func(n, parent *sitter.Node) {
+		if n.IsMissing() {
+			missing(parent, n.Type(), int(n.StartByte()))
+			return
+		}
+		if n.Type() == "ERROR" {
+			errs = append(errs, newError(int(n.StartByte()), int(n.EndByte()), errorMessage(src, n, parent)))
+			return
+		}
+		if !n.HasError() {
+			return
+		}
+		children := allChildren(n)
+		found := false
+		for _, child := range children {
+			found = found || child.HasError() || child.IsMissing() || child.Type() == "ERROR"
+			walk(child, n)
+		}
+		if !found {
+			// the node has a MISSING hidden token (i.e. _semicolon) that is
+			// not one of its children, only the string of the node has it
+			typ := "_semicolon"
+			if str := n.String(); strings.Contains(str, "(MISSING ") {
+				typ = strings.Fields(str[strings.Index(str, "(MISSING ")+len("(MISSING "):])[0]
+				typ = strings.TrimRight(typ, ")")
+			}
+			missing(n, typ, int(n.EndByte()))
+		}
+	}
+	walk(root, nil)
+	return errs
+}
+
+// expressionTokens are the tokens followed by an expression
+var expressionTokens = map[string]bool{
+	"=": true, ":=": true, "(": true, "[": true, ",": true, "?": true, ":": true, "return": true, "emit": true,
+	"+": true, "-": true, "*": true, "/": true, "%": true, "**": true, "==": true, "!=": true, "<": true, "<=": true,
+	">": true, ">=": true, "&&": true, "||": true, "!": true, "&": true, "|": true, "^": true, "~": true, "<<": true,
+	">>": true, "+=": true, "-=": true, "*=": true, "/=": true, "%=": true, "|=": true, "&=": true, "^=": true,
+}
+
+// listItems are the items of the nodes that are lists
+var listItems = map[string]string{
+	"source_file":        "a pragma, an import or a declaration",
+	"contract_body":      "a contract member",
+	"function_body":      "a statement",
+	"block_statement":    "a statement",
+	"assembly_statement": "an assembly statement",
+	"yul_block":          "an assembly statement",
+}
+
+// keywordConstructs are the constructs that start with a keyword
+var keywordConstructs = map[string]string{
+	"if":          "if statement",
+	"for":         "for statement",
+	"while":       "while statement",
+	"do":          "do while statement",
+	"try":         "try statement",
+	"emit":        "emit statement",
+	"return":      "return statement",
+	"function":    "function definition",
+	"modifier":    "modifier definition",
+	"constructor": "constructor definition",
+	"event":       "event definition",
+	"struct":      "struct definition",
+	"enum":        "enum definition",
+	"using":       "using directive",
+	"import":      "import directive",
+	"pragma":      "pragma directive",
+}
+
+// errorMessage returns the message of an ERROR node, the node has the tokens
+// that were skipped to recover from the error
+func errorMessage(src []byte, n, parent *sitter.Node) string {
+	leaves := []*sitter.Node{}
+	var collect func(n *sitter.Node)
+	collect = func(n *sitter.Node) {
+		children := allChildren(n)
+		if len(children) == 0 && n.EndByte() > n.StartByte() {
+			leaves = append(leaves, n)
+		}
+		for _, child := range children {
+			collect(child)
+		}
+	}
+	collect(n)
+
+	construct := "source unit"
+	if parent != nil {
+		construct = constructName(parent.Type())
+	}
+	if len(leaves) == 0 {
+		return fmt.Sprintf("syntax error in %s", construct)
+	}
+
+	// the parser stopped after the last token
+	last := leaves[len(leaves)-1]
+	switch typ := last.Type(); {
+	case expressionTokens[typ]:
+		return fmt.Sprintf("expected an expression after '%s'", typ)
+	case typ == "is":
+		return "expected a base contract after 'is'"
+	case typ == "contract" || typ == "interface" || typ == "library" || typ == "struct" || typ == "enum" ||
+		typ == "event" || typ == "function" || typ == "modifier":
+		return fmt.Sprintf("expected a name after '%s'", typ)
+	}
+
+	// a keyword followed by an invalid token (i.e. 'contract {')
+	for i, leaf := range leaves[:len(leaves)-1] {
+		switch leaf.Type() {
+		case "contract", "interface", "library", "struct", "enum", "event", "modifier":
+			if leaves[i+1].Type() != "identifier" {
+				return fmt.Sprintf("expected a name after '%s'", leaf.Type())
+			}
+		}
+	}
+
+	// complete constructs without the semicolon at the end (i.e. 'a = 1 }')
+	var item string
+	if parent != nil {
+		item = listItems[parent.Type()]
+	}
+	if item != "" {
+		complete := true
+		for _, child := range allChildren(n) {
+			complete = complete && child.IsNamed() && child.Type() != "ERROR"
+		}
+		if complete && item == "a statement" {
+			return fmt.Sprintf("expected ';' after '%s'", shortText(n.Content(src)))
+		}
+		// the construct that starts with the first token is invalid (i.e. 'if (a {')
+		if name, ok := keywordConstructs[leaves[0].Type()]; ok && len(leaves) > 1 {
+			return fmt.Sprintf("invalid %s", name)
+		}
+		return fmt.Sprintf("unexpected '%s', expected %s", shortText(leaves[0].Content(src)), item)
+	}
+	return fmt.Sprintf("unexpected '%s' in %s", shortText(leaves[0].Content(src)), construct)
+}
+
+// tokenName returns the name of a token in the messages
+func tokenName(typ string) string {
+	if typ == "_semicolon" {
+		return "';'"
+	}
+	if typ == "identifier" || strings.Contains(typ, "_") {
+		return constructName(typ)
+	}
+	return "'" + typ + "'"
+}
+
+// constructName returns the name of a node type in the messages (i.e.
+// 'state variable declaration')
+func constructName(typ string) string {
+	switch typ {
+	case "source_file":
+		return "source unit"
+	case "event_paramater":
+		return "event parameter"
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(typ, "_"), "_", " ")
+}
+
+// shortText returns the first line of a text with at most 30 characters
+func shortText(text string) string {
+	if i := strings.IndexByte(text, '\n'); i != -1 {
+		text = text[:i]
+	}
+	if runes := []rune(text); len(runes) > 30 {
+		return string(runes[:30]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,247 @@
+package solcparser
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	treesitter "github.com/umbracle/solidity-parser-go/tree-sitter"
+)
+
+// treeSitterNames returns the node types and the field names of the syntax
+// tree that the Go code of the files uses
+func treeSitterNames(t *testing.T, files ...string) (map[string]token.Position, map[string]token.Position) {
+	types := map[string]token.Position{}
+	fields := map[string]token.Position{}
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		add := func(names map[string]token.Position, expr ast.Expr) {
+			if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				name, _ := strconv.Unquote(lit.Value)
+				names[name] = fset.Position(lit.Pos())
+			}
+		}
+		isType := func(expr ast.Expr) bool {
+			call, ok := expr.(*ast.CallExpr)
+			if !ok {
+				return false
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			return ok && sel.Sel.Name == "Type"
+		}
+
+		ast.Inspect(f, func(node ast.Node) bool {
+			switch obj := node.(type) {
+			case *ast.SwitchStmt:
+				// switch n.Type() { case "..." }
+				if obj.Tag != nil && isType(obj.Tag) {
+					for _, stmt := range obj.Body.List {
+						for _, expr := range stmt.(*ast.CaseClause).List {
+							add(types, expr)
+						}
+					}
+				}
+
+			case *ast.BinaryExpr:
+				// n.Type() == "..."
+				if isType(obj.X) {
+					add(types, obj.Y)
+				}
+
+			case *ast.CallExpr:
+				var name string
+				switch fun := obj.Fun.(type) {
+				case *ast.Ident:
+					name = fun.Name
+				case *ast.SelectorExpr:
+					name = fun.Sel.Name
+				}
+				switch name {
+				case "ChildByFieldName", "fieldChildren", "isField":
+					add(fields, obj.Args[len(obj.Args)-1])
+				case "childOf", "lastChildOf":
+					add(types, obj.Args[1])
+				case "between":
+					add(types, obj.Args[1])
+					add(types, obj.Args[2])
+				}
+			}
+			return true
+		})
+	}
+	return types, fields
+}
+
+func TestTreeSitterABI(t *testing.T) {
+	lang := treesitter.GetLanguage()
+
+	symbols := map[string]bool{
+		// the nodes of the syntax errors
+		"ERROR": true,
+	}
+	for i := uint32(0); i < lang.SymbolCount(); i++ {
+		symbols[lang.SymbolName(sitter.Symbol(i))] = true
+	}
+	fields := map[string]bool{}
+	for i := 1; lang.FieldName(i) != ""; i++ {
+		fields[lang.FieldName(i)] = true
+	}
+
+	usedTypes, usedFields := treeSitterNames(t, "tree-sitter-ast.go", "tree-sitter.go", "document.go")
+	if len(usedTypes) == 0 || len(usedFields) == 0 {
+		t.Fatal("names not found")
+	}
+	for name, pos := range usedTypes {
+		if !symbols[name] {
+			t.Errorf("%s: node type '%s' not found in the grammar", pos, name)
+		}
+	}
+	for name, pos := range usedFields {
+		if !fields[name] {
+			t.Errorf("%s: field '%s' not found in the grammar", pos, name)
+		}
+	}
+}
+
+// upstream reads the UPSTREAM file of a vendored grammar
+func upstream(t *testing.T, dir string) map[string]string {
+	f, err := os.Open(filepath.Join(dir, "UPSTREAM"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	res := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			t.Fatalf("bad line in %s/UPSTREAM '%s'", dir, line)
+		}
+		res[parts[0]] = strings.TrimSpace(parts[1])
+	}
+	return res
+}
+
+func sha256File(t *testing.T, file string) string {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func TestGrammarVersions(t *testing.T) {
+	for _, dir := range []string{"antlr-sol", "tree-sitter-sol"} {
+		info := upstream(t, dir)
+		for _, key := range []string{"repository", "version", "source", "generated"} {
+			if info[key] == "" {
+				t.Fatalf("%s: %s not found", dir, key)
+			}
+		}
+
+		// the generated parser is the one of the record
+		if hash := sha256File(t, info["generated"]); hash != info["generated-sha256"] {
+			t.Fatalf("%s: %s changed without updating its checksum (%s)", dir, info["generated"], hash)
+		}
+
+		// the vendored source is the one of the record, if any
+		source := filepath.Join(dir, info["source"])
+		if info["source-sha256"] == "" {
+			if _, err := os.Stat(source); err == nil {
+				t.Fatalf("%s: checksum of %s not found", dir, source)
+			}
+			continue
+		}
+		if hash := sha256File(t, source); hash != info["source-sha256"] {
+			t.Fatalf("%s: %s changed without updating its checksum (%s)", dir, source, hash)
+		}
+	}
+
+	// the antlr parser is generated from the vendored grammar
+	if sha256File(t, "antlr/Solidity.g4") != sha256File(t, "antlr-sol/Solidity.g4") {
+		t.Fatal("antlr/Solidity.g4 is not the vendored grammar")
+	}
+}
+
+func TestTreeSitterErrors(t *testing.T) {
+	cases := []struct {
+		src  string
+		msg  string
+		line int
+		col  int
+		fix  string
+	}{
+		{"contract A {\n  uint a\n}", "missing ';' in state variable declaration", 2, 8, "contract A {\n  uint a;\n}"},
+		{"pragma solidity ^0.8.0", "missing ';' in pragma directive", 1, 22, "pragma solidity ^0.8.0;"},
+		{"contract A { function f( { } }", "missing ')' in function definition", 1, 24, "contract A { function f() { } }"},
+		{"contract A {", "missing '}' in contract body", 1, 12, "contract A {}"},
+		{"contract A { function f() { uint x = ; } }", "expected an expression after '='", 1, 35, ""},
+		{"contract A { function f() {\n  a = 1\n} }", "expected ';' after 'a = 1'", 2, 2, ""},
+		{"contract A { function f() { if (a { } } }", "invalid if statement", 1, 28, ""},
+		{"contract { }", "expected a name after 'contract'", 1, 0, ""},
+		{"contract A is { }", "expected a base contract after 'is'", 1, 11, ""},
+		{"x", "unexpected 'x', expected a pragma, an import or a declaration", 1, 0, ""},
+	}
+
+	for _, c := range cases {
+		root, err := NewTreeSitterCtx(context.Background(), c.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs := TreeSitterErrors(root, c.src)
+		if len(errs) != 1 {
+			t.Fatalf("bad errors for '%s': %v", c.src, errs)
+		}
+		e := errs[0]
+		if e.Error() != c.msg {
+			t.Fatalf("bad message for '%s': %s", c.src, e.Error())
+		}
+		if loc := e.Loc(); loc.Start.Line != c.line || loc.Start.Column != c.col {
+			t.Fatalf("bad location for '%s': %d:%d", c.src, loc.Start.Line, loc.Start.Column)
+		}
+		if c.fix == "" {
+			if len(e.Fixes()) != 0 {
+				t.Fatalf("unexpected fix for '%s'", c.src)
+			}
+			continue
+		}
+		if len(e.Fixes()) != 1 {
+			t.Fatalf("fix not found for '%s'", c.src)
+		}
+		if fixed, _, err := ApplyFixes(c.src, e.Fixes()); err != nil || fixed != c.fix {
+			t.Fatalf("bad fix for '%s': '%s'", c.src, fixed)
+		}
+	}
+
+	// a valid source has no errors
+	root := NewTreeSitter("contract A { uint a; }")
+	if root == nil {
+		t.Fatal("tree expected")
+	}
+	if errs := TreeSitterErrors(root, "contract A { uint a; }"); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
@@ -0,0 +1,215 @@
+package types
+
+import (
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/constant"
+)
+
+// ABIType returns the canonical name of a type in the contract ABI (i.e.
+// uint256 for uint, address for a contract and (uint256,bool) for a struct).
+// It returns false for the types without an ABI encoding like mappings.
+func (i *Info) ABIType(t Type) (string, bool) {
+	switch obj := t.(type) {
+	case *IntType, *FixedPointType, *BoolType, *FixedBytesType:
+		return obj.String(), true
+	case *AddressType, *ContractType:
+		return "address", true
+	case *BytesType:
+		return "bytes", true
+	case *StringType:
+		return "string", true
+	case *EnumType:
+		return "uint8", true
+	case *UserDefinedValueType:
+		return i.ABIType(obj.Underlying)
+
+	case *FunctionType:
+		if obj.Kind == FunctionKindExternal {
+			return "function", true
+		}
+
+	case *ArrayType:
+		base, ok := i.ABIType(obj.Base)
+		if !ok {
+			return "", false
+		}
+		if obj.Length == nil {
+			return base + "[]", true
+		}
+		return base + "[" + obj.Length.String() + "]", true
+
+	case *StructType:
+		if obj.Def == nil {
+			return "", false
+		}
+		members := []string{}
+		for _, m := range obj.Def.Members {
+			member, ok := i.ABIType(i.TypeOf(m))
+			if !ok {
+				return "", false
+			}
+			members = append(members, member)
+		}
+		return "(" + strings.Join(members, ",") + ")", true
+	}
+	return "", false
+}
+
+// Signature returns the canonical signature of a function, an event, an
+// error or the getter of a public state variable (i.e.
+// transfer(address,uint256)). It returns false for the declarations without
+// a signature like constructors and for the parameters without an ABI
+// encoding. The functions of the libraries have the signatures of the solc
+// library calls (i.e. f(L.S storage,uint256)), see LibraryType.
+func (i *Info) Signature(decl interface{}) (string, bool) {
+	var name string
+	var params []Type
+
+	switch obj := decl.(type) {
+	case *solcparser.FunctionDefinition:
+		if obj.Name == "" || obj.IsConstructor {
+			return "", false
+		}
+		if owner := i.owner[obj]; owner != nil && owner.Kind == "library" {
+			return i.librarySignature(obj)
+		}
+		name = obj.Name
+		for _, p := range obj.Parameters {
+			params = append(params, i.TypeOf(p))
+		}
+
+	case *solcparser.EventDefinition:
+		name = obj.Name
+		for _, p := range obj.Parameters {
+			params = append(params, i.TypeOf(p))
+		}
+
+	case *solcparser.CustomErrorDefinition:
+		name = obj.Name
+		for _, p := range obj.Parameters {
+			params = append(params, i.TypeOf(p))
+		}
+
+	case *solcparser.StateVariableDeclarationVariable:
+		// the keys of the mappings and the indexes of the arrays
+		name = obj.Name
+		typ := i.TypeOf(obj)
+		for {
+			if m, ok := typ.(*MappingType); ok {
+				params = append(params, m.Key)
+				typ = m.Value
+			} else if a, ok := typ.(*ArrayType); ok {
+				params = append(params, tUint256)
+				typ = a.Base
+			} else {
+				break
+			}
+		}
+
+	default:
+		return "", false
+	}
+
+	names := []string{}
+	for _, p := range params {
+		abi, ok := i.ABIType(p)
+		if !ok {
+			return "", false
+		}
+		names = append(names, abi)
+	}
+	return name + "(" + strings.Join(names, ",") + ")", true
+}
+
+// librarySignature returns the signature of a function of a library
+func (i *Info) librarySignature(fn *solcparser.FunctionDefinition) (string, bool) {
+	names := []string{}
+	for _, p := range fn.Parameters {
+		typ := i.TypeOf(p)
+		if typ == nil {
+			return "", false
+		}
+		name, ok := i.LibraryType(typ)
+		if !ok {
+			return "", false
+		}
+		names = append(names, name)
+	}
+	return fn.Name + "(" + strings.Join(names, ",") + ")", true
+}
+
+// LibraryType returns the name of a type in the signatures of the library
+// functions. Unlike the ABI, the structs, the enums and the contracts are
+// referred by their qualified names (i.e. L.S), the mappings are allowed and
+// the types in storage have the storage suffix (i.e. uint256[] storage).
+func (i *Info) LibraryType(t Type) (string, bool) {
+	name, ok := i.libraryTypeName(t)
+	if !ok {
+		return "", false
+	}
+	switch obj := t.(type) {
+	case *BytesType:
+		return withStorage(name, obj.Location), true
+	case *StringType:
+		return withStorage(name, obj.Location), true
+	case *ArrayType:
+		return withStorage(name, obj.Location), true
+	case *StructType:
+		return withStorage(name, obj.Location), true
+	case *MappingType:
+		return name + " storage", true
+	}
+	return name, true
+}
+
+// libraryTypeName returns the name of a type in the signatures of the
+// library functions without the data location
+func (i *Info) libraryTypeName(t Type) (string, bool) {
+	switch obj := t.(type) {
+	case *StructType:
+		return obj.Name, true
+	case *EnumType:
+		return obj.Name, true
+	case *ContractType:
+		return obj.Def.Name, true
+	case *UserDefinedValueType:
+		return i.libraryTypeName(obj.Underlying)
+
+	case *ArrayType:
+		base, ok := i.libraryTypeName(obj.Base)
+		if !ok {
+			return "", false
+		}
+		if obj.Length == nil {
+			return base + "[]", true
+		}
+		return base + "[" + obj.Length.String() + "]", true
+
+	case *MappingType:
+		key, ok := i.libraryTypeName(obj.Key)
+		if !ok {
+			return "", false
+		}
+		value, ok := i.libraryTypeName(obj.Value)
+		if !ok {
+			return "", false
+		}
+		return "mapping(" + key + " => " + value + ")", true
+	}
+	return i.ABIType(t)
+}
+
+func withStorage(name, location string) string {
+	if location == Storage {
+		return name + " storage"
+	}
+	return name
+}
+
+// Selector returns the first four bytes of the keccak256 hash of a signature,
+// the topic of an event is the whole hash
+func Selector(signature string) []byte {
+	return constant.Keccak256([]byte(signature))[:4]
+}
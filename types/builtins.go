@@ -0,0 +1,177 @@
+package types
+
+func builtin(name string, returns ...Type) *FunctionType {
+	return &FunctionType{
+		Name:     name,
+		Kind:     FunctionKindBuiltin,
+		Returns:  returns,
+		Variadic: true,
+	}
+}
+
+func builtinFn(name string, params []Type, returns ...Type) *FunctionType {
+	return &FunctionType{
+		Name:    name,
+		Kind:    FunctionKindBuiltin,
+		Params:  params,
+		Returns: returns,
+	}
+}
+
+// globals are the builtin variables and functions available everywhere
+var globals = map[string]Type{
+	"msg":   &MagicType{Kind: "msg"},
+	"block": &MagicType{Kind: "block"},
+	"tx":    &MagicType{Kind: "tx"},
+	"abi":   &MagicType{Kind: "abi"},
+	"now":   tUint256,
+
+	"keccak256":    builtinFn("keccak256", []Type{tBytesMemory}, tBytes32),
+	"sha256":       builtinFn("sha256", []Type{tBytesMemory}, tBytes32),
+	"sha3":         builtinFn("sha3", []Type{tBytesMemory}, tBytes32),
+	"ripemd160":    builtinFn("ripemd160", []Type{tBytesMemory}, &FixedBytesType{Size: 20}),
+	"ecrecover":    builtinFn("ecrecover", []Type{tBytes32, tUint8, tBytes32, tBytes32}, tAddress),
+	"addmod":       builtinFn("addmod", []Type{tUint256, tUint256, tUint256}, tUint256),
+	"mulmod":       builtinFn("mulmod", []Type{tUint256, tUint256, tUint256}, tUint256),
+	"gasleft":      builtinFn("gasleft", []Type{}, tUint256),
+	"blockhash":    builtinFn("blockhash", []Type{tUint256}, tBytes32),
+	"blobhash":     builtinFn("blobhash", []Type{tUint256}, tBytes32),
+	"selfdestruct": builtinFn("selfdestruct", []Type{tAddressPayable}),
+	"suicide":      builtinFn("suicide", []Type{tAddressPayable}),
+	"require":      builtin("require"),
+	"assert":       builtinFn("assert", []Type{tBool}),
+	"revert":       builtin("revert"),
+}
+
+// magicMember returns the type of a member of the global variables
+func magicMember(kind string, member string) (Type, bool) {
+	switch kind {
+	case "msg":
+		switch member {
+		case "sender":
+			return tAddress, true
+		case "value", "gas":
+			return tUint256, true
+		case "data":
+			return &BytesType{Location: Calldata}, true
+		case "sig":
+			return tBytes4, true
+		}
+
+	case "block":
+		switch member {
+		case "coinbase":
+			return tAddressPayable, true
+		case "timestamp", "number", "difficulty", "prevrandao", "gaslimit", "chainid", "basefee", "blobbasefee":
+			return tUint256, true
+		case "blockhash":
+			return globals["blockhash"], true
+		}
+
+	case "tx":
+		switch member {
+		case "origin":
+			return tAddress, true
+		case "gasprice":
+			return tUint256, true
+		}
+
+	case "abi":
+		switch member {
+		case "encode", "encodePacked", "encodeWithSelector", "encodeWithSignature", "encodeCall":
+			return builtin("abi."+member, tBytesMemory), true
+		case "decode":
+			// the return types depend on the arguments
+			return builtin("abi.decode"), true
+		}
+	}
+	return nil, false
+}
+
+// metaMember returns the type of the members of 'type(X)'
+func metaMember(arg Type, member string) (Type, bool) {
+	switch member {
+	case "min", "max":
+		if _, ok := arg.(*IntType); ok {
+			return arg, true
+		}
+		if _, ok := arg.(*EnumType); ok {
+			return arg, true
+		}
+	case "name":
+		if _, ok := arg.(*ContractType); ok {
+			return tStringMemory, true
+		}
+	case "creationCode", "runtimeCode":
+		if _, ok := arg.(*ContractType); ok {
+			return tBytesMemory, true
+		}
+	case "interfaceId":
+		if _, ok := arg.(*ContractType); ok {
+			return tBytes4, true
+		}
+	}
+	return nil, false
+}
+
+// addressMember returns the type of the members of the address type
+func addressMember(addr *AddressType, member string) (Type, bool) {
+	switch member {
+	case "balance":
+		return tUint256, true
+	case "code":
+		return tBytesMemory, true
+	case "codehash":
+		return tBytes32, true
+	case "transfer":
+		return builtinFn("transfer", []Type{tUint256}), true
+	case "send":
+		return builtinFn("send", []Type{tUint256}, tBool), true
+	case "call", "delegatecall", "staticcall", "callcode":
+		fn := builtinFn(member, []Type{tBytesMemory}, tBool, tBytesMemory)
+		if member == "call" {
+			fn.Mutability = "payable"
+		}
+		return fn, true
+	}
+	return nil, false
+}
+
+// arrayMember returns the type of the members of arrays and bytes
+func arrayMember(typ Type, member string) (Type, bool) {
+	var base Type
+	switch obj := typ.(type) {
+	case *ArrayType:
+		if member == "length" {
+			return tUint256, true
+		}
+		if obj.Length != nil || obj.Location != Storage {
+			return nil, false
+		}
+		base = obj.Base
+	case *BytesType:
+		if member == "length" {
+			return tUint256, true
+		}
+		if obj.Location != Storage {
+			return nil, false
+		}
+		base = &FixedBytesType{Size: 1}
+	case *FixedBytesType:
+		if member == "length" {
+			return tUint8, true
+		}
+		return nil, false
+	default:
+		return nil, false
+	}
+
+	switch member {
+	case "push":
+		// push() returns a reference to the new element, push(x) returns nothing
+		return &FunctionType{Name: "push", Kind: FunctionKindBuiltin, Returns: []Type{base}, Variadic: true}, true
+	case "pop":
+		return builtinFn("pop", []Type{}), true
+	}
+	return nil, false
+}
@@ -0,0 +1,1380 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/constant"
+)
+
+// Diagnostic is a problem found while assigning the types
+type Diagnostic struct {
+	Message string
+	File    string
+	Loc     *solcparser.Location
+}
+
+func (d *Diagnostic) String() string {
+	if d.Loc == nil {
+		return d.File + ": " + d.Message
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Loc.Start.Line, d.Loc.Start.Column+1, d.Message)
+}
+
+// Info is the result of the type checker
+type Info struct {
+	// Types are the types of the expressions and the variable declarations
+	Types map[solcparser.INode]Type
+
+	// Refs are the declarations referenced by the identifiers, member accesses,
+	// user defined type names and modifier invocations
+	Refs map[solcparser.INode]interface{}
+
+	Diagnostics []*Diagnostic
+
+	// owner is the contract that declares each member
+	owner map[interface{}]*solcparser.ContractDefinition
+}
+
+// TypeOf returns the type of an expression or nil if it is not known
+func (i *Info) TypeOf(expr interface{}) Type {
+	node, ok := expr.(solcparser.INode)
+	if !ok {
+		return nil
+	}
+	return i.Types[node]
+}
+
+// RefOf returns the declaration referenced by an identifier, a member access,
+// a user defined type name or a modifier invocation
+func (i *Info) RefOf(expr interface{}) interface{} {
+	node, ok := expr.(solcparser.INode)
+	if !ok {
+		return nil
+	}
+	return i.Refs[node]
+}
+
+// Check assigns a type to all the expressions of the project
+func Check(p *solcparser.Project) *Info {
+	c := newChecker(p)
+	for _, file := range p.Files() {
+		c.checkFile(file, p.Units[file])
+	}
+	return c.info
+}
+
+type scope struct {
+	parent *scope
+	names  map[string][]interface{}
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, names: map[string][]interface{}{}}
+}
+
+func (s *scope) declare(name string, decl interface{}) {
+	if name == "" {
+		return
+	}
+	s.names[name] = append(s.names[name], decl)
+}
+
+func (s *scope) lookup(name string) []interface{} {
+	for sc := s; sc != nil; sc = sc.parent {
+		if decls, ok := sc.names[name]; ok {
+			return decls
+		}
+	}
+	return nil
+}
+
+type checker struct {
+	project *solcparser.Project
+	info    *Info
+
+	// owner is the contract that declares each member
+	owner map[interface{}]*solcparser.ContractDefinition
+
+	// file scope shared by all the source units
+	global *scope
+
+	lin map[*solcparser.ContractDefinition][]*solcparser.ContractDefinition
+
+	// state of the current walk
+	file     string
+	contract *solcparser.ContractDefinition
+	function *solcparser.FunctionDefinition
+	modifier *solcparser.ModifierDefinition
+	scope    *scope
+}
+
+func newChecker(p *solcparser.Project) *checker {
+	c := &checker{
+		project: p,
+		info: &Info{
+			Types: map[solcparser.INode]Type{},
+			Refs:  map[solcparser.INode]interface{}{},
+		},
+		owner:  map[interface{}]*solcparser.ContractDefinition{},
+		global: newScope(nil),
+		lin:    map[*solcparser.ContractDefinition][]*solcparser.ContractDefinition{},
+	}
+	c.info.owner = c.owner
+	for _, file := range p.Files() {
+		for _, child := range p.Units[file].Children {
+			c.global.declare(declName(child), child)
+			if contract, ok := child.(*solcparser.ContractDefinition); ok {
+				for _, node := range contract.SubNodes {
+					c.owner[node] = contract
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *checker) errorf(node interface{}, format string, args ...interface{}) {
+	var loc *solcparser.Location
+	if n, ok := node.(solcparser.INode); ok {
+		loc = n.GetLoc()
+	}
+	c.info.Diagnostics = append(c.info.Diagnostics, &Diagnostic{
+		Message: fmt.Sprintf(format, args...),
+		File:    c.file,
+		Loc:     loc,
+	})
+}
+
+func (c *checker) record(node interface{}, typ Type) Type {
+	if n, ok := node.(solcparser.INode); ok && typ != nil {
+		c.info.Types[n] = typ
+	}
+	return typ
+}
+
+func (c *checker) recordRef(node interface{}, decl interface{}) {
+	if n, ok := node.(solcparser.INode); ok && decl != nil {
+		c.info.Refs[n] = decl
+	}
+}
+
+// linearize returns the cached linearization of a contract
+func (c *checker) linearize(contract *solcparser.ContractDefinition) []*solcparser.ContractDefinition {
+	if lin, ok := c.lin[contract]; ok {
+		return lin
+	}
+	lin, err := c.project.Linearize(contract)
+	if err != nil {
+		lin = []*solcparser.ContractDefinition{contract}
+	}
+	c.lin[contract] = lin
+	return lin
+}
+
+func (c *checker) contractType(contract *solcparser.ContractDefinition) *ContractType {
+	return &ContractType{Def: contract, Bases: c.linearize(contract)}
+}
+
+// contractScope returns the scope with the members of the contract and its bases
+func (c *checker) contractScope(contract *solcparser.ContractDefinition) *scope {
+	s := newScope(c.global)
+	for _, base := range c.linearize(contract) {
+		for _, node := range base.SubNodes {
+			if decl, ok := node.(*solcparser.StateVariableDeclaration); ok {
+				for _, v := range decl.Variables {
+					if vv, ok := v.(*solcparser.StateVariableDeclarationVariable); ok {
+						s.declare(vv.Name, vv)
+					}
+				}
+				continue
+			}
+			s.declare(declName(node), node)
+		}
+	}
+	return s
+}
+
+func (c *checker) checkFile(file string, unit *solcparser.SourceUnit) {
+	c.file = file
+	c.contract = nil
+	c.scope = c.global
+
+	for _, child := range unit.Children {
+		switch obj := child.(type) {
+		case *solcparser.ContractDefinition:
+			c.checkContract(obj)
+		case *solcparser.FunctionDefinition:
+			c.checkFunction(obj)
+		case *solcparser.FileLevelConstant:
+			typ := c.resolveTypeName(obj.TypeName, "")
+			c.record(obj, typ)
+			c.checkAssign(obj.InitialValue, c.expr(obj.InitialValue), typ)
+		default:
+			c.checkDecl(obj)
+		}
+	}
+}
+
+func (c *checker) checkContract(contract *solcparser.ContractDefinition) {
+	c.contract = contract
+	c.scope = c.contractScope(contract)
+	defer func() {
+		c.contract = nil
+		c.scope = c.global
+	}()
+
+	for _, spec := range contract.BaseContracts {
+		if obj, ok := spec.(*solcparser.InheritanceSpecifier); ok {
+			if name, ok := obj.BaseName.(*solcparser.UserDefinedTypeName); ok {
+				if decl, ok := c.lookupType(name.NamePath); ok {
+					c.recordRef(name, decl)
+				}
+			}
+			for _, arg := range obj.Arguments {
+				c.expr(arg)
+			}
+		}
+	}
+
+	for _, node := range contract.SubNodes {
+		switch obj := node.(type) {
+		case *solcparser.StateVariableDeclaration:
+			for _, v := range obj.Variables {
+				vv, ok := v.(*solcparser.StateVariableDeclarationVariable)
+				if !ok {
+					continue
+				}
+				typ := c.resolveTypeName(vv.TypeName, Storage)
+				c.record(vv, typ)
+				if vv.Expression != nil {
+					c.checkAssign(vv.Expression, c.expr(vv.Expression), typ)
+				}
+			}
+		case *solcparser.FunctionDefinition:
+			c.checkFunction(obj)
+		case *solcparser.ModifierDefinition:
+			c.checkModifier(obj)
+		default:
+			c.checkDecl(obj)
+		}
+	}
+}
+
+// checkDecl records the types of the members of a struct and of the
+// parameters of an event or an error
+func (c *checker) checkDecl(node interface{}) {
+	var list []interface{}
+	switch obj := node.(type) {
+	case *solcparser.StructDefinition:
+		list = obj.Members
+	case *solcparser.EventDefinition:
+		list = obj.Parameters
+	case *solcparser.CustomErrorDefinition:
+		list = obj.Parameters
+	}
+	for _, p := range list {
+		if decl, ok := p.(*solcparser.VariableDeclaration); ok {
+			c.record(decl, c.resolveTypeName(decl.TypeName, decl.StorageLocation))
+		}
+	}
+}
+
+func (c *checker) declareParams(params interface{}) {
+	list, ok := params.([]interface{})
+	if !ok {
+		return
+	}
+	for _, p := range list {
+		decl, ok := p.(*solcparser.VariableDeclaration)
+		if !ok {
+			continue
+		}
+		c.record(decl, c.resolveTypeName(decl.TypeName, decl.StorageLocation))
+		c.scope.declare(decl.Name, decl)
+	}
+}
+
+func (c *checker) checkFunction(fn *solcparser.FunctionDefinition) {
+	parent := c.scope
+	c.scope = newScope(parent)
+	c.function = fn
+	defer func() {
+		c.scope = parent
+		c.function = nil
+	}()
+
+	c.declareParams(fn.Parameters)
+	c.declareParams(fn.ReturnParameters)
+
+	for _, m := range fn.Modifiers {
+		inv, ok := m.(*solcparser.ModifierInvocation)
+		if !ok {
+			continue
+		}
+		// a modifier or the constructor of a base contract
+		for _, decl := range c.scope.lookup(inv.Name) {
+			_, isModifier := decl.(*solcparser.ModifierDefinition)
+			_, isContract := decl.(*solcparser.ContractDefinition)
+			if isModifier || isContract {
+				c.recordRef(inv, decl)
+				break
+			}
+		}
+		for _, arg := range inv.Arguments {
+			c.expr(arg)
+		}
+	}
+	if fn.Body != nil {
+		c.stmt(fn.Body)
+	}
+}
+
+func (c *checker) checkModifier(m *solcparser.ModifierDefinition) {
+	parent := c.scope
+	c.scope = newScope(parent)
+	c.modifier = m
+	defer func() {
+		c.scope = parent
+		c.modifier = nil
+	}()
+
+	c.declareParams(m.Parameters)
+	if m.Body != nil {
+		c.stmt(m.Body)
+	}
+}
+
+func (c *checker) stmt(s interface{}) {
+	switch obj := s.(type) {
+	case *solcparser.Block:
+		parent := c.scope
+		c.scope = newScope(parent)
+		for _, st := range obj.Statements {
+			c.stmt(st)
+		}
+		c.scope = parent
+
+	case *solcparser.UncheckedStatement:
+		c.stmt(obj.Block)
+
+	case *solcparser.ExpressionStatement:
+		if id, ok := obj.Expression.(*solcparser.Identifier); ok && id.Name == "_" && c.modifier != nil {
+			// the placeholder of the body of the modified function
+			return
+		}
+		c.expr(obj.Expression)
+
+	case *solcparser.VariableDeclarationStatement:
+		c.checkVariableDeclaration(obj)
+
+	case *solcparser.IfStatement:
+		c.checkCondition(obj.Condition)
+		c.stmt(obj.TrueBody)
+		if obj.FalseBody != nil {
+			c.stmt(obj.FalseBody)
+		}
+
+	case *solcparser.WhileStatement:
+		c.checkCondition(obj.Condition)
+		c.stmt(obj.Body)
+
+	case *solcparser.DoWhileStatement:
+		c.stmt(obj.Body)
+		c.checkCondition(obj.Condition)
+
+	case *solcparser.ForStatement:
+		parent := c.scope
+		c.scope = newScope(parent)
+		if obj.InitExpression != nil {
+			c.stmt(obj.InitExpression)
+		}
+		if obj.ConditionExpression != nil {
+			c.checkCondition(obj.ConditionExpression)
+		}
+		if obj.LoopExpression != nil {
+			c.stmt(obj.LoopExpression)
+		}
+		c.stmt(obj.Body)
+		c.scope = parent
+
+	case *solcparser.ReturnStatement:
+		c.checkReturn(obj)
+
+	case *solcparser.EmitStatement:
+		c.expr(obj.EventCall)
+
+	case *solcparser.RevertStatement:
+		c.expr(obj.RevertCall)
+
+	case *solcparser.TryStatement:
+		c.expr(obj.Expression)
+
+		parent := c.scope
+		c.scope = newScope(parent)
+		c.declareParams(obj.ReturnParameters)
+		c.stmt(obj.Body)
+		c.scope = parent
+
+		for _, clause := range obj.CatchClause {
+			cc, ok := clause.(*solcparser.CatchClause)
+			if !ok {
+				continue
+			}
+			c.scope = newScope(parent)
+			c.declareParams(cc.Parameters)
+			c.stmt(cc.Body)
+			c.scope = parent
+		}
+	}
+}
+
+func (c *checker) checkCondition(expr interface{}) {
+	typ := c.expr(expr)
+	if typ != nil && !ImplicitlyConvertible(typ, tBool) {
+		c.errorf(expr, "expected a bool condition but found %s", typ)
+	}
+}
+
+func (c *checker) checkVariableDeclaration(obj *solcparser.VariableDeclarationStatement) {
+	var initType Type
+	if obj.InitialValue != nil {
+		initType = c.expr(obj.InitialValue)
+	}
+
+	declTypes := []Type{}
+	for _, v := range obj.Variables {
+		decl, ok := v.(*solcparser.VariableDeclaration)
+		if !ok {
+			declTypes = append(declTypes, nil)
+			continue
+		}
+		var typ Type
+		if decl.TypeName != nil {
+			typ = c.resolveTypeName(decl.TypeName, decl.StorageLocation)
+		}
+		declTypes = append(declTypes, typ)
+	}
+
+	if len(obj.Variables) == 1 {
+		if declTypes[0] == nil && initType != nil {
+			// var declaration
+			declTypes[0] = MobileType(initType)
+		} else if initType != nil {
+			c.checkAssign(obj.InitialValue, initType, declTypes[0])
+		}
+	} else if tuple, ok := initType.(*TupleType); ok {
+		if len(tuple.Components) != len(declTypes) {
+			c.errorf(obj, "expected a tuple with %d components but found %d", len(declTypes), len(tuple.Components))
+		} else {
+			for i, typ := range declTypes {
+				if typ == nil && obj.Variables[i] != nil {
+					declTypes[i] = MobileType(tuple.Components[i])
+				} else if typ != nil && tuple.Components[i] != nil && !ImplicitlyConvertible(tuple.Components[i], typ) {
+					c.errorf(obj, "cannot convert %s to %s", tuple.Components[i], typ)
+				}
+			}
+		}
+	}
+
+	for i, v := range obj.Variables {
+		decl, ok := v.(*solcparser.VariableDeclaration)
+		if !ok {
+			continue
+		}
+		c.record(decl, declTypes[i])
+		c.scope.declare(decl.Name, decl)
+	}
+}
+
+func (c *checker) checkReturn(obj *solcparser.ReturnStatement) {
+	if obj.Expression == nil {
+		return
+	}
+	typ := c.expr(obj.Expression)
+	if c.function == nil || typ == nil {
+		return
+	}
+	returns := c.paramTypes(c.function.ReturnParameters)
+	var expected Type
+	if len(returns) == 1 {
+		expected = returns[0]
+	} else {
+		expected = &TupleType{Components: returns}
+	}
+	c.checkAssign(obj.Expression, typ, expected)
+}
+
+func (c *checker) checkAssign(node interface{}, from, to Type) {
+	if from == nil || to == nil {
+		return
+	}
+	if !ImplicitlyConvertible(from, to) {
+		c.errorf(node, "cannot implicitly convert %s to %s", from, to)
+	}
+}
+
+// expr returns the type of the expression and records it
+func (c *checker) expr(e interface{}) Type {
+	if e == nil {
+		return nil
+	}
+	return c.record(e, c.exprType(e))
+}
+
+func (c *checker) exprType(e interface{}) Type {
+	switch obj := e.(type) {
+	case *solcparser.BooleanLiteral:
+		return tBool
+
+	case *solcparser.NumberLiteral:
+		return c.numberLiteral(obj)
+
+	case *solcparser.StringLiteral:
+		return &StringLiteralType{Value: obj.Value}
+
+	case *solcparser.HexLiteral:
+		if obj.Data == nil {
+			// the parser reports the invalid digits
+			c.errorf(obj, "invalid hex literal")
+			return nil
+		}
+		return &StringLiteralType{Value: string(obj.Data)}
+
+	case *solcparser.Identifier:
+		return c.identifier(obj)
+
+	case *solcparser.ElementaryTypeName:
+		return &TypeType{Actual: c.resolveTypeName(obj, "")}
+
+	case *solcparser.TypeNameExpression:
+		return &TypeType{Actual: c.resolveTypeName(obj.TypeName, "")}
+
+	case *solcparser.UserDefinedTypeName:
+		return &TypeType{Actual: c.resolveTypeName(obj, "")}
+
+	case *solcparser.TupleExpression:
+		return c.tuple(obj)
+
+	case *solcparser.UnaryOperation:
+		return c.unary(obj)
+
+	case *solcparser.BinaryOperation:
+		return c.binary(obj)
+
+	case *solcparser.Conditional:
+		c.checkCondition(obj.Condition)
+		t, f := c.expr(obj.TrueExpression), c.expr(obj.FalseExpression)
+		if t == nil || f == nil {
+			return nil
+		}
+		common := CommonType(t, f)
+		if common == nil {
+			c.errorf(obj, "true expression's type %s does not match false expression's type %s", t, f)
+		}
+		return common
+
+	case *solcparser.MemberAccess:
+		return c.member(obj)
+
+	case *solcparser.IndexAccess:
+		return c.index(obj)
+
+	case *solcparser.IndexRangeAccess:
+		base := c.expr(obj.Base)
+		c.expr(obj.IndexStart)
+		c.expr(obj.IndexEnd)
+		return base
+
+	case *solcparser.FunctionCall:
+		return c.call(obj)
+
+	case *solcparser.NameValueExpression:
+		typ := c.expr(obj.Expression)
+		if list, ok := obj.Arguments.(*solcparser.NameValueList); ok {
+			for _, arg := range list.Args {
+				c.expr(arg)
+			}
+		}
+		return typ
+
+	case *solcparser.NewExpression:
+		typ := c.resolveTypeName(obj.TypeName, Memory)
+		if typ == nil {
+			return nil
+		}
+		fn := &FunctionType{Name: "new", Kind: FunctionKindCreation, Returns: []Type{typ}}
+		switch actual := typ.(type) {
+		case *ContractType:
+			fn.Params = c.constructorParams(actual.Def)
+		case *ArrayType, *BytesType, *StringType:
+			fn.Params = []Type{tUint256}
+		default:
+			c.errorf(obj, "cannot create an instance of %s", typ)
+			return nil
+		}
+		return fn
+	}
+
+	c.errorf(e, "unexpected expression %T", e)
+	return nil
+}
+
+func (c *checker) numberLiteral(obj *solcparser.NumberLiteral) Type {
+	var value *big.Rat
+	if obj.Value != nil {
+		value = new(big.Rat).Set(obj.Value)
+	} else {
+		// the literals without a value are invalid or not built by the parser
+		var err error
+		if value, err = solcparser.ParseNumber(obj.Number, obj.SubDenomination); err != nil {
+			c.errorf(obj, "%v", err)
+			return nil
+		}
+	}
+	isAddress := len(obj.Number) == 42 && strings.HasPrefix(obj.Number, "0x")
+	return &RationalType{Value: value, IsAddress: isAddress}
+}
+
+func (c *checker) identifier(obj *solcparser.Identifier) Type {
+	switch obj.Name {
+	case "this":
+		if c.contract != nil {
+			return c.contractType(c.contract)
+		}
+	case "super":
+		if c.contract != nil {
+			typ := c.contractType(c.contract)
+			typ.Super = true
+			return typ
+		}
+	case "type":
+		// handled in the function call
+		return &MagicType{Kind: "meta"}
+	case "payable":
+		return &TypeType{Actual: tAddressPayable}
+	}
+
+	decls := c.scope.lookup(obj.Name)
+	if len(decls) == 0 {
+		if typ, ok := globals[obj.Name]; ok {
+			return typ
+		}
+		c.errorf(obj, "undeclared identifier %s", obj.Name)
+		return nil
+	}
+	c.recordRef(obj, decls[0])
+	return c.declType(decls[0], false)
+}
+
+// declType returns the type of a reference to a declaration
+func (c *checker) declType(decl interface{}, external bool) Type {
+	switch obj := decl.(type) {
+	case *solcparser.VariableDeclaration:
+		if typ, ok := c.info.Types[obj]; ok {
+			return typ
+		}
+		return c.resolveTypeName(obj.TypeName, obj.StorageLocation)
+
+	case *solcparser.StateVariableDeclarationVariable:
+		if typ, ok := c.info.Types[obj]; ok {
+			return typ
+		}
+		typ := c.withScope(c.owner[obj], func() Type {
+			return c.resolveTypeName(obj.TypeName, Storage)
+		})
+		return typ
+
+	case *solcparser.FileLevelConstant:
+		return c.resolveTypeName(obj.TypeName, "")
+
+	case *solcparser.FunctionDefinition:
+		return c.functionType(obj, external)
+
+	case *solcparser.EventDefinition:
+		return &FunctionType{Name: obj.Name, Kind: FunctionKindEvent, Params: c.paramTypes(obj.Parameters), Decl: obj}
+
+	case *solcparser.CustomErrorDefinition:
+		return &FunctionType{Name: obj.Name, Kind: FunctionKindError, Params: c.paramTypes(obj.Parameters), Decl: obj}
+
+	case *solcparser.ModifierDefinition:
+		return &ModifierType{Def: obj}
+
+	case *solcparser.ContractDefinition:
+		return &TypeType{Actual: c.contractType(obj)}
+
+	case *solcparser.StructDefinition, *solcparser.EnumDefinition, *solcparser.TypeDefinition:
+		return &TypeType{Actual: c.userType(decl, "")}
+	}
+	return nil
+}
+
+// withScope evaluates fn with the contract as the scope for the type names
+func (c *checker) withScope(contract *solcparser.ContractDefinition, fn func() Type) Type {
+	if contract == nil || contract == c.contract {
+		return fn()
+	}
+	oldContract, oldScope := c.contract, c.scope
+	c.contract, c.scope = contract, c.contractScope(contract)
+	defer func() {
+		c.contract, c.scope = oldContract, oldScope
+	}()
+	return fn()
+}
+
+func (c *checker) functionType(fn *solcparser.FunctionDefinition, external bool) *FunctionType {
+	typ := c.withScope(c.owner[fn], func() Type {
+		kind := FunctionKindInternal
+		if external || fn.Visibility == "external" {
+			kind = FunctionKindExternal
+		}
+		mutability := fn.StateMutability
+		if mutability == "" {
+			mutability = "nonpayable"
+		}
+		return &FunctionType{
+			Name:       fn.Name,
+			Kind:       kind,
+			Params:     c.paramTypes(fn.Parameters),
+			Returns:    c.paramTypes(fn.ReturnParameters),
+			Mutability: mutability,
+			Decl:       fn,
+		}
+	})
+	return typ.(*FunctionType)
+}
+
+func (c *checker) paramTypes(params interface{}) []Type {
+	res := []Type{}
+	list, _ := params.([]interface{})
+	for _, p := range list {
+		decl, ok := p.(*solcparser.VariableDeclaration)
+		if !ok {
+			continue
+		}
+		res = append(res, c.resolveTypeName(decl.TypeName, decl.StorageLocation))
+	}
+	return res
+}
+
+func (c *checker) constructorParams(contract *solcparser.ContractDefinition) []Type {
+	for _, node := range contract.SubNodes {
+		if fn, ok := node.(*solcparser.FunctionDefinition); ok && fn.IsConstructor {
+			return c.withScope(contract, func() Type {
+				return &TupleType{Components: c.paramTypes(fn.Parameters)}
+			}).(*TupleType).Components
+		}
+	}
+	return []Type{}
+}
+
+func (c *checker) tuple(obj *solcparser.TupleExpression) Type {
+	comps := []Type{}
+	for _, comp := range obj.Components {
+		if comp == nil {
+			comps = append(comps, nil)
+			continue
+		}
+		comps = append(comps, c.expr(comp))
+	}
+
+	if obj.IsArray {
+		if len(comps) == 0 {
+			c.errorf(obj, "unable to deduce the type of an empty array")
+			return nil
+		}
+		base := MobileType(comps[0])
+		for _, comp := range comps[1:] {
+			if base == nil || comp == nil {
+				return nil
+			}
+			base = CommonType(base, comp)
+		}
+		if base == nil {
+			c.errorf(obj, "unable to deduce the common type of the array elements")
+			return nil
+		}
+		return &ArrayType{Base: base, Length: big.NewInt(int64(len(comps))), Location: Memory}
+	}
+
+	if len(comps) == 1 {
+		return comps[0]
+	}
+	return &TupleType{Components: comps}
+}
+
+func (c *checker) unary(obj *solcparser.UnaryOperation) Type {
+	typ := c.expr(obj.SubExpression)
+	if typ == nil {
+		return nil
+	}
+	switch obj.Operator {
+	case "!":
+		if !ImplicitlyConvertible(typ, tBool) {
+			c.errorf(obj, "unary operator ! cannot be applied to %s", typ)
+		}
+		return tBool
+	case "delete":
+		return tEmptyTuple
+	case "-", "~":
+		if r, ok := typ.(*RationalType); ok {
+			if obj.Operator == "-" {
+				return &RationalType{Value: new(big.Rat).Neg(r.Value)}
+			}
+			if !r.Value.IsInt() {
+				c.errorf(obj, "unary operator ~ cannot be applied to %s", typ)
+				return nil
+			}
+			return &RationalType{Value: new(big.Rat).SetInt(new(big.Int).Not(r.Value.Num()))}
+		}
+	}
+	switch typ.(type) {
+	case *IntType, *FixedPointType:
+		return typ
+	case *FixedBytesType:
+		if obj.Operator == "~" {
+			return typ
+		}
+	}
+	c.errorf(obj, "unary operator %s cannot be applied to %s", obj.Operator, typ)
+	return nil
+}
+
+func isAssignment(op string) bool {
+	return strings.HasSuffix(op, "=") && op != "==" && op != "!=" && op != "<=" && op != ">="
+}
+
+func (c *checker) binary(obj *solcparser.BinaryOperation) Type {
+	left, right := c.expr(obj.Left), c.expr(obj.Right)
+	if left == nil || right == nil {
+		return nil
+	}
+
+	if isAssignment(obj.Operator) {
+		if obj.Operator == "=" {
+			c.checkAssign(obj.Right, right, left)
+		} else if c.arithmetic(obj, strings.TrimSuffix(obj.Operator, "="), left, right) == nil {
+			return nil
+		}
+		return left
+	}
+
+	switch obj.Operator {
+	case "&&", "||":
+		if !ImplicitlyConvertible(left, tBool) || !ImplicitlyConvertible(right, tBool) {
+			c.errorf(obj, "operator %s not compatible with types %s and %s", obj.Operator, left, right)
+		}
+		return tBool
+
+	case "==", "!=", "<", ">", "<=", ">=":
+		if CommonType(left, right) == nil {
+			c.errorf(obj, "operator %s not compatible with types %s and %s", obj.Operator, left, right)
+		}
+		return tBool
+	}
+	return c.arithmetic(obj, obj.Operator, left, right)
+}
+
+func (c *checker) arithmetic(obj *solcparser.BinaryOperation, op string, left, right Type) Type {
+	l, lok := left.(*RationalType)
+	r, rok := right.(*RationalType)
+	if lok && rok {
+		res, err := constant.Fold(op, l.Value, r.Value)
+		if err != nil {
+			c.errorf(obj, "%v", err)
+			return nil
+		}
+		return &RationalType{Value: res}
+	}
+
+	switch op {
+	case "**", "<<", ">>":
+		// the result has the type of the left operand
+		if _, ok := MobileType(right).(*IntType); !ok {
+			c.errorf(obj, "operator %s not compatible with types %s and %s", op, left, right)
+			return nil
+		}
+		if lok {
+			return MobileType(left)
+		}
+		return left
+	}
+
+	common := CommonType(left, right)
+	if common == nil {
+		c.errorf(obj, "operator %s not compatible with types %s and %s", op, left, right)
+		return nil
+	}
+	switch common.(type) {
+	case *IntType, *FixedPointType:
+		return common
+	case *FixedBytesType:
+		if op == "&" || op == "|" || op == "^" {
+			return common
+		}
+	}
+	c.errorf(obj, "operator %s not compatible with types %s and %s", op, left, right)
+	return nil
+}
+
+func (c *checker) member(obj *solcparser.MemberAccess) Type {
+	base := c.expr(obj.Expression)
+	if base == nil {
+		return nil
+	}
+	name := obj.MemberName
+
+	switch typ := base.(type) {
+	case *MagicType:
+		if typ.Kind == "meta" && typ.Arg != nil {
+			if res, ok := metaMember(typ.Arg, name); ok {
+				return res
+			}
+		} else if res, ok := magicMember(typ.Kind, name); ok {
+			return res
+		}
+
+	case *AddressType:
+		if res, ok := addressMember(typ, name); ok {
+			return res
+		}
+
+	case *ContractType:
+		members := c.contractMembers(typ, name, false)
+		if len(members) != 0 {
+			c.recordRef(obj, members[0])
+			return c.memberType(members[0], true)
+		}
+
+	case *TypeType:
+		if res := c.typeMember(obj, typ.Actual, name); res != nil {
+			return res
+		}
+
+	case *StructType:
+		if typ.Def != nil {
+			for _, m := range typ.Def.Members {
+				decl, ok := m.(*solcparser.VariableDeclaration)
+				if ok && decl.Name == name {
+					c.recordRef(obj, decl)
+					res := c.withScope(c.owner[typ.Def], func() Type {
+						return c.resolveTypeName(decl.TypeName, typ.Location)
+					})
+					return res
+				}
+			}
+		}
+
+	case *ArrayType, *BytesType, *FixedBytesType:
+		if res, ok := arrayMember(base, name); ok {
+			return res
+		}
+
+	case *FunctionType:
+		switch name {
+		case "selector":
+			return tBytes4
+		case "address":
+			return tAddress
+		case "value", "gas":
+			return typ
+		}
+	}
+
+	// library functions attached with 'using for'
+	if fn := c.boundFunction(base, name); fn != nil {
+		c.recordRef(obj, fn.Decl)
+		return fn
+	}
+
+	c.errorf(obj, "member %s not found in %s", name, base)
+	return nil
+}
+
+// memberType returns the type of a contract member accessed from outside the contract
+func (c *checker) memberType(decl interface{}, external bool) Type {
+	if vv, ok := decl.(*solcparser.StateVariableDeclarationVariable); ok && external {
+		return c.getterType(vv)
+	}
+	return c.declType(decl, external)
+}
+
+// getterType returns the type of the getter of a public state variable
+func (c *checker) getterType(vv *solcparser.StateVariableDeclarationVariable) Type {
+	fn := &FunctionType{Name: vv.Name, Kind: FunctionKindExternal, Mutability: "view", Params: []Type{}, Decl: vv}
+
+	typ := c.declType(vv, false)
+	for {
+		switch obj := typ.(type) {
+		case *MappingType:
+			fn.Params = append(fn.Params, obj.Key)
+			typ = obj.Value
+			continue
+		case *ArrayType:
+			fn.Params = append(fn.Params, tUint256)
+			typ = obj.Base
+			continue
+		}
+		break
+	}
+	if st, ok := typ.(*StructType); ok && st.Def != nil {
+		for _, m := range st.Def.Members {
+			decl, ok := m.(*solcparser.VariableDeclaration)
+			if !ok {
+				continue
+			}
+			mt := c.withScope(c.owner[st.Def], func() Type {
+				return c.resolveTypeName(decl.TypeName, Memory)
+			})
+			switch mt.(type) {
+			case *MappingType, *ArrayType:
+				continue
+			}
+			fn.Returns = append(fn.Returns, mt)
+		}
+	} else if typ != nil {
+		fn.Returns = []Type{typ}
+	}
+	return fn
+}
+
+// contractMembers returns the members with the given name accessible from a contract type
+func (c *checker) contractMembers(typ *ContractType, name string, internal bool) []interface{} {
+	res := []interface{}{}
+	lin := c.linearize(typ.Def)
+	if typ.Super && len(lin) > 0 {
+		lin = lin[1:]
+		internal = true
+	}
+	for _, base := range lin {
+		for _, node := range base.SubNodes {
+			switch obj := node.(type) {
+			case *solcparser.FunctionDefinition:
+				if obj.Name != name {
+					continue
+				}
+				if internal || obj.Visibility == "public" || obj.Visibility == "external" || obj.Visibility == "default" {
+					res = append(res, obj)
+				}
+			case *solcparser.StateVariableDeclaration:
+				for _, v := range obj.Variables {
+					vv, ok := v.(*solcparser.StateVariableDeclarationVariable)
+					if ok && vv.Name == name && (internal || vv.Visibility == "public") {
+						res = append(res, vv)
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// typeMember returns the members of a type name (i.e. Lib.f, Enum.Value, Contract.Struct)
+func (c *checker) typeMember(obj *solcparser.MemberAccess, actual Type, name string) Type {
+	switch typ := actual.(type) {
+	case *ContractType:
+		for _, base := range c.linearize(typ.Def) {
+			for _, node := range base.SubNodes {
+				if fn, ok := node.(*solcparser.FunctionDefinition); ok && fn.Name == name {
+					c.recordRef(obj, fn)
+					if typ.Def.Kind == "library" && (fn.Visibility == "public" || fn.Visibility == "external") {
+						// public library functions are called with delegatecall
+						return c.functionType(fn, false)
+					}
+					return c.declType(fn, false)
+				}
+				if declName(node) == name {
+					c.recordRef(obj, node)
+					return c.declType(node, false)
+				}
+			}
+		}
+
+	case *EnumType:
+		if typ.Def != nil {
+			for _, m := range typ.Def.Members {
+				if v, ok := m.(*solcparser.EnumValue); ok && v.Name == name {
+					return typ
+				}
+			}
+		}
+
+	case *UserDefinedValueType:
+		switch name {
+		case "wrap":
+			return builtinFn("wrap", []Type{typ.Underlying}, typ)
+		case "unwrap":
+			return builtinFn("unwrap", []Type{typ}, typ.Underlying)
+		}
+
+	case *StringType:
+		if name == "concat" {
+			return builtin("string.concat", tStringMemory)
+		}
+
+	case *BytesType:
+		if name == "concat" {
+			return builtin("bytes.concat", tBytesMemory)
+		}
+	}
+	return nil
+}
+
+// boundFunction returns the library function attached to the type with 'using for'
+func (c *checker) boundFunction(base Type, name string) *FunctionType {
+	if c.contract == nil {
+		return nil
+	}
+	for _, contract := range c.linearize(c.contract) {
+		for _, node := range contract.SubNodes {
+			using, ok := node.(*solcparser.UsingForDeclaration)
+			if !ok {
+				continue
+			}
+			if using.TypeName != nil {
+				target := c.withScope(contract, func() Type {
+					return c.resolveTypeName(using.TypeName, "")
+				})
+				if !Identical(target, base) {
+					continue
+				}
+			}
+			lib, ok := c.project.Contract(using.LibraryName)
+			if !ok {
+				continue
+			}
+			for _, node := range lib.SubNodes {
+				fn, ok := node.(*solcparser.FunctionDefinition)
+				if !ok || fn.Name != name {
+					continue
+				}
+				typ := c.functionType(fn, false)
+				if len(typ.Params) == 0 || !ImplicitlyConvertible(base, typ.Params[0]) && !Identical(base, typ.Params[0]) {
+					continue
+				}
+				bound := *typ
+				bound.Params = typ.Params[1:]
+				bound.Bound = true
+				return &bound
+			}
+		}
+	}
+	return nil
+}
+
+func (c *checker) index(obj *solcparser.IndexAccess) Type {
+	base := c.expr(obj.Base)
+	index := c.expr(obj.Index)
+	if base == nil {
+		return nil
+	}
+
+	switch typ := base.(type) {
+	case *MappingType:
+		if index != nil && !ImplicitlyConvertible(index, typ.Key) {
+			c.errorf(obj.Index, "cannot implicitly convert %s to %s", index, typ.Key)
+		}
+		return typ.Value
+
+	case *ArrayType:
+		c.checkIndex(obj.Index, index)
+		return withLocationOf(typ.Base, typ.Location)
+
+	case *BytesType, *FixedBytesType:
+		c.checkIndex(obj.Index, index)
+		return &FixedBytesType{Size: 1}
+
+	case *TypeType:
+		// array type names (i.e. uint[2])
+		var length *big.Int
+		if r, ok := index.(*RationalType); ok && r.Value.IsInt() {
+			length = r.Value.Num()
+		}
+		return &TypeType{Actual: &ArrayType{Base: typ.Actual, Length: length}}
+	}
+
+	c.errorf(obj, "indexed expression has type %s", base)
+	return nil
+}
+
+func (c *checker) checkIndex(node interface{}, index Type) {
+	if index == nil {
+		return
+	}
+	if _, ok := MobileType(index).(*IntType); !ok {
+		c.errorf(node, "index must be an integer but found %s", index)
+	}
+}
+
+func (c *checker) call(obj *solcparser.FunctionCall) Type {
+	// type(X)
+	if ident, ok := obj.Expression.(*solcparser.Identifier); ok && ident.Name == "type" {
+		if len(obj.Arguments) != 1 {
+			c.errorf(obj, "type() expects one argument")
+			return nil
+		}
+		arg, ok := c.expr(obj.Arguments[0]).(*TypeType)
+		if !ok {
+			c.errorf(obj, "type() expects a type name")
+			return nil
+		}
+		c.record(obj.Expression, &MagicType{Kind: "meta"})
+		return &MagicType{Kind: "meta", Arg: arg.Actual}
+	}
+
+	args := []Type{}
+	for _, arg := range obj.Arguments {
+		args = append(args, c.expr(arg))
+	}
+
+	callee := c.expr(obj.Expression)
+	if callee == nil {
+		return nil
+	}
+
+	switch typ := callee.(type) {
+	case *TypeType:
+		if st, ok := typ.Actual.(*StructType); ok {
+			return &StructType{Name: st.Name, Def: st.Def, Location: Memory}
+		}
+		// explicit conversion
+		if len(args) != 1 {
+			c.errorf(obj, "exactly one argument expected for the explicit type conversion")
+		}
+		return withLocationOf(typ.Actual, Memory)
+
+	case *FunctionType:
+		fn := c.resolveOverload(obj, typ, args)
+		return c.callResult(obj, fn, args)
+	}
+
+	c.errorf(obj, "type %s is not callable", callee)
+	return nil
+}
+
+// resolveOverload picks the overloaded function that matches the arguments
+func (c *checker) resolveOverload(obj *solcparser.FunctionCall, fn *FunctionType, args []Type) *FunctionType {
+	var candidates []interface{}
+	external := fn.Kind == FunctionKindExternal
+
+	switch expr := obj.Expression.(type) {
+	case *solcparser.Identifier:
+		candidates = c.scope.lookup(expr.Name)
+	case *solcparser.MemberAccess:
+		if ct, ok := c.info.TypeOf(expr.Expression).(*ContractType); ok {
+			candidates = c.contractMembers(ct, expr.MemberName, false)
+		}
+	}
+	if len(candidates) < 2 {
+		return fn
+	}
+
+	for _, cand := range candidates {
+		typ, ok := c.memberType(cand, external).(*FunctionType)
+		if !ok || len(typ.Params) != len(args) {
+			continue
+		}
+		match := true
+		for i := range args {
+			if args[i] != nil && !ImplicitlyConvertible(args[i], typ.Params[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			c.recordRef(obj.Expression, cand)
+			c.record(obj.Expression, typ)
+			return typ
+		}
+	}
+	return fn
+}
+
+func (c *checker) callResult(obj *solcparser.FunctionCall, fn *FunctionType, args []Type) Type {
+	switch fn.Name {
+	case "abi.decode":
+		if len(obj.Arguments) != 2 {
+			c.errorf(obj, "abi.decode expects two arguments")
+			return nil
+		}
+		switch typ := args[1].(type) {
+		case *TypeType:
+			return withLocationOf(typ.Actual, Memory)
+		case *TupleType:
+			comps := []Type{}
+			for _, comp := range typ.Components {
+				tt, ok := comp.(*TypeType)
+				if !ok {
+					c.errorf(obj, "abi.decode expects a tuple of types")
+					return nil
+				}
+				comps = append(comps, withLocationOf(tt.Actual, Memory))
+			}
+			return &TupleType{Components: comps}
+		}
+		c.errorf(obj, "abi.decode expects a tuple of types")
+		return nil
+
+	case "push":
+		if len(args) != 0 {
+			return tEmptyTuple
+		}
+	}
+
+	if !fn.Variadic && len(obj.Names) == 0 {
+		if len(args) != len(fn.Params) {
+			c.errorf(obj, "wrong argument count for function call: %d arguments given but expected %d", len(args), len(fn.Params))
+		} else {
+			for i, arg := range args {
+				if arg != nil && fn.Params[i] != nil && !ImplicitlyConvertible(arg, fn.Params[i]) {
+					c.errorf(obj.Arguments[i], "invalid implicit conversion from %s to %s", arg, fn.Params[i])
+				}
+			}
+		}
+	}
+
+	if len(fn.Returns) == 1 {
+		return fn.Returns[0]
+	}
+	return &TupleType{Components: fn.Returns}
+}
+
+func withLocationOf(typ Type, location string) Type {
+	switch obj := typ.(type) {
+	case *BytesType:
+		return &BytesType{Location: location}
+	case *StringType:
+		return &StringType{Location: location}
+	case *ArrayType:
+		return &ArrayType{Base: obj.Base, Length: obj.Length, Location: location}
+	case *StructType:
+		return &StructType{Name: obj.Name, Def: obj.Def, Location: location}
+	}
+	return typ
+}
+
+func declName(node interface{}) string {
+	switch obj := node.(type) {
+	case *solcparser.ContractDefinition:
+		return obj.Name
+	case *solcparser.StructDefinition:
+		return obj.Name
+	case *solcparser.EnumDefinition:
+		return obj.Name
+	case *solcparser.TypeDefinition:
+		return obj.Name
+	case *solcparser.FileLevelConstant:
+		return obj.Name
+	case *solcparser.FunctionDefinition:
+		return obj.Name
+	case *solcparser.EventDefinition:
+		return obj.Name
+	case *solcparser.CustomErrorDefinition:
+		return obj.Name
+	case *solcparser.ModifierDefinition:
+		return obj.Name
+	}
+	return ""
+}
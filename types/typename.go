@@ -0,0 +1,199 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/constant"
+)
+
+// resolveTypeName returns the type of a type name with the given data location
+func (c *checker) resolveTypeName(typeName interface{}, location string) Type {
+	switch obj := typeName.(type) {
+	case nil:
+		return nil
+
+	case *solcparser.ElementaryTypeName:
+		typ := elementaryType(obj.Name, obj.StateMutability)
+		if typ == nil {
+			c.errorf(obj, "unknown elementary type %s", obj.Name)
+			return nil
+		}
+		return withLocationOf(typ, location)
+
+	case *solcparser.UserDefinedTypeName:
+		decl, ok := c.lookupType(obj.NamePath)
+		if !ok {
+			c.errorf(obj, "identifier %s not found or not a type", obj.NamePath)
+			return nil
+		}
+		c.recordRef(obj, decl)
+		return c.userType(decl, location)
+
+	case *solcparser.Mapping:
+		key := c.resolveTypeName(obj.KeyType, Memory)
+		value := c.resolveTypeName(obj.ValueType, Storage)
+		if key == nil || value == nil {
+			return nil
+		}
+		return &MappingType{Key: key, Value: value}
+
+	case *solcparser.ArrayTypeName:
+		base := c.resolveTypeName(obj.BaseTypeName, location)
+		if base == nil {
+			return nil
+		}
+		typ := &ArrayType{Base: base, Location: location}
+		if obj.Length != nil {
+			c.expr(obj.Length)
+			value, err := constant.Eval(obj.Length, constant.WithProject(c.project), constant.WithContract(c.contract))
+			if err != nil {
+				c.errorf(obj.Length, "invalid array length: %v", err)
+				return nil
+			}
+			length, ok := value.Int()
+			if !ok || length.Sign() <= 0 {
+				c.errorf(obj.Length, "invalid array length, expected a positive integer constant")
+				return nil
+			}
+			typ.Length = length
+		}
+		return typ
+
+	case *solcparser.FunctionTypeName:
+		kind := FunctionKindInternal
+		if obj.Visibility == "external" {
+			kind = FunctionKindExternal
+		}
+		mutability := obj.StateMutability
+		if mutability == "" {
+			mutability = "nonpayable"
+		}
+		return &FunctionType{
+			Kind:       kind,
+			Params:     c.paramTypes(obj.ParameterTypes),
+			Returns:    c.paramTypes(obj.ReturnTypes),
+			Mutability: mutability,
+		}
+	}
+
+	c.errorf(typeName, "unexpected type name %T", typeName)
+	return nil
+}
+
+// lookupType finds the declaration of a user defined type name
+func (c *checker) lookupType(path string) (interface{}, bool) {
+	parts := strings.Split(path, ".")
+	name := parts[len(parts)-1]
+
+	if len(parts) > 1 {
+		// Contract.Name (imported unit aliases are ignored)
+		contract, ok := c.project.Contract(parts[len(parts)-2])
+		if !ok {
+			return nil, false
+		}
+		for _, node := range contract.SubNodes {
+			if isTypeDecl(node) && declName(node) == name {
+				return node, true
+			}
+		}
+		return nil, false
+	}
+
+	for _, decl := range c.scope.lookup(name) {
+		if isTypeDecl(decl) {
+			return decl, true
+		}
+	}
+	return nil, false
+}
+
+func isTypeDecl(node interface{}) bool {
+	switch node.(type) {
+	case *solcparser.ContractDefinition, *solcparser.StructDefinition, *solcparser.EnumDefinition, *solcparser.TypeDefinition:
+		return true
+	}
+	return false
+}
+
+// userType returns the type of a user defined type declaration
+func (c *checker) userType(decl interface{}, location string) Type {
+	qualified := func(name string) string {
+		if owner, ok := c.owner[decl]; ok {
+			return owner.Name + "." + name
+		}
+		return name
+	}
+
+	switch obj := decl.(type) {
+	case *solcparser.ContractDefinition:
+		return c.contractType(obj)
+
+	case *solcparser.StructDefinition:
+		return &StructType{Name: qualified(obj.Name), Def: obj, Location: location}
+
+	case *solcparser.EnumDefinition:
+		return &EnumType{Name: qualified(obj.Name), Def: obj}
+
+	case *solcparser.TypeDefinition:
+		underlying := c.withScope(c.owner[obj], func() Type {
+			return c.resolveTypeName(obj.Definition, "")
+		})
+		return &UserDefinedValueType{Name: qualified(obj.Name), Def: obj, Underlying: underlying}
+	}
+	return nil
+}
+
+// elementaryType returns the type of an elementary type name or nil if it is not valid
+func elementaryType(name string, mutability string) Type {
+	switch name {
+	case "address":
+		return &AddressType{Payable: mutability == "payable"}
+	case "bool":
+		return tBool
+	case "string":
+		return &StringType{}
+	case "bytes":
+		return &BytesType{}
+	case "byte":
+		return &FixedBytesType{Size: 1}
+	case "uint":
+		return tUint256
+	case "int":
+		return &IntType{Signed: true, Bits: 256}
+	case "fixed":
+		return &FixedPointType{Signed: true, Bits: 128, Decimals: 18}
+	case "ufixed":
+		return &FixedPointType{Bits: 128, Decimals: 18}
+	}
+
+	var bits, decimals int
+	switch {
+	case strings.HasPrefix(name, "uint"):
+		if _, err := fmt.Sscanf(name, "uint%d", &bits); err == nil && validBits(bits) {
+			return &IntType{Bits: bits}
+		}
+	case strings.HasPrefix(name, "int"):
+		if _, err := fmt.Sscanf(name, "int%d", &bits); err == nil && validBits(bits) {
+			return &IntType{Signed: true, Bits: bits}
+		}
+	case strings.HasPrefix(name, "bytes"):
+		if _, err := fmt.Sscanf(name, "bytes%d", &bits); err == nil && bits >= 1 && bits <= 32 {
+			return &FixedBytesType{Size: bits}
+		}
+	case strings.HasPrefix(name, "ufixed"):
+		if _, err := fmt.Sscanf(name, "ufixed%dx%d", &bits, &decimals); err == nil && validBits(bits) && decimals <= 80 {
+			return &FixedPointType{Bits: bits, Decimals: decimals}
+		}
+	case strings.HasPrefix(name, "fixed"):
+		if _, err := fmt.Sscanf(name, "fixed%dx%d", &bits, &decimals); err == nil && validBits(bits) && decimals <= 80 {
+			return &FixedPointType{Signed: true, Bits: bits, Decimals: decimals}
+		}
+	}
+	return nil
+}
+
+func validBits(bits int) bool {
+	return bits >= 8 && bits <= 256 && bits%8 == 0
+}
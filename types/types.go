@@ -0,0 +1,503 @@
+// Package types assigns a Solidity type to the expressions of a parsed project.
+//
+// The checker only uses syntactic information: the declared types of the
+// variables and functions, the implicit conversion rules of the language, the
+// rational type of the literals and the global builtins. Expressions whose type
+// cannot be resolved are reported as diagnostics.
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// Type is the type of an expression
+type Type interface {
+	String() string
+}
+
+// Data locations of the reference types
+const (
+	Storage  = "storage"
+	Memory   = "memory"
+	Calldata = "calldata"
+)
+
+// IntType is a signed or unsigned integer type
+type IntType struct {
+	Signed bool
+	Bits   int
+}
+
+func (i *IntType) String() string {
+	if i.Signed {
+		return fmt.Sprintf("int%d", i.Bits)
+	}
+	return fmt.Sprintf("uint%d", i.Bits)
+}
+
+// Min returns the minimum value of the type
+func (i *IntType) Min() *big.Int {
+	if !i.Signed {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(i.Bits-1)))
+}
+
+// Max returns the maximum value of the type
+func (i *IntType) Max() *big.Int {
+	bits := i.Bits
+	if i.Signed {
+		bits--
+	}
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits)), big.NewInt(1))
+}
+
+// FixedPointType is a signed or unsigned fixed point number
+type FixedPointType struct {
+	Signed   bool
+	Bits     int
+	Decimals int
+}
+
+func (f *FixedPointType) String() string {
+	if f.Signed {
+		return fmt.Sprintf("fixed%dx%d", f.Bits, f.Decimals)
+	}
+	return fmt.Sprintf("ufixed%dx%d", f.Bits, f.Decimals)
+}
+
+// AddressType is the address type
+type AddressType struct {
+	Payable bool
+}
+
+func (a *AddressType) String() string {
+	if a.Payable {
+		return "address payable"
+	}
+	return "address"
+}
+
+// BoolType is the bool type
+type BoolType struct{}
+
+func (b *BoolType) String() string {
+	return "bool"
+}
+
+// FixedBytesType is one of the bytes1 to bytes32 types
+type FixedBytesType struct {
+	Size int
+}
+
+func (f *FixedBytesType) String() string {
+	return fmt.Sprintf("bytes%d", f.Size)
+}
+
+// BytesType is the dynamic bytes type
+type BytesType struct {
+	Location string
+}
+
+func (b *BytesType) String() string {
+	return withLocation("bytes", b.Location)
+}
+
+// StringType is the string type
+type StringType struct {
+	Location string
+}
+
+func (s *StringType) String() string {
+	return withLocation("string", s.Location)
+}
+
+// RationalType is the type of the number literals and the constant
+// expressions built from them
+type RationalType struct {
+	Value *big.Rat
+
+	// IsAddress is true if the literal is a 40 digits hex number
+	IsAddress bool
+}
+
+func (r *RationalType) String() string {
+	if r.Value.IsInt() {
+		return "int_const " + r.Value.Num().String()
+	}
+	return "rational_const " + r.Value.Num().String() + " / " + r.Value.Denom().String()
+}
+
+// StringLiteralType is the type of the string and hex literals
+type StringLiteralType struct {
+	Value string
+}
+
+func (s *StringLiteralType) String() string {
+	return fmt.Sprintf("literal_string %q", s.Value)
+}
+
+// ArrayType is a fixed size or dynamic array
+type ArrayType struct {
+	Base     Type
+	Length   *big.Int
+	Location string
+}
+
+func (a *ArrayType) String() string {
+	length := ""
+	if a.Length != nil {
+		length = a.Length.String()
+	}
+	return withLocation(a.Base.String()+"["+length+"]", a.Location)
+}
+
+// MappingType is a mapping
+type MappingType struct {
+	Key   Type
+	Value Type
+}
+
+func (m *MappingType) String() string {
+	return "mapping(" + m.Key.String() + " => " + m.Value.String() + ")"
+}
+
+// StructType is a user defined struct
+type StructType struct {
+	Name     string
+	Def      *solcparser.StructDefinition
+	Location string
+}
+
+func (s *StructType) String() string {
+	return withLocation("struct "+s.Name, s.Location)
+}
+
+// EnumType is a user defined enum
+type EnumType struct {
+	Name string
+	Def  *solcparser.EnumDefinition
+}
+
+func (e *EnumType) String() string {
+	return "enum " + e.Name
+}
+
+// ContractType is a contract, interface or library
+type ContractType struct {
+	Def *solcparser.ContractDefinition
+
+	// Bases is the linearization of the contract
+	Bases []*solcparser.ContractDefinition
+
+	// Super is true for the 'super' keyword
+	Super bool
+}
+
+func (c *ContractType) String() string {
+	kind := c.Def.Kind
+	if kind == "interface" {
+		kind = "contract"
+	}
+	if c.Super {
+		return kind + " super " + c.Def.Name
+	}
+	return kind + " " + c.Def.Name
+}
+
+// UserDefinedValueType is a type defined with 'type X is Y'
+type UserDefinedValueType struct {
+	Name       string
+	Def        *solcparser.TypeDefinition
+	Underlying Type
+}
+
+func (u *UserDefinedValueType) String() string {
+	return u.Name
+}
+
+// Kinds of function types
+const (
+	FunctionKindInternal = "internal"
+	FunctionKindExternal = "external"
+	FunctionKindEvent    = "event"
+	FunctionKindError    = "error"
+	FunctionKindBuiltin  = "builtin"
+	FunctionKindCreation = "creation"
+)
+
+// FunctionType is the type of a function, event, error or builtin
+type FunctionType struct {
+	Name       string
+	Kind       string
+	Params     []Type
+	Returns    []Type
+	Mutability string
+
+	// Variadic functions (i.e. builtins like require or abi.encode) do not check their arguments
+	Variadic bool
+
+	// Decl is the declaration of the function if any
+	Decl interface{}
+
+	// Bound is true for library functions attached with 'using for'
+	Bound bool
+}
+
+func (f *FunctionType) String() string {
+	params := []string{}
+	for _, p := range f.Params {
+		params = append(params, p.String())
+	}
+	str := "function (" + strings.Join(params, ",") + ")"
+	if f.Kind == FunctionKindExternal {
+		str += " external"
+	}
+	if f.Mutability != "" && f.Mutability != "nonpayable" {
+		str += " " + f.Mutability
+	}
+	if len(f.Returns) != 0 {
+		returns := []string{}
+		for _, r := range f.Returns {
+			returns = append(returns, r.String())
+		}
+		str += " returns (" + strings.Join(returns, ",") + ")"
+	}
+	return str
+}
+
+// TupleType is the type of a tuple expression or a function with multiple returns.
+// Empty components are nil.
+type TupleType struct {
+	Components []Type
+}
+
+func (t *TupleType) String() string {
+	comps := []string{}
+	for _, c := range t.Components {
+		if c == nil {
+			comps = append(comps, "")
+		} else {
+			comps = append(comps, c.String())
+		}
+	}
+	return "tuple(" + strings.Join(comps, ",") + ")"
+}
+
+// TypeType is the type of an expression that refers to a type (i.e. 'uint' in 'uint(x)')
+type TypeType struct {
+	Actual Type
+}
+
+func (t *TypeType) String() string {
+	return "type(" + t.Actual.String() + ")"
+}
+
+// MagicType is the type of the global variables (msg, block, tx, abi) and of 'type(X)'
+type MagicType struct {
+	Kind string
+
+	// Arg is the type argument of 'type(X)'
+	Arg Type
+}
+
+func (m *MagicType) String() string {
+	if m.Kind == "meta" && m.Arg != nil {
+		return "type(" + m.Arg.String() + ")"
+	}
+	return m.Kind
+}
+
+// ModifierType is the type of a modifier
+type ModifierType struct {
+	Def *solcparser.ModifierDefinition
+}
+
+func (m *ModifierType) String() string {
+	return "modifier " + m.Def.Name
+}
+
+func withLocation(str, location string) string {
+	if location == "" {
+		return str
+	}
+	return str + " " + location
+}
+
+var (
+	tUint256        = &IntType{Bits: 256}
+	tUint8          = &IntType{Bits: 8}
+	tBool           = &BoolType{}
+	tAddress        = &AddressType{}
+	tAddressPayable = &AddressType{Payable: true}
+	tBytes32        = &FixedBytesType{Size: 32}
+	tBytes4         = &FixedBytesType{Size: 4}
+	tBytesMemory    = &BytesType{Location: Memory}
+	tStringMemory   = &StringType{Location: Memory}
+	tEmptyTuple     = &TupleType{Components: []Type{}}
+)
+
+// Identical returns true if both types are the same, ignoring the data location
+func Identical(a, b Type) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return withoutLocation(a).String() == withoutLocation(b).String()
+}
+
+func withoutLocation(t Type) Type {
+	switch obj := t.(type) {
+	case *BytesType:
+		return &BytesType{}
+	case *StringType:
+		return &StringType{}
+	case *ArrayType:
+		return &ArrayType{Base: withoutLocation(obj.Base), Length: obj.Length}
+	case *StructType:
+		return &StructType{Name: obj.Name, Def: obj.Def}
+	}
+	return t
+}
+
+// ImplicitlyConvertible returns true if a value of type 'from' can be used
+// where a value of type 'to' is expected without an explicit conversion
+func ImplicitlyConvertible(from, to Type) bool {
+	if from == nil || to == nil {
+		return false
+	}
+	if Identical(from, to) {
+		return true
+	}
+
+	switch f := from.(type) {
+	case *IntType:
+		t, ok := to.(*IntType)
+		if !ok {
+			return false
+		}
+		if f.Signed == t.Signed {
+			return t.Bits >= f.Bits
+		}
+		// unsigned to a larger signed type
+		return !f.Signed && t.Bits > f.Bits
+
+	case *RationalType:
+		switch t := to.(type) {
+		case *IntType:
+			if !f.Value.IsInt() {
+				return false
+			}
+			num := f.Value.Num()
+			return num.Cmp(t.Min()) >= 0 && num.Cmp(t.Max()) <= 0
+		case *FixedBytesType:
+			return f.Value.Sign() == 0
+		case *AddressType:
+			return f.IsAddress
+		case *FixedPointType:
+			return true
+		}
+		return false
+
+	case *StringLiteralType:
+		switch t := to.(type) {
+		case *StringType, *BytesType:
+			return true
+		case *FixedBytesType:
+			return len(f.Value) <= t.Size
+		}
+		return false
+
+	case *FixedBytesType:
+		t, ok := to.(*FixedBytesType)
+		return ok && t.Size >= f.Size
+
+	case *AddressType:
+		t, ok := to.(*AddressType)
+		return ok && f.Payable && !t.Payable
+
+	case *ContractType:
+		t, ok := to.(*ContractType)
+		if !ok {
+			return false
+		}
+		for _, base := range f.Bases {
+			if base == t.Def {
+				return true
+			}
+		}
+		return false
+
+	case *TupleType:
+		t, ok := to.(*TupleType)
+		if !ok || len(f.Components) != len(t.Components) {
+			return false
+		}
+		for i := range f.Components {
+			if t.Components[i] == nil {
+				// the value is discarded
+				continue
+			}
+			if !ImplicitlyConvertible(f.Components[i], t.Components[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// MobileType returns the type used to store a literal in a variable
+func MobileType(t Type) Type {
+	switch obj := t.(type) {
+	case *RationalType:
+		if !obj.Value.IsInt() {
+			return &FixedPointType{Signed: obj.Value.Sign() < 0, Bits: 128, Decimals: 18}
+		}
+		num := obj.Value.Num()
+		signed := num.Sign() < 0
+		for bits := 8; bits <= 256; bits += 8 {
+			typ := &IntType{Signed: signed, Bits: bits}
+			if num.Cmp(typ.Min()) >= 0 && num.Cmp(typ.Max()) <= 0 {
+				return typ
+			}
+		}
+		return nil
+	case *StringLiteralType:
+		return tStringMemory
+	case *TupleType:
+		comps := []Type{}
+		for _, c := range obj.Components {
+			if c == nil {
+				comps = append(comps, nil)
+			} else {
+				comps = append(comps, MobileType(c))
+			}
+		}
+		return &TupleType{Components: comps}
+	}
+	return t
+}
+
+// CommonType returns the type both types can be implicitly converted to
+func CommonType(a, b Type) Type {
+	if ImplicitlyConvertible(b, a) {
+		return MobileType(a)
+	}
+	if ImplicitlyConvertible(a, b) {
+		return MobileType(b)
+	}
+	ma, mb := MobileType(a), MobileType(b)
+	if ma != a || mb != b {
+		if ImplicitlyConvertible(mb, ma) {
+			return ma
+		}
+		if ImplicitlyConvertible(ma, mb) {
+			return mb
+		}
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+// checkSource type checks a contract and returns the type of the
+// expression returned by the function 'f'
+func checkSource(t *testing.T, src string) (Type, *Info) {
+	t.Helper()
+
+	p := solcparser.NewProject()
+	if err := p.AddSource("a.sol", src); err != nil {
+		t.Fatal(err)
+	}
+	info := Check(p)
+
+	var res Type
+	for _, contract := range p.Contracts() {
+		for _, node := range contract.SubNodes {
+			fn, ok := node.(*solcparser.FunctionDefinition)
+			if !ok || fn.Name != "f" {
+				continue
+			}
+			for _, st := range fn.Body.(*solcparser.Block).Statements {
+				if ret, ok := st.(*solcparser.ReturnStatement); ok {
+					res = info.TypeOf(ret.Expression)
+				}
+			}
+		}
+	}
+	return res, info
+}
+
+func TestCheckExpressions(t *testing.T) {
+	cases := []struct {
+		src  string
+		expr string
+		typ  string
+	}{
+		{"uint8 x;", "x + 1", "uint8"},
+		{"uint8 x; uint16 y;", "x * y", "uint16"},
+		{"", "1 + 2 * 3", "int_const 7"},
+		{"", "1 / 2", "rational_const 1 / 2"},
+		{"", "1 ether", "int_const 1000000000000000000"},
+		{"", "msg.sender", "address"},
+		{"", "msg.data", "bytes calldata"},
+		{"", "block.timestamp > 0", "bool"},
+		{"address payable a;", "a", "address payable"},
+		{"", "payable(msg.sender)", "address payable"},
+		{"address a;", "a.balance", "uint256"},
+		{"mapping(address => uint) m;", "m[msg.sender]", "uint256"},
+		{"mapping(uint => mapping(uint => bool)) m;", "m[1][2]", "bool"},
+		{"uint[] a;", "a.length", "uint256"},
+		{"uint[3] a;", "a", "uint256[3] storage"},
+		{"struct S { uint a; bytes b; } S s;", "s.b", "bytes storage"},
+		{"struct S { uint a; }", "S(1)", "struct A.S memory"},
+		{"enum E { X, Y }", "E.Y", "enum A.E"},
+		{"", "type(uint8).max", "uint8"},
+		{"", "type(A).name", "string memory"},
+		{"", "keccak256(abi.encodePacked(uint(1)))", "bytes32"},
+		{"", "abi.decode(msg.data, (uint, address))", "tuple(uint256,address)"},
+		{"", "uint16(1)", "uint16"},
+		{"", "this", "contract A"},
+		{"", "address(this)", "address"},
+		{"function g(uint a) internal pure returns (uint, bool) {}", "g(1)", "tuple(uint256,bool)"},
+		{"function g(uint a) internal returns (uint) {} function g(bool a) internal returns (bool) {}", "g(true)", "bool"},
+		{"", "true ? 1 : 2", "uint8"},
+		{"", "new uint[](3)", "uint256[] memory"},
+		{"", "[1, 2, 3]", "uint8[3] memory"},
+		{"", "\"abc\"", "literal_string \"abc\""},
+		{"type Price is uint128;", "Price.unwrap(Price.wrap(1))", "uint128"},
+		{"", "bytes.concat(\"a\", \"b\")", "bytes memory"},
+	}
+
+	for _, c := range cases {
+		src := "contract A { " + c.src + " function f() public { return " + c.expr + "; } }"
+		typ, info := checkSource(t, src)
+		if typ == nil {
+			t.Fatalf("no type for '%s': %v", c.expr, info.Diagnostics)
+		}
+		if typ.String() != c.typ {
+			t.Fatalf("bad type for '%s': expected '%s' but found '%s'", c.expr, c.typ, typ.String())
+		}
+	}
+}
+
+func TestCheckContracts(t *testing.T) {
+	src := `
+interface IERC20 {
+	function transfer(address to, uint amount) external returns (bool);
+}
+
+library SafeMath {
+	function add(uint a, uint b) internal pure returns (uint) {
+		return a + b;
+	}
+}
+
+contract Base {
+	uint public total;
+
+	function g() internal virtual returns (uint) {
+		return total;
+	}
+}
+
+contract A is Base {
+	using SafeMath for uint;
+
+	IERC20 token;
+
+	function g() internal override returns (uint) {
+		return super.g();
+	}
+
+	function f() public returns (bool) {
+		uint x = total.add(1);
+		(uint y, ) = (x, true);
+		return token.transfer(msg.sender, y);
+	}
+}
+`
+	typ, info := checkSource(t, src)
+	if len(info.Diagnostics) != 0 {
+		t.Fatal(info.Diagnostics)
+	}
+	if typ == nil || typ.String() != "bool" {
+		t.Fatalf("bad type %v", typ)
+	}
+}
+
+func TestCheckRefs(t *testing.T) {
+	src := `
+contract Base {
+	struct Point { uint x; }
+
+	constructor(uint x) {}
+
+	modifier onlyOwner() { _; }
+}
+
+contract A is Base(1) {
+	modifier onlyOwner() override { _; }
+
+	function f(Point memory p) public onlyOwner returns (uint) {
+		return p.x;
+	}
+}
+`
+	p := solcparser.NewProject()
+	if err := p.AddSource("a.sol", src); err != nil {
+		t.Fatal(err)
+	}
+	info := Check(p)
+	if len(info.Diagnostics) != 0 {
+		t.Fatal(info.Diagnostics)
+	}
+	base, _ := p.Contract("Base")
+	a, _ := p.Contract("A")
+
+	refs := map[string]interface{}{}
+	solcparser.Inspect(p.Units["a.sol"], func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *solcparser.UserDefinedTypeName:
+			refs[obj.NamePath] = info.RefOf(obj)
+		case *solcparser.ModifierInvocation:
+			refs[obj.Name] = info.RefOf(obj)
+		}
+		return true
+	})
+	if refs["Base"] != base {
+		t.Fatal("the base contract should be referenced")
+	}
+	if refs["Point"] != base.SubNodes[0] {
+		t.Fatal("the struct should be referenced")
+	}
+	if refs["onlyOwner"] != a.SubNodes[0] {
+		t.Fatal("the modifier of A should be referenced")
+	}
+}
+
+func TestCheckDiagnostics(t *testing.T) {
+	cases := []struct {
+		src string
+		msg string
+	}{
+		{
+			"function f() public { return x; }",
+			"a.sol:1:43: undeclared identifier x",
+		},
+		{
+			"function f() public { uint8 x = 256; }",
+			"cannot implicitly convert int_const 256 to uint8",
+		},
+		{
+			"function f() public { if (1) {} }",
+			"expected a bool condition but found int_const 1",
+		},
+		{
+			"function f() public { address a; a.foo(); }",
+			"member foo not found in address",
+		},
+		{
+			"function g(uint a) internal {} function f() public { g(1, 2); }",
+			"wrong argument count for function call: 2 arguments given but expected 1",
+		},
+		{
+			"function g(uint8 a) internal {} function f() public { uint x; g(x); }",
+			"invalid implicit conversion from uint256 to uint8",
+		},
+		{
+			"uint[] a; function f() public { a[true]; }",
+			"index must be an integer but found bool",
+		},
+		{
+			"function f() public { _; }",
+			"undeclared identifier _",
+		},
+	}
+
+	for _, c := range cases {
+		_, info := checkSource(t, "contract A { "+c.src+" }")
+		if len(info.Diagnostics) != 1 {
+			t.Fatalf("one diagnostic expected for '%s' but found %v", c.src, info.Diagnostics)
+		}
+		if str := info.Diagnostics[0].String(); !strings.HasSuffix(str, c.msg) {
+			t.Fatalf("bad diagnostic for '%s': %s", c.src, str)
+		}
+	}
+}
+
+func TestSignature(t *testing.T) {
+	src := `
+interface I {}
+
+contract A {
+	struct Point { uint x; int8 y; }
+	enum Kind { A, B }
+	type Price is uint128;
+
+	mapping(address => mapping(uint => bool)) public allowed;
+	uint[] public values;
+	mapping(uint => uint) m;
+
+	event Transfer(address indexed from, address indexed to, uint value);
+	error Insufficient(uint available, uint required);
+
+	constructor() {}
+	function transfer(address to, uint amount) public returns (bool) {}
+	function move(Point memory p, Kind k, Price price, I target, bytes32[2] calldata data, string[] memory names) external {}
+	function callback(function (uint) external returns (bool) fn) external {}
+}`
+	p := solcparser.NewProject()
+	if err := p.AddSource("a.sol", src); err != nil {
+		t.Fatal(err)
+	}
+	info := Check(p)
+	contract, _ := p.Contract("A")
+
+	expected := map[string]string{
+		"allowed":      "allowed(address,uint256)",
+		"values":       "values(uint256)",
+		"m":            "m(uint256)",
+		"Transfer":     "Transfer(address,address,uint256)",
+		"Insufficient": "Insufficient(uint256,uint256)",
+		"transfer":     "transfer(address,uint256)",
+		"move":         "move((uint256,int8),uint8,uint128,address,bytes32[2],string[])",
+		"callback":     "callback(function)",
+	}
+	found := 0
+	solcparser.Inspect(contract, func(node interface{}) bool {
+		sig, ok := info.Signature(node)
+		if fn, isFn := node.(*solcparser.FunctionDefinition); isFn && fn.IsConstructor {
+			if ok {
+				t.Fatal("the constructor has no signature")
+			}
+			return true
+		}
+		if !ok {
+			return true
+		}
+		name := sig[:strings.Index(sig, "(")]
+		if expected[name] != sig {
+			t.Fatalf("bad signature of %s: %s", name, sig)
+		}
+		found++
+		return true
+	})
+	if found != len(expected) {
+		t.Fatalf("expected %d signatures but found %d", len(expected), found)
+	}
+
+	if sel := Selector("transfer(address,uint256)"); fmt.Sprintf("%x", sel) != "a9059cbb" {
+		t.Fatalf("bad selector %x", sel)
+	}
+}
+
+func TestLibrarySignature(t *testing.T) {
+	src := `
+contract C {}
+
+struct Global { uint a; }
+
+library L {
+	struct S { uint a; }
+	enum E { A, B }
+	type P is uint128;
+
+	function f(uint256) external {}
+	function g(uint256[] storage) external {}
+	function h(uint256[] memory) public {}
+	function lf(S storage s, uint x) external {}
+	function m(mapping(address => uint) storage balances) external {}
+	function e(E kind, C target) public {}
+	function s(S memory value, S[] storage values, Global memory global) public {}
+	function p(P price, bytes storage data, string memory name) public {}
+}`
+	p := solcparser.NewProject()
+	if err := p.AddSource("a.sol", src); err != nil {
+		t.Fatal(err)
+	}
+	info := Check(p)
+	library, _ := p.Contract("L")
+
+	// the signatures and the selectors of the library calls of solc
+	expected := map[string][2]string{
+		"f":  {"f(uint256)", "b3de648b"},
+		"g":  {"g(uint256[] storage)", "c6bfd994"},
+		"h":  {"h(uint256[])", "40dcbb08"},
+		"lf": {"lf(L.S storage,uint256)", "17197936"},
+		"m":  {"m(mapping(address => uint256) storage)", "f0b8acd0"},
+		"e":  {"e(L.E,C)", "5f9b5637"},
+		"s":  {"s(L.S,L.S[] storage,Global)", "6c0d55cc"},
+		"p":  {"p(uint128,bytes storage,string)", "95cd5732"},
+	}
+	found := 0
+	for _, node := range library.SubNodes {
+		fn, ok := node.(*solcparser.FunctionDefinition)
+		if !ok {
+			continue
+		}
+		sig, ok := info.Signature(fn)
+		if !ok {
+			t.Fatalf("no signature for %s", fn.Name)
+		}
+		if sig != expected[fn.Name][0] {
+			t.Fatalf("bad signature of %s: %s", fn.Name, sig)
+		}
+		if sel := fmt.Sprintf("%x", Selector(sig)); sel != expected[fn.Name][1] {
+			t.Fatalf("bad selector of %s: %s", sig, sel)
+		}
+		found++
+	}
+	if found != len(expected) {
+		t.Fatalf("expected %d signatures but found %d", len(expected), found)
+	}
+}
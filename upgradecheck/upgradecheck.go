@@ -0,0 +1,496 @@
+// Package upgradecheck compares the storage layouts of two versions of an
+// upgradeable contract and reports the changes that are not safe to deploy
+// behind a proxy.
+package upgradecheck
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+	"github.com/umbracle/solidity-parser-go/layout"
+)
+
+const (
+	// KindDeleted is a state variable removed from the layout
+	KindDeleted = "deleted"
+
+	// KindRenamed is a state variable whose slot is used by a variable with another name
+	KindRenamed = "renamed"
+
+	// KindMoved is a state variable stored in a different slot or offset
+	KindMoved = "moved"
+
+	// KindTypeChanged is a state variable with an incompatible type
+	KindTypeChanged = "type-changed"
+
+	// KindInserted is a new state variable that uses slots of the previous layout
+	KindInserted = "inserted"
+
+	// KindGapChanged is a storage gap that does not end at the same slot
+	KindGapChanged = "gap-changed"
+
+	// KindParentInserted is a new base contract with storage placed before existing ones
+	KindParentInserted = "parent-inserted"
+
+	// KindParentRemoved is a base contract with storage that is no longer inherited
+	KindParentRemoved = "parent-removed"
+
+	// KindConstructor is a constructor in the implementation contract
+	KindConstructor = "constructor"
+
+	// KindImmutable is an immutable variable in the implementation contract
+	KindImmutable = "immutable"
+)
+
+// Problem is an unsafe change between the two versions of the contract
+type Problem struct {
+	Kind    string               `json:"kind"`
+	Message string               `json:"message"`
+	File    string               `json:"file,omitempty"`
+	Loc     *solcparser.Location `json:"loc,omitempty"`
+}
+
+func (p *Problem) String() string {
+	if p.Loc == nil {
+		if p.File == "" {
+			return p.Message
+		}
+		return p.File + ": " + p.Message
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Loc.Start.Line, p.Loc.Start.Column+1, p.Message)
+}
+
+// Report is the result of the upgrade check
+type Report struct {
+	Problems []*Problem `json:"problems"`
+}
+
+// Ok returns true if the upgrade is safe
+func (r *Report) Ok() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *Report) add(kind string, file string, loc *solcparser.Location, format string, args ...interface{}) {
+	r.Problems = append(r.Problems, &Problem{
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+		File:    file,
+		Loc:     loc,
+	})
+}
+
+// Check compares the contract with the given name in both projects
+func Check(oldProject, newProject *solcparser.Project, name string) (*Report, error) {
+	oldContract, ok := oldProject.Contract(name)
+	if !ok {
+		return nil, fmt.Errorf("contract %s not found in the old version", name)
+	}
+	newContract, ok := newProject.Contract(name)
+	if !ok {
+		return nil, fmt.Errorf("contract %s not found in the new version", name)
+	}
+	return CheckContracts(oldProject, oldContract, newProject, newContract)
+}
+
+// CheckContracts compares two versions of a contract
+func CheckContracts(oldProject *solcparser.Project, oldContract *solcparser.ContractDefinition, newProject *solcparser.Project, newContract *solcparser.ContractDefinition) (*Report, error) {
+	oldLayout, err := layout.ComputeContract(oldProject, oldContract)
+	if err != nil {
+		return nil, fmt.Errorf("old version: %v", err)
+	}
+	newLayout, err := layout.ComputeContract(newProject, newContract)
+	if err != nil {
+		return nil, fmt.Errorf("new version: %v", err)
+	}
+	oldLin, err := oldProject.Linearize(oldContract)
+	if err != nil {
+		return nil, err
+	}
+	newLin, err := newProject.Linearize(newContract)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{
+		report:     &Report{Problems: []*Problem{}},
+		oldProject: oldProject,
+		newProject: newProject,
+		oldLayout:  oldLayout,
+		newLayout:  newLayout,
+	}
+	c.checkLinearization(oldLin, newLin)
+	c.checkLayout()
+	c.checkImplementation(oldLin, newLin)
+	return c.report, nil
+}
+
+type checker struct {
+	report     *Report
+	oldProject *solcparser.Project
+	newProject *solcparser.Project
+	oldLayout  *layout.StorageLayout
+	newLayout  *layout.StorageLayout
+}
+
+func (c *checker) newPos(item *layout.StorageItem) (string, *solcparser.Location) {
+	return c.newProject.SourceOf(item.Scope), item.Variable.GetLoc()
+}
+
+func (c *checker) oldPos(item *layout.StorageItem) (string, *solcparser.Location) {
+	return c.oldProject.SourceOf(item.Scope), item.Variable.GetLoc()
+}
+
+func itemKey(item *layout.StorageItem) string {
+	return item.Scope.Name + "." + item.Label
+}
+
+func isGap(item *layout.StorageItem) bool {
+	return strings.HasPrefix(item.Label, "__gap") && strings.HasPrefix(item.Type, "t_array(") && !strings.HasSuffix(item.Type, "dyn_storage")
+}
+
+func (c *checker) checkLayout() {
+	oldItems := map[string]*layout.StorageItem{}
+	for _, item := range c.oldLayout.Storage {
+		oldItems[itemKey(item)] = item
+	}
+	newItems := map[string]*layout.StorageItem{}
+	for _, item := range c.newLayout.Storage {
+		newItems[itemKey(item)] = item
+	}
+
+	// new variables that take the place of an old variable with another name
+	renamed := map[*layout.StorageItem]bool{}
+
+	oldEnd := big.NewInt(0)
+	gaps := [][2]*big.Int{}
+
+	for _, old := range c.oldLayout.Storage {
+		start, end := itemRange(old, c.oldLayout.Types)
+		if end.Cmp(oldEnd) > 0 {
+			oldEnd = end
+		}
+
+		if isGap(old) {
+			gaps = append(gaps, [2]*big.Int{start, end})
+
+			item, ok := newItems[itemKey(old)]
+			if !ok {
+				item = c.findGap(start, end, oldItems)
+			}
+			if item == nil {
+				file, loc := c.oldPos(old)
+				c.report.add(KindDeleted, file, loc, "storage gap %s was deleted", itemKey(old))
+				continue
+			}
+			_, newEnd := itemRange(item, c.newLayout.Types)
+			if newEnd.Cmp(end) != 0 {
+				file, loc := c.newPos(item)
+				c.report.add(KindGapChanged, file, loc, "storage gap %s ends at slot %s instead of %s", itemKey(old), newEnd, end)
+			}
+			continue
+		}
+
+		item, ok := newItems[itemKey(old)]
+		if !ok {
+			if other := c.findAt(old.Slot, old.Offset); other != nil && !isGap(other) && c.compatible(old.Type, other.Type, false) {
+				renamed[other] = true
+				file, loc := c.newPos(other)
+				c.report.add(KindRenamed, file, loc, "variable %s was renamed to %s", itemKey(old), itemKey(other))
+			} else {
+				file, loc := c.oldPos(old)
+				c.report.add(KindDeleted, file, loc, "variable %s was deleted", itemKey(old))
+			}
+			continue
+		}
+
+		file, loc := c.newPos(item)
+		if item.Slot != old.Slot || item.Offset != old.Offset {
+			c.report.add(KindMoved, file, loc, "variable %s moved from slot %s (offset %d) to slot %s (offset %d)", itemKey(old), old.Slot, old.Offset, item.Slot, item.Offset)
+			continue
+		}
+		if !c.compatible(old.Type, item.Type, false) {
+			c.report.add(KindTypeChanged, file, loc, "variable %s changed its type from %s to %s", itemKey(old), typeLabel(old.Type, c.oldLayout.Types), typeLabel(item.Type, c.newLayout.Types))
+		}
+	}
+
+	for _, item := range c.newLayout.Storage {
+		if _, ok := oldItems[itemKey(item)]; ok || renamed[item] {
+			continue
+		}
+		start, end := itemRange(item, c.newLayout.Types)
+		if start.Cmp(oldEnd) >= 0 {
+			// appended at the end of the layout
+			continue
+		}
+		inGap := false
+		for _, gap := range gaps {
+			if start.Cmp(gap[0]) >= 0 && end.Cmp(gap[1]) <= 0 {
+				inGap = true
+				break
+			}
+		}
+		if !inGap {
+			file, loc := c.newPos(item)
+			c.report.add(KindInserted, file, loc, "variable %s was inserted at slot %s in the middle of the existing layout", itemKey(item), item.Slot)
+		}
+	}
+}
+
+// findAt returns the new variable stored at the given slot and offset
+func (c *checker) findAt(slot string, offset uint64) *layout.StorageItem {
+	for _, item := range c.newLayout.Storage {
+		if item.Slot == slot && item.Offset == offset {
+			return item
+		}
+	}
+	return nil
+}
+
+// findGap returns the new storage gap with another name that starts in the
+// slots of an old gap
+func (c *checker) findGap(start, end *big.Int, oldItems map[string]*layout.StorageItem) *layout.StorageItem {
+	for _, item := range c.newLayout.Storage {
+		if _, ok := oldItems[itemKey(item)]; ok || !isGap(item) {
+			continue
+		}
+		if slot, _ := itemRange(item, c.newLayout.Types); slot.Cmp(start) >= 0 && slot.Cmp(end) < 0 {
+			return item
+		}
+	}
+	return nil
+}
+
+// compatible returns true if the new type can read the data stored with the old type.
+// Structs can only grow if they are not stored in place (i.e. as mapping values).
+func (c *checker) compatible(oldID, newID string, canGrow bool) bool {
+	oldType, newType := c.oldLayout.Types[oldID], c.newLayout.Types[newID]
+	if oldType == nil || newType == nil {
+		return oldID == newID
+	}
+
+	if oldID != newID {
+		// contracts and addresses are interchangeable
+		isAddress := func(id string) bool {
+			return id == "t_address" || id == "t_address_payable" || strings.HasPrefix(id, "t_contract(")
+		}
+		return isAddress(oldID) && isAddress(newID)
+	}
+
+	switch oldType.Encoding {
+	case layout.EncodingMapping:
+		return c.compatible(oldType.Key, newType.Key, false) && c.compatible(oldType.Value, newType.Value, true)
+
+	case layout.EncodingDynamicArray:
+		return c.compatible(oldType.Base, newType.Base, false)
+	}
+
+	if oldType.Base != "" && !c.compatible(oldType.Base, newType.Base, false) {
+		return false
+	}
+	if len(oldType.Members) != 0 || len(newType.Members) != 0 {
+		if len(newType.Members) < len(oldType.Members) {
+			return false
+		}
+		for i, m := range oldType.Members {
+			n := newType.Members[i]
+			if m.Label != n.Label || m.Slot != n.Slot || m.Offset != n.Offset || !c.compatible(m.Type, n.Type, false) {
+				return false
+			}
+		}
+		if oldType.NumberOfBytes != newType.NumberOfBytes && !canGrow {
+			return false
+		}
+		return true
+	}
+	return oldType.NumberOfBytes == newType.NumberOfBytes
+}
+
+func (c *checker) checkLinearization(oldLin, newLin []*solcparser.ContractDefinition) {
+	names := func(lin []*solcparser.ContractDefinition) map[string]int {
+		res := map[string]int{}
+		// index from the most base-like contract
+		for i := range lin {
+			res[lin[len(lin)-1-i].Name] = i
+		}
+		return res
+	}
+	oldNames, newNames := names(oldLin), names(newLin)
+
+	// position (from the base) of the most derived contract also present in the old version
+	lastShared := -1
+	for name, i := range newNames {
+		if _, ok := oldNames[name]; ok && i > lastShared {
+			lastShared = i
+		}
+	}
+
+	for i := len(newLin) - 1; i >= 0; i-- {
+		contract := newLin[i]
+		if _, ok := oldNames[contract.Name]; ok {
+			continue
+		}
+		if newNames[contract.Name] < lastShared && hasStorage(contract) {
+			c.report.add(KindParentInserted, c.newProject.SourceOf(contract), contract.GetLoc(), "base contract %s with storage was inserted in the inheritance chain", contract.Name)
+		}
+	}
+	for i := len(oldLin) - 1; i >= 0; i-- {
+		contract := oldLin[i]
+		if _, ok := newNames[contract.Name]; ok {
+			continue
+		}
+		if hasStorage(contract) {
+			c.report.add(KindParentRemoved, c.oldProject.SourceOf(contract), contract.GetLoc(), "base contract %s with storage is no longer inherited", contract.Name)
+		}
+	}
+}
+
+// checkImplementation reports the constructs that do not work behind a proxy
+// and that are new in the implementation. The constructors and immutables
+// annotated with @custom:oz-upgrades-unsafe-allow are allowed.
+func (c *checker) checkImplementation(oldLin, newLin []*solcparser.ContractDefinition) {
+	oldContracts := map[string]*solcparser.ContractDefinition{}
+	for _, contract := range oldLin {
+		oldContracts[contract.Name] = contract
+	}
+
+	for i := len(newLin) - 1; i >= 0; i-- {
+		contract := newLin[i]
+		file := c.newProject.SourceOf(contract)
+		src, _ := c.newProject.Source(file)
+		allowed := unsafeAllow(src, contract)
+
+		// the constructor and the immutables of the old version
+		hadConstructor := false
+		oldImmutables := map[string]bool{}
+		if old, ok := oldContracts[contract.Name]; ok {
+			for _, node := range old.SubNodes {
+				switch obj := node.(type) {
+				case *solcparser.FunctionDefinition:
+					hadConstructor = hadConstructor || obj.IsConstructor
+				case *solcparser.StateVariableDeclaration:
+					for _, v := range obj.Variables {
+						if vv, ok := v.(*solcparser.StateVariableDeclarationVariable); ok && vv.IsInmutable {
+							oldImmutables[vv.Name] = true
+						}
+					}
+				}
+			}
+		}
+
+		for _, node := range contract.SubNodes {
+			switch obj := node.(type) {
+			case *solcparser.FunctionDefinition:
+				if !obj.IsConstructor || hadConstructor || allowed["constructor"] || unsafeAllow(src, obj)["constructor"] {
+					continue
+				}
+				c.report.add(KindConstructor, file, obj.GetLoc(), "contract %s has a constructor, use an initializer instead", contract.Name)
+			case *solcparser.StateVariableDeclaration:
+				if allowed["state-variable-immutable"] || unsafeAllow(src, obj)["state-variable-immutable"] {
+					continue
+				}
+				for _, v := range obj.Variables {
+					if vv, ok := v.(*solcparser.StateVariableDeclarationVariable); ok && vv.IsInmutable && !oldImmutables[vv.Name] {
+						c.report.add(KindImmutable, file, vv.GetLoc(), "variable %s.%s is immutable and it is not stored in the proxy", contract.Name, vv.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// unsafeAllow returns the checks disabled by the @custom:oz-upgrades-unsafe-allow
+// tags of the NatSpec comment before a node (i.e. constructor)
+func unsafeAllow(src string, node solcparser.INode) map[string]bool {
+	res := map[string]bool{}
+	loc := node.GetLoc()
+	if loc == nil || loc.Start.Offset > len(src) {
+		return res
+	}
+	for _, line := range docComment(src, loc.Start.Offset) {
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if field == "@custom:oz-upgrades-unsafe-allow" {
+				for _, check := range fields[i+1:] {
+					res[check] = true
+				}
+			}
+		}
+	}
+	return res
+}
+
+// docComment returns the lines of the NatSpec comment (/// or /** */) that
+// ends right before the offset
+func docComment(src string, offset int) []string {
+	before := strings.TrimRight(src[:offset], " \t\r\n")
+
+	if strings.HasSuffix(before, "*/") {
+		start := strings.LastIndex(before, "/**")
+		if start == -1 || strings.LastIndex(before, "/*") != start {
+			// not a doc comment
+			return nil
+		}
+		lines := []string{}
+		for _, line := range strings.Split(before[start+3:len(before)-2], "\n") {
+			lines = append(lines, strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		}
+		return lines
+	}
+
+	// the consecutive /// lines
+	lines := []string{}
+	for {
+		i := strings.LastIndex(before, "\n")
+		line := strings.TrimSpace(before[i+1:])
+		if !strings.HasPrefix(line, "///") {
+			break
+		}
+		lines = append(lines, strings.TrimPrefix(line, "///"))
+		if i == -1 {
+			break
+		}
+		before = strings.TrimRight(before[:i], " \t\r")
+	}
+	return lines
+}
+
+func hasStorage(contract *solcparser.ContractDefinition) bool {
+	for _, node := range contract.SubNodes {
+		decl, ok := node.(*solcparser.StateVariableDeclaration)
+		if !ok {
+			continue
+		}
+		for _, v := range decl.Variables {
+			if vv, ok := v.(*solcparser.StateVariableDeclarationVariable); ok && !vv.IsDeclaredConst && !vv.IsInmutable {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// itemRange returns the first slot and the slot after the last one used by the item
+func itemRange(item *layout.StorageItem, types map[string]*layout.TypeInfo) (*big.Int, *big.Int) {
+	start, _ := new(big.Int).SetString(item.Slot, 10)
+
+	size := big.NewInt(32)
+	if typ, ok := types[item.Type]; ok {
+		size, _ = new(big.Int).SetString(typ.NumberOfBytes, 10)
+	}
+	// bytes used from the start of the slot
+	used := new(big.Int).Add(size, new(big.Int).SetUint64(item.Offset))
+	slots, mod := new(big.Int).DivMod(used, big.NewInt(32), new(big.Int))
+	if mod.Sign() != 0 {
+		slots.Add(slots, big.NewInt(1))
+	}
+	return start, new(big.Int).Add(start, slots)
+}
+
+func typeLabel(id string, types map[string]*layout.TypeInfo) string {
+	if typ, ok := types[id]; ok {
+		return typ.Label
+	}
+	return id
+}
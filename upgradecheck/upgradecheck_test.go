@@ -0,0 +1,200 @@
+package upgradecheck
+
+import (
+	"reflect"
+	"testing"
+
+	solcparser "github.com/umbracle/solidity-parser-go"
+)
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		old      string
+		new      string
+		contract string
+		kinds    []string
+	}{
+		{
+			// append at the end
+			"contract A { uint a; }",
+			"contract A { uint a; uint b; }",
+			"A",
+			[]string{},
+		},
+		{
+			"contract A { uint a; uint b; }",
+			"contract A { uint a; }",
+			"A",
+			[]string{KindDeleted},
+		},
+		{
+			"contract A { uint a; uint b; }",
+			"contract A { uint b; uint a; }",
+			"A",
+			[]string{KindMoved, KindMoved},
+		},
+		{
+			"contract A { uint a; }",
+			"contract A { address a; }",
+			"A",
+			[]string{KindTypeChanged},
+		},
+		{
+			"contract A { uint a; }",
+			"contract A { uint b; }",
+			"A",
+			[]string{KindRenamed},
+		},
+		{
+			"contract A { uint a; uint b; }",
+			"contract A { uint a; uint c; uint b; }",
+			"A",
+			[]string{KindMoved, KindInserted},
+		},
+		{
+			// use one slot of the gap
+			"contract A { uint a; uint[50] __gap; } contract B is A { uint b; }",
+			"contract A { uint a; uint c; uint[49] __gap; } contract B is A { uint b; }",
+			"B",
+			[]string{},
+		},
+		{
+			// the gap is not shrunk
+			"contract A { uint a; uint[50] __gap; } contract B is A { uint b; }",
+			"contract A { uint a; uint c; uint[50] __gap; } contract B is A { uint b; }",
+			"B",
+			[]string{KindGapChanged, KindMoved},
+		},
+		{
+			"contract A { uint a; } contract B is A { uint b; }",
+			"contract X { uint x; } contract A { uint a; } contract B is X, A { uint b; }",
+			"B",
+			[]string{KindParentInserted, KindMoved, KindMoved, KindInserted},
+		},
+		{
+			// parents without storage can be inserted
+			"contract A { uint a; } contract B is A { uint b; }",
+			"contract X { } contract A { uint a; } contract B is X, A { uint b; }",
+			"B",
+			[]string{},
+		},
+		{
+			// the gap is deleted
+			"contract A { uint a; uint[50] __gap; } contract B is A { uint b; }",
+			"contract A { uint a; } contract B is A { uint b; }",
+			"B",
+			[]string{KindDeleted, KindMoved},
+		},
+		{
+			// the gap is renamed
+			"contract A { uint a; uint[50] __gap; } contract B is A { uint b; }",
+			"contract A { uint a; uint c; uint[49] __gap_A; } contract B is A { uint b; }",
+			"B",
+			[]string{},
+		},
+		{
+			"contract A { uint a; }",
+			"contract A { uint a; uint immutable b; constructor() {} }",
+			"A",
+			[]string{KindImmutable, KindConstructor},
+		},
+		{
+			// the constructor and the immutable of the old version
+			"contract A { uint a; uint immutable b; constructor() {} }",
+			"contract A { uint a; uint immutable b; uint c; constructor() {} }",
+			"A",
+			[]string{},
+		},
+		{
+			"contract A { uint a; }",
+			"contract A {\n  uint a;\n  /// @custom:oz-upgrades-unsafe-allow constructor\n  constructor() { _disableInitializers(); }\n}",
+			"A",
+			[]string{},
+		},
+		{
+			"contract A { uint a; }",
+			"contract A {\n  uint a;\n  /**\n   * @custom:oz-upgrades-unsafe-allow state-variable-immutable\n   */\n  uint immutable b;\n  uint immutable c;\n}",
+			"A",
+			[]string{KindImmutable},
+		},
+		{
+			"contract A { uint a; }",
+			"/// @custom:oz-upgrades-unsafe-allow constructor state-variable-immutable\ncontract A { uint a; uint immutable b; constructor() {} }",
+			"A",
+			[]string{},
+		},
+		{
+			// the annotation of another check
+			"contract A { uint a; }",
+			"contract A {\n  uint a;\n  /// @custom:oz-upgrades-unsafe-allow selfdestruct\n  constructor() {}\n}",
+			"A",
+			[]string{KindConstructor},
+		},
+		{
+			// structs in mappings can grow
+			"contract A { struct S { uint a; } mapping(uint => S) m; }",
+			"contract A { struct S { uint a; uint b; } mapping(uint => S) m; }",
+			"A",
+			[]string{},
+		},
+		{
+			"contract A { struct S { uint a; } S s; uint b; }",
+			"contract A { struct S { uint a; uint b; } S s; uint b; }",
+			"A",
+			[]string{KindTypeChanged, KindMoved},
+		},
+		{
+			"interface I {} contract A { I a; }",
+			"interface I {} contract A { address a; }",
+			"A",
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		oldProject := solcparser.NewProject()
+		if err := oldProject.AddSource("old.sol", c.old); err != nil {
+			t.Fatal(err)
+		}
+		newProject := solcparser.NewProject()
+		if err := newProject.AddSource("new.sol", c.new); err != nil {
+			t.Fatal(err)
+		}
+
+		report, err := Check(oldProject, newProject, c.contract)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		kinds := []string{}
+		for _, p := range report.Problems {
+			kinds = append(kinds, p.Kind)
+		}
+		if !reflect.DeepEqual(kinds, c.kinds) {
+			t.Fatalf("bad problems for '%s':\n%v\n%v", c.new, kinds, report.Problems)
+		}
+	}
+}
+
+func TestCheckPositions(t *testing.T) {
+	oldProject := solcparser.NewProject()
+	if err := oldProject.AddSource("old.sol", "contract A {\n  uint a;\n}"); err != nil {
+		t.Fatal(err)
+	}
+	newProject := solcparser.NewProject()
+	if err := newProject.AddSource("new.sol", "contract A {\n  address a;\n}"); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Check(oldProject, newProject, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Problems) != 1 {
+		t.Fatal("one problem expected")
+	}
+	expected := "new.sol:2:3: variable A.a changed its type from uint256 to address"
+	if str := report.Problems[0].String(); str != expected {
+		t.Fatalf("bad problem '%s'", str)
+	}
+}
@@ -0,0 +1,59 @@
+package solcparser
+
+import (
+	"math/big"
+	"reflect"
+)
+
+var (
+	locationType = reflect.TypeOf(&Location{})
+	nodeType     = reflect.TypeOf(Node{})
+	ratType      = reflect.TypeOf(&big.Rat{})
+)
+
+// Inspect traverses the AST in depth-first order. It calls f for every
+// node and it visits the children of the node if f returns true.
+func Inspect(node interface{}, f func(node interface{}) bool) {
+	inspect(reflect.ValueOf(node), f)
+}
+
+func inspect(v reflect.Value, f func(node interface{}) bool) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			inspect(v.Elem(), f)
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			inspect(v.Index(i), f)
+		}
+
+	case reflect.Ptr:
+		if v.IsNil() || v.Type() == locationType || v.Type() == ratType || v.Elem().Kind() != reflect.Struct {
+			return
+		}
+		if !f(v.Interface()) {
+			return
+		}
+		inspectFields(v.Elem(), f)
+	}
+}
+
+func inspectFields(elem reflect.Value, f func(node interface{}) bool) {
+	typ := elem.Type()
+	for i := 0; i < elem.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Type == nodeType || field.PkgPath != "" {
+			// skip the embedded Node and the unexported fields
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			// the fields of an embedded declaration (i.e. the VariableDeclaration
+			// of a StateVariableDeclarationVariable) belong to the node
+			inspectFields(elem.Field(i), f)
+			continue
+		}
+		inspect(elem.Field(i), f)
+	}
+}
@@ -0,0 +1,54 @@
+package solcparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	p := Parse("contract A { function f(uint a) public { a = a + 1; if (a > 2) { g(a); } } }")
+	if len(p.Errors) != 0 {
+		t.Fatal(p.Errors)
+	}
+
+	idents := []string{}
+	Inspect(p.Result, func(node interface{}) bool {
+		if ident, ok := node.(*Identifier); ok {
+			idents = append(idents, ident.Name)
+		}
+		return true
+	})
+	// the parameter, the assignment, the condition and the call
+	if expected := []string{"a", "a", "a", "a", "a", "g"}; !reflect.DeepEqual(idents, expected) {
+		t.Fatalf("bad identifiers %v", idents)
+	}
+
+	// do not visit the children of the if statement
+	count := 0
+	Inspect(p.Result, func(node interface{}) bool {
+		if _, ok := node.(*Identifier); ok {
+			count++
+		}
+		_, ok := node.(*IfStatement)
+		return !ok
+	})
+	if count != 3 {
+		t.Fatalf("bad count %d", count)
+	}
+
+	// the type and the value of the state variables
+	p = Parse("contract A { uint constant B = C; }")
+	nodes := []string{}
+	Inspect(p.Result, func(node interface{}) bool {
+		switch obj := node.(type) {
+		case *ElementaryTypeName:
+			nodes = append(nodes, obj.Name)
+		case *Identifier:
+			nodes = append(nodes, obj.Name)
+		}
+		return true
+	})
+	if expected := []string{"uint", "B", "C"}; !reflect.DeepEqual(nodes, expected) {
+		t.Fatalf("bad state variable nodes %v", nodes)
+	}
+}